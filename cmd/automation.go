@@ -3,6 +3,7 @@ package main
 import (
 	"strconv"
 
+	authmodels "github.com/abhinavxd/libredesk/internal/auth/models"
 	amodels "github.com/abhinavxd/libredesk/internal/automation/models"
 	"github.com/abhinavxd/libredesk/internal/envelope"
 	"github.com/valyala/fasthttp"
@@ -13,6 +14,15 @@ type updateAutomationRuleExecutionModeReq struct {
 	Mode string `json:"mode"`
 }
 
+type simulateAutomationRuleReq struct {
+	amodels.RuleRecord
+	Limit int `json:"limit"`
+}
+
+type rollbackAutomationRuleReq struct {
+	Version int `json:"version"`
+}
+
 // handleGetAutomationRules gets all automation rules
 func handleGetAutomationRules(r *fastglue.Request) error {
 	var (
@@ -56,6 +66,7 @@ func handleToggleAutomationRule(r *fastglue.Request) error {
 func handleUpdateAutomationRule(r *fastglue.Request) error {
 	var (
 		app     = r.Context.(*App)
+		auser   = r.RequestCtx.UserValue("user").(authmodels.User)
 		rule    = amodels.RuleRecord{}
 		id, err = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
 	)
@@ -67,7 +78,7 @@ func handleUpdateAutomationRule(r *fastglue.Request) error {
 		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), nil, envelope.InputError)
 	}
 
-	updatedRule, err := app.automation.UpdateRule(id, rule)
+	updatedRule, err := app.automation.UpdateRule(id, rule, auser.ID)
 	if err != nil {
 		return sendErrorEnvelope(r, err)
 	}
@@ -77,13 +88,14 @@ func handleUpdateAutomationRule(r *fastglue.Request) error {
 // handleCreateAutomationRule creates a new automation rule
 func handleCreateAutomationRule(r *fastglue.Request) error {
 	var (
-		app  = r.Context.(*App)
-		rule = amodels.RuleRecord{}
+		app   = r.Context.(*App)
+		auser = r.RequestCtx.UserValue("user").(authmodels.User)
+		rule  = amodels.RuleRecord{}
 	)
 	if err := r.Decode(&rule, "json"); err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), nil, envelope.InputError)
 	}
-	createdRule, err := app.automation.CreateRule(rule)
+	createdRule, err := app.automation.CreateRule(rule, auser.ID)
 	if err != nil {
 		return sendErrorEnvelope(r, err)
 	}
@@ -143,3 +155,201 @@ func handleUpdateAutomationRuleExecutionMode(r *fastglue.Request) error {
 	}
 	return r.SendEnvelope(true)
 }
+
+// handleSimulateAutomationRule evaluates an automation rule against recent conversations without applying its actions.
+func handleSimulateAutomationRule(r *fastglue.Request) error {
+	var (
+		app = r.Context.(*App)
+		req = simulateAutomationRuleReq{}
+	)
+	if err := r.Decode(&req, "json"); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), nil, envelope.InputError)
+	}
+	out, err := app.automation.SimulateRule(req.RuleRecord, req.Limit)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(out)
+}
+
+// handleGetAutomationRuleExecutions returns the execution log of an automation rule.
+func handleGetAutomationRuleExecutions(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		id, _ = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+		total = 0
+	)
+	if id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("validation.invalidValue"), nil, envelope.InputError)
+	}
+	page, pageSize := getPagination(r)
+	executions, err := app.automation.GetRuleExecutions(id, page, pageSize)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	if len(executions) > 0 {
+		total = executions[0].Total
+	}
+	return r.SendEnvelope(envelope.PageResults{
+		Results:    executions,
+		Total:      total,
+		PerPage:    pageSize,
+		TotalPages: (total + pageSize - 1) / pageSize,
+		Page:       page,
+	})
+}
+
+// handleGetAutomationRuleVersions returns the version history of an automation rule.
+func handleGetAutomationRuleVersions(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		id, _ = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+		total = 0
+	)
+	if id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("validation.invalidValue"), nil, envelope.InputError)
+	}
+	page, pageSize := getPagination(r)
+	versions, err := app.automation.GetRuleVersions(id, page, pageSize)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	if len(versions) > 0 {
+		total = versions[0].Total
+	}
+	return r.SendEnvelope(envelope.PageResults{
+		Results:    versions,
+		Total:      total,
+		PerPage:    pageSize,
+		TotalPages: (total + pageSize - 1) / pageSize,
+		Page:       page,
+	})
+}
+
+// handleRollbackAutomationRule restores an automation rule to an earlier version.
+func handleRollbackAutomationRule(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		auser = r.RequestCtx.UserValue("user").(authmodels.User)
+		id, _ = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+		req   = rollbackAutomationRuleReq{}
+	)
+	if id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("validation.invalidValue"), nil, envelope.InputError)
+	}
+	if err := r.Decode(&req, "json"); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), nil, envelope.InputError)
+	}
+	if req.Version <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("validation.invalidValue"), nil, envelope.InputError)
+	}
+	rule, err := app.automation.RollbackRule(id, req.Version, auser.ID)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(rule)
+}
+
+// handleExportAutomationRules exports all automation rules with referenced records identified by name.
+func handleExportAutomationRules(r *fastglue.Request) error {
+	var app = r.Context.(*App)
+	out, err := app.automation.ExportRules()
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(out)
+}
+
+// handleImportAutomationRules imports exported automation rules.
+func handleImportAutomationRules(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		auser = r.RequestCtx.UserValue("user").(authmodels.User)
+		rules = []amodels.RuleExport{}
+	)
+	if err := r.Decode(&rules, "json"); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), nil, envelope.InputError)
+	}
+	out, err := app.automation.ImportRules(rules, auser.ID)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(out)
+}
+
+// handleGetAutomationSchedules returns all scheduled automations.
+func handleGetAutomationSchedules(r *fastglue.Request) error {
+	var app = r.Context.(*App)
+	out, err := app.automation.GetSchedules()
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(out)
+}
+
+// handleGetAutomationSchedule returns a scheduled automation.
+func handleGetAutomationSchedule(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		id, _ = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+	)
+	if id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("validation.invalidValue"), nil, envelope.InputError)
+	}
+	out, err := app.automation.GetSchedule(id)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(out)
+}
+
+// handleCreateAutomationSchedule creates a scheduled automation.
+func handleCreateAutomationSchedule(r *fastglue.Request) error {
+	var (
+		app      = r.Context.(*App)
+		schedule = amodels.Schedule{}
+	)
+	if err := r.Decode(&schedule, "json"); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), nil, envelope.InputError)
+	}
+	out, err := app.automation.CreateSchedule(schedule)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(out)
+}
+
+// handleUpdateAutomationSchedule updates a scheduled automation.
+func handleUpdateAutomationSchedule(r *fastglue.Request) error {
+	var (
+		app      = r.Context.(*App)
+		id, _    = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+		schedule = amodels.Schedule{}
+	)
+	if id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("validation.invalidValue"), nil, envelope.InputError)
+	}
+	if err := r.Decode(&schedule, "json"); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), nil, envelope.InputError)
+	}
+	out, err := app.automation.UpdateSchedule(id, schedule)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(out)
+}
+
+// handleDeleteAutomationSchedule deletes a scheduled automation.
+func handleDeleteAutomationSchedule(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		id, _ = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+	)
+	if id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("validation.invalidValue"), nil, envelope.InputError)
+	}
+	if err := app.automation.DeleteSchedule(id); err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(true)
+}
@@ -1,7 +1,12 @@
 package main
 
 import (
+	"encoding/json"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	businessHours "github.com/abhinavxd/libredesk/internal/business_hours"
 	models "github.com/abhinavxd/libredesk/internal/business_hours/models"
@@ -54,8 +59,14 @@ func handleCreateBusinessHours(r *fastglue.Request) error {
 	if businessHours.Name == "" {
 		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.Ts("globals.messages.empty", "name", "`name`"), nil, envelope.InputError)
 	}
+	if err := validateHolidays(app, businessHours); err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	if err := normalizeWorkingHours(app, &businessHours); err != nil {
+		return sendErrorEnvelope(r, err)
+	}
 
-	createdBusinessHours, err := app.businessHours.Create(businessHours.Name, businessHours.Description, businessHours.IsAlwaysOpen, businessHours.Hours, businessHours.Holidays)
+	createdBusinessHours, err := app.businessHours.Create(businessHours.Name, businessHours.Description, businessHours.IsAlwaysOpen, businessHours.Hours, businessHours.Holidays, businessHours.HolidayCalendarURL)
 	if err != nil {
 		return sendErrorEnvelope(r, err)
 	}
@@ -94,9 +105,122 @@ func handleUpdateBusinessHours(r *fastglue.Request) error {
 	if businessHours.Name == "" {
 		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("globals.messages.somethingWentWrong"), nil, envelope.InputError)
 	}
-	updatedBusinessHours, err := app.businessHours.Update(id, businessHours.Name, businessHours.Description, businessHours.IsAlwaysOpen, businessHours.Hours, businessHours.Holidays)
+	if err := validateHolidays(app, businessHours); err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	if err := normalizeWorkingHours(app, &businessHours); err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	updatedBusinessHours, err := app.businessHours.Update(id, businessHours.Name, businessHours.Description, businessHours.IsAlwaysOpen, businessHours.Hours, businessHours.Holidays, businessHours.HolidayCalendarURL)
 	if err != nil {
 		return sendErrorEnvelope(r, err)
 	}
 	return r.SendEnvelope(updatedBusinessHours)
 }
+
+// handleImportHolidays imports holidays into the business hour with the given id from an uploaded iCalendar file,
+// or from its holiday calendar URL when no file is uploaded.
+func handleImportHolidays(r *fastglue.Request) error {
+	var (
+		app = r.Context.(*App)
+	)
+	id, err := strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+	if err != nil || id == 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("globals.messages.somethingWentWrong"), nil, envelope.InputError)
+	}
+
+	file, err := r.RequestCtx.FormFile("file")
+	if err != nil {
+		updated, err := app.businessHours.ImportHolidaysFromURL(id)
+		if err != nil {
+			return sendErrorEnvelope(r, err)
+		}
+		return r.SendEnvelope(updated)
+	}
+
+	fileContent, err := file.Open()
+	if err != nil {
+		app.lo.Error("error opening uploaded file", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, app.i18n.T("globals.messages.somethingWentWrong"), nil, envelope.GeneralError)
+	}
+	defer fileContent.Close()
+
+	updated, err := app.businessHours.ImportHolidays(id, fileContent)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(updated)
+}
+
+// timeOfDayRe matches times in "HH:MM" format.
+var timeOfDayRe = regexp.MustCompile(`^(?:[01]\d|2[0-3]):[0-5]\d$`)
+
+// validateHolidays validates the holidays and holiday calendar URL of business hours.
+func validateHolidays(app *App, bh models.BusinessHours) error {
+	if bh.HolidayCalendarURL.String != "" && !businessHours.IsValidCalendarURL(bh.HolidayCalendarURL.String) {
+		return envelope.NewError(envelope.InputError, app.i18n.T("validation.invalidUrl"), nil)
+	}
+
+	// Holidays default to an empty object, which holds no holidays.
+	if len(bh.Holidays) == 0 || strings.TrimSpace(string(bh.Holidays)) == "{}" {
+		return nil
+	}
+	var holidays []models.Holiday
+	if err := json.Unmarshal(bh.Holidays, &holidays); err != nil {
+		return envelope.NewError(envelope.InputError, app.i18n.Ts("validation.invalidValue", "name", "`holidays`"), nil)
+	}
+	for _, h := range holidays {
+		if _, err := time.Parse(time.DateOnly, h.Date); err != nil {
+			return envelope.NewError(envelope.InputError, app.i18n.Ts("validation.invalidValue", "name", "`date`"), nil)
+		}
+		if h.From == "" && h.To == "" {
+			continue
+		}
+		if !timeOfDayRe.MatchString(h.From) || !timeOfDayRe.MatchString(h.To) || h.From >= h.To {
+			return envelope.NewError(envelope.InputError, app.i18n.T("validation.invalidTimeFormat"), nil)
+		}
+	}
+	return nil
+}
+
+// normalizeWorkingHours validates the shifts of each day, which must not overlap, and sets the open and close times
+// of days with shifts to the start of the first and end of the last shift for clients that do not read shifts.
+// Open and close times that disagree with the shifts are rejected rather than overwritten.
+func normalizeWorkingHours(app *App, bh *models.BusinessHours) error {
+	if bh.IsAlwaysOpen || len(bh.Hours) == 0 {
+		return nil
+	}
+	var hours map[string]models.WorkingHours
+	if err := json.Unmarshal(bh.Hours, &hours); err != nil {
+		return envelope.NewError(envelope.InputError, app.i18n.Ts("validation.invalidValue", "name", "`hours`"), nil)
+	}
+	for day, wh := range hours {
+		if len(wh.Shifts) == 0 {
+			continue
+		}
+		slices.SortFunc(wh.Shifts, func(a, b models.Shift) int {
+			return strings.Compare(a.Open, b.Open)
+		})
+		for i, shift := range wh.Shifts {
+			if !timeOfDayRe.MatchString(shift.Open) || !timeOfDayRe.MatchString(shift.Close) || shift.Open >= shift.Close {
+				return envelope.NewError(envelope.InputError, app.i18n.T("validation.invalidTimeFormat"), nil)
+			}
+			if i > 0 && shift.Open < wh.Shifts[i-1].Close {
+				return envelope.NewError(envelope.InputError, app.i18n.Ts("validation.invalidValue", "name", "`shifts`"), nil)
+			}
+		}
+		dayOpen, dayClose := wh.Shifts[0].Open, wh.Shifts[len(wh.Shifts)-1].Close
+		if (wh.Open != "" && wh.Open != dayOpen) || (wh.Close != "" && wh.Close != dayClose) {
+			return envelope.NewError(envelope.InputError, app.i18n.Ts("validation.invalidValue", "name", "`shifts`"), nil)
+		}
+		wh.Open, wh.Close = dayOpen, dayClose
+		hours[day] = wh
+	}
+	b, err := json.Marshal(hours)
+	if err != nil {
+		app.lo.Error("error marshalling working hours", "error", err)
+		return envelope.NewError(envelope.GeneralError, app.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	bh.Hours = b
+	return nil
+}
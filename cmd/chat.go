@@ -152,6 +152,10 @@ func handleGetChatSettings(r *fastglue.Request) error {
 				if bhID, ok := settings["app.business_hours_id"].(string); ok {
 					response.DefaultBusinessHoursID, _ = strconv.Atoi(bhID)
 				}
+				// Business hours of the widget's inbox take precedence over the default.
+				if inbox, err := getWidgetInbox(r); err == nil && inbox.BusinessHoursID.Valid {
+					response.DefaultBusinessHoursID = inbox.BusinessHoursID.Int
+				}
 				if tz, ok := settings["app.timezone"].(string); ok && tz != "" {
 					if loc, err := time.LoadLocation(tz); err == nil {
 						_, offset := time.Now().In(loc).Zone()
@@ -405,6 +409,9 @@ func handleAuthExchange(r *fastglue.Request) error {
 	if len(claims.ContactCustomAttributes) > 0 {
 		if err := app.user.SaveCustomAttributes(contactID, claims.ContactCustomAttributes, false); err != nil {
 			app.lo.Error("error saving custom attributes during auth exchange", "contact_id", contactID, "error", err)
+		} else {
+			// SLA policy conditions can match on contact attributes.
+			app.conversation.EvaluateContactSLAPolicies(contactID)
 		}
 	}
 
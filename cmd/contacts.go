@@ -153,6 +153,8 @@ func handleUpdateContact(r *fastglue.Request) error {
 	if err := app.user.UpdateContact(id, contactToUpdate); err != nil {
 		return sendErrorEnvelope(r, err)
 	}
+	// SLA policy conditions can match on the contact's email and company.
+	app.conversation.EvaluateContactSLAPolicies(id)
 
 	// Delete avatar?
 	if avatarURL == "" && contact.AvatarURL.Valid {
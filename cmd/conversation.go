@@ -452,6 +452,59 @@ func handleGetConversationParticipants(r *fastglue.Request) error {
 	return r.SendEnvelope(p)
 }
 
+// handleGetConversationAutomationExecutions returns the automation rules that fired on a conversation.
+func handleGetConversationAutomationExecutions(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		uuid  = r.RequestCtx.UserValue("uuid").(string)
+		auser = r.RequestCtx.UserValue("user").(amodels.User)
+		total = 0
+	)
+	user, err := app.user.GetAgentCachedOrLoad(auser.ID)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	if _, err = enforceConversationAccess(app, uuid, user); err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	page, pageSize := getPagination(r)
+	executions, err := app.automation.GetConversationExecutions(uuid, page, pageSize)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	if len(executions) > 0 {
+		total = executions[0].Total
+	}
+	return r.SendEnvelope(envelope.PageResults{
+		Results:    executions,
+		Total:      total,
+		PerPage:    pageSize,
+		TotalPages: (total + pageSize - 1) / pageSize,
+		Page:       page,
+	})
+}
+
+// handleGetConversationAutomationScheduledActions returns the delayed automation actions of a conversation.
+func handleGetConversationAutomationScheduledActions(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		uuid  = r.RequestCtx.UserValue("uuid").(string)
+		auser = r.RequestCtx.UserValue("user").(amodels.User)
+	)
+	user, err := app.user.GetAgentCachedOrLoad(auser.ID)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	if _, err = enforceConversationAccess(app, uuid, user); err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	actions, err := app.automation.GetConversationScheduledActions(uuid)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(actions)
+}
+
 // handleUpdateUserAssignee updates the user assigned to a conversation.
 func handleUpdateUserAssignee(r *fastglue.Request) error {
 	var (
@@ -711,6 +764,9 @@ func handleUpdateContactCustomAttributes(r *fastglue.Request) error {
 	}
 	// Broadcast update.
 	app.conversation.BroadcastContactUpdate(conversation.ContactID, map[string]any{"custom_attributes": attributes})
+
+	// SLA policy conditions can match on contact attributes.
+	app.conversation.EvaluateContactSLAPolicies(conversation.ContactID)
 	return r.SendEnvelope(true)
 }
 
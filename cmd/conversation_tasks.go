@@ -0,0 +1,167 @@
+package main
+
+import (
+	"strconv"
+
+	amodels "github.com/abhinavxd/libredesk/internal/auth/models"
+	cmodels "github.com/abhinavxd/libredesk/internal/conversation/models"
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	umodels "github.com/abhinavxd/libredesk/internal/user/models"
+	"github.com/valyala/fasthttp"
+	"github.com/volatiletech/null/v9"
+	"github.com/zerodha/fastglue"
+)
+
+type taskReq struct {
+	Title      string    `json:"title"`
+	AssigneeID int       `json:"assignee_id"`
+	DueAt      null.Time `json:"due_at"`
+}
+
+type taskCompleteReq struct {
+	Completed bool `json:"completed"`
+}
+
+// handleGetConversationTasks returns the tasks of a conversation.
+func handleGetConversationTasks(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		auser = r.RequestCtx.UserValue("user").(amodels.User)
+		uuid  = r.RequestCtx.UserValue("uuid").(string)
+	)
+	user, err := app.user.GetAgentCachedOrLoad(auser.ID)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	conv, err := enforceConversationAccess(app, uuid, user)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	tasks, err := app.conversation.GetConversationTasks(conv.ID)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(tasks)
+}
+
+// handleCreateConversationTask adds a task to a conversation.
+func handleCreateConversationTask(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		auser = r.RequestCtx.UserValue("user").(amodels.User)
+		uuid  = r.RequestCtx.UserValue("uuid").(string)
+		req   = taskReq{}
+	)
+	if err := r.Decode(&req, "json"); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), err.Error(), envelope.InputError)
+	}
+	user, err := app.user.GetAgentCachedOrLoad(auser.ID)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	conv, err := enforceConversationAccess(app, uuid, user)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	task, err := app.conversation.CreateTask(conv.ID, conv.UUID, req.Title, req.AssigneeID, req.DueAt, user)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(task)
+}
+
+// handleUpdateConversationTask updates the title, assignee and due date of a conversation task.
+func handleUpdateConversationTask(r *fastglue.Request) error {
+	var (
+		app = r.Context.(*App)
+		req = taskReq{}
+	)
+	if err := r.Decode(&req, "json"); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), err.Error(), envelope.InputError)
+	}
+	task, user, err := enforceConversationTaskAccess(r)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	task, err = app.conversation.UpdateTask(task.ID, task.ConversationUUID, req.Title, req.AssigneeID, req.DueAt, user)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(task)
+}
+
+// handleCompleteConversationTask marks a conversation task as completed or reopens it.
+func handleCompleteConversationTask(r *fastglue.Request) error {
+	var (
+		app = r.Context.(*App)
+		req = taskCompleteReq{}
+	)
+	if err := r.Decode(&req, "json"); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), err.Error(), envelope.InputError)
+	}
+	task, user, err := enforceConversationTaskAccess(r)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	task, err = app.conversation.SetTaskCompleted(task.ID, req.Completed, user)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(task)
+}
+
+// handleDeleteConversationTask deletes a conversation task.
+func handleDeleteConversationTask(r *fastglue.Request) error {
+	var app = r.Context.(*App)
+	task, _, err := enforceConversationTaskAccess(r)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	if err := app.conversation.DeleteTask(task.ID); err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(true)
+}
+
+// handleGetMyTasks returns the tasks assigned to the current user.
+func handleGetMyTasks(r *fastglue.Request) error {
+	var (
+		app              = r.Context.(*App)
+		auser            = r.RequestCtx.UserValue("user").(amodels.User)
+		includeCompleted = r.RequestCtx.QueryArgs().GetBool("include_completed")
+	)
+	tasks, err := app.conversation.GetUserTasks(auser.ID, includeCompleted)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(tasks)
+}
+
+// enforceConversationTaskAccess fetches the task in the request path and checks that it belongs to the conversation
+// in the request path and that the current user can access that conversation.
+func enforceConversationTaskAccess(r *fastglue.Request) (cmodels.ConversationTask, umodels.User, error) {
+	var (
+		app   = r.Context.(*App)
+		auser = r.RequestCtx.UserValue("user").(amodels.User)
+		uuid  = r.RequestCtx.UserValue("uuid").(string)
+		id, _ = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+	)
+	if id <= 0 {
+		return cmodels.ConversationTask{}, umodels.User{}, envelope.NewError(envelope.InputError, app.i18n.T("validation.invalidValue"), nil)
+	}
+	user, err := app.user.GetAgentCachedOrLoad(auser.ID)
+	if err != nil {
+		return cmodels.ConversationTask{}, user, err
+	}
+	if _, err := enforceConversationAccess(app, uuid, user); err != nil {
+		return cmodels.ConversationTask{}, user, err
+	}
+	task, err := app.conversation.GetTask(id)
+	if err != nil {
+		return task, user, err
+	}
+	if task.ConversationUUID != uuid {
+		return task, user, envelope.NewError(envelope.NotFoundError, app.i18n.T("globals.messages.notFound"), nil)
+	}
+	return task, user, nil
+}
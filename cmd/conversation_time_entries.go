@@ -0,0 +1,182 @@
+package main
+
+import (
+	"strconv"
+	"time"
+
+	amodels "github.com/abhinavxd/libredesk/internal/auth/models"
+	cmodels "github.com/abhinavxd/libredesk/internal/conversation/models"
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+type timeEntryReq struct {
+	StartedAt       time.Time `json:"started_at"`
+	DurationSeconds int       `json:"duration_seconds"`
+	Note            string    `json:"note"`
+	Billable        bool      `json:"billable"`
+}
+
+// handleGetConversationTimeEntries returns the time entries logged on a conversation.
+func handleGetConversationTimeEntries(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		auser = r.RequestCtx.UserValue("user").(amodels.User)
+		uuid  = r.RequestCtx.UserValue("uuid").(string)
+	)
+	user, err := app.user.GetAgentCachedOrLoad(auser.ID)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	conv, err := enforceConversationAccess(app, uuid, user)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	entries, err := app.conversation.GetTimeEntries(conv.ID)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(entries)
+}
+
+// handleCreateConversationTimeEntry logs time spent by the current agent on a conversation.
+func handleCreateConversationTimeEntry(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		auser = r.RequestCtx.UserValue("user").(amodels.User)
+		uuid  = r.RequestCtx.UserValue("uuid").(string)
+		req   = timeEntryReq{}
+	)
+	if err := r.Decode(&req, "json"); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), err.Error(), envelope.InputError)
+	}
+	user, err := app.user.GetAgentCachedOrLoad(auser.ID)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	conv, err := enforceConversationAccess(app, uuid, user)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	entry, err := app.conversation.CreateTimeEntry(conv.ID, user.ID, req.StartedAt, req.DurationSeconds, req.Note, req.Billable)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(entry)
+}
+
+// handleUpdateConversationTimeEntry updates a time entry. Agents can only update their own entries unless they are admins.
+func handleUpdateConversationTimeEntry(r *fastglue.Request) error {
+	var (
+		app = r.Context.(*App)
+		req = timeEntryReq{}
+	)
+	if err := r.Decode(&req, "json"); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), err.Error(), envelope.InputError)
+	}
+	entry, err := enforceTimeEntryAccess(r)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	entry, err = app.conversation.UpdateTimeEntry(entry.ID, req.StartedAt, req.DurationSeconds, req.Note, req.Billable)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(entry)
+}
+
+// handleDeleteConversationTimeEntry deletes a time entry. Agents can only delete their own entries unless they are admins.
+func handleDeleteConversationTimeEntry(r *fastglue.Request) error {
+	var app = r.Context.(*App)
+	entry, err := enforceTimeEntryAccess(r)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	if err := app.conversation.DeleteTimeEntry(entry.ID); err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(true)
+}
+
+// handleStartConversationTimer starts a timer for the current agent on a conversation.
+func handleStartConversationTimer(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		auser = r.RequestCtx.UserValue("user").(amodels.User)
+		uuid  = r.RequestCtx.UserValue("uuid").(string)
+		req   = timeEntryReq{}
+	)
+	if err := r.Decode(&req, "json"); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), err.Error(), envelope.InputError)
+	}
+	user, err := app.user.GetAgentCachedOrLoad(auser.ID)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	conv, err := enforceConversationAccess(app, uuid, user)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	entry, err := app.conversation.StartTimer(conv.ID, user.ID, req.Note, req.Billable)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(entry)
+}
+
+// handleStopConversationTimer stops the running timer of the current agent on a conversation.
+func handleStopConversationTimer(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		auser = r.RequestCtx.UserValue("user").(amodels.User)
+		uuid  = r.RequestCtx.UserValue("uuid").(string)
+	)
+	user, err := app.user.GetAgentCachedOrLoad(auser.ID)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	conv, err := enforceConversationAccess(app, uuid, user)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	entry, err := app.conversation.StopTimer(conv.ID, user.ID)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(entry)
+}
+
+// enforceTimeEntryAccess fetches the time entry in the request path and checks that it belongs to the conversation
+// in the request path, that the current user can access that conversation and that the entry is theirs, unless
+// they are an admin.
+func enforceTimeEntryAccess(r *fastglue.Request) (cmodels.TimeEntry, error) {
+	var (
+		app   = r.Context.(*App)
+		auser = r.RequestCtx.UserValue("user").(amodels.User)
+		uuid  = r.RequestCtx.UserValue("uuid").(string)
+		id, _ = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+	)
+	if id <= 0 {
+		return cmodels.TimeEntry{}, envelope.NewError(envelope.InputError, app.i18n.T("validation.invalidValue"), nil)
+	}
+	user, err := app.user.GetAgentCachedOrLoad(auser.ID)
+	if err != nil {
+		return cmodels.TimeEntry{}, err
+	}
+	conv, err := enforceConversationAccess(app, uuid, user)
+	if err != nil {
+		return cmodels.TimeEntry{}, err
+	}
+	entry, err := app.conversation.GetTimeEntry(id)
+	if err != nil {
+		return entry, err
+	}
+	if entry.ConversationID != conv.ID {
+		return entry, envelope.NewError(envelope.NotFoundError, app.i18n.T("globals.messages.notFound"), nil)
+	}
+	if !user.HasAdminRole() && entry.UserID.Int != user.ID {
+		return entry, envelope.NewError(envelope.PermissionError, app.i18n.T("conversation.canOnlyEditOwnTimeEntry"), nil)
+	}
+	return entry, nil
+}
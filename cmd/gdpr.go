@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	amodels "github.com/abhinavxd/libredesk/internal/auth/models"
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	"github.com/abhinavxd/libredesk/internal/gdpr/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+// handleGetContactDataRequests returns the data export and erasure requests raised for a contact.
+func handleGetContactDataRequests(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		id, _ = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+	)
+	if id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("validation.invalidValue"), nil, envelope.InputError)
+	}
+	requests, err := app.gdpr.GetRequests(id)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(requests)
+}
+
+// handleExportContactData queues an export of all data held about a contact.
+func handleExportContactData(r *fastglue.Request) error {
+	return createContactDataRequest(r, models.RequestTypeExport)
+}
+
+// handleEraseContactData queues an erasure of a contact's personal data.
+func handleEraseContactData(r *fastglue.Request) error {
+	return createContactDataRequest(r, models.RequestTypeErasure)
+}
+
+// createContactDataRequest queues a data request of the given type for the contact in the request path.
+func createContactDataRequest(r *fastglue.Request, typ string) error {
+	var (
+		app   = r.Context.(*App)
+		auser = r.RequestCtx.UserValue("user").(amodels.User)
+		id, _ = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+	)
+	if id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("validation.invalidValue"), nil, envelope.InputError)
+	}
+	app.lo.Info("queuing contact data request", "contact_id", id, "type", typ, "actor_id", auser.ID)
+	request, err := app.gdpr.CreateRequest(id, auser.ID, typ)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(request)
+}
+
+// handleDownloadContactDataExport serves the archive of a completed contact data export.
+func handleDownloadContactDataExport(r *fastglue.Request) error {
+	var (
+		app          = r.Context.(*App)
+		id, _        = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+		requestID, _ = strconv.Atoi(r.RequestCtx.UserValue("request_id").(string))
+	)
+	if id <= 0 || requestID <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("validation.invalidValue"), nil, envelope.InputError)
+	}
+	filename, archive, err := app.gdpr.GetExportArchive(id, requestID)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	r.RequestCtx.Response.Header.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	r.RequestCtx.Response.Header.Set("X-Content-Type-Options", "nosniff")
+	r.RequestCtx.SetContentType("application/zip")
+	r.RequestCtx.SetBody(archive)
+	return nil
+}
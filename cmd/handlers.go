@@ -66,6 +66,8 @@ func initHandlers(g *fastglue.Fastglue, hub *ws.Hub) {
 	g.PUT("/api/v1/conversations/{uuid}/last-seen", perm(handleUpdateConversationAssigneeLastSeen, "conversations:read"))
 	g.PUT("/api/v1/conversations/{uuid}/mark-unread", perm(handleMarkConversationAsUnread, "conversations:read"))
 	g.POST("/api/v1/conversations/{uuid}/tags", perm(handleUpdateConversationtags, "conversations:update_tags"))
+	g.GET("/api/v1/conversations/{uuid}/skills", perm(handleGetConversationSkills, "conversations:read"))
+	g.PUT("/api/v1/conversations/{uuid}/skills", perm(handleUpdateConversationSkills, "conversations:write"))
 	g.GET("/api/v1/conversations/{uuid}/page-visits", perm(handleGetContactPageVisits, "conversations:read"))
 	g.GET("/api/v1/conversations/{cuuid}/messages/{uuid}", perm(handleGetMessage, "messages:read"))
 	g.GET("/api/v1/conversations/{uuid}/messages", perm(handleGetMessages, "messages:read"))
@@ -80,6 +82,22 @@ func initHandlers(g *fastglue.Fastglue, hub *ws.Hub) {
 	g.GET("/api/v1/drafts", auth(handleGetAllDrafts))
 	g.POST("/api/v1/conversations/{uuid}/draft", auth(handleUpsertConversationDraft))
 	g.DELETE("/api/v1/conversations/{uuid}/draft", auth(handleDeleteConversationDraft))
+	// Task endpoints
+	g.GET("/api/v1/tasks/me", auth(handleGetMyTasks))
+	g.GET("/api/v1/conversations/{uuid}/tasks", perm(handleGetConversationTasks, "conversations:read"))
+	g.POST("/api/v1/conversations/{uuid}/tasks", perm(handleCreateConversationTask, "conversations:write"))
+	g.PUT("/api/v1/conversations/{uuid}/tasks/{id}", perm(handleUpdateConversationTask, "conversations:write"))
+	g.PUT("/api/v1/conversations/{uuid}/tasks/{id}/complete", perm(handleCompleteConversationTask, "conversations:write"))
+	g.DELETE("/api/v1/conversations/{uuid}/tasks/{id}", perm(handleDeleteConversationTask, "conversations:write"))
+	g.GET("/api/v1/conversations/{uuid}/automation-executions", perm(handleGetConversationAutomationExecutions, "conversations:read"))
+	g.GET("/api/v1/conversations/{uuid}/automation-scheduled-actions", perm(handleGetConversationAutomationScheduledActions, "conversations:read"))
+	// Time tracking endpoints
+	g.GET("/api/v1/conversations/{uuid}/time-entries", perm(handleGetConversationTimeEntries, "conversations:read"))
+	g.POST("/api/v1/conversations/{uuid}/time-entries", perm(handleCreateConversationTimeEntry, "conversations:write"))
+	g.PUT("/api/v1/conversations/{uuid}/time-entries/{id}", perm(handleUpdateConversationTimeEntry, "conversations:write"))
+	g.DELETE("/api/v1/conversations/{uuid}/time-entries/{id}", perm(handleDeleteConversationTimeEntry, "conversations:write"))
+	g.POST("/api/v1/conversations/{uuid}/timer/start", perm(handleStartConversationTimer, "conversations:write"))
+	g.POST("/api/v1/conversations/{uuid}/timer/stop", perm(handleStopConversationTimer, "conversations:write"))
 
 	// Search.
 	g.GET("/api/v1/conversations/search", perm(handleSearchConversations, "conversations:read"))
@@ -107,6 +125,13 @@ func initHandlers(g *fastglue.Fastglue, hub *ws.Hub) {
 	g.DELETE("/api/v1/statuses/{id}", perm(handleDeleteStatus, "status:manage"))
 	g.GET("/api/v1/priorities", auth(handleGetPriorities))
 
+	// Resolution requirements.
+	g.GET("/api/v1/resolution-requirements", perm(handleGetResolutionRequirements, "status:manage"))
+	g.GET("/api/v1/resolution-requirements/{id}", perm(handleGetResolutionRequirement, "status:manage"))
+	g.POST("/api/v1/resolution-requirements", perm(handleCreateResolutionRequirement, "status:manage"))
+	g.PUT("/api/v1/resolution-requirements/{id}", perm(handleUpdateResolutionRequirement, "status:manage"))
+	g.DELETE("/api/v1/resolution-requirements/{id}", perm(handleDeleteResolutionRequirement, "status:manage"))
+
 	// Tags.
 	g.GET("/api/v1/tags", auth(handleGetTags))
 	g.POST("/api/v1/tags", perm(handleCreateTag, "tags:manage"))
@@ -115,6 +140,12 @@ func initHandlers(g *fastglue.Fastglue, hub *ws.Hub) {
 	g.POST("/api/v1/tags/import", perm(handleImportTags, "tags:manage"))
 	g.GET("/api/v1/tags/import/status", perm(handleGetTagImportStatus, "tags:manage"))
 
+	// Skills.
+	g.GET("/api/v1/skills", auth(handleGetSkills))
+	g.POST("/api/v1/skills", perm(handleCreateSkill, "teams:manage"))
+	g.PUT("/api/v1/skills/{id}", perm(handleUpdateSkill, "teams:manage"))
+	g.DELETE("/api/v1/skills/{id}", perm(handleDeleteSkill, "teams:manage"))
+
 	// Macros.
 	g.GET("/api/v1/macros", auth(handleGetMacros))
 	g.GET("/api/v1/macros/{id}", perm(handleGetMacro, "macros:manage"))
@@ -140,6 +171,13 @@ func initHandlers(g *fastglue.Fastglue, hub *ws.Hub) {
 	g.GET("/api/v1/agents/import/status", perm(handleGetAgentImportStatus, "users:manage"))
 	g.POST("/api/v1/agents/{id}/api-key", perm(handleGenerateAPIKey, "users:manage"))
 	g.DELETE("/api/v1/agents/{id}/api-key", perm(handleRevokeAPIKey, "users:manage"))
+	g.GET("/api/v1/agents/{id}/skills", perm(handleGetAgentSkills, "users:manage"))
+	g.PUT("/api/v1/agents/{id}/skills", perm(handleUpdateAgentSkills, "users:manage"))
+	g.GET("/api/v1/agents/{id}/capacity", perm(handleGetAgentCapacity, "users:manage"))
+	g.PUT("/api/v1/agents/{id}/capacity", perm(handleUpdateAgentCapacity, "users:manage"))
+	g.GET("/api/v1/agents/{id}/schedule", perm(handleGetAgentSchedule, "users:manage"))
+	g.PUT("/api/v1/agents/{id}/schedule", perm(handleUpdateAgentSchedule, "users:manage"))
+	g.DELETE("/api/v1/agents/{id}/schedule", perm(handleDeleteAgentSchedule, "users:manage"))
 	g.POST("/api/v1/agents/reset-password", rateLimit(tryAuth(handleResetPassword), "auth"))
 	g.POST("/api/v1/agents/set-password", rateLimit(tryAuth(handleSetPassword), "auth"))
 
@@ -149,6 +187,12 @@ func initHandlers(g *fastglue.Fastglue, hub *ws.Hub) {
 	g.PUT("/api/v1/contacts/{id}", perm(handleUpdateContact, "contacts:write"))
 	g.PUT("/api/v1/contacts/{id}/block", perm(handleBlockContact, "contacts:block"))
 
+	// Contact data export and erasure.
+	g.GET("/api/v1/contacts/{id}/data-requests", perm(handleGetContactDataRequests, "contacts:read"))
+	g.POST("/api/v1/contacts/{id}/data-requests/export", perm(handleExportContactData, "contacts:export"))
+	g.POST("/api/v1/contacts/{id}/data-requests/erase", perm(handleEraseContactData, "contacts:erase"))
+	g.GET("/api/v1/contacts/{id}/data-requests/{request_id}/download", perm(handleDownloadContactDataExport, "contacts:export"))
+
 	// Contact notes.
 	g.GET("/api/v1/contacts/{id}/notes", perm(handleGetContactNotes, "contact_notes:read"))
 	g.POST("/api/v1/contacts/{id}/notes", perm(handleCreateContactNote, "contact_notes:write"))
@@ -165,12 +209,23 @@ func initHandlers(g *fastglue.Fastglue, hub *ws.Hub) {
 	// Automations.
 	g.GET("/api/v1/automations/rules", perm(handleGetAutomationRules, "automations:manage"))
 	g.GET("/api/v1/automations/rules/{id}", perm(handleGetAutomationRule, "automations:manage"))
+	g.GET("/api/v1/automations/rules/export", perm(handleExportAutomationRules, "automations:manage"))
+	g.GET("/api/v1/automations/rules/{id}/executions", perm(handleGetAutomationRuleExecutions, "automations:manage"))
+	g.GET("/api/v1/automations/rules/{id}/versions", perm(handleGetAutomationRuleVersions, "automations:manage"))
 	g.POST("/api/v1/automations/rules", perm(handleCreateAutomationRule, "automations:manage"))
+	g.POST("/api/v1/automations/rules/simulate", perm(handleSimulateAutomationRule, "automations:manage"))
+	g.POST("/api/v1/automations/rules/import", perm(handleImportAutomationRules, "automations:manage"))
+	g.POST("/api/v1/automations/rules/{id}/rollback", perm(handleRollbackAutomationRule, "automations:manage"))
 	g.PUT("/api/v1/automations/rules/{id}/toggle", perm(handleToggleAutomationRule, "automations:manage"))
 	g.PUT("/api/v1/automations/rules/{id}", perm(handleUpdateAutomationRule, "automations:manage"))
 	g.PUT("/api/v1/automations/rules/weights", perm(handleUpdateAutomationRuleWeights, "automations:manage"))
 	g.PUT("/api/v1/automations/rules/execution-mode", perm(handleUpdateAutomationRuleExecutionMode, "automations:manage"))
 	g.DELETE("/api/v1/automations/rules/{id}", perm(handleDeleteAutomationRule, "automations:manage"))
+	g.GET("/api/v1/automations/schedules", perm(handleGetAutomationSchedules, "automations:manage"))
+	g.GET("/api/v1/automations/schedules/{id}", perm(handleGetAutomationSchedule, "automations:manage"))
+	g.POST("/api/v1/automations/schedules", perm(handleCreateAutomationSchedule, "automations:manage"))
+	g.PUT("/api/v1/automations/schedules/{id}", perm(handleUpdateAutomationSchedule, "automations:manage"))
+	g.DELETE("/api/v1/automations/schedules/{id}", perm(handleDeleteAutomationSchedule, "automations:manage"))
 
 	// Inboxes.
 	g.GET("/api/v1/inboxes", auth(handleGetInboxes))
@@ -180,6 +235,13 @@ func initHandlers(g *fastglue.Fastglue, hub *ws.Hub) {
 	g.PUT("/api/v1/inboxes/{id}", perm(handleUpdateInbox, "inboxes:manage"))
 	g.DELETE("/api/v1/inboxes/{id}", perm(handleDeleteInbox, "inboxes:manage"))
 
+	// Data retention policies.
+	g.GET("/api/v1/retention-policies", perm(handleGetRetentionPolicies, "inboxes:manage"))
+	g.GET("/api/v1/inboxes/{id}/retention-policy", perm(handleGetRetentionPolicy, "inboxes:manage"))
+	g.PUT("/api/v1/inboxes/{id}/retention-policy", perm(handleUpdateRetentionPolicy, "inboxes:manage"))
+	g.DELETE("/api/v1/inboxes/{id}/retention-policy", perm(handleDeleteRetentionPolicy, "inboxes:manage"))
+	g.GET("/api/v1/inboxes/{id}/retention-policy/dry-run", perm(handleRetentionPolicyDryRun, "inboxes:manage"))
+
 	// OAuth endpoints for email inboxes.
 	g.POST("/api/v1/inboxes/oauth/{provider}/authorize", perm(handleOAuthAuthorize, "inboxes:manage"))
 	g.GET("/api/v1/inboxes/oauth/{provider}/callback", perm(handleOAuthCallback, "inboxes:manage"))
@@ -218,6 +280,8 @@ func initHandlers(g *fastglue.Fastglue, hub *ws.Hub) {
 	g.GET("/api/v1/reports/overview/csat", perm(handleOverviewCSAT, "reports:manage"))
 	g.GET("/api/v1/reports/overview/messages", perm(handleOverviewMessageVolume, "reports:manage"))
 	g.GET("/api/v1/reports/overview/tags", perm(handleOverviewTagDistribution, "reports:manage"))
+	g.GET("/api/v1/reports/time", perm(handleTimeReport, "reports:manage"))
+	g.GET("/api/v1/reports/sla", perm(handleSLAReport, "reports:manage"))
 
 	// Templates.
 	g.GET("/api/v1/templates", perm(handleGetTemplates, "templates:manage"))
@@ -232,6 +296,7 @@ func initHandlers(g *fastglue.Fastglue, hub *ws.Hub) {
 	g.POST("/api/v1/business-hours", perm(handleCreateBusinessHours, "business_hours:manage"))
 	g.PUT("/api/v1/business-hours/{id}", perm(handleUpdateBusinessHours, "business_hours:manage"))
 	g.DELETE("/api/v1/business-hours/{id}", perm(handleDeleteBusinessHour, "business_hours:manage"))
+	g.POST("/api/v1/business-hours/{id}/holidays/import", perm(handleImportHolidays, "business_hours:manage"))
 
 	// SLAs.
 	g.GET("/api/v1/sla", auth(handleGetSLAs))
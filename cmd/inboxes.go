@@ -340,6 +340,13 @@ func validateInbox(app *App, inbox imodels.Inbox) error {
 			return err
 		}
 	}
+
+	// Validate business hours if specified.
+	if inbox.BusinessHoursID.Valid {
+		if _, err := app.businessHours.Get(inbox.BusinessHoursID.Int); err != nil {
+			return envelope.NewError(envelope.InputError, app.i18n.Ts("validation.invalidValue", "name", "`business_hours_id`"), nil)
+		}
+	}
 	return nil
 }
 
@@ -25,9 +25,11 @@ import (
 	contextlink "github.com/abhinavxd/libredesk/internal/context_link"
 	"github.com/abhinavxd/libredesk/internal/conversation"
 	"github.com/abhinavxd/libredesk/internal/conversation/priority"
+	"github.com/abhinavxd/libredesk/internal/conversation/resolution"
 	"github.com/abhinavxd/libredesk/internal/conversation/status"
 	"github.com/abhinavxd/libredesk/internal/csat"
 	customAttribute "github.com/abhinavxd/libredesk/internal/custom_attribute"
+	"github.com/abhinavxd/libredesk/internal/gdpr"
 	"github.com/abhinavxd/libredesk/internal/importer"
 	"github.com/abhinavxd/libredesk/internal/inbox"
 	"github.com/abhinavxd/libredesk/internal/inbox/channel/email"
@@ -42,9 +44,12 @@ import (
 	"github.com/abhinavxd/libredesk/internal/oidc"
 	"github.com/abhinavxd/libredesk/internal/ratelimit"
 	"github.com/abhinavxd/libredesk/internal/report"
+	"github.com/abhinavxd/libredesk/internal/retention"
 	"github.com/abhinavxd/libredesk/internal/role"
 	"github.com/abhinavxd/libredesk/internal/search"
 	"github.com/abhinavxd/libredesk/internal/setting"
+	"github.com/abhinavxd/libredesk/internal/shift"
+	"github.com/abhinavxd/libredesk/internal/skill"
 	"github.com/abhinavxd/libredesk/internal/sla"
 	"github.com/abhinavxd/libredesk/internal/ssrf"
 	"github.com/abhinavxd/libredesk/internal/tag"
@@ -286,6 +291,8 @@ func initConversations(
 	sla *sla.Manager,
 	status *status.Manager,
 	priority *priority.Manager,
+	resolution *resolution.Manager,
+	customAttribute *customAttribute.Manager,
 	hub *ws.Hub,
 	db *sqlx.DB,
 	inboxStore *inbox.Manager,
@@ -304,7 +311,7 @@ func initConversations(
 		continuityConfig.BatchCheckInterval = ko.MustDuration("conversation.continuity_scan_interval")
 	}
 
-	c, err := conversation.New(hub, i18n, sla, status, priority, inboxStore, userStore, teamStore, mediaStore, settings, csat, automationEngine, template, webhook, dispatcher, conversation.Opts{
+	c, err := conversation.New(hub, i18n, sla, status, priority, resolution, customAttribute, inboxStore, userStore, teamStore, mediaStore, settings, csat, automationEngine, template, webhook, dispatcher, conversation.Opts{
 		DB:                       db,
 		Lo:                       initLogger("conversation_manager"),
 		OutgoingMessageQueueSize: ko.MustInt("message.outgoing_queue_size"),
@@ -332,6 +339,34 @@ func initTag(db *sqlx.DB, i18n *i18n.I18n) *tag.Manager {
 	return mgr
 }
 
+// initSkill inits skill manager.
+func initSkill(db *sqlx.DB, i18n *i18n.I18n) *skill.Manager {
+	var lo = initLogger("skill_manager")
+	mgr, err := skill.New(skill.Opts{
+		DB:   db,
+		Lo:   lo,
+		I18n: i18n,
+	})
+	if err != nil {
+		log.Fatalf("error initializing skills: %v", err)
+	}
+	return mgr
+}
+
+// initShift inits agent shift schedule manager.
+func initShift(db *sqlx.DB, i18n *i18n.I18n) *shift.Manager {
+	var lo = initLogger("shift_manager")
+	mgr, err := shift.New(shift.Opts{
+		DB:   db,
+		Lo:   lo,
+		I18n: i18n,
+	})
+	if err != nil {
+		log.Fatalf("error initializing shift manager: %v", err)
+	}
+	return mgr
+}
+
 // initViews inits view manager.
 func initView(db *sqlx.DB, i18n *i18n.I18n) *view.Manager {
 	var lo = initLogger("view_manager")
@@ -361,12 +396,13 @@ func initMacro(db *sqlx.DB, i18n *i18n.I18n) *macro.Manager {
 }
 
 // initBusinessHours inits business hours manager.
-func initBusinessHours(db *sqlx.DB, i18n *i18n.I18n) *businesshours.Manager {
+func initBusinessHours(db *sqlx.DB, i18n *i18n.I18n, dialControl ssrf.Control) *businesshours.Manager {
 	var lo = initLogger("business-hours")
 	m, err := businesshours.New(businesshours.Opts{
-		DB:   db,
-		Lo:   lo,
-		I18n: i18n,
+		DB:          db,
+		Lo:          lo,
+		I18n:        i18n,
+		DialControl: dialControl,
 	})
 	if err != nil {
 		log.Fatalf("error initializing business hours manager: %v", err)
@@ -606,9 +642,10 @@ func initInbox(db *sqlx.DB, i18n *i18n.I18n) *inbox.Manager {
 func initAutomationEngine(db *sqlx.DB, i18n *i18n.I18n) *automation.Engine {
 	var lo = initLogger("automation_engine")
 	engine, err := automation.New(automation.Opts{
-		DB:   db,
-		Lo:   lo,
-		I18n: i18n,
+		DB:                    db,
+		Lo:                    lo,
+		I18n:                  i18n,
+		ExecutionLogRetention: cmp.Or(ko.Duration("automation.execution_log_retention"), automation.DefaultExecutionLogRetention),
 	})
 	if err != nil {
 		log.Fatalf("error initializing automation engine: %v", err)
@@ -965,6 +1002,19 @@ func initPriority(db *sqlx.DB, i18n *i18n.I18n) *priority.Manager {
 	return manager
 }
 
+// initResolution inits resolution requirements manager.
+func initResolution(db *sqlx.DB, i18n *i18n.I18n) *resolution.Manager {
+	manager, err := resolution.New(resolution.Opts{
+		DB:   db,
+		Lo:   initLogger("resolution-manager"),
+		I18n: i18n,
+	})
+	if err != nil {
+		log.Fatalf("error initializing resolution manager: %v", err)
+	}
+	return manager
+}
+
 // initAI inits AI manager.
 func initAI(ctx context.Context, db *sqlx.DB, i18n *i18n.I18n, dialControl ssrf.Control) *ai.Manager {
 	lo := initLogger("ai")
@@ -1040,6 +1090,35 @@ func initActivityLog(db *sqlx.DB, i18n *i18n.I18n) *activitylog.Manager {
 	return m
 }
 
+// initRetention inits data retention manager.
+func initRetention(db *sqlx.DB, i18n *i18n.I18n, mediaStore *media.Manager, activityLog *activitylog.Manager, systemUserID int) *retention.Manager {
+	lo := initLogger("retention")
+	m, err := retention.New(mediaStore, activityLog, retention.Opts{
+		DB:           db,
+		Lo:           lo,
+		I18n:         i18n,
+		SystemUserID: systemUserID,
+	})
+	if err != nil {
+		log.Fatalf("error initializing retention manager: %v", err)
+	}
+	return m
+}
+
+// initGDPR inits contact data export and erasure manager.
+func initGDPR(db *sqlx.DB, i18n *i18n.I18n, userStore *user.Manager, mediaStore *media.Manager, retention *retention.Manager, rdb *redis.Client, activityLog *activitylog.Manager) *gdpr.Manager {
+	lo := initLogger("gdpr")
+	m, err := gdpr.New(userStore, mediaStore, retention, pageVisitStore{rdb: rdb}, activityLog, gdpr.Opts{
+		DB:   db,
+		Lo:   lo,
+		I18n: i18n,
+	})
+	if err != nil {
+		log.Fatalf("error initializing gdpr manager: %v", err)
+	}
+	return m
+}
+
 // initReport inits report manager.
 func initReport(db *sqlx.DB, i18n *i18n.I18n) *report.Manager {
 	lo := initLogger("report")
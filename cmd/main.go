@@ -23,10 +23,14 @@ import (
 	"github.com/abhinavxd/libredesk/internal/colorlog"
 	"github.com/abhinavxd/libredesk/internal/csat"
 	customAttribute "github.com/abhinavxd/libredesk/internal/custom_attribute"
+	"github.com/abhinavxd/libredesk/internal/gdpr"
 	"github.com/abhinavxd/libredesk/internal/macro"
 	notifier "github.com/abhinavxd/libredesk/internal/notification"
 	"github.com/abhinavxd/libredesk/internal/report"
+	"github.com/abhinavxd/libredesk/internal/retention"
 	"github.com/abhinavxd/libredesk/internal/search"
+	"github.com/abhinavxd/libredesk/internal/shift"
+	"github.com/abhinavxd/libredesk/internal/skill"
 	"github.com/abhinavxd/libredesk/internal/sla"
 	umodels "github.com/abhinavxd/libredesk/internal/user/models"
 	"github.com/abhinavxd/libredesk/internal/view"
@@ -36,6 +40,7 @@ import (
 	contextlink "github.com/abhinavxd/libredesk/internal/context_link"
 	"github.com/abhinavxd/libredesk/internal/conversation"
 	"github.com/abhinavxd/libredesk/internal/conversation/priority"
+	"github.com/abhinavxd/libredesk/internal/conversation/resolution"
 	"github.com/abhinavxd/libredesk/internal/conversation/status"
 	"github.com/abhinavxd/libredesk/internal/importer"
 	"github.com/abhinavxd/libredesk/internal/inbox"
@@ -91,7 +96,9 @@ type App struct {
 	team             *team.Manager
 	status           *status.Manager
 	priority         *priority.Manager
+	resolution       *resolution.Manager
 	tag              *tag.Manager
+	skill            *skill.Manager
 	inbox            *inbox.Manager
 	tmpl             *template.Manager
 	macro            *macro.Manager
@@ -109,6 +116,9 @@ type App struct {
 	userNotification *notifier.UserNotificationManager
 	customAttribute  *customAttribute.Manager
 	report           *report.Manager
+	retention        *retention.Manager
+	shift            *shift.Manager
+	gdpr             *gdpr.Manager
 	webhook          *webhook.Manager
 	contextLink      *contextlink.Manager
 	rateLimit        *ratelimit.Limiter
@@ -207,6 +217,11 @@ func main() {
 		messageIncomingQWorkers     = ko.MustDuration("message.incoming_queue_workers")
 		messageOutgoingScanInterval = ko.MustDuration(msgOutgoingScanIntervalKey)
 		slaEvaluationInterval       = ko.MustDuration("sla.evaluation_interval")
+		retentionInterval           = cmp.Or(ko.Duration("retention.interval"), time.Hour)
+		gdprInterval                = cmp.Or(ko.Duration("gdpr.interval"), time.Minute)
+		taskReminderInterval        = cmp.Or(ko.Duration("conversation.task_reminder_interval"), time.Minute)
+		shiftCheckInterval          = cmp.Or(ko.Duration("autoassigner.shift_check_interval"), time.Minute)
+		holidayCalendarInterval     = cmp.Or(ko.Duration("business_hours.holiday_calendar_refresh_interval"), 24*time.Hour)
 		lo                          = initLogger(appName)
 		rdb                         = initRedis()
 		constants                   = initConstants()
@@ -215,13 +230,15 @@ func main() {
 		oidc                        = initOIDC(db, settings, i18n)
 		status                      = initStatus(db, i18n)
 		priority                    = initPriority(db, i18n)
+		resolution                  = initResolution(db, i18n)
+		customAttribute             = initCustomAttribute(db, i18n)
 		ssrfControl                 = initSSRFControl()
 		auth                        = initAuth(oidc, rdb, i18n, ssrfControl)
 		template                    = initTemplate(db, fs, constants, i18n)
 		media                       = initMedia(db, i18n, settings)
 		inbox                       = initInbox(db, i18n)
 		team                        = initTeam(db, i18n)
-		businessHours               = initBusinessHours(db, i18n)
+		businessHours               = initBusinessHours(db, i18n, ssrfControl)
 		webhook                     = initWebhook(db, i18n, ssrfControl)
 		user                        = initUser(i18n, db)
 		wsHub                       = initWS(user)
@@ -231,20 +248,28 @@ func main() {
 		automation                  = initAutomationEngine(db, i18n)
 		ai                          = initAI(ctx, db, i18n, ssrfControl)
 		sla                         = initSLA(db, team, settings, businessHours, template, user, i18n, notifDispatcher)
-		conversation                = initConversations(i18n, sla, status, priority, wsHub, db, inbox, user, team, media, settings, csat, automation, template, webhook, notifDispatcher)
+		conversation                = initConversations(i18n, sla, status, priority, resolution, customAttribute, wsHub, db, inbox, user, team, media, settings, csat, automation, template, webhook, notifDispatcher)
 		aiAgent                     = initAIAgent(db, i18n, ai, conversation, media, settings, user, notifier, rdb)
 		autoassigner                = initAutoAssigner(team, user, conversation)
+		shift                       = initShift(db, i18n)
 		rateLimiter                 = initRateLimit(rdb)
 	)
 
 	wsHub.SetConversationStore(conversation)
 	automation.SetConversationStore(conversation)
+	automation.SetBusinessHoursStore(sla)
+	sla.SetConversationStore(conversation)
+	automation.SetSLAStore(sla)
 	systemUser, err := user.GetSystemUser()
 	if err != nil {
 		log.Fatalf("error fetching system user: %v", err)
 	}
 	automation.SetSystemUserID(systemUser.ID)
 	conversation.SetAIAgent(aiAgent)
+	conversation.SetAITriager(ai)
+	activityLog := initActivityLog(db, i18n)
+	retention := initRetention(db, i18n, media, activityLog, systemUser.ID)
+	gdpr := initGDPR(db, i18n, user, media, retention, rdb, activityLog)
 
 	startInboxes(ctx, inbox, conversation, user, conversation.SignAvatarURL)
 
@@ -260,9 +285,14 @@ func main() {
 	go media.DeleteUnlinkedMedia(ctx)
 	go user.MonitorUserAvailability(ctx, onUsersOffline(conversation))
 	go conversation.RunDraftCleaner(ctx, draftRetentionDuration)
+	go conversation.RunTaskReminder(ctx, taskReminderInterval)
 	go userNotification.RunNotificationCleaner(ctx)
 	go aiAgent.Run(ctx, cmp.Or(ko.Int("ai_agent.worker_count"), 10))
 	go ai.Run(ctx)
+	go retention.Run(ctx, retentionInterval)
+	go gdpr.Run(ctx, gdprInterval)
+	go shift.Run(ctx, shiftCheckInterval, onShiftAvailability(user, conversation, activityLog, systemUser))
+	go businessHours.RunCalendarRefresh(ctx, holidayCalendarInterval)
 
 	var app = &App{
 		ctx:              ctx,
@@ -280,20 +310,25 @@ func main() {
 		csat:             csat,
 		status:           status,
 		priority:         priority,
+		resolution:       resolution,
 		tmpl:             template,
 		notifier:         notifier,
 		consts:           atomic.Value{},
 		conversation:     conversation,
 		automation:       automation,
 		businessHours:    businessHours,
-		activityLog:      initActivityLog(db, i18n),
-		customAttribute:  initCustomAttribute(db, i18n),
+		activityLog:      activityLog,
+		retention:        retention,
+		shift:            shift,
+		gdpr:             gdpr,
+		customAttribute:  customAttribute,
 		authz:            initAuthz(i18n),
 		view:             initView(db, i18n),
 		report:           initReport(db, i18n),
 		search:           initSearch(db, i18n),
 		role:             initRole(db, i18n),
 		tag:              initTag(db, i18n),
+		skill:            initSkill(db, i18n),
 		macro:            initMacro(db, i18n),
 		ai:               ai,
 		aiAgent:          aiAgent,
@@ -366,6 +401,22 @@ func main() {
 	colorlog.Green("Shutdown complete.")
 }
 
+// onShiftAvailability returns a callback for the shift manager that refreshes, broadcasts and records
+// the availability of agents whose shift started or ended, with the system user as the actor.
+func onShiftAvailability(user *user.Manager, conv *conversation.Manager, activityLog *activitylog.Manager, systemUser umodels.User) func(int, string) {
+	return func(userID int, status string) {
+		user.InvalidateAgentCache(userID)
+		conv.BroadcastAgentAvailability(userID, status)
+
+		// Errors are logged by the managers.
+		agent, err := user.GetAgent(userID, "")
+		if err != nil {
+			return
+		}
+		activityLog.UserAvailability(systemUser.ID, systemUser.Email.String, status, "", agent.Email.String, agent.ID)
+	}
+}
+
 // onUsersOffline returns a callback for MonitorUserAvailability that broadcasts
 // offline status to the appropriate clients based on user type.
 func onUsersOffline(conv *conversation.Manager) func([]umodels.OfflineUser) {
@@ -1,8 +1,16 @@
 package main
 
 import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
 	"strconv"
+	"time"
 
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	rmodels "github.com/abhinavxd/libredesk/internal/report/models"
+	"github.com/valyala/fasthttp"
+	"github.com/volatiletech/null/v9"
 	"github.com/zerodha/fastglue"
 )
 
@@ -82,3 +90,112 @@ func handleOverviewTagDistribution(r *fastglue.Request) error {
 	}
 	return r.SendEnvelope(tags)
 }
+
+// handleTimeReport retrieves time logged on conversations over a date range, as JSON or as a CSV download.
+// `from` and `to` are inclusive dates in YYYY-MM-DD format in `timezone`, which defaults to the app timezone.
+func handleTimeReport(r *fastglue.Request) error {
+	var (
+		app      = r.Context.(*App)
+		groupBy  = string(r.RequestCtx.QueryArgs().Peek("group_by"))
+		format   = string(r.RequestCtx.QueryArgs().Peek("format"))
+		timezone = string(r.RequestCtx.QueryArgs().Peek("timezone"))
+	)
+	if timezone == "" {
+		timezone = app.setting.GetAppTimezone()
+	}
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	from, err := time.Parse(time.DateOnly, string(r.RequestCtx.QueryArgs().Peek("from")))
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("report.invalidDateRange"), nil, envelope.InputError)
+	}
+	to, err := time.Parse(time.DateOnly, string(r.RequestCtx.QueryArgs().Peek("to")))
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("report.invalidDateRange"), nil, envelope.InputError)
+	}
+
+	rows, err := app.report.GetTimeReport(groupBy, from, to.AddDate(0, 0, 1), timezone)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	if format != "csv" {
+		return r.SendEnvelope(rows)
+	}
+
+	var (
+		buf = &bytes.Buffer{}
+		w   = csv.NewWriter(buf)
+	)
+	w.Write([]string{groupBy + "_id", "name", "entries", "conversations", "total_hours", "billable_hours"})
+	for _, row := range rows {
+		w.Write([]string{
+			row.Key,
+			row.Name,
+			strconv.Itoa(row.EntryCount),
+			strconv.Itoa(row.ConversationCount),
+			strconv.FormatFloat(float64(row.TotalSeconds)/3600, 'f', 2, 64),
+			strconv.FormatFloat(float64(row.BillableSeconds)/3600, 'f', 2, 64),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		app.lo.Error("error writing time report csv", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, app.i18n.T("globals.messages.somethingWentWrong"), nil, envelope.GeneralError)
+	}
+
+	filename := fmt.Sprintf("time-report-%s-%s-to-%s.csv", groupBy, from.Format(time.DateOnly), to.Format(time.DateOnly))
+	r.RequestCtx.Response.Header.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	r.RequestCtx.Response.Header.Set("X-Content-Type-Options", "nosniff")
+	r.RequestCtx.SetContentType("text/csv; charset=utf-8")
+	r.RequestCtx.SetBody(buf.Bytes())
+	return nil
+}
+
+// handleSLAReport retrieves SLA compliance over a date range, grouped by team, agent, policy, priority or inbox.
+// `from` and `to` are inclusive dates in YYYY-MM-DD format in `timezone`, which defaults to the app timezone.
+// `key`, a group key from the breakdown, limits the trend and breached conversations to that group.
+func handleSLAReport(r *fastglue.Request) error {
+	var (
+		app      = r.Context.(*App)
+		groupBy  = string(r.RequestCtx.QueryArgs().Peek("group_by"))
+		timezone = string(r.RequestCtx.QueryArgs().Peek("timezone"))
+		key      null.String
+	)
+	if r.RequestCtx.QueryArgs().Has("key") {
+		key = null.StringFrom(string(r.RequestCtx.QueryArgs().Peek("key")))
+	}
+	if groupBy == "" {
+		groupBy = rmodels.SLAReportByAll
+	}
+	if timezone == "" {
+		timezone = app.setting.GetAppTimezone()
+	}
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.Ts("validation.invalidValue", "name", "`timezone`"), nil, envelope.InputError)
+	}
+	from, err := time.ParseInLocation(time.DateOnly, string(r.RequestCtx.QueryArgs().Peek("from")), loc)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("report.invalidDateRange"), nil, envelope.InputError)
+	}
+	to, err := time.ParseInLocation(time.DateOnly, string(r.RequestCtx.QueryArgs().Peek("to")), loc)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("report.invalidDateRange"), nil, envelope.InputError)
+	}
+
+	report, err := app.report.GetSLAReport(groupBy, key, from, to.AddDate(0, 0, 1), loc.String())
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+
+	// Link breached conversations.
+	rootURL, _ := app.setting.GetAppRootURL()
+	for i := range report.Breached {
+		report.Breached[i].URL = rootURL + "/inboxes/all/conversation/" + report.Breached[i].UUID
+	}
+	return r.SendEnvelope(report)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/knadh/go-i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+func TestHandleSLAReport_InvalidInput(t *testing.T) {
+	tr, err := i18n.New([]byte(`{"_.code":"en","_.name":"English","report.invalidDateRange":"invalid date range"}`))
+	require.NoError(t, err)
+	app := &App{i18n: tr}
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "unknown timezone", query: "group_by=team&key=1&timezone=Mars/Olympus&from=2026-01-01&to=2026-01-07"},
+		{name: "missing from", query: "group_by=team&key=1&timezone=UTC&to=2026-01-07"},
+		{name: "invalid to", query: "group_by=team&key=1&timezone=UTC&from=2026-01-01&to=07-01-2026"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Request.SetRequestURI("/api/v1/reports/sla?" + tt.query)
+			r := &fastglue.Request{RequestCtx: ctx, Context: app}
+
+			require.NoError(t, handleSLAReport(r))
+			assert.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode())
+			assert.Contains(t, string(ctx.Response.Body()), `"error_type":"InputException"`)
+		})
+	}
+}
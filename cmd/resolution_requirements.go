@@ -0,0 +1,88 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/abhinavxd/libredesk/internal/conversation/resolution/models"
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+// handleGetResolutionRequirements returns all resolution requirements.
+func handleGetResolutionRequirements(r *fastglue.Request) error {
+	var app = r.Context.(*App)
+	out, err := app.resolution.GetAll()
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(out)
+}
+
+// handleGetResolutionRequirement returns a resolution requirement.
+func handleGetResolutionRequirement(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		id, _ = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+	)
+	if id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("validation.invalidValue"), nil, envelope.InputError)
+	}
+	out, err := app.resolution.Get(id)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(out)
+}
+
+// handleCreateResolutionRequirement creates a resolution requirement.
+func handleCreateResolutionRequirement(r *fastglue.Request) error {
+	var (
+		app = r.Context.(*App)
+		// Fields left out of the request take the column defaults.
+		req = models.Requirement{BypassAutomation: true, BypassAIAssistant: true, Enabled: true}
+	)
+	if err := r.Decode(&req, "json"); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), err.Error(), envelope.InputError)
+	}
+	out, err := app.resolution.Create(req)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(out)
+}
+
+// handleUpdateResolutionRequirement updates a resolution requirement.
+func handleUpdateResolutionRequirement(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		req   = models.Requirement{}
+		id, _ = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+	)
+	if id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("validation.invalidValue"), nil, envelope.InputError)
+	}
+	if err := r.Decode(&req, "json"); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), err.Error(), envelope.InputError)
+	}
+	out, err := app.resolution.Update(id, req)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(out)
+}
+
+// handleDeleteResolutionRequirement deletes a resolution requirement.
+func handleDeleteResolutionRequirement(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		id, _ = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+	)
+	if id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("validation.invalidValue"), nil, envelope.InputError)
+	}
+	if err := app.resolution.Delete(id); err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(true)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	"github.com/abhinavxd/libredesk/internal/retention/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+// handleGetRetentionPolicies returns the data retention policies of all inboxes.
+func handleGetRetentionPolicies(r *fastglue.Request) error {
+	var app = r.Context.(*App)
+	policies, err := app.retention.GetAll()
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(policies)
+}
+
+// handleGetRetentionPolicy returns the data retention policy of an inbox.
+func handleGetRetentionPolicy(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		id, _ = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+	)
+	if id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("validation.invalidInbox"), nil, envelope.InputError)
+	}
+	policy, err := app.retention.Get(id)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(policy)
+}
+
+// handleUpdateRetentionPolicy creates or updates the data retention policy of an inbox.
+func handleUpdateRetentionPolicy(r *fastglue.Request) error {
+	var (
+		app    = r.Context.(*App)
+		policy = models.Policy{}
+		id, _  = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+	)
+	if id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("validation.invalidInbox"), nil, envelope.InputError)
+	}
+	if err := r.Decode(&policy, "json"); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), err.Error(), envelope.InputError)
+	}
+	result, err := app.retention.Upsert(id, policy)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(result)
+}
+
+// handleDeleteRetentionPolicy deletes the data retention policy of an inbox.
+func handleDeleteRetentionPolicy(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		id, _ = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+	)
+	if id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("validation.invalidInbox"), nil, envelope.InputError)
+	}
+	if err := app.retention.Delete(id); err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(true)
+}
+
+// handleRetentionPolicyDryRun returns what the data retention policy of an inbox would purge right now, without purging anything.
+func handleRetentionPolicyDryRun(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		id, _ = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+	)
+	if id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("validation.invalidInbox"), nil, envelope.InputError)
+	}
+	report, err := app.retention.DryRun(id)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(report)
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	amodels "github.com/abhinavxd/libredesk/internal/auth/models"
+	cmodels "github.com/abhinavxd/libredesk/internal/conversation/models"
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	smodels "github.com/abhinavxd/libredesk/internal/skill/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+// handleGetSkills returns all skills.
+func handleGetSkills(r *fastglue.Request) error {
+	var (
+		app = r.Context.(*App)
+	)
+	skills, err := app.skill.GetAll()
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(skills)
+}
+
+// handleCreateSkill creates a new skill.
+func handleCreateSkill(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		skill = smodels.Skill{}
+	)
+	if err := r.Decode(&skill, "json"); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), err.Error(), envelope.InputError)
+	}
+
+	skill.Name = strings.TrimSpace(skill.Name)
+	if skill.Name == "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.Ts("globals.messages.empty", "name", "`name`"), nil, envelope.InputError)
+	}
+
+	createdSkill, err := app.skill.Create(skill.Name, skill.Description)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(createdSkill)
+}
+
+// handleUpdateSkill updates an existing skill.
+func handleUpdateSkill(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		skill = smodels.Skill{}
+	)
+	id, err := strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+	if err != nil || id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("globals.messages.somethingWentWrong"), nil, envelope.InputError)
+	}
+
+	if err := r.Decode(&skill, "json"); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), err.Error(), envelope.InputError)
+	}
+
+	skill.Name = strings.TrimSpace(skill.Name)
+	if skill.Name == "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.Ts("globals.messages.empty", "name", "`name`"), nil, envelope.InputError)
+	}
+
+	updatedSkill, err := app.skill.Update(id, skill.Name, skill.Description)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(updatedSkill)
+}
+
+// handleDeleteSkill deletes a skill.
+func handleDeleteSkill(r *fastglue.Request) error {
+	var (
+		app = r.Context.(*App)
+	)
+	id, err := strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+	if err != nil || id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("globals.messages.somethingWentWrong"), nil, envelope.InputError)
+	}
+
+	if err = app.skill.Delete(id); err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(true)
+}
+
+// handleGetAgentSkills returns the skills of an agent.
+func handleGetAgentSkills(r *fastglue.Request) error {
+	var (
+		app = r.Context.(*App)
+	)
+	id, err := strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+	if err != nil || id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("globals.messages.somethingWentWrong"), nil, envelope.InputError)
+	}
+
+	skills, err := app.skill.GetAgentSkills(id)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(skills)
+}
+
+// handleUpdateAgentSkills replaces the skills of an agent.
+func handleUpdateAgentSkills(r *fastglue.Request) error {
+	var (
+		app    = r.Context.(*App)
+		skills = []smodels.AgentSkill{}
+	)
+	id, err := strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+	if err != nil || id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("globals.messages.somethingWentWrong"), nil, envelope.InputError)
+	}
+
+	if err := r.Decode(&skills, "json"); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), err.Error(), envelope.InputError)
+	}
+
+	if err := app.skill.SetAgentSkills(id, skills); err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(true)
+}
+
+// handleGetConversationSkills returns the skills a conversation requires of the agent it is auto assigned to.
+func handleGetConversationSkills(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		auser = r.RequestCtx.UserValue("user").(amodels.User)
+		uuid  = r.RequestCtx.UserValue("uuid").(string)
+	)
+	user, err := app.user.GetAgentCachedOrLoad(auser.ID)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	if _, err := enforceConversationAccess(app, uuid, user); err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+
+	skills, err := app.conversation.GetConversationSkills(uuid)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(skills)
+}
+
+// handleUpdateConversationSkills replaces the skills a conversation requires.
+func handleUpdateConversationSkills(r *fastglue.Request) error {
+	var (
+		app    = r.Context.(*App)
+		auser  = r.RequestCtx.UserValue("user").(amodels.User)
+		uuid   = r.RequestCtx.UserValue("uuid").(string)
+		skills = []cmodels.RequiredSkill{}
+	)
+	if err := r.Decode(&skills, "json"); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), err.Error(), envelope.InputError)
+	}
+
+	user, err := app.user.GetAgentCachedOrLoad(auser.ID)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	if _, err := enforceConversationAccess(app, uuid, user); err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+
+	if err := app.conversation.SetConversationSkills(uuid, skills); err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(true)
+}
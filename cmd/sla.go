@@ -1,15 +1,28 @@
 package main
 
 import (
+	"encoding/json"
 	"strconv"
 	"time"
 
+	autoModels "github.com/abhinavxd/libredesk/internal/automation/models"
+	cstatusmodels "github.com/abhinavxd/libredesk/internal/conversation/status/models"
 	"github.com/abhinavxd/libredesk/internal/envelope"
+	slapkg "github.com/abhinavxd/libredesk/internal/sla"
 	smodels "github.com/abhinavxd/libredesk/internal/sla/models"
 	"github.com/valyala/fasthttp"
 	"github.com/zerodha/fastglue"
 )
 
+// slaEscalationActions are the automation actions an SLA escalation can run.
+var slaEscalationActions = map[string]bool{
+	autoModels.ActionAssignTeam:     true,
+	autoModels.ActionAssignUser:     true,
+	autoModels.ActionSetPriority:    true,
+	autoModels.ActionAddTags:        true,
+	autoModels.ActionTriggerWebhook: true,
+}
+
 // handleGetSLAs returns all SLAs.
 func handleGetSLAs(r *fastglue.Request) error {
 	var (
@@ -54,10 +67,11 @@ func handleCreateSLA(r *fastglue.Request) error {
 		return sendErrorEnvelope(r, err)
 	}
 
-	createdSLA, err := app.sla.Create(sla.Name, sla.Description, sla.FirstResponseTime, sla.ResolutionTime, sla.NextResponseTime, sla.Notifications)
+	createdSLA, err := app.sla.Create(sla.Name, sla.Description, sla.FirstResponseTime, sla.ResolutionTime, sla.NextResponseTime, sla.Notifications, sla.PauseStatuses, sla.PriorityTargets, sla.Escalations, sla.Conditions, sla.MatchWeight)
 	if err != nil {
 		return sendErrorEnvelope(r, err)
 	}
+	app.automation.ReloadSLAPolicies()
 
 	return r.SendEnvelope(createdSLA)
 }
@@ -78,14 +92,19 @@ func handleUpdateSLA(r *fastglue.Request) error {
 		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), err.Error(), envelope.InputError)
 	}
 
+	if err := keepOmittedSLAFields(app, id, r.RequestCtx.PostBody(), &sla); err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+
 	if err := validateSLA(app, &sla); err != nil {
 		return sendErrorEnvelope(r, err)
 	}
 
-	updatedSLA, err := app.sla.Update(id, sla.Name, sla.Description, sla.FirstResponseTime, sla.ResolutionTime, sla.NextResponseTime, sla.Notifications)
+	updatedSLA, err := app.sla.Update(id, sla.Name, sla.Description, sla.FirstResponseTime, sla.ResolutionTime, sla.NextResponseTime, sla.Notifications, sla.PauseStatuses, sla.PriorityTargets, sla.Escalations, sla.Conditions, sla.MatchWeight)
 	if err != nil {
 		return sendErrorEnvelope(r, err)
 	}
+	app.automation.ReloadSLAPolicies()
 
 	return r.SendEnvelope(updatedSLA)
 }
@@ -103,10 +122,40 @@ func handleDeleteSLA(r *fastglue.Request) error {
 	if err = app.sla.Delete(id); err != nil {
 		return sendErrorEnvelope(r, err)
 	}
+	app.automation.ReloadSLAPolicies()
 
 	return r.SendEnvelope(true)
 }
 
+// keepOmittedSLAFields copies the stored values of the fields missing from the request body into sla,
+// so an update that doesn't send a field, such as one from a form that doesn't edit it, doesn't clear it.
+func keepOmittedSLAFields(app *App, id int, body []byte, sla *smodels.SLAPolicy) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return envelope.NewError(envelope.InputError, app.i18n.T("errors.parsingRequest"), nil)
+	}
+	current, err := app.sla.Get(id)
+	if err != nil {
+		return err
+	}
+	if _, ok := fields["pause_statuses"]; !ok {
+		sla.PauseStatuses = current.PauseStatuses
+	}
+	if _, ok := fields["priority_targets"]; !ok {
+		sla.PriorityTargets = current.PriorityTargets
+	}
+	if _, ok := fields["escalations"]; !ok {
+		sla.Escalations = current.Escalations
+	}
+	if _, ok := fields["conditions"]; !ok {
+		sla.Conditions = current.Conditions
+	}
+	if _, ok := fields["match_weight"]; !ok {
+		sla.MatchWeight = current.MatchWeight
+	}
+	return nil
+}
+
 // validateSLA validates the SLA policy and returns an envelope.Error if any validation fails.
 func validateSLA(app *App, sla *smodels.SLAPolicy) error {
 	if sla.Name == "" {
@@ -185,5 +234,97 @@ func validateSLA(app *App, sla *smodels.SLAPolicy) error {
 		}
 	}
 
+	// Validate priority targets.
+	var seen = make(map[int]bool, len(sla.PriorityTargets))
+	for _, t := range sla.PriorityTargets {
+		if _, err := app.priority.Get(t.PriorityID); err != nil || seen[t.PriorityID] {
+			return envelope.NewError(envelope.InputError, app.i18n.Ts("validation.invalidValue", "name", "`priority_id`"), nil)
+		}
+		seen[t.PriorityID] = true
+		for _, d := range []string{t.FirstResponseTime.String, t.NextResponseTime.String, t.ResolutionTime.String} {
+			if d == "" {
+				continue
+			}
+			dur, err := time.ParseDuration(d)
+			if err != nil {
+				return envelope.NewError(envelope.InputError, app.i18n.T("validation.invalidDuration"), nil)
+			}
+			if dur.Minutes() < 1 {
+				return envelope.NewError(envelope.InputError, app.i18n.T("sla.minimumDurationOneMinute"), nil)
+			}
+		}
+		frt, _, rt := sla.Targets(t.PriorityID)
+		if frt.String != "" && rt.String != "" {
+			f, _ := time.ParseDuration(frt.String)
+			r, _ := time.ParseDuration(rt.String)
+			if f > r {
+				return envelope.NewError(envelope.InputError, app.i18n.T("sla.firstResponseTimeAfterResolution"), nil)
+			}
+		}
+	}
+
+	// Validate escalations, each metric and trigger runs at most one escalation.
+	var keys = make(map[string]bool, len(sla.Escalations))
+	for i := range sla.Escalations {
+		esc := &sla.Escalations[i]
+		switch esc.Metric {
+		case slapkg.MetricFirstResponse, slapkg.MetricResolution, slapkg.MetricNextResponse:
+		default:
+			return envelope.NewError(envelope.InputError, app.i18n.Ts("validation.invalidValue", "name", "`metric`"), nil)
+		}
+		switch esc.Trigger {
+		case slapkg.EscalationTriggerBreach:
+			esc.Percent = 0
+		case slapkg.EscalationTriggerPercent:
+			if esc.Percent < 1 || esc.Percent > 99 {
+				return envelope.NewError(envelope.InputError, app.i18n.Ts("validation.invalidValue", "name", "`percent`"), nil)
+			}
+		default:
+			return envelope.NewError(envelope.InputError, app.i18n.Ts("validation.invalidValue", "name", "`trigger`"), nil)
+		}
+		if keys[esc.Key()] {
+			return envelope.NewError(envelope.InputError, app.i18n.Ts("validation.invalidValue", "name", "`escalations`"), nil)
+		}
+		keys[esc.Key()] = true
+		if len(esc.Actions) == 0 {
+			return envelope.NewError(envelope.InputError, app.i18n.Ts("globals.messages.empty", "name", "`actions`"), nil)
+		}
+		for _, action := range esc.Actions {
+			if !slaEscalationActions[action.Type] || len(action.Value) == 0 {
+				return envelope.NewError(envelope.InputError, app.i18n.Ts("validation.invalidValue", "name", "`actions`"), nil)
+			}
+		}
+	}
+
+	// Validate match conditions, used to select the policy automatically.
+	if !sla.Conditions.IsEmpty() {
+		switch sla.Conditions.GroupOperator {
+		case autoModels.OperatorAnd, autoModels.OperatorOR:
+		default:
+			return envelope.NewError(envelope.InputError, app.i18n.Ts("validation.invalidValue", "name", "`group_operator`"), nil)
+		}
+		if len(sla.Conditions.Groups) > 2 {
+			return envelope.NewError(envelope.InputError, app.i18n.Ts("validation.invalidValue", "name", "`conditions`"), nil)
+		}
+		for _, group := range sla.Conditions.Groups {
+			for _, rule := range group.Rules {
+				if rule.Field == "" || rule.Operator == "" {
+					return envelope.NewError(envelope.InputError, app.i18n.Ts("validation.invalidValue", "name", "`conditions`"), nil)
+				}
+			}
+		}
+	}
+	if sla.MatchWeight < 0 {
+		return envelope.NewError(envelope.InputError, app.i18n.Ts("validation.invalidValue", "name", "`match_weight`"), nil)
+	}
+
+	// Validate pause statuses, resolved statuses already stop the clocks.
+	for _, id := range append(append([]int{}, sla.PauseStatuses.Resolution...), sla.PauseStatuses.NextResponse...) {
+		s, err := app.status.Get(id)
+		if err != nil || s.Category == cstatusmodels.CategoryResolved {
+			return envelope.NewError(envelope.InputError, app.i18n.Ts("validation.invalidValue", "name", "`pause_statuses`"), nil)
+		}
+	}
+
 	return nil
 }
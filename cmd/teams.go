@@ -59,8 +59,14 @@ func handleCreateTeam(r *fastglue.Request) error {
 	if err := r.Decode(&req, "json"); err != nil {
 		return sendErrorEnvelope(r, envelope.NewError(envelope.InputError, app.i18n.T("errors.parsingRequest"), nil))
 	}
+	if req.SkillFallbackMinutes < 0 {
+		return sendErrorEnvelope(r, envelope.NewError(envelope.InputError, app.i18n.Ts("validation.invalidValue", "name", "`skill_fallback_minutes`"), nil))
+	}
+	if req.StickyAssignmentDays < 0 {
+		return sendErrorEnvelope(r, envelope.NewError(envelope.InputError, app.i18n.Ts("validation.invalidValue", "name", "`sticky_assignment_days`"), nil))
+	}
 
-	createdTeam, err := app.team.Create(req.Name, req.Timezone, req.ConversationAssignmentType, req.BusinessHoursID, req.SLAPolicyID, req.Emoji.String, req.MaxAutoAssignedConversations)
+	createdTeam, err := app.team.Create(req.Name, req.Timezone, req.ConversationAssignmentType, req.BusinessHoursID, req.SLAPolicyID, req.Emoji.String, req.MaxAutoAssignedConversations, req.SkillFallbackMinutes, req.StickyAssignmentDays)
 	if err != nil {
 		return sendErrorEnvelope(r, err)
 	}
@@ -82,8 +88,14 @@ func handleUpdateTeam(r *fastglue.Request) error {
 	if err := r.Decode(&req, "json"); err != nil {
 		return sendErrorEnvelope(r, envelope.NewError(envelope.InputError, app.i18n.T("errors.parsingRequest"), nil))
 	}
+	if req.SkillFallbackMinutes < 0 {
+		return sendErrorEnvelope(r, envelope.NewError(envelope.InputError, app.i18n.Ts("validation.invalidValue", "name", "`skill_fallback_minutes`"), nil))
+	}
+	if req.StickyAssignmentDays < 0 {
+		return sendErrorEnvelope(r, envelope.NewError(envelope.InputError, app.i18n.Ts("validation.invalidValue", "name", "`sticky_assignment_days`"), nil))
+	}
 
-	updatedTeam, err := app.team.Update(id, req.Name, req.Timezone, req.ConversationAssignmentType, req.BusinessHoursID, req.SLAPolicyID, req.Emoji.String, req.MaxAutoAssignedConversations)
+	updatedTeam, err := app.team.Update(id, req.Name, req.Timezone, req.ConversationAssignmentType, req.BusinessHoursID, req.SLAPolicyID, req.Emoji.String, req.MaxAutoAssignedConversations, req.SkillFallbackMinutes, req.StickyAssignmentDays)
 	if err != nil {
 		return sendErrorEnvelope(r, err)
 	}
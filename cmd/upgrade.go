@@ -46,6 +46,7 @@ var migList = []migFunc{
 	{"v2.4.0", migrations.V2_4_0},
 	{"v2.5.0", migrations.V2_5_0},
 	{"v2.6.0", migrations.V2_6_0},
+	{"v2.7.0", migrations.V2_7_0},
 }
 
 // upgrade upgrades the database to the current version by running SQL migration files
@@ -13,6 +13,7 @@ import (
 	"github.com/abhinavxd/libredesk/internal/image"
 	mmodels "github.com/abhinavxd/libredesk/internal/media/models"
 	notifier "github.com/abhinavxd/libredesk/internal/notification"
+	shmodels "github.com/abhinavxd/libredesk/internal/shift/models"
 	"github.com/abhinavxd/libredesk/internal/stringutil"
 	tmpl "github.com/abhinavxd/libredesk/internal/template"
 	"github.com/abhinavxd/libredesk/internal/user/models"
@@ -610,6 +611,130 @@ func handleRevokeAPIKey(r *fastglue.Request) error {
 	return r.SendEnvelope(true)
 }
 
+// handleGetAgentCapacity returns the auto assignment capacity of an agent.
+func handleGetAgentCapacity(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		id, _ = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+	)
+	if id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("globals.messages.somethingWentWrong"), nil, envelope.InputError)
+	}
+
+	capacity, err := app.user.GetAgentCapacity(id)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(capacity)
+}
+
+// handleUpdateAgentCapacity updates the auto assignment capacity of an agent.
+func handleUpdateAgentCapacity(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		id, _ = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+		req   = models.AgentCapacity{}
+	)
+	if id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("globals.messages.somethingWentWrong"), nil, envelope.InputError)
+	}
+
+	if err := r.Decode(&req, "json"); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), err.Error(), envelope.InputError)
+	}
+
+	if err := app.user.UpdateAgentCapacity(id, req); err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(true)
+}
+
+// handleGetAgentSchedule returns the work schedule of an agent.
+func handleGetAgentSchedule(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		id, _ = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+	)
+	if id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("globals.messages.somethingWentWrong"), nil, envelope.InputError)
+	}
+
+	schedule, err := app.shift.Get(id)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	return r.SendEnvelope(schedule)
+}
+
+// handleUpdateAgentSchedule creates or replaces the work schedule of an agent.
+func handleUpdateAgentSchedule(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		id, _ = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+		req   = shmodels.Schedule{}
+	)
+	if id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("globals.messages.somethingWentWrong"), nil, envelope.InputError)
+	}
+
+	if err := r.Decode(&req, "json"); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("errors.parsingRequest"), err.Error(), envelope.InputError)
+	}
+
+	// Check if agent exists.
+	if _, err := app.user.GetAgent(id, ""); err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+
+	schedule, status, err := app.shift.Upsert(id, req)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	onScheduleAvailabilityRestored(r, id, status)
+	return r.SendEnvelope(schedule)
+}
+
+// handleDeleteAgentSchedule deletes the work schedule of an agent.
+func handleDeleteAgentSchedule(r *fastglue.Request) error {
+	var (
+		app   = r.Context.(*App)
+		id, _ = strconv.Atoi(r.RequestCtx.UserValue("id").(string))
+	)
+	if id <= 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, app.i18n.T("globals.messages.somethingWentWrong"), nil, envelope.InputError)
+	}
+
+	status, err := app.shift.Delete(id)
+	if err != nil {
+		return sendErrorEnvelope(r, err)
+	}
+	onScheduleAvailabilityRestored(r, id, status)
+	return r.SendEnvelope(true)
+}
+
+// onScheduleAvailabilityRestored refreshes, broadcasts and records the availability of an agent brought back from
+// the away status set by their shift when their schedule was deleted or disabled.
+func onScheduleAvailabilityRestored(r *fastglue.Request, id int, status string) {
+	if status == "" {
+		return
+	}
+	var (
+		app   = r.Context.(*App)
+		auser = r.RequestCtx.UserValue("user").(amodels.User)
+		ip    = realip.FromRequest(r.RequestCtx)
+	)
+	app.user.InvalidateAgentCache(id)
+	app.conversation.BroadcastAgentAvailability(id, status)
+
+	agent, err := app.user.GetAgent(id, "")
+	if err != nil {
+		return
+	}
+	if err := app.activityLog.UserAvailability(auser.ID, auser.Email, status, ip, agent.Email.String, agent.ID); err != nil {
+		app.lo.Error("error creating activity log", "error", err)
+	}
+}
+
 // validateAgentRequest validates common agent request fields and normalizes the email
 func validateAgentRequest(r *fastglue.Request, req *agentReq) error {
 	var app = r.Context.(*App)
@@ -13,6 +13,7 @@ import (
 	"github.com/abhinavxd/libredesk/internal/httputil"
 	"github.com/abhinavxd/libredesk/internal/inbox/channel/livechat"
 	"github.com/fasthttp/websocket"
+	"github.com/redis/go-redis/v9"
 	"github.com/zerodha/fastglue"
 )
 
@@ -346,9 +347,19 @@ func handleWidgetPageVisit(app *App, data json.RawMessage, contactID int) {
 }
 
 func getPageVisitsFromRedis(app *App, contactID int) []map[string]string {
+	return pageVisitStore{rdb: app.redis}.GetPageVisits(contactID)
+}
+
+// pageVisitStore reads and deletes the page visits of widget contacts stored in redis.
+type pageVisitStore struct {
+	rdb *redis.Client
+}
+
+// GetPageVisits returns the most recent page visits of a contact.
+func (s pageVisitStore) GetPageVisits(contactID int) []map[string]string {
 	redisCtx := context.Background()
 	key := fmt.Sprintf("%s%d", pageVisitRedisKeyPrefix, contactID)
-	entries, err := app.redis.LRange(redisCtx, key, 0, maxPageVisits-1).Result()
+	entries, err := s.rdb.LRange(redisCtx, key, 0, maxPageVisits-1).Result()
 	if err != nil {
 		return nil
 	}
@@ -361,3 +372,8 @@ func getPageVisitsFromRedis(app *App, contactID int) []map[string]string {
 	}
 	return pages
 }
+
+// DeletePageVisits deletes all page visits of a contact.
+func (s pageVisitStore) DeletePageVisits(contactID int) error {
+	return s.rdb.Del(context.Background(), fmt.Sprintf("%s%d", pageVisitRedisKeyPrefix, contactID)).Err()
+}
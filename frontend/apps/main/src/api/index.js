@@ -81,6 +81,17 @@ const deleteStatus = (id) => http.delete(`/api/v1/statuses/${id}`)
 const createTag = (data) => http.post('/api/v1/tags', data)
 const updateTag = (id, data) => http.put(`/api/v1/tags/${id}`, data)
 const deleteTag = (id) => http.delete(`/api/v1/tags/${id}`)
+const getSkills = () => http.get('/api/v1/skills')
+const createSkill = (data) => http.post('/api/v1/skills', data)
+const updateSkill = (id, data) => http.put(`/api/v1/skills/${id}`, data)
+const deleteSkill = (id) => http.delete(`/api/v1/skills/${id}`)
+const getAgentSkills = (id) => http.get(`/api/v1/agents/${id}/skills`)
+const updateAgentSkills = (id, data) => http.put(`/api/v1/agents/${id}/skills`, data)
+const getAgentCapacity = (id) => http.get(`/api/v1/agents/${id}/capacity`)
+const updateAgentCapacity = (id, data) => http.put(`/api/v1/agents/${id}/capacity`, data)
+const getAgentSchedule = (id) => http.get(`/api/v1/agents/${id}/schedule`)
+const updateAgentSchedule = (id, data) => http.put(`/api/v1/agents/${id}/schedule`, data)
+const deleteAgentSchedule = (id) => http.delete(`/api/v1/agents/${id}/schedule`)
 const getTemplate = (id) => http.get(`/api/v1/templates/${id}`)
 const getTemplates = (type) => http.get('/api/v1/templates', { params: { type: type } })
 const createTemplate = (data) =>
@@ -112,6 +123,12 @@ const updateBusinessHours = (id, data) =>
     }
   })
 const deleteBusinessHours = (id) => http.delete(`/api/v1/business-hours/${id}`)
+const importHolidays = (id, data) =>
+  http.post(`/api/v1/business-hours/${id}/holidays/import`, data, {
+    headers: {
+      'Content-Type': 'multipart/form-data'
+    }
+  })
 
 const getAllSLAs = () => http.get('/api/v1/sla')
 const getSLA = (id) => http.get(`/api/v1/sla/${id}`)
@@ -605,6 +622,7 @@ export default {
   createBusinessHours,
   updateBusinessHours,
   deleteBusinessHours,
+  importHolidays,
   getAllSLAs,
   getSLA,
   createSLA,
@@ -679,6 +697,17 @@ export default {
   createTag,
   updateTag,
   deleteTag,
+  getSkills,
+  createSkill,
+  updateSkill,
+  deleteSkill,
+  getAgentSkills,
+  updateAgentSkills,
+  getAgentCapacity,
+  updateAgentCapacity,
+  getAgentSchedule,
+  updateAgentSchedule,
+  deleteAgentSchedule,
   getStatuses,
   getPriorities,
   createStatus,
@@ -38,6 +38,15 @@ export function useActivityLogFilters () {
             }, {
                 label: t('activityLog.entryType.agentRolePermissionsChanged'),
                 value: 'agent_role_permissions_changed'
+            }, {
+                label: t('activityLog.entryType.conversationsPurged'),
+                value: 'conversations_purged'
+            }, {
+                label: t('activityLog.entryType.contactDataExported'),
+                value: 'contact_data_exported'
+            }, {
+                label: t('activityLog.entryType.contactDataErased'),
+                value: 'contact_data_erased'
             }]
         },
     }))
@@ -160,6 +160,49 @@ export function useConversationFilters () {
             }, {})
     })
 
+    // Conditions evaluated by automation rules on both new and updated conversations.
+    const conversationStateFilters = computed(() => ({
+        channel: {
+            label: t('globals.terms.channel'),
+            type: FIELD_TYPE.SELECT,
+            operators: FIELD_OPERATORS.SELECT,
+            options: [
+                { label: t('globals.terms.email'), value: 'email' },
+                { label: t('globals.terms.liveChat'), value: 'livechat' }
+            ]
+        },
+        tags: {
+            label: t('globals.terms.tag', 2),
+            type: FIELD_TYPE.MULTI_SELECT,
+            operators: FIELD_OPERATORS.MULTI_SELECT
+        },
+        within_business_hours: {
+            label: t('admin.automation.withinBusinessHours'),
+            type: FIELD_TYPE.BOOLEAN,
+            operators: FIELD_OPERATORS.BOOLEAN
+        },
+        contact_message_count: {
+            label: t('admin.automation.contactMessageCount'),
+            type: FIELD_TYPE.NUMBER,
+            operators: FIELD_OPERATORS.NUMBER
+        },
+        has_attachment: {
+            label: t('admin.automation.hasAttachment'),
+            type: FIELD_TYPE.BOOLEAN,
+            operators: FIELD_OPERATORS.BOOLEAN
+        },
+        returning_contact: {
+            label: t('admin.automation.returningContact'),
+            type: FIELD_TYPE.BOOLEAN,
+            operators: FIELD_OPERATORS.BOOLEAN
+        },
+        contact_company: {
+            label: t('admin.automation.contactCompany'),
+            type: FIELD_TYPE.TEXT,
+            operators: FIELD_OPERATORS.TEXT_AUTOMATION
+        }
+    }))
+
     const newConversationFilters = computed(() => ({
         contact_email: {
             label: t('globals.terms.email'),
@@ -205,7 +248,8 @@ export function useConversationFilters () {
             type: FIELD_TYPE.SELECT,
             operators: FIELD_OPERATORS.SELECT,
             options: iStore.options
-        }
+        },
+        ...conversationStateFilters.value
     }))
 
     const conversationFilters = computed(() => ({
@@ -282,7 +326,8 @@ export function useConversationFilters () {
             type: FIELD_TYPE.SELECT,
             operators: FIELD_OPERATORS.SELECT,
             options: tStore.options
-        }
+        },
+        ...conversationStateFilters.value
     }))
 
     const conversationActions = computed(() => ({
@@ -345,6 +390,40 @@ export function useConversationFilters () {
         trigger_webhook: {
             label: t('actions.triggerWebhook'),
             type: FIELD_TYPE.WEBHOOK
+        },
+        set_conversation_attribute: {
+            label: t('actions.setConversationAttribute'),
+            type: FIELD_TYPE.ATTRIBUTE,
+            options: customAttributeStore.conversationAttributeOptions.map(attribute => ({
+                label: attribute.label,
+                value: attribute.key
+            }))
+        },
+        set_contact_attribute: {
+            label: t('actions.setContactAttribute'),
+            type: FIELD_TYPE.ATTRIBUTE,
+            options: customAttributeStore.contactAttributeOptions.map(attribute => ({
+                label: attribute.label,
+                value: attribute.key
+            }))
+        },
+        add_participant: {
+            label: t('actions.addParticipant'),
+            type: FIELD_TYPE.SELECT,
+            options: uStore.options
+        },
+        change_inbox: {
+            label: t('actions.changeInbox'),
+            type: FIELD_TYPE.SELECT,
+            options: iStore.options
+        },
+        ai_triage: {
+            label: t('actions.aiTriage'),
+            type: FIELD_TYPE.TEXT
+        },
+        require_skill: {
+            label: t('actions.requireSkill'),
+            type: FIELD_TYPE.SKILL
         }
     }))
 
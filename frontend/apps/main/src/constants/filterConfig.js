@@ -17,6 +17,8 @@ export const FIELD_TYPE = {
     DATE: 'date',
     WEBHOOK: 'webhook',
     RECIPIENTS: 'recipients',
+    ATTRIBUTE: 'attribute',
+    SKILL: 'skill',
 }
 
 export const OPERATOR = {
@@ -29,7 +31,8 @@ export const OPERATOR = {
     GREATER_THAN: 'greater than',
     LESS_THAN: 'less than',
     BETWEEN: 'between',
-    STARTS_WITH: 'starts with'
+    STARTS_WITH: 'starts with',
+    MATCHES_REGEX: 'matches regex'
 }
 
 // operatorLabel returns a clearer display label for operators whose meaning is ambiguous with
@@ -54,7 +57,7 @@ export const FIELD_OPERATORS = {
         OPERATOR.CONTAINS,
         OPERATOR.NOT_CONTAINS
     ],
-    // "starts with" is only implemented by the automation evaluator, not the SQL filter builder.
+    // "starts with" and "matches regex" are only implemented by the automation evaluator, not the SQL filter builder.
     TEXT_AUTOMATION: [
         OPERATOR.EQUALS,
         OPERATOR.NOT_EQUALS,
@@ -62,7 +65,8 @@ export const FIELD_OPERATORS = {
         OPERATOR.NOT_SET,
         OPERATOR.CONTAINS,
         OPERATOR.NOT_CONTAINS,
-        OPERATOR.STARTS_WITH
+        OPERATOR.STARTS_WITH,
+        OPERATOR.MATCHES_REGEX
     ],
     // For text columns that do not support partial matching, only allow exact match operators.
     TEXT_EXACT: [OPERATOR.EQUALS, OPERATOR.NOT_EQUALS, OPERATOR.SET, OPERATOR.NOT_SET],
@@ -158,6 +158,13 @@ export const adminNavItems = [
         isTitleKeyPlural: true,
         icon: 'UsersRound'
       },
+      {
+        titleKey: 'globals.terms.skill',
+        href: '/admin/teams/skills',
+        permission: 'teams:manage',
+        isTitleKeyPlural: true,
+        icon: 'GraduationCap'
+      },
       {
         titleKey: 'globals.terms.role',
         href: '/admin/teams/roles',
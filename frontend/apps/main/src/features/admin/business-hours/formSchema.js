@@ -5,11 +5,24 @@ const timeRegex = /^([01]\d|2[0-3]):([0-5]\d)$/
 export const createFormSchema = (t) => z.object({
     name: z.string().min(1, t('globals.messages.required')),
     description: z.string().nullable().optional().transform(v => v ?? ''),
+    holiday_calendar_url: z
+        .union([z.literal(''), z.string().url(t('validation.invalidUrl'))])
+        .nullable()
+        .optional()
+        .transform(v => v ?? ''),
     is_always_open: z.boolean(),
     hours: z.record(
         z.object({
             open: z.string().regex(timeRegex, t('validation.invalidTimeFormat')),
             close: z.string().regex(timeRegex, t('validation.invalidTimeFormat')),
+            shifts: z
+                .array(
+                    z.object({
+                        open: z.string().regex(timeRegex, t('validation.invalidTimeFormat')),
+                        close: z.string().regex(timeRegex, t('validation.invalidTimeFormat')),
+                    })
+                )
+                .optional()
         })
     ).optional()
 }).superRefine((data, ctx) => {
@@ -22,6 +22,8 @@ export const createFormSchema = (t) => z.object({
     }),
   enabled: z.boolean().optional(),
   csat_enabled: z.boolean().optional(),
+  out_of_office_enabled: z.boolean().optional(),
+  business_hours_id: z.number().nullable().optional(),
   prompt_tags_on_reply: z.boolean().optional(),
   enable_plus_addressing: z.boolean().optional(),
   auth_type: z.enum([AUTH_TYPE_PASSWORD, AUTH_TYPE_OAUTH2]),
@@ -15,6 +15,8 @@ export const createFormSchema = (t) => z.object({
   name: z.string().min(1, { message: t('globals.messages.required') }),
   enabled: z.boolean(),
   csat_enabled: z.boolean(),
+  out_of_office_enabled: z.boolean(),
+  business_hours_id: z.number().nullable().optional(),
   prompt_tags_on_reply: z.boolean(),
   secret: z.string().nullable().optional(),
   linked_email_inbox_id: z.number().nullable().optional(),
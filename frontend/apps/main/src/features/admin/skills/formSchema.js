@@ -0,0 +1,12 @@
+import * as z from 'zod'
+
+export const createFormSchema = (t) => z.object({
+  name: z
+    .string({
+      required_error: t('globals.messages.required'),
+    })
+    .min(1, {
+      message: t('globals.messages.required'),
+    }),
+  description: z.string().optional().default('')
+})
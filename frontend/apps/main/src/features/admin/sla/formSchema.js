@@ -57,6 +57,30 @@ export const createFormSchema = (t) =>
                 )
                 .optional()
                 .default([]),
+            pause_statuses: z
+                .object({
+                    resolution: z.array(z.string()).default([]),
+                    next_response: z.array(z.string()).default([]),
+                })
+                .optional(),
+            priority_targets: z
+                .array(
+                    z.object({
+                        priority_id: z.string().min(1, { message: t('globals.messages.required') }),
+                        first_response_time: z.string().nullable().optional().refine(val => !val || isGoHourMinuteDuration(val), {
+                        message: t('validation.invalidDuration'),
+                    }),
+                        resolution_time: z.string().nullable().optional().refine(val => !val || isGoHourMinuteDuration(val), {
+                        message: t('validation.invalidDuration'),
+                    }),
+                        next_response_time: z.string().nullable().optional().refine(val => !val || isGoHourMinuteDuration(val), {
+                        message: t('validation.invalidDuration'),
+                    }),
+                    })
+                )
+                .optional()
+                .default([]),
+            match_weight: z.coerce.number().int().min(0).default(0),
         })
         .superRefine((data, ctx) => {
             const { first_response_time, resolution_time, next_response_time } = data
@@ -11,6 +11,8 @@ export const createTeamFormSchema = (t) => z.object({
   emoji: z.string({ required_error: t('globals.messages.required') }),
   conversation_assignment_type: z.string({ required_error: t('globals.messages.required') }),
   max_auto_assigned_conversations: z.coerce.number().optional().default(0),
+  skill_fallback_minutes: z.coerce.number().min(0).optional().default(0),
+  sticky_assignment_days: z.coerce.number().min(0).optional().default(0),
   timezone: z.string({ required_error: t('globals.messages.required') }),
   business_hours_id: z.number().optional().nullable(),
   sla_policy_id: z.number().optional().nullable(),
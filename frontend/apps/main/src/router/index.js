@@ -390,6 +390,11 @@ const routes = [
                   }
                 ]
               },
+              {
+                path: 'skills',
+                component: () => import('@main/views/admin/skills/SkillsView.vue'),
+                meta: { titleKey: 'globals.terms.skill', titleCount: 2 }
+              },
               {
                 path: 'roles',
                 component: () => import('@main/views/admin/roles/Roles.vue'),
@@ -57,19 +57,22 @@ export function useBusinessHours () {
     }
 
     const dayName = getDayName(localDate.getDay())
-    const schedule = businessHours.hours[dayName]
+    const shifts = getShifts(businessHours.hours[dayName])
 
-    if (!schedule || !schedule.open || !schedule.close) {
-      return false
-    }
+    const currentTime = format(localDate, 'HH:mm')
+    return shifts.some(shift => currentTime >= shift.open && currentTime <= shift.close)
+  }
 
-    // Check if open and close times are the same (closed day)
-    if (schedule.open === schedule.close) {
-      return false
+  // Returns the working windows of a day, its shifts or else its open and close times.
+  function getShifts (schedule) {
+    if (!schedule) {
+      return []
     }
-
-    const currentTime = format(localDate, 'HH:mm')
-    return currentTime >= schedule.open && currentTime <= schedule.close
+    const shifts = schedule.shifts?.length ? schedule.shifts : [{ open: schedule.open, close: schedule.close }]
+    // Open and close times that are the same mean a closed day.
+    return shifts
+      .filter(shift => shift.open && shift.close && shift.open !== shift.close)
+      .sort((a, b) => a.open.localeCompare(b.open))
   }
 
   function isHoliday (businessHours, date) {
@@ -97,34 +100,32 @@ export function useBusinessHours () {
       }
 
       const dayName = getDayName(localDate.getDay())
-      const schedule = businessHours.hours[dayName]
-
-      if (!schedule || !schedule.open || !schedule.close || schedule.open === schedule.close) {
-        continue
-      }
-
-      // Parse opening time
-      const [openHour, openMinute] = schedule.open.split(':').map(Number)
-      let nextWorking = setMinutes(setHours(localDate, openHour), openMinute)
-
-      // Handle same-day logic
-      if (i === 0) {
-        const currentTime = format(localDate, 'HH:mm')
-        // Currently within business hours
-        if (currentTime >= schedule.open && currentTime < schedule.close) {
-          return new Date(localDate.getTime() - (adjustedOffset * 60000))
+      const shifts = getShifts(businessHours.hours[dayName])
+
+      for (const shift of shifts) {
+        // Parse opening time
+        const [openHour, openMinute] = shift.open.split(':').map(Number)
+        const nextWorking = setMinutes(setHours(localDate, openHour), openMinute)
+
+        // Handle same-day logic
+        if (i === 0) {
+          const currentTime = format(localDate, 'HH:mm')
+          // Currently within business hours
+          if (currentTime >= shift.open && currentTime < shift.close) {
+            return new Date(localDate.getTime() - (adjustedOffset * 60000))
+          }
+          // Before opening time of this shift
+          if (currentTime < shift.open) {
+            return new Date(nextWorking.getTime() - (adjustedOffset * 60000))
+          }
+          // Past closing time, check next shift
+          continue
         }
-        // Before opening time today
-        if (currentTime < schedule.open) {
-          return new Date(nextWorking.getTime() - (adjustedOffset * 60000))
-        }
-        // Past closing time, check next day
-        continue
-      }
 
-      // For future days, return the opening time
-      // Convert back from business timezone to user timezone
-      return new Date(nextWorking.getTime() - (adjustedOffset * 60000))
+        // For future days, return the opening time of the first shift
+        // Convert back from business timezone to user timezone
+        return new Date(nextWorking.getTime() - (adjustedOffset * 60000))
+      }
     }
 
     return null
@@ -261,6 +261,55 @@ func (al *Manager) RolePermissionsChanged(actorID int, actorEmail, ip string, ro
 	)
 }
 
+// ConversationsPurged records a data retention purge of conversations in an inbox.
+func (al *Manager) ConversationsPurged(actorID, inboxID int, inboxName, action string, count int) error {
+	description := al.i18n.Ts("activityLog.conversationsPurged",
+		"action", action,
+		"count", fmt.Sprintf("%d", count),
+		"inboxName", inboxName,
+		"inboxId", fmt.Sprintf("#%d", inboxID))
+	return al.create(
+		models.ConversationsPurged,
+		description,
+		actorID,
+		"inbox",
+		inboxID,
+		"",
+	)
+}
+
+// ContactDataExported records the completion of a contact data export request.
+func (al *Manager) ContactDataExported(actorID int, actorEmail string, contactID int) error {
+	description := al.i18n.Ts("activityLog.contactDataExported",
+		"actorEmail", actorEmail,
+		"actorId", fmt.Sprintf("#%d", actorID),
+		"contactId", fmt.Sprintf("#%d", contactID))
+	return al.create(
+		models.ContactDataExported,
+		description,
+		actorID,
+		umodels.UserModel,
+		contactID,
+		"",
+	)
+}
+
+// ContactDataErased records the completion of a contact data erasure request.
+func (al *Manager) ContactDataErased(actorID int, actorEmail string, contactID int) error {
+	description := al.i18n.Ts("activityLog.contactDataErased",
+		"actorEmail", actorEmail,
+		"actorId", fmt.Sprintf("#%d", actorID),
+		"contactId", fmt.Sprintf("#%d", contactID))
+	return al.create(
+		models.ContactDataErased,
+		description,
+		actorID,
+		umodels.UserModel,
+		contactID,
+		"",
+	)
+}
+
 // create creates a new activity log in DB.
 func (m *Manager) create(activityType, activityDescription string, actorID int, targetModelType string, targetModelID int, ip string) error {
 	if _, err := m.q.InsertActivity.Exec(activityType, activityDescription, actorID, targetModelType, targetModelID, ip); err != nil {
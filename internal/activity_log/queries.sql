@@ -14,6 +14,7 @@ FROM
     activity_logs WHERE 1=1 
 
 -- name: insert-activity
+-- Entries logged outside of a request, such as retention purges, have no IP and pass an empty string, stored as NULL.
 INSERT INTO activity_logs (
     activity_type, 
     activity_description, 
@@ -22,5 +23,5 @@ INSERT INTO activity_logs (
     target_model_id, 
     ip
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
+    $1, $2, $3, $4, $5, NULLIF($6, '')::inet
 );
\ No newline at end of file
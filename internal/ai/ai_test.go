@@ -103,3 +103,27 @@ func TestEnsureHTMLFragment(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTriage(t *testing.T) {
+	priorities := []string{"Low", "High"}
+	teams := []string{"Billing", "Support"}
+	tags := []string{"refund", "bug", "vip"}
+
+	got, ok := parseTriage("```json\n{\"priority\": \"high\", \"team\": \"Sales\", \"tags\": [\"Refund\", \"unknown\", \"refund\"]}\n```", priorities, teams, tags)
+	if !ok {
+		t.Fatal("expected triage to parse")
+	}
+	if got.Priority != "High" {
+		t.Errorf("priority: got %q, want %q", got.Priority, "High")
+	}
+	if got.Team != "" {
+		t.Errorf("team not in the allowed list must be dropped, got %q", got.Team)
+	}
+	if len(got.Tags) != 1 || got.Tags[0] != "refund" {
+		t.Errorf("tags: got %v, want [refund]", got.Tags)
+	}
+
+	if _, ok := parseTriage("no json here", priorities, teams, tags); ok {
+		t.Error("expected unparseable response to fail")
+	}
+}
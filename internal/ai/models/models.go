@@ -131,6 +131,13 @@ type TagRef struct {
 	Name string `db:"name"`
 }
 
+// Triage is the outcome of classifying a conversation; empty fields leave the conversation unchanged.
+type Triage struct {
+	Priority string   `json:"priority"`
+	Team     string   `json:"team"`
+	Tags     []string `json:"tags"`
+}
+
 // SearchResult is one hit from the in-memory embedding search.
 type SearchResult struct {
 	SourceType string  `json:"source_type"`
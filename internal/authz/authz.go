@@ -6,6 +6,7 @@ import (
 	authzmodels "github.com/abhinavxd/libredesk/internal/authz/models"
 	cmodels "github.com/abhinavxd/libredesk/internal/conversation/models"
 	"github.com/abhinavxd/libredesk/internal/envelope"
+	gdprmodels "github.com/abhinavxd/libredesk/internal/gdpr/models"
 	umodels "github.com/abhinavxd/libredesk/internal/user/models"
 	"github.com/knadh/go-i18n"
 	"github.com/volatiletech/null/v9"
@@ -64,11 +65,18 @@ func CanReadAssignment(user umodels.User, assignedUserID, assignedTeamID null.In
 }
 
 // EnforceMediaAccess checks read access on the model linked to a media item.
+// Contact export archives need the export permission as they hold all data of a contact.
 func (e *Enforcer) EnforceMediaAccess(user umodels.User, model string) (bool, error) {
-	if model != "messages" {
+	var perm string
+	switch model {
+	case "messages":
+		perm = "messages:read"
+	case gdprmodels.MediaModelRequest:
+		perm = authzmodels.PermContactsExport
+	default:
 		return true, nil
 	}
-	if !slices.Contains(user.Permissions, "messages:read") {
+	if !slices.Contains(user.Permissions, perm) {
 		return false, envelope.NewError(envelope.UnauthorizedError, e.i18n.T("status.deniedPermission"), nil)
 	}
 	return true, nil
@@ -170,6 +170,8 @@ func mediaAccessCases() []mediaAccessCase {
 		{"non-messages model with perms", user(1, []string{"messages:read"}), "contacts", true, false},
 		{"empty model treated as non-messages", user(1, nil), "", true, false},
 		{"case-sensitive: Messages not equal to messages", user(1, nil), "Messages", true, false},
+		{"export archive with export", user(1, []string{"contacts:export"}), "contact_data_requests", true, false},
+		{"export archive without export", user(1, []string{"contacts:read", "messages:read"}), "contact_data_requests", false, true},
 	}
 }
 
@@ -335,9 +337,9 @@ func TestExhaustiveMediaAccess(t *testing.T) {
 	allPerms := []string{
 		"messages:read", "messages:write",
 		"conversations:read", "conversations:read_all",
-		"tags:manage", "users:manage",
+		"tags:manage", "users:manage", "contacts:export",
 	}
-	models := []string{"messages", "conversations", "tags", "users", "contacts", "", "MESSAGES", "Messages"}
+	models := []string{"messages", "conversations", "tags", "users", "contacts", "contact_data_requests", "", "MESSAGES", "Messages"}
 
 	checked := 0
 	for _, perms := range powerSet(allPerms) {
@@ -349,6 +351,9 @@ func TestExhaustiveMediaAccess(t *testing.T) {
 			if m == "messages" && !slices.Contains(perms, "messages:read") {
 				want, wantErr = false, true
 			}
+			if m == "contact_data_requests" && !slices.Contains(perms, "contacts:export") {
+				want, wantErr = false, true
+			}
 			checked++
 			if got != want {
 				t.Errorf("perms=%v model=%q: got allow=%v want=%v", perms, m, got, want)
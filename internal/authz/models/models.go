@@ -81,6 +81,8 @@ const (
 	PermContactsRead    = "contacts:read"
 	PermContactsWrite   = "contacts:write"
 	PermContactsBlock   = "contacts:block"
+	PermContactsExport  = "contacts:export"
+	PermContactsErase   = "contacts:erase"
 
 	// Contact Notes
 	PermContactNotesRead   = "contact_notes:read"
@@ -133,6 +135,8 @@ var validPermissions = map[string]struct{}{
 	PermContactsRead:                    {},
 	PermContactsWrite:                   {},
 	PermContactsBlock:                   {},
+	PermContactsExport:                  {},
+	PermContactsErase:                   {},
 	PermContactNotesRead:                {},
 	PermContactNotesWrite:               {},
 	PermContactNotesDelete:              {},
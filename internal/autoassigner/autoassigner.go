@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -25,12 +27,24 @@ var (
 
 const (
 	AssignmentTypeRoundRobin = "Round robin"
+	AssignmentTypeLeastBusy  = "Least busy"
+
+	// stickyLookback is the number of previous conversations of a contact looked at for sticky assignment.
+	stickyLookback = 10
 )
 
 type conversationStore interface {
 	GetUnassignedConversations() ([]models.Conversation, error)
 	ClaimUnassignedConversation(conversationUUID string, userID, expectedTeamID int, user umodels.User) error
 	ActiveUserConversationsCount(userID int) (int, error)
+	ActiveUserConversationsCountByChannel(userID int) (map[string]int, error)
+	GetContactPreviousConversations(contactID int, limit int) ([]models.PreviousConversation, error)
+}
+
+// capacity is the units of work an agent can be auto assigned, 0 for unlimited, and the units a conversation on each channel takes.
+type capacity struct {
+	units   int
+	weights tmodels.ChannelWeights
 }
 
 type teamStore interface {
@@ -39,10 +53,35 @@ type teamStore interface {
 }
 
 // Engine represents a manager for assigning unassigned conversations
-// to team agents in a round-robin pattern.
+// to team agents in a round-robin pattern or to the least busy agent.
+// skillBalancer is a round-robin balancer of the agents of a team that have the required skills.
+type skillBalancer struct {
+	required models.RequiredSkills
+	balancer *balance.Balance
+}
+
 type Engine struct {
 	roundRobinBalancer map[int]*balance.Balance
-	// Mutex to protect the balancer map
+	// skillBalancers holds the round-robin balancers of each team for the skills conversations require, keyed by skillsKey,
+	// so that agents without the skills don't use up turns in the team balancer.
+	skillBalancers map[int]map[string]skillBalancer
+	// leastBusyPool holds the available agents of teams using the least busy strategy.
+	leastBusyPool map[int][]int
+	// lastAssignedAt holds the time each agent was last auto assigned a conversation, loaded from the DB when the
+	// pools are populated so that the order of equally busy agents survives restarts.
+	lastAssignedAt map[int]time.Time
+	// userSkills holds the skill proficiencies of the agents in the pools.
+	userSkills map[int]tmodels.SkillProficiencies
+	// teamSkillFallback holds how long conversations of a team wait for an agent with the required skills
+	// before being assigned to any agent of the team, 0 to never fall back.
+	teamSkillFallback map[int]time.Duration
+	// userCapacity holds the capacity of the agents in the pools.
+	userCapacity map[int]capacity
+	// onlineMembers holds the online agents of each team.
+	onlineMembers map[int]map[int]struct{}
+	// teamStickyDays holds the days within which returning contacts of a team are assigned to their previous agent, 0 when disabled.
+	teamStickyDays map[int]int
+	// Mutex to protect the balancer and pool maps
 	balanceMu              sync.Mutex
 	teamMaxAutoAssignments map[int]int
 
@@ -65,6 +104,14 @@ func New(teamStore teamStore, conversationStore conversationStore, systemUser um
 		lo:                     lo,
 		teamMaxAutoAssignments: make(map[int]int),
 		roundRobinBalancer:     make(map[int]*balance.Balance),
+		skillBalancers:         make(map[int]map[string]skillBalancer),
+		leastBusyPool:          make(map[int][]int),
+		lastAssignedAt:         make(map[int]time.Time),
+		userSkills:             make(map[int]tmodels.SkillProficiencies),
+		teamSkillFallback:      make(map[int]time.Duration),
+		userCapacity:           make(map[int]capacity),
+		onlineMembers:          make(map[int]map[int]struct{}),
+		teamStickyDays:         make(map[int]int),
 	}
 	return &e, nil
 }
@@ -134,6 +181,13 @@ func (e *Engine) populateTeamBalancer() error {
 	}
 
 	for _, team := range teams {
+		e.teamSkillFallback[team.ID] = time.Duration(team.SkillFallbackMinutes) * time.Minute
+		e.teamStickyDays[team.ID] = team.StickyAssignmentDays
+		if team.ConversationAssignmentType == AssignmentTypeLeastBusy {
+			e.populateLeastBusyPool(team)
+			continue
+		}
+		delete(e.leastBusyPool, team.ID)
 		if team.ConversationAssignmentType != AssignmentTypeRoundRobin {
 			continue
 		}
@@ -144,6 +198,8 @@ func (e *Engine) populateTeamBalancer() error {
 			continue
 		}
 
+		e.setOnlineMembers(team.ID, users)
+
 		// Shuffle users to prevent ordering bias, as every app restart will pick the same first user.
 		rand.New(rand.NewSource(time.Now().UnixNano())).Shuffle(len(users), func(i, j int) {
 			users[i], users[j] = users[j], users[i]
@@ -163,8 +219,14 @@ func (e *Engine) populateTeamBalancer() error {
 				e.lo.Debug("user is away, skipping autoasssignment ", "team_id", team.ID, "user_id", user.ID, "availability_status", user.AvailabilityStatus)
 				continue
 			}
+			if user.OffShift {
+				e.lo.Debug("user is off shift, skipping autoasssignment ", "team_id", team.ID, "user_id", user.ID)
+				continue
+			}
 
 			// Add user to the balancer pool
+			e.userSkills[user.ID] = user.Skills
+			e.userCapacity[user.ID] = capacity{units: user.Capacity, weights: user.ChannelWeights}
 			uid := strconv.Itoa(user.ID)
 			existingUsers[uid] = struct{}{}
 			if err := balancer.Add(uid, 1); err != nil {
@@ -187,12 +249,61 @@ func (e *Engine) populateTeamBalancer() error {
 			}
 		}
 
+		// Keep the skill balancers in line with the team pool and the latest skills of its agents.
+		for _, sb := range e.skillBalancers[team.ID] {
+			e.syncSkillBalancer(team.ID, sb)
+		}
+
 		// Set max auto assigned conversations for the team
 		e.teamMaxAutoAssignments[team.ID] = team.MaxAutoAssignedConversations
 	}
 	return nil
 }
 
+// populateLeastBusyPool replaces the least busy pool of a team with its available members.
+func (e *Engine) populateLeastBusyPool(team tmodels.Team) {
+	users, err := e.teamStore.GetMembers(team.ID)
+	if err != nil {
+		e.lo.Error("error fetching team members", "team_id", team.ID, "error", err)
+		return
+	}
+	e.setOnlineMembers(team.ID, users)
+
+	pool := make([]int, 0, len(users))
+	for _, user := range users {
+		// Skip user if availability status is `away_manual` or `away_and_reassigning`
+		if user.AvailabilityStatus == umodels.AwayManual || user.AvailabilityStatus == umodels.AwayAndReassigning {
+			e.lo.Debug("user is away, skipping autoasssignment ", "team_id", team.ID, "user_id", user.ID, "availability_status", user.AvailabilityStatus)
+			continue
+		}
+		if user.OffShift {
+			e.lo.Debug("user is off shift, skipping autoasssignment ", "team_id", team.ID, "user_id", user.ID)
+			continue
+		}
+		e.userSkills[user.ID] = user.Skills
+		e.userCapacity[user.ID] = capacity{units: user.Capacity, weights: user.ChannelWeights}
+		if user.LastAutoAssignedAt.Time.After(e.lastAssignedAt[user.ID]) {
+			e.lastAssignedAt[user.ID] = user.LastAutoAssignedAt.Time
+		}
+		pool = append(pool, user.ID)
+	}
+	e.leastBusyPool[team.ID] = pool
+
+	// Set max auto assigned conversations for the team
+	e.teamMaxAutoAssignments[team.ID] = team.MaxAutoAssignedConversations
+}
+
+// setOnlineMembers replaces the online agents of a team.
+func (e *Engine) setOnlineMembers(teamID int, users []tmodels.TeamMember) {
+	online := make(map[int]struct{})
+	for _, user := range users {
+		if user.AvailabilityStatus == umodels.Online && !user.OffShift {
+			online[user.ID] = struct{}{}
+		}
+	}
+	e.onlineMembers[teamID] = online
+}
+
 // assignConversations function fetches conversations that have been assigned to teams but not to any individual user,
 // and then proceeds to assign them to team members based on a round-robin strategy.
 func (e *Engine) assignConversations() error {
@@ -205,14 +316,38 @@ func (e *Engine) assignConversations() error {
 		e.lo.Debug("found unassigned conversations", "count", len(unassignedConversations))
 	}
 
+	// Active conversation counts and capacity units in use of agents, fetched once per run and updated as conversations are assigned.
+	activeCounts := make(map[int]int)
+	loads := make(map[int]int)
+
 	for _, conv := range unassignedConversations {
 		teamID := conv.AssignedTeamID.Int
 		teamMax := e.teamMaxAutoAssignments[teamID]
-		poolSize := e.poolSize(teamID)
+		enforceSkills := e.skillsEnforced(conv, time.Now())
+
+		// Returning contacts go to the agent of their previous conversation when possible, otherwise the team's strategy applies.
+		if userID, ok := e.previousAgent(conv); ok && e.assignToPreviousAgent(conv, userID, teamMax, enforceSkills, activeCounts, loads) {
+			continue
+		}
+
+		if pool, ok := e.getLeastBusyPool(teamID); ok {
+			if enforceSkills {
+				pool = e.skilledUsers(pool, conv.RequiredSkills)
+			}
+			e.assignToLeastBusy(conv, pool, teamMax, activeCounts, loads)
+			continue
+		}
+
+		// Conversations requiring skills rotate through the agents of the team with the skills.
+		var required models.RequiredSkills
+		if enforceSkills {
+			required = conv.RequiredSkills
+		}
+		poolSize := e.poolSize(teamID, required)
 
 		// Try each user in the pool; skip capped users and retry on assignment failure.
 		for range poolSize {
-			userIDStr, err := e.getUserFromPool(teamID)
+			userIDStr, err := e.getUserFromPool(teamID, required)
 			if err != nil {
 				// Log other errors.
 				if err != ErrTeamNotFound && err != ErrNoUsersInPool {
@@ -239,6 +374,11 @@ func (e *Engine) assignConversations() error {
 				continue
 			}
 
+			if !e.hasCapacity(userID, conv.InboxChannel, loads) {
+				e.lo.Debug("user does not have capacity for the conversation, trying next user", "user_id", userID, "conversation_uuid", conv.UUID)
+				continue
+			}
+
 			if err := e.conversationStore.ClaimUnassignedConversation(conv.UUID, userID, teamID, e.systemUser); err != nil {
 				// Already assigned by someone else, stop trying.
 				if errors.Is(err, conversation.ErrConversationAlreadyAssigned) {
@@ -248,14 +388,257 @@ func (e *Engine) assignConversations() error {
 				e.lo.Error("error assigning conversation", "conversation_uuid", conv.UUID, "user_id", userID, "error", err)
 				continue
 			}
+			e.markAssigned(userID)
+			e.addLoad(userID, conv.InboxChannel, loads)
+			activeCounts[userID] = activeConversationsCount + 1
 			break
 		}
 	}
 	return nil
 }
 
-// getUserFromPool returns user ID from the team balancer pool.
-func (e *Engine) getUserFromPool(assignedTeamID int) (string, error) {
+// assignToLeastBusy assigns a conversation to the agent of the pool with the fewest active conversations, trying the next
+// least busy agent on assignment failure. Agents at the team's max auto assigned conversations or without capacity are skipped.
+func (e *Engine) assignToLeastBusy(conv models.Conversation, pool []int, teamMax int, activeCounts, loads map[int]int) {
+	teamID := conv.AssignedTeamID.Int
+	for _, userID := range pool {
+		if _, ok := activeCounts[userID]; ok {
+			continue
+		}
+		count, err := e.conversationStore.ActiveUserConversationsCount(userID)
+		if err != nil {
+			e.lo.Error("error fetching active conversations count for user", "user_id", userID, "error", err)
+			continue
+		}
+		activeCounts[userID] = count
+	}
+
+	e.balanceMu.Lock()
+	candidates := leastBusyOrder(pool, activeCounts, e.lastAssignedAt, teamMax)
+	e.balanceMu.Unlock()
+	if len(candidates) == 0 {
+		e.lo.Debug("all users have reached max auto assigned conversations limit", "team_id", teamID, "conversation_uuid", conv.UUID)
+		return
+	}
+
+	for _, userID := range candidates {
+		if !e.hasCapacity(userID, conv.InboxChannel, loads) {
+			e.lo.Debug("user does not have capacity for the conversation, trying next user", "user_id", userID, "conversation_uuid", conv.UUID)
+			continue
+		}
+		if err := e.conversationStore.ClaimUnassignedConversation(conv.UUID, userID, teamID, e.systemUser); err != nil {
+			// Already assigned by someone else, stop trying.
+			if errors.Is(err, conversation.ErrConversationAlreadyAssigned) {
+				e.lo.Debug("conversation already assigned, skipping", "conversation_uuid", conv.UUID)
+				return
+			}
+			e.lo.Error("error assigning conversation", "conversation_uuid", conv.UUID, "user_id", userID, "error", err)
+			continue
+		}
+		e.markAssigned(userID)
+		e.addLoad(userID, conv.InboxChannel, loads)
+		activeCounts[userID]++
+		return
+	}
+}
+
+// previousAgent returns the agent who handled the most recent previous conversation of the conversation's contact
+// if the team of the conversation has sticky assignment enabled.
+func (e *Engine) previousAgent(conv models.Conversation) (int, bool) {
+	e.balanceMu.Lock()
+	days := e.teamStickyDays[conv.AssignedTeamID.Int]
+	e.balanceMu.Unlock()
+	if days <= 0 || conv.ContactID == 0 {
+		return 0, false
+	}
+	previous, err := e.conversationStore.GetContactPreviousConversations(conv.ContactID, stickyLookback)
+	if err != nil {
+		e.lo.Error("error fetching contact previous conversations", "contact_id", conv.ContactID, "error", err)
+		return 0, false
+	}
+	return stickyAgent(conv, previous, time.Duration(days)*24*time.Hour)
+}
+
+// stickyAgent returns the agent assigned to the most recent of the previous conversations, ordered newest first, that started before
+// the conversation and has an agent. The previous conversation must have had activity within the window before the conversation started.
+func stickyAgent(conv models.Conversation, previous []models.PreviousConversation, window time.Duration) (int, bool) {
+	for _, p := range previous {
+		if p.UUID == conv.UUID || p.CreatedAt.After(conv.CreatedAt) || !p.AssignedUserID.Valid {
+			continue
+		}
+		lastActivity := p.CreatedAt
+		if p.LastMessageAt.Valid {
+			lastActivity = p.LastMessageAt.Time
+		}
+		if conv.CreatedAt.Sub(lastActivity) > window {
+			return 0, false
+		}
+		return p.AssignedUserID.Int, true
+	}
+	return 0, false
+}
+
+// assignToPreviousAgent assigns a conversation to the agent of the contact's previous conversation if they are online, in the team,
+// have the required skills and are under the team's max auto assigned conversations and their capacity.
+// It reports whether the conversation needs no further assignment.
+func (e *Engine) assignToPreviousAgent(conv models.Conversation, userID, teamMax int, enforceSkills bool, activeCounts, loads map[int]int) bool {
+	teamID := conv.AssignedTeamID.Int
+	e.balanceMu.Lock()
+	_, online := e.onlineMembers[teamID][userID]
+	e.balanceMu.Unlock()
+	if !online {
+		e.lo.Debug("previous agent is not online in the team, falling back to team strategy", "user_id", userID, "conversation_uuid", conv.UUID)
+		return false
+	}
+	if enforceSkills && len(e.skilledUsers([]int{userID}, conv.RequiredSkills)) == 0 {
+		e.lo.Debug("previous agent does not have the required skills, falling back to team strategy", "user_id", userID, "conversation_uuid", conv.UUID)
+		return false
+	}
+
+	count, ok := activeCounts[userID]
+	if !ok {
+		var err error
+		if count, err = e.conversationStore.ActiveUserConversationsCount(userID); err != nil {
+			e.lo.Error("error fetching active conversations count for user", "user_id", userID, "error", err)
+			return false
+		}
+		activeCounts[userID] = count
+	}
+	if teamMax != 0 && count >= teamMax {
+		e.lo.Debug("previous agent has reached max auto assigned conversations limit, falling back to team strategy", "user_id", userID, "conversation_uuid", conv.UUID)
+		return false
+	}
+	if !e.hasCapacity(userID, conv.InboxChannel, loads) {
+		e.lo.Debug("previous agent does not have capacity for the conversation, falling back to team strategy", "user_id", userID, "conversation_uuid", conv.UUID)
+		return false
+	}
+
+	if err := e.conversationStore.ClaimUnassignedConversation(conv.UUID, userID, teamID, e.systemUser); err != nil {
+		// Already assigned by someone else, stop trying.
+		if errors.Is(err, conversation.ErrConversationAlreadyAssigned) {
+			e.lo.Debug("conversation already assigned, skipping", "conversation_uuid", conv.UUID)
+			return true
+		}
+		e.lo.Error("error assigning conversation", "conversation_uuid", conv.UUID, "user_id", userID, "error", err)
+		return false
+	}
+	e.markAssigned(userID)
+	e.addLoad(userID, conv.InboxChannel, loads)
+	activeCounts[userID] = count + 1
+	return true
+}
+
+// leastBusyOrder returns the users that are under the max active conversations (0 is unlimited) ordered by their active conversations,
+// with ties broken by the longest time since last assignment and then by user ID. Users without an active count are skipped.
+func leastBusyOrder(users []int, activeCounts map[int]int, lastAssignedAt map[int]time.Time, maxActive int) []int {
+	candidates := make([]int, 0, len(users))
+	for _, userID := range users {
+		count, ok := activeCounts[userID]
+		if !ok || (maxActive != 0 && count >= maxActive) {
+			continue
+		}
+		candidates = append(candidates, userID)
+	}
+	slices.SortFunc(candidates, func(a, b int) int {
+		if activeCounts[a] != activeCounts[b] {
+			return activeCounts[a] - activeCounts[b]
+		}
+		if c := lastAssignedAt[a].Compare(lastAssignedAt[b]); c != 0 {
+			return c
+		}
+		return a - b
+	})
+	return candidates
+}
+
+// skillsEnforced reports whether a conversation may only be assigned to agents with its required skills at time now,
+// which is until the skill fallback of its team has passed since the skills were required.
+func (e *Engine) skillsEnforced(conv models.Conversation, now time.Time) bool {
+	if len(conv.RequiredSkills) == 0 {
+		return false
+	}
+	e.balanceMu.Lock()
+	fallback := e.teamSkillFallback[conv.AssignedTeamID.Int]
+	e.balanceMu.Unlock()
+	if fallback > 0 && conv.SkillsRequiredAt.Valid && now.Sub(conv.SkillsRequiredAt.Time) >= fallback {
+		return false
+	}
+	return true
+}
+
+// skilledUsers returns the users that have all the required skills.
+func (e *Engine) skilledUsers(users []int, required models.RequiredSkills) []int {
+	e.balanceMu.Lock()
+	defer e.balanceMu.Unlock()
+	skilled := make([]int, 0, len(users))
+	for _, userID := range users {
+		if hasRequiredSkills(e.userSkills[userID], required) {
+			skilled = append(skilled, userID)
+		}
+	}
+	return skilled
+}
+
+// hasRequiredSkills reports whether the skill proficiencies meet the minimum proficiency of every required skill.
+func hasRequiredSkills(skills tmodels.SkillProficiencies, required models.RequiredSkills) bool {
+	for _, r := range required {
+		if skills[r.SkillID] < r.MinProficiency {
+			return false
+		}
+	}
+	return true
+}
+
+// hasCapacity reports whether a user has the capacity units left for a conversation on the channel.
+// The units in use are computed from the user's open assigned conversations on first use and cached in loads.
+func (e *Engine) hasCapacity(userID int, channel string, loads map[int]int) bool {
+	e.balanceMu.Lock()
+	c := e.userCapacity[userID]
+	e.balanceMu.Unlock()
+	if c.units == 0 {
+		return true
+	}
+	load, ok := loads[userID]
+	if !ok {
+		counts, err := e.conversationStore.ActiveUserConversationsCountByChannel(userID)
+		if err != nil {
+			e.lo.Error("error fetching active conversations count by channel for user", "user_id", userID, "error", err)
+			return false
+		}
+		load = c.weights.Load(counts)
+		loads[userID] = load
+	}
+	return load+c.weights.Weight(channel) <= c.units
+}
+
+// addLoad adds the capacity units of a conversation on the channel to the units in use by a user.
+func (e *Engine) addLoad(userID int, channel string, loads map[int]int) {
+	e.balanceMu.Lock()
+	c := e.userCapacity[userID]
+	e.balanceMu.Unlock()
+	if c.units == 0 {
+		return
+	}
+	loads[userID] += c.weights.Weight(channel)
+}
+
+// markAssigned records the time a user was last auto assigned a conversation.
+func (e *Engine) markAssigned(userID int) {
+	e.balanceMu.Lock()
+	defer e.balanceMu.Unlock()
+	e.lastAssignedAt[userID] = time.Now()
+}
+
+// getLeastBusyPool returns the agents of a team using the least busy strategy.
+func (e *Engine) getLeastBusyPool(teamID int) ([]int, bool) {
+	e.balanceMu.Lock()
+	defer e.balanceMu.Unlock()
+	pool, ok := e.leastBusyPool[teamID]
+	return pool, ok
+}
+
+// getUserFromPool returns user ID from the team balancer pool, or from the balancer of the team's agents with the required skills.
+func (e *Engine) getUserFromPool(assignedTeamID int, required models.RequiredSkills) (string, error) {
 	e.balanceMu.Lock()
 	defer e.balanceMu.Unlock()
 
@@ -263,6 +646,9 @@ func (e *Engine) getUserFromPool(assignedTeamID int) (string, error) {
 	if !ok {
 		return "", ErrTeamNotFound
 	}
+	if len(required) > 0 {
+		pool = e.getSkillBalancer(assignedTeamID, required)
+	}
 	id := pool.Get()
 	// Empty id means the pool has no users (e.g. all team members are away).
 	if id == "" {
@@ -271,12 +657,64 @@ func (e *Engine) getUserFromPool(assignedTeamID int) (string, error) {
 	return id, nil
 }
 
-func (e *Engine) poolSize(teamID int) int {
+func (e *Engine) poolSize(teamID int, required models.RequiredSkills) int {
 	e.balanceMu.Lock()
 	defer e.balanceMu.Unlock()
 	pool, ok := e.roundRobinBalancer[teamID]
 	if !ok {
 		return 0
 	}
+	if len(required) > 0 {
+		pool = e.getSkillBalancer(teamID, required)
+	}
 	return len(pool.ItemIDs())
 }
+
+// getSkillBalancer returns the balancer of the agents of a team with the required skills, creating it on first use.
+// The caller must hold balanceMu.
+func (e *Engine) getSkillBalancer(teamID int, required models.RequiredSkills) *balance.Balance {
+	key := skillsKey(required)
+	if sb, ok := e.skillBalancers[teamID][key]; ok {
+		return sb.balancer
+	}
+	if e.skillBalancers[teamID] == nil {
+		e.skillBalancers[teamID] = make(map[string]skillBalancer)
+	}
+	sb := skillBalancer{required: required, balancer: balance.NewBalance()}
+	e.syncSkillBalancer(teamID, sb)
+	e.skillBalancers[teamID][key] = sb
+	return sb.balancer
+}
+
+// syncSkillBalancer adds the agents of the team pool with the required skills to a skill balancer and removes the rest.
+// The caller must hold balanceMu.
+func (e *Engine) syncSkillBalancer(teamID int, sb skillBalancer) {
+	skilled := make(map[string]struct{})
+	for _, id := range e.roundRobinBalancer[teamID].ItemIDs() {
+		userID, err := strconv.Atoi(id)
+		if err != nil || !hasRequiredSkills(e.userSkills[userID], sb.required) {
+			continue
+		}
+		skilled[id] = struct{}{}
+		if err := sb.balancer.Add(id, 1); err != nil && err != balance.ErrDuplicateID {
+			e.lo.Error("error adding user to skill balancer pool", "team_id", teamID, "user_id", id, "error", err)
+		}
+	}
+	for _, id := range sb.balancer.ItemIDs() {
+		if _, ok := skilled[id]; !ok {
+			if err := sb.balancer.Remove(id); err != nil {
+				e.lo.Error("error removing user from skill balancer pool", "team_id", teamID, "user_id", id, "error", err)
+			}
+		}
+	}
+}
+
+// skillsKey returns a key identifying a set of required skills regardless of their order.
+func skillsKey(required models.RequiredSkills) string {
+	parts := make([]string, 0, len(required))
+	for _, r := range required {
+		parts = append(parts, strconv.Itoa(r.SkillID)+":"+strconv.Itoa(r.MinProficiency))
+	}
+	slices.Sort(parts)
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,429 @@
+package autoassigner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abhinavxd/libredesk/internal/conversation"
+	"github.com/abhinavxd/libredesk/internal/conversation/models"
+	tmodels "github.com/abhinavxd/libredesk/internal/team/models"
+	umodels "github.com/abhinavxd/libredesk/internal/user/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/null/v9"
+	"github.com/zerodha/logf"
+)
+
+type mockConversationStore struct {
+	unassigned    []models.Conversation
+	activeCount   map[int]int
+	channelCounts map[int]map[string]int
+	previous      map[int][]models.PreviousConversation
+	assigned      map[string]int
+	taken         map[string]bool
+}
+
+func (m *mockConversationStore) GetUnassignedConversations() ([]models.Conversation, error) {
+	var out []models.Conversation
+	for _, c := range m.unassigned {
+		if _, ok := m.assigned[c.UUID]; !ok {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockConversationStore) ClaimUnassignedConversation(conversationUUID string, userID, expectedTeamID int, user umodels.User) error {
+	if m.taken[conversationUUID] {
+		return conversation.ErrConversationAlreadyAssigned
+	}
+	m.assigned[conversationUUID] = userID
+	m.activeCount[userID]++
+	return nil
+}
+
+func (m *mockConversationStore) ActiveUserConversationsCount(userID int) (int, error) {
+	return m.activeCount[userID], nil
+}
+
+func (m *mockConversationStore) ActiveUserConversationsCountByChannel(userID int) (map[string]int, error) {
+	return m.channelCounts[userID], nil
+}
+
+func (m *mockConversationStore) GetContactPreviousConversations(contactID int, limit int) ([]models.PreviousConversation, error) {
+	return m.previous[contactID], nil
+}
+
+type mockTeamStore struct {
+	teams   []tmodels.Team
+	members map[int][]tmodels.TeamMember
+}
+
+func (m *mockTeamStore) GetAll() ([]tmodels.Team, error) {
+	return m.teams, nil
+}
+
+func (m *mockTeamStore) GetMembers(teamID int) ([]tmodels.TeamMember, error) {
+	return m.members[teamID], nil
+}
+
+func newLeastBusyEngine(t *testing.T, maxAssigned int, members []tmodels.TeamMember, activeCount map[int]int, conversations int) (*Engine, *mockConversationStore) {
+	t.Helper()
+	convStore := &mockConversationStore{
+		activeCount: activeCount,
+		assigned:    make(map[string]int),
+		taken:       make(map[string]bool),
+	}
+	for i := range conversations {
+		convStore.unassigned = append(convStore.unassigned, models.Conversation{
+			UUID:           string(rune('a' + i)),
+			AssignedTeamID: null.IntFrom(1),
+		})
+	}
+	teamStore := &mockTeamStore{
+		teams:   []tmodels.Team{{ID: 1, ConversationAssignmentType: AssignmentTypeLeastBusy, MaxAutoAssignedConversations: maxAssigned}},
+		members: map[int][]tmodels.TeamMember{1: members},
+	}
+	lo := logf.New(logf.Opts{})
+	e, err := New(teamStore, convStore, umodels.User{ID: 1}, &lo)
+	assert.NoError(t, err)
+	assert.NoError(t, e.reloadBalancer())
+	return e, convStore
+}
+
+func TestLeastBusyOrder(t *testing.T) {
+	now := time.Now()
+	counts := map[int]int{1: 3, 2: 1, 3: 1, 4: 0, 5: 5}
+	lastAssigned := map[int]time.Time{
+		2: now.Add(-time.Minute),
+		3: now.Add(-time.Hour),
+	}
+
+	// Fewest active conversations first, ties go to the agent assigned longest ago.
+	assert.Equal(t, []int{4, 3, 2, 1, 5}, leastBusyOrder([]int{1, 2, 3, 4, 5}, counts, lastAssigned, 0))
+
+	// Agents at the max are skipped, as are agents whose count is unknown.
+	assert.Equal(t, []int{4, 3, 2}, leastBusyOrder([]int{1, 2, 3, 4, 5, 6}, counts, lastAssigned, 3))
+
+	// Agents never assigned before go first on a tie, in order of ID.
+	assert.Equal(t, []int{6, 7, 2}, leastBusyOrder([]int{2, 7, 6}, map[int]int{2: 0, 6: 0, 7: 0}, lastAssigned, 0))
+}
+
+func TestLeastBusyAssignmentEvensOutLoad(t *testing.T) {
+	members := []tmodels.TeamMember{{ID: 10}, {ID: 11}, {ID: 12}}
+	e, convStore := newLeastBusyEngine(t, 0, members, map[int]int{10: 4, 11: 1, 12: 0}, 6)
+
+	assert.NoError(t, e.assignConversations())
+	assert.Len(t, convStore.assigned, 6)
+
+	// The busiest agent gets nothing until the others catch up, leaving everyone within one conversation of each other.
+	assert.Equal(t, map[int]int{10: 4, 11: 4, 12: 3}, convStore.activeCount)
+}
+
+func TestLeastBusyAssignmentRotatesOnTies(t *testing.T) {
+	members := []tmodels.TeamMember{{ID: 10}, {ID: 11}, {ID: 12}}
+	e, convStore := newLeastBusyEngine(t, 0, members, map[int]int{10: 0, 11: 0, 12: 0}, 3)
+
+	assert.NoError(t, e.assignConversations())
+
+	// Equally busy agents each get one conversation instead of the same agent winning every tie.
+	assert.Equal(t, map[int]int{10: 1, 11: 1, 12: 1}, convStore.activeCount)
+}
+
+func TestLeastBusyAssignmentUsesStoredLastAssignment(t *testing.T) {
+	now := time.Now()
+	members := []tmodels.TeamMember{
+		{ID: 10, LastAutoAssignedAt: null.TimeFrom(now.Add(-time.Minute))},
+		{ID: 11, LastAutoAssignedAt: null.TimeFrom(now.Add(-time.Hour))},
+		{ID: 12, LastAutoAssignedAt: null.TimeFrom(now.Add(-2 * time.Minute))},
+	}
+	e, convStore := newLeastBusyEngine(t, 0, members, map[int]int{10: 0, 11: 0, 12: 0}, 2)
+
+	assert.NoError(t, e.assignConversations())
+
+	// After a restart, ties go to the agents assigned longest ago as recorded in the DB.
+	assert.Equal(t, map[string]int{"a": 11, "b": 12}, convStore.assigned)
+
+	// A reload doesn't move the in memory assignment times back.
+	assert.NoError(t, e.reloadBalancer())
+	assert.True(t, e.lastAssignedAt[11].After(now))
+}
+
+func TestLeastBusyAssignmentRespectsMaxAndAvailability(t *testing.T) {
+	members := []tmodels.TeamMember{
+		{ID: 10},
+		{ID: 11},
+		{ID: 12, AvailabilityStatus: umodels.AwayManual},
+	}
+	e, convStore := newLeastBusyEngine(t, 2, members, map[int]int{10: 1, 11: 2, 12: 0}, 3)
+
+	assert.NoError(t, e.assignConversations())
+
+	// Only one agent is under the max and away agents are never picked, the rest stay unassigned.
+	assert.Len(t, convStore.assigned, 1)
+	assert.Equal(t, map[int]int{10: 2, 11: 2, 12: 0}, convStore.activeCount)
+}
+
+func TestLeastBusyAssignmentSkipsTakenConversations(t *testing.T) {
+	members := []tmodels.TeamMember{{ID: 10}, {ID: 11}}
+	e, convStore := newLeastBusyEngine(t, 0, members, map[int]int{10: 0, 11: 0}, 2)
+	convStore.taken["a"] = true
+
+	assert.NoError(t, e.assignConversations())
+
+	// A conversation claimed by someone else does not count towards any agent.
+	assert.Equal(t, map[string]int{"b": 10}, convStore.assigned)
+	assert.Equal(t, map[int]int{10: 1, 11: 0}, convStore.activeCount)
+}
+
+func TestHasRequiredSkills(t *testing.T) {
+	skills := tmodels.SkillProficiencies{1: 3, 2: 5}
+
+	assert.True(t, hasRequiredSkills(skills, nil))
+	assert.True(t, hasRequiredSkills(skills, models.RequiredSkills{{SkillID: 1, MinProficiency: 3}, {SkillID: 2, MinProficiency: 1}}))
+
+	// A lower proficiency or a missing skill fails the requirement.
+	assert.False(t, hasRequiredSkills(skills, models.RequiredSkills{{SkillID: 1, MinProficiency: 4}}))
+	assert.False(t, hasRequiredSkills(skills, models.RequiredSkills{{SkillID: 1, MinProficiency: 1}, {SkillID: 3, MinProficiency: 1}}))
+	assert.False(t, hasRequiredSkills(nil, models.RequiredSkills{{SkillID: 1, MinProficiency: 1}}))
+}
+
+func TestSkillsEnforcedUntilFallback(t *testing.T) {
+	now := time.Now()
+	e := &Engine{teamSkillFallback: map[int]time.Duration{1: 10 * time.Minute, 2: 0}}
+	required := models.RequiredSkills{{SkillID: 1, MinProficiency: 1}}
+	conv := func(teamID int, requiredAt time.Time) models.Conversation {
+		return models.Conversation{AssignedTeamID: null.IntFrom(teamID), RequiredSkills: required, SkillsRequiredAt: null.TimeFrom(requiredAt)}
+	}
+
+	assert.False(t, e.skillsEnforced(models.Conversation{AssignedTeamID: null.IntFrom(1)}, now))
+	assert.True(t, e.skillsEnforced(conv(1, now.Add(-9*time.Minute)), now))
+	assert.False(t, e.skillsEnforced(conv(1, now.Add(-10*time.Minute)), now))
+
+	// Teams without a fallback wait for a skilled agent forever.
+	assert.True(t, e.skillsEnforced(conv(2, now.Add(-24*time.Hour)), now))
+}
+
+func TestLeastBusyAssignmentRequiresSkills(t *testing.T) {
+	members := []tmodels.TeamMember{
+		{ID: 10},
+		{ID: 11, Skills: tmodels.SkillProficiencies{1: 2}},
+		{ID: 12, Skills: tmodels.SkillProficiencies{1: 4}},
+	}
+	e, convStore := newLeastBusyEngine(t, 0, members, map[int]int{10: 0, 11: 0, 12: 5}, 2)
+	convStore.unassigned[0].RequiredSkills = models.RequiredSkills{{SkillID: 1, MinProficiency: 3}}
+	convStore.unassigned[0].SkillsRequiredAt = null.TimeFrom(time.Now())
+
+	assert.NoError(t, e.assignConversations())
+
+	// The skilled agent gets the conversation requiring the skill even though they are the busiest, the other goes to anyone.
+	assert.Equal(t, map[string]int{"a": 12, "b": 10}, convStore.assigned)
+}
+
+func TestRoundRobinAssignmentRequiresSkillsUntilFallback(t *testing.T) {
+	convStore := &mockConversationStore{
+		unassigned: []models.Conversation{{
+			UUID:             "a",
+			AssignedTeamID:   null.IntFrom(1),
+			RequiredSkills:   models.RequiredSkills{{SkillID: 1, MinProficiency: 1}},
+			SkillsRequiredAt: null.TimeFrom(time.Now().Add(-time.Minute)),
+		}},
+		activeCount: map[int]int{10: 0},
+		assigned:    make(map[string]int),
+		taken:       make(map[string]bool),
+	}
+	teamStore := &mockTeamStore{
+		teams:   []tmodels.Team{{ID: 1, ConversationAssignmentType: AssignmentTypeRoundRobin, SkillFallbackMinutes: 5}},
+		members: map[int][]tmodels.TeamMember{1: {{ID: 10}}},
+	}
+	lo := logf.New(logf.Opts{})
+	e, err := New(teamStore, convStore, umodels.User{ID: 1}, &lo)
+	assert.NoError(t, err)
+	assert.NoError(t, e.reloadBalancer())
+
+	// Nobody in the team has the skill, so the conversation waits.
+	assert.NoError(t, e.assignConversations())
+	assert.Empty(t, convStore.assigned)
+
+	// Once the fallback has passed it goes to the team pool.
+	convStore.unassigned[0].SkillsRequiredAt = null.TimeFrom(time.Now().Add(-5 * time.Minute))
+	assert.NoError(t, e.assignConversations())
+	assert.Equal(t, map[string]int{"a": 10}, convStore.assigned)
+}
+
+func TestRoundRobinSkillBalancer(t *testing.T) {
+	var (
+		required = models.RequiredSkills{{SkillID: 1, MinProficiency: 2}}
+		members  = []tmodels.TeamMember{
+			{ID: 10},
+			{ID: 11, Skills: tmodels.SkillProficiencies{1: 2}},
+			{ID: 12, Skills: tmodels.SkillProficiencies{1: 3}},
+		}
+		teamStore = &mockTeamStore{
+			teams:   []tmodels.Team{{ID: 1, ConversationAssignmentType: AssignmentTypeRoundRobin}},
+			members: map[int][]tmodels.TeamMember{1: members},
+		}
+		lo = logf.New(logf.Opts{})
+	)
+	e, err := New(teamStore, &mockConversationStore{}, umodels.User{ID: 1}, &lo)
+	assert.NoError(t, err)
+	assert.NoError(t, e.reloadBalancer())
+
+	// Only the skilled agents take turns, without advancing the team balancer.
+	assert.Equal(t, 2, e.poolSize(1, required))
+	picked := make(map[string]int)
+	for range 4 {
+		id, err := e.getUserFromPool(1, required)
+		assert.NoError(t, err)
+		picked[id]++
+	}
+	assert.Equal(t, map[string]int{"11": 2, "12": 2}, picked)
+	assert.Equal(t, 3, e.poolSize(1, nil))
+
+	// The skill balancer follows skill changes on reload.
+	// The pool shuffles the members in place.
+	for i := range members {
+		if members[i].ID == 11 {
+			members[i].Skills = nil
+		}
+	}
+	assert.NoError(t, e.reloadBalancer())
+	assert.Equal(t, 1, e.poolSize(1, required))
+	id, err := e.getUserFromPool(1, required)
+	assert.NoError(t, err)
+	assert.Equal(t, "12", id)
+
+	assert.Equal(t, skillsKey(models.RequiredSkills{{SkillID: 2, MinProficiency: 1}, {SkillID: 1, MinProficiency: 3}}),
+		skillsKey(models.RequiredSkills{{SkillID: 1, MinProficiency: 3}, {SkillID: 2, MinProficiency: 1}}))
+}
+
+func TestChannelWeights(t *testing.T) {
+	weights := tmodels.ChannelWeights{"livechat": 4}
+
+	// Channels without a weight take one unit.
+	assert.Equal(t, 4, weights.Weight("livechat"))
+	assert.Equal(t, 1, weights.Weight("email"))
+	assert.Equal(t, 1, tmodels.ChannelWeights(nil).Weight("livechat"))
+
+	assert.Equal(t, 11, weights.Load(map[string]int{"livechat": 2, "email": 3}))
+	assert.Equal(t, 0, weights.Load(nil))
+}
+
+func TestAssignmentRespectsChannelCapacity(t *testing.T) {
+	weights := tmodels.ChannelWeights{"livechat": 4, "email": 1}
+	members := []tmodels.TeamMember{
+		// 16 of 20 units in use, room for one more chat or four emails.
+		{ID: 10, Capacity: 20, ChannelWeights: weights},
+		// Unlimited capacity.
+		{ID: 11},
+	}
+	for _, assignmentType := range []string{AssignmentTypeLeastBusy, AssignmentTypeRoundRobin} {
+		e, convStore := newLeastBusyEngine(t, 0, members, map[int]int{10: 0, 11: 5}, 0)
+		e.teamStore.(*mockTeamStore).teams[0].ConversationAssignmentType = assignmentType
+		assert.NoError(t, e.reloadBalancer())
+		convStore.channelCounts = map[int]map[string]int{10: {"livechat": 3, "email": 4}}
+		for i, channel := range []string{"livechat", "livechat", "email"} {
+			convStore.unassigned = append(convStore.unassigned, models.Conversation{
+				UUID:           string(rune('a' + i)),
+				AssignedTeamID: null.IntFrom(1),
+				InboxChannel:   channel,
+			})
+		}
+
+		assert.NoError(t, e.assignConversations())
+
+		// Everything is assigned without agent 10 going over its 4 units left.
+		assert.Len(t, convStore.assigned, 3, assignmentType)
+		var added int
+		for _, conv := range convStore.unassigned {
+			if convStore.assigned[conv.UUID] == 10 {
+				added += weights.Weight(conv.InboxChannel)
+			}
+		}
+		assert.LessOrEqual(t, added, 4, assignmentType)
+
+		// The least busy agent 10 takes the first chat which fills it, the rest go to the agent without a capacity limit.
+		if assignmentType == AssignmentTypeLeastBusy {
+			assert.Equal(t, map[string]int{"a": 10, "b": 11, "c": 11}, convStore.assigned)
+		}
+	}
+}
+
+func TestStickyAgent(t *testing.T) {
+	now := time.Now()
+	conv := models.Conversation{UUID: "new", CreatedAt: now}
+	window := 7 * 24 * time.Hour
+
+	// The conversation itself and previous conversations without an agent are skipped.
+	agent, ok := stickyAgent(conv, []models.PreviousConversation{
+		{UUID: "new", CreatedAt: now},
+		{UUID: "b", CreatedAt: now.Add(-time.Hour)},
+		{UUID: "c", CreatedAt: now.Add(-48 * time.Hour), AssignedUserID: null.IntFrom(10)},
+		{UUID: "d", CreatedAt: now.Add(-72 * time.Hour), AssignedUserID: null.IntFrom(11)},
+	}, window)
+	assert.True(t, ok)
+	assert.Equal(t, 10, agent)
+
+	// Activity on the previous conversation counts towards the window, not just when it started.
+	agent, ok = stickyAgent(conv, []models.PreviousConversation{
+		{UUID: "b", CreatedAt: now.Add(-30 * 24 * time.Hour), LastMessageAt: null.TimeFrom(now.Add(-24 * time.Hour)), AssignedUserID: null.IntFrom(12)},
+	}, window)
+	assert.True(t, ok)
+	assert.Equal(t, 12, agent)
+
+	_, ok = stickyAgent(conv, []models.PreviousConversation{
+		{UUID: "b", CreatedAt: now.Add(-8 * 24 * time.Hour), AssignedUserID: null.IntFrom(10)},
+	}, window)
+	assert.False(t, ok)
+
+	_, ok = stickyAgent(conv, nil, window)
+	assert.False(t, ok)
+}
+
+func TestStickyAssignment(t *testing.T) {
+	members := []tmodels.TeamMember{
+		{ID: 10, AvailabilityStatus: umodels.Online},
+		{ID: 11, AvailabilityStatus: umodels.Online},
+		{ID: 12, AvailabilityStatus: umodels.Offline},
+	}
+	e, convStore := newLeastBusyEngine(t, 3, members, map[int]int{10: 0, 11: 2, 12: 2}, 0)
+	e.teamStore.(*mockTeamStore).teams[0].StickyAssignmentDays = 7
+	assert.NoError(t, e.reloadBalancer())
+
+	now := time.Now()
+	previous := func(userID int) []models.PreviousConversation {
+		return []models.PreviousConversation{{UUID: "old", CreatedAt: now.Add(-24 * time.Hour), AssignedUserID: null.IntFrom(userID)}}
+	}
+	convStore.previous = map[int][]models.PreviousConversation{1: previous(11), 2: previous(12), 3: previous(11)}
+	for i, contactID := range []int{1, 2, 3} {
+		convStore.unassigned = append(convStore.unassigned, models.Conversation{
+			UUID:           string(rune('a' + i)),
+			ContactID:      contactID,
+			CreatedAt:      now,
+			AssignedTeamID: null.IntFrom(1),
+		})
+	}
+
+	assert.NoError(t, e.assignConversations())
+
+	// The previous agent gets the conversation even though they are busier, until they reach the max.
+	// Conversations of an offline previous agent or one at the max go to the least busy agent instead.
+	assert.Equal(t, map[string]int{"a": 11, "b": 10, "c": 10}, convStore.assigned)
+}
+
+func TestAssignmentSkipsOffShiftAgents(t *testing.T) {
+	members := []tmodels.TeamMember{{ID: 10, OffShift: true}, {ID: 11}}
+	for _, assignmentType := range []string{AssignmentTypeLeastBusy, AssignmentTypeRoundRobin} {
+		e, convStore := newLeastBusyEngine(t, 0, members, map[int]int{10: 0, 11: 5}, 0)
+		e.teamStore.(*mockTeamStore).teams[0].ConversationAssignmentType = assignmentType
+		assert.NoError(t, e.reloadBalancer())
+		convStore.unassigned = []models.Conversation{
+			{UUID: "a", AssignedTeamID: null.IntFrom(1)},
+			{UUID: "b", AssignedTeamID: null.IntFrom(1)},
+		}
+
+		assert.NoError(t, e.assignConversations())
+
+		// Agents outside their shift are left out of the pool even when they are the least busy.
+		assert.Equal(t, map[string]int{"a": 11, "b": 11}, convStore.assigned, assignmentType)
+	}
+}
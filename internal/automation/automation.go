@@ -15,6 +15,7 @@ import (
 	cmodels "github.com/abhinavxd/libredesk/internal/conversation/models"
 	"github.com/abhinavxd/libredesk/internal/dbutil"
 	"github.com/abhinavxd/libredesk/internal/envelope"
+	slamodels "github.com/abhinavxd/libredesk/internal/sla/models"
 	umodels "github.com/abhinavxd/libredesk/internal/user/models"
 	"github.com/jmoiron/sqlx"
 	"github.com/knadh/go-i18n"
@@ -36,6 +37,9 @@ const (
 	NewConversation    TaskType = "new"
 	UpdateConversation TaskType = "update"
 	TimeTrigger        TaskType = "time-trigger"
+	ScheduledActions   TaskType = "scheduled-actions"
+	Schedules          TaskType = "schedules"
+	SLASelection       TaskType = "sla-selection"
 )
 
 // ConversationTask represents a unit of work for processing conversations.
@@ -49,11 +53,19 @@ type ConversationTask struct {
 type Engine struct {
 	rules             []models.Rule
 	rulesMu           sync.RWMutex
+	db                *sqlx.DB
 	q                 queries
 	lo                *logf.Logger
 	i18n              *i18n.I18n
 	conversationStore conversationStore
+	businessHours     businessHoursStore
+	slaStore          slaStore
 	systemUserID      int
+
+	// slaPolicies caches the policies of slaStore for selection, reloaded when a policy changes.
+	slaPolicies       []slamodels.SLAPolicy
+	slaPoliciesLoaded bool
+	slaPoliciesMu     sync.RWMutex
 	taskQueue         chan ConversationTask
 	closed            bool
 	closedMu          sync.RWMutex
@@ -61,23 +73,47 @@ type Engine struct {
 
 	suppressed   map[string]int
 	suppressedMu sync.Mutex
+
+	aiTriageQueue  chan aiTriageTask
+	aiTriageClosed bool
+	aiTriageMu     sync.RWMutex
+
+	executions         chan models.Execution
+	executionRetention time.Duration
 }
 
 type Opts struct {
 	DB   *sqlx.DB
 	Lo   *logf.Logger
 	I18n *i18n.I18n
+	// ExecutionLogRetention is how long rule execution records are kept.
+	ExecutionLogRetention time.Duration
 }
 
 type conversationStore interface {
 	ApplyAction(action models.RuleAction, conversation cmodels.Conversation, user umodels.User) error
 	GetConversation(teamID int, uuid, refNum string) (cmodels.Conversation, error)
 	GetConversationsCreatedAfter(time.Time) ([]cmodels.Conversation, error)
+	GetRecentConversations(limit int) ([]cmodels.Conversation, error)
+	GetConversationAutomationStats(conversationID int) (cmodels.AutomationStats, error)
+	GetConversationsByFilters(filtersJSON string, limit int) ([]cmodels.Conversation, error)
+	ValidateListFilters(filtersJSON string) error
+	SendOutOfOfficeReply(conversation cmodels.Conversation) error
+	SendAutomationDigest(subject, message string, recipients []string, conversations []cmodels.Conversation) error
+}
+
+type businessHoursStore interface {
+	IsWithinBusinessHours(assignedTeamID, inboxID int, t time.Time) (bool, error)
+}
+
+type slaStore interface {
+	GetAll() ([]slamodels.SLAPolicy, error)
 }
 
 type queries struct {
 	GetAll                  *sqlx.Stmt `query:"get-all"`
 	GetRule                 *sqlx.Stmt `query:"get-rule"`
+	GetAllRules             *sqlx.Stmt `query:"get-all-rules"`
 	InsertRule              *sqlx.Stmt `query:"insert-rule"`
 	UpdateRule              *sqlx.Stmt `query:"update-rule"`
 	DeleteRule              *sqlx.Stmt `query:"delete-rule"`
@@ -85,6 +121,34 @@ type queries struct {
 	GetEnabledRules         *sqlx.Stmt `query:"get-enabled-rules"`
 	UpdateRuleWeight        *sqlx.Stmt `query:"update-rule-weight"`
 	UpdateRuleExecutionMode *sqlx.Stmt `query:"update-rule-execution-mode"`
+
+	InsertExecution           *sqlx.Stmt `query:"insert-execution"`
+	GetRuleExecutions         *sqlx.Stmt `query:"get-rule-executions"`
+	GetConversationExecutions *sqlx.Stmt `query:"get-conversation-executions"`
+	DeleteExecutionsBefore    *sqlx.Stmt `query:"delete-executions-before"`
+
+	InsertScheduledAction                *sqlx.Stmt `query:"insert-scheduled-action"`
+	ClaimDueScheduledActions             *sqlx.Stmt `query:"claim-due-scheduled-actions"`
+	ResetRunningScheduledActions         *sqlx.Stmt `query:"reset-running-scheduled-actions"`
+	GetPendingScheduledActions           *sqlx.Stmt `query:"get-pending-scheduled-actions"`
+	GetConversationScheduledActions      *sqlx.Stmt `query:"get-conversation-scheduled-actions"`
+	UpdateScheduledActionStatus          *sqlx.Stmt `query:"update-scheduled-action-status"`
+	UpdateScheduledActionPreviousValues  *sqlx.Stmt `query:"update-scheduled-action-previous-values"`
+	DeleteFinishedScheduledActionsBefore *sqlx.Stmt `query:"delete-finished-scheduled-actions-before"`
+
+	InsertRuleVersion *sqlx.Stmt `query:"insert-rule-version"`
+	GetRuleVersions   *sqlx.Stmt `query:"get-rule-versions"`
+	GetRuleVersion    *sqlx.Stmt `query:"get-rule-version"`
+	GetRuleReferences *sqlx.Stmt `query:"get-rule-references"`
+
+	GetSchedules            *sqlx.Stmt `query:"get-schedules"`
+	GetSchedule             *sqlx.Stmt `query:"get-schedule"`
+	InsertSchedule          *sqlx.Stmt `query:"insert-schedule"`
+	UpdateSchedule          *sqlx.Stmt `query:"update-schedule"`
+	DeleteSchedule          *sqlx.Stmt `query:"delete-schedule"`
+	GetDueSchedules         *sqlx.Stmt `query:"get-due-schedules"`
+	ClaimScheduleRun        *sqlx.Stmt `query:"claim-schedule-run"`
+	UpdateScheduleRunResult *sqlx.Stmt `query:"update-schedule-run-result"`
 }
 
 // New initializes a new Engine.
@@ -92,9 +156,13 @@ func New(opt Opts) (*Engine, error) {
 	var (
 		q queries
 		e = &Engine{
-			lo:        opt.Lo,
-			i18n:      opt.I18n,
-			taskQueue: make(chan ConversationTask, MaxQueueSize),
+			db:                 opt.DB,
+			lo:                 opt.Lo,
+			i18n:               opt.I18n,
+			taskQueue:          make(chan ConversationTask, MaxQueueSize),
+			executions:         make(chan models.Execution, MaxQueueSize),
+			aiTriageQueue:      make(chan aiTriageTask, aiTriageQueueSize),
+			executionRetention: opt.ExecutionLogRetention,
 		}
 	)
 	if err := dbutil.ScanSQLFile("queries.sql", &q, opt.DB, efs); err != nil {
@@ -110,6 +178,11 @@ func (e *Engine) SetConversationStore(store conversationStore) {
 	e.conversationStore = store
 }
 
+// SetBusinessHoursStore sets the store used to evaluate business hours conditions.
+func (e *Engine) SetBusinessHoursStore(store businessHoursStore) {
+	e.businessHours = store
+}
+
 // SetSystemUserID sets the system user ID used to identify events raised by automation actions.
 func (e *Engine) SetSystemUserID(id int) {
 	e.systemUserID = id
@@ -131,23 +204,56 @@ func (e *Engine) Run(ctx context.Context, workerCount int) {
 		go e.worker(ctx)
 	}
 
+	// AI triage worker.
+	e.wg.Add(1)
+	go e.aiTriageWorker(ctx)
+
+	// Execution log writer.
+	go e.runExecutionLog(ctx)
+
+	// Retry delayed actions left running by a previous shutdown.
+	if _, err := e.q.ResetRunningScheduledActions.Exec(); err != nil {
+		e.lo.Error("error resetting running scheduled actions", "error", err)
+	}
+
 	// Hourly ticker for timed triggers.
 	ticker := time.NewTicker(1 * time.Hour)
 	defer func() {
 		ticker.Stop()
 	}()
 
+	// Ticker for delayed actions that are due.
+	scheduledTicker := time.NewTicker(scheduledActionsInterval)
+	defer scheduledTicker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
 			e.lo.Info("queuing time triggers")
-			e.taskQueue <- ConversationTask{taskType: TimeTrigger}
+			e.enqueueTask(ConversationTask{taskType: TimeTrigger})
+		case <-scheduledTicker.C:
+			e.enqueueTask(ConversationTask{taskType: ScheduledActions})
+			e.enqueueTask(ConversationTask{taskType: Schedules})
 		}
 	}
 }
 
+// enqueueTask queues a periodic task without blocking, dropping it if the queue is full as the next tick queues it again.
+func (e *Engine) enqueueTask(task ConversationTask) {
+	e.closedMu.RLock()
+	defer e.closedMu.RUnlock()
+	if e.closed {
+		return
+	}
+	select {
+	case e.taskQueue <- task:
+	default:
+		e.lo.Warn("automation task queue is full, dropping task", "task_type", task.taskType)
+	}
+}
+
 // worker processes tasks from the taskQueue until it's closed or context is done.
 func (e *Engine) worker(ctx context.Context) {
 	defer e.wg.Done()
@@ -166,6 +272,12 @@ func (e *Engine) worker(ctx context.Context) {
 				e.handleUpdateConversation(task.conversation, task.eventType, task.previousValues)
 			case TimeTrigger:
 				e.handleTimeTrigger()
+			case ScheduledActions:
+				e.handleScheduledActions()
+			case Schedules:
+				e.handleSchedules()
+			case SLASelection:
+				e.selectSLAPolicy(task.conversation)
 			}
 		}
 	}
@@ -180,6 +292,10 @@ func (e *Engine) Close() {
 	}
 	e.closed = true
 	close(e.taskQueue)
+	e.aiTriageMu.Lock()
+	e.aiTriageClosed = true
+	close(e.aiTriageQueue)
+	e.aiTriageMu.Unlock()
 	// Wait for all workers.
 	e.wg.Wait()
 }
@@ -219,36 +335,93 @@ func (e *Engine) ToggleRule(id int) (models.RuleRecord, error) {
 	return result, nil
 }
 
-// UpdateRule updates an existing rule.
-func (e *Engine) UpdateRule(id int, rule models.RuleRecord) (models.RuleRecord, error) {
+// UpdateRule updates an existing rule and saves the result as a new version authored by authorID.
+func (e *Engine) UpdateRule(id int, rule models.RuleRecord, authorID int) (models.RuleRecord, error) {
+	var result models.RuleRecord
+	if err := e.withTx(func(tx *sqlx.Tx) error {
+		var err error
+		result, err = e.updateRule(tx, id, rule, authorID)
+		return err
+	}); err != nil {
+		return models.RuleRecord{}, err
+	}
+	// Reload rules.
+	e.ReloadRules()
+	return result, nil
+}
+
+// CreateRule creates a new rule and saves it as the first version authored by authorID.
+func (e *Engine) CreateRule(rule models.RuleRecord, authorID int) (models.RuleRecord, error) {
+	rule.Enabled = true
+	var result models.RuleRecord
+	if err := e.withTx(func(tx *sqlx.Tx) error {
+		var err error
+		result, err = e.createRule(tx, rule, authorID)
+		return err
+	}); err != nil {
+		return models.RuleRecord{}, err
+	}
+	// Reload rules.
+	e.ReloadRules()
+	return result, nil
+}
+
+// updateRule updates a rule and saves its new version within tx.
+func (e *Engine) updateRule(tx *sqlx.Tx, id int, rule models.RuleRecord, authorID int) (models.RuleRecord, error) {
 	if rule.Events == nil {
 		rule.Events = pq.StringArray{}
 	}
+	var previous models.RuleRecord
+	if err := tx.Stmtx(e.q.GetRule).Get(&previous, id); err != nil && err != sql.ErrNoRows {
+		e.lo.Error("error fetching rule", "error", err)
+		return models.RuleRecord{}, envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
 	var result models.RuleRecord
-	if err := e.q.UpdateRule.Get(&result, id, rule.Name, rule.Description, rule.Type, rule.Events, rule.Rules, rule.Enabled); err != nil {
+	if err := tx.Stmtx(e.q.UpdateRule).Get(&result, id, rule.Name, rule.Description, rule.Type, rule.Events, rule.Rules, rule.Enabled); err != nil {
 		e.lo.Error("error updating rule", "error", err)
 		return models.RuleRecord{}, envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
 	}
-	// Reload rules.
-	e.ReloadRules()
+	if err := e.saveVersion(tx, result, ruleDiff(previous, result), authorID); err != nil {
+		return models.RuleRecord{}, err
+	}
 	return result, nil
 }
 
-// CreateRule creates a new rule.
-func (e *Engine) CreateRule(rule models.RuleRecord) (models.RuleRecord, error) {
+// createRule inserts a rule and saves its first version within tx.
+func (e *Engine) createRule(tx *sqlx.Tx, rule models.RuleRecord, authorID int) (models.RuleRecord, error) {
 	if rule.Events == nil {
 		rule.Events = pq.StringArray{}
 	}
 	var result models.RuleRecord
-	if err := e.q.InsertRule.Get(&result, rule.Name, rule.Description, rule.Type, rule.Events, rule.Rules); err != nil {
+	if err := tx.Stmtx(e.q.InsertRule).Get(&result, rule.Name, rule.Description, rule.Type, rule.Events, rule.Rules, rule.Enabled); err != nil {
 		e.lo.Error("error creating rule", "error", err)
 		return models.RuleRecord{}, envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
 	}
-	// Reload rules.
-	e.ReloadRules()
+	if err := e.saveVersion(tx, result, nil, authorID); err != nil {
+		return models.RuleRecord{}, err
+	}
 	return result, nil
 }
 
+// withTx runs fn in a transaction, committing it if fn succeeds.
+func (e *Engine) withTx(fn func(tx *sqlx.Tx) error) error {
+	tx, err := e.db.Beginx()
+	if err != nil {
+		e.lo.Error("error starting transaction", "error", err)
+		return envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		e.lo.Error("error committing transaction", "error", err)
+		return envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return nil
+}
+
 // DeleteRule deletes a rule by ID.
 func (e *Engine) DeleteRule(id int) error {
 	if _, err := e.q.DeleteRule.Exec(id); err != nil {
@@ -284,21 +457,24 @@ func (e *Engine) UpdateRuleExecutionMode(ruleType, mode string) error {
 	return nil
 }
 
-// EvaluateNewConversationRules enqueues a new conversation for rule evaluation.
-func (e *Engine) EvaluateNewConversationRules(conversation cmodels.Conversation) {
+// EvaluateNewConversationRules enqueues a new conversation for rule evaluation, the out of office reply is sent once
+// the rules have run. Returns false if the conversation could not be enqueued.
+func (e *Engine) EvaluateNewConversationRules(conversation cmodels.Conversation) bool {
 	e.closedMu.RLock()
 	defer e.closedMu.RUnlock()
 	if e.closed {
-		return
+		return false
 	}
 	select {
 	case e.taskQueue <- ConversationTask{
 		taskType:     NewConversation,
 		conversation: conversation,
 	}:
+		return true
 	default:
 		// Queue is full.
 		e.lo.Warn("EvaluateNewConversationRules: newConversationQ is full, unable to enqueue conversation")
+		return false
 	}
 }
 
@@ -343,23 +519,41 @@ func (e *Engine) EvaluateConversationUpdateRulesByID(conversationID int, convers
 // handleNewConversation handles new conversation events.
 func (e *Engine) handleNewConversation(conversation cmodels.Conversation) {
 	e.lo.Debug("handling new conversation for automation rule evaluation", "uuid", conversation.UUID)
+	e.selectSLAPolicy(conversation)
+	defer e.sendOutOfOfficeReply(conversation.UUID)
 	rules := e.filterRulesByType(models.RuleTypeNewConversation, "")
 	if len(rules) == 0 {
 		e.lo.Info("no rules to evaluate for new conversation rule evaluation", "uuid", conversation.UUID)
 		return
 	}
-	e.evalConversationRules(rules, conversation, nil)
+	e.evalConversationRules(rules, conversation, nil, models.RuleTypeNewConversation)
+}
+
+// sendOutOfOfficeReply sends the out of office reply for a new conversation after the new conversation rules have run,
+// so the business hours of a team assigned by the rules are used when the inbox has none.
+func (e *Engine) sendOutOfOfficeReply(uuid string) {
+	conversation, err := e.conversationStore.GetConversation(0, uuid, "")
+	if err != nil {
+		e.lo.Error("error fetching conversation for out of office reply", "uuid", uuid, "error", err)
+		return
+	}
+	if err := e.conversationStore.SendOutOfOfficeReply(conversation); err != nil {
+		e.lo.Error("error sending out of office reply", "uuid", uuid, "error", err)
+	}
 }
 
 // handleUpdateConversation handles update conversation events with specific eventType.
 func (e *Engine) handleUpdateConversation(conversation cmodels.Conversation, eventType string, previousValues map[string]string) {
 	e.lo.Debug("handling update conversation for automation rule evaluation", "uuid", conversation.UUID, "event_type", eventType)
+	// Cancel delayed actions the update has invalidated.
+	e.recheckScheduledActions(conversation, previousValues)
+	e.selectSLAPolicy(conversation)
 	rules := e.filterRulesByType(models.RuleTypeConversationUpdate, eventType)
 	if len(rules) == 0 {
 		e.lo.Info("no rules to evaluate for conversation update", "uuid", conversation.UUID, "event_type", eventType)
 		return
 	}
-	e.evalConversationRules(rules, conversation, previousValues)
+	e.evalConversationRules(rules, conversation, previousValues, eventType)
 }
 
 // handleTimeTrigger handles time trigger events.
@@ -384,7 +578,7 @@ func (e *Engine) handleTimeTrigger() {
 			e.lo.Error("error fetching conversation for time trigger", "uuid", c.UUID, "error", err)
 			continue
 		}
-		e.evalConversationRules(rules, conversation, nil)
+		e.evalConversationRules(rules, conversation, nil, models.RuleTypeTimeTrigger)
 	}
 }
 
@@ -435,6 +629,8 @@ func (e *Engine) queryRules() []models.Rule {
 		}
 		// Set values from DB.
 		for i := range rulesBatch {
+			rulesBatch[i].ID = rule.ID
+			rulesBatch[i].Version = rule.Version
 			rulesBatch[i].Type = rule.Type
 			rulesBatch[i].Events = rule.Events
 			rulesBatch[i].ExecutionMode = rule.ExecutionMode
@@ -0,0 +1,64 @@
+package automation
+
+import (
+	"testing"
+
+	"github.com/abhinavxd/libredesk/internal/automation/models"
+	cmodels "github.com/abhinavxd/libredesk/internal/conversation/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/volatiletech/null/v9"
+)
+
+func TestHandleNewConversation_SendsOutOfOfficeReplyAfterRules(t *testing.T) {
+	mockStore := new(mockConversationStore)
+	mockStore.On("ApplyAction", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	engine := createTestEngine(mockStore)
+
+	conversation := createTestConversation()
+	assigned := createTestConversation(func(c *cmodels.Conversation) {
+		c.AssignedTeamID = null.IntFrom(2)
+	})
+	mockStore.On("GetConversation", 0, conversation.UUID, "").Return(assigned, nil)
+	mockStore.On("SendOutOfOfficeReply", assigned).Return(nil)
+
+	rule := createTestRule(
+		[]models.RuleGroup{
+			{
+				LogicalOp: models.OperatorAnd,
+				Rules: []models.RuleDetail{
+					{Field: models.ConversationStatus, Operator: models.RuleOperatorEquals, Value: "1", FieldType: models.FieldTypeConversationField},
+				},
+			},
+		},
+		[]models.RuleAction{
+			{Type: models.ActionAssignTeam, Value: []string{"2"}},
+		},
+		models.OperatorOR,
+	)
+	rule.Type = models.RuleTypeNewConversation
+	engine.rules = []models.Rule{rule}
+
+	engine.handleNewConversation(conversation)
+
+	// The reply is evaluated on the conversation as it is after the rules assigned the team.
+	mockStore.AssertCalled(t, "SendOutOfOfficeReply", assigned)
+	var methods []string
+	for _, call := range mockStore.Calls {
+		methods = append(methods, call.Method)
+	}
+	assert.Equal(t, []string{"ApplyAction", "GetConversation", "SendOutOfOfficeReply"}, methods)
+}
+
+func TestHandleNewConversation_SendsOutOfOfficeReplyWithoutRules(t *testing.T) {
+	mockStore := new(mockConversationStore)
+	engine := createTestEngine(mockStore)
+
+	conversation := createTestConversation()
+	mockStore.On("GetConversation", 0, conversation.UUID, "").Return(conversation, nil)
+	mockStore.On("SendOutOfOfficeReply", conversation).Return(nil)
+
+	engine.handleNewConversation(conversation)
+
+	mockStore.AssertCalled(t, "SendOutOfOfficeReply", conversation)
+}
@@ -0,0 +1,88 @@
+package automation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abhinavxd/libredesk/internal/automation/models"
+	cmodels "github.com/abhinavxd/libredesk/internal/conversation/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "x * * * *"} {
+		_, err := parseCron(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+	// Sunday.
+	from := time.Date(2026, 10, 18, 8, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"*/15 * * * *", from, time.Date(2026, 10, 18, 8, 45, 0, 0, time.UTC)},
+		{"0 8 * * 1-5", from, time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)},
+		{"0 8 * * MON-FRI", from.In(kolkata), time.Date(2026, 10, 19, 8, 0, 0, 0, kolkata)},
+		{"0 0 * * SUN", from, time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", from, time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)},
+		{"@daily", from, time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)},
+		{"30 8 18 10 *", from, time.Date(2027, 10, 18, 8, 30, 0, 0, time.UTC)},
+		// Day of month or day of week when both are restricted.
+		{"0 9 1 * 1", from, time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", from, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", from, time.Time{}},
+	}
+	for _, tt := range tests {
+		cron, err := parseCron(tt.expr)
+		require.NoError(t, err, tt.expr)
+		assert.True(t, tt.want.Equal(cron.next(tt.from)), "%s: got %v, want %v", tt.expr, cron.next(tt.from), tt.want)
+	}
+}
+
+func TestResolveRelativeFilterTimes(t *testing.T) {
+	now := time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)
+	filters := `{"logic":"AND","rules":[{"model":"conversations","field":"created_at","operator":"less than","value":"now-2h"},` +
+		`{"model":"users","field":"email","operator":"equals","value":"nowak@example.com"}]}`
+
+	out, err := resolveRelativeFilterTimes([]byte(filters), now)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"logic":"AND","rules":[{"model":"conversations","field":"created_at","operator":"less than","value":"2026-10-18T06:00:00Z"},`+
+		`{"model":"users","field":"email","operator":"equals","value":"nowak@example.com"}]}`, out)
+
+	_, err = resolveRelativeFilterTimes([]byte(`[{"field":"created_at","value":"now-2x"}]`), now)
+	assert.Error(t, err)
+}
+
+func TestApplySchedule_NotifiesOncePerRun(t *testing.T) {
+	mockStore := new(mockConversationStore)
+	engine := createTestEngine(mockStore)
+	conversations := []cmodels.Conversation{{UUID: "a"}, {UUID: "b"}}
+	mockStore.On("GetConversationsByFilters", mock.Anything, scheduleMaxConversations).Return(conversations, nil)
+	for _, c := range conversations {
+		mockStore.On("GetConversation", 0, c.UUID, "").Return(c, nil)
+	}
+	mockStore.On("ApplyAction", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockStore.On("SendAutomationDigest", "Stale", "Still open", []string{"assignee"}, conversations).Return(nil).Once()
+
+	count, err := engine.applySchedule(models.Schedule{
+		Filters: []byte(`{"logic":"AND","rules":[]}`),
+		Actions: []byte(`[{"type":"set_priority","value":["1"]},` +
+			`{"type":"notify","value":[],"subject":"Stale","message":"Still open","recipients":["assignee"]}]`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 2, mockStore.callCount, "only the other actions are applied per conversation")
+	for _, action := range mockStore.appliedActions {
+		assert.Equal(t, models.ActionSetPriority, action.Type)
+	}
+	mockStore.AssertExpectations(t)
+}
@@ -10,48 +10,126 @@ import (
 	"github.com/abhinavxd/libredesk/internal/automation/models"
 	cmodels "github.com/abhinavxd/libredesk/internal/conversation/models"
 	umodels "github.com/abhinavxd/libredesk/internal/user/models"
+	"github.com/volatiletech/null/v9"
 )
 
-// evalConversationRules evaluates a list of rules against a given conversation.
-func (e *Engine) evalConversationRules(rules []models.Rule, conversation cmodels.Conversation, previousValues map[string]string) {
+// evalConversationRules evaluates a list of rules against a given conversation and records an execution for each rule that fires.
+// trigger is the rule type or event that caused the evaluation.
+func (e *Engine) evalConversationRules(rules []models.Rule, conversation cmodels.Conversation, previousValues map[string]string, trigger string) {
 	for _, rule := range rules {
 		e.lo.Debug("evaluating rules for conversation", "rule", rule, "conversation_id", conversation.ID)
 
-		if len(rule.Groups) > 2 {
-			e.lo.Warn("WARNING: more than 2 groups found for rules skipping evaluation")
-			continue
-		}
-
-		var groupEvalResults []bool
-		for idx, group := range rule.Groups {
-			if len(group.Rules) == 0 {
-				e.lo.Debug("no rules found in group, skipping rule group evaluation", "group_num", idx+1, "conversation_uuid", conversation.UUID)
-				continue
-			}
-			result := e.evaluateGroup(group.Rules, group.LogicalOp, conversation, previousValues)
-			e.lo.Debug("group rule evaluation complete", "logical_op", group.LogicalOp, "result", result, "conversation_uuid", conversation.UUID)
-			groupEvalResults = append(groupEvalResults, result)
-		}
-
-		if evaluateFinalResult(groupEvalResults, rule.GroupOperator) {
+		if matched, groups := e.ruleMatches(rule, conversation, previousValues); matched {
 			e.lo.Debug("all rules within groups evaluated successfully, executing actions", "conversation_uuid", conversation.UUID)
 			e.suppress(conversation.UUID)
+			var (
+				executed = make([]models.ExecutedAction, 0, len(rule.Actions))
+				triage   bool
+			)
 			for _, action := range rule.Actions {
+				action.RuleID = rule.ID
+				executedAction := models.ExecutedAction{RuleAction: action}
+				// Delayed actions are queued and applied later by the scheduler.
+				if action.Delay != "" {
+					runAt, err := e.scheduleAction(rule, conversation, previousValues, action)
+					if err != nil {
+						executedAction.Error = err.Error()
+					} else {
+						executedAction.RunAt = null.TimeFrom(runAt)
+					}
+					executed = append(executed, executedAction)
+					continue
+				}
+				// AI triage actions are applied by the triage worker, which records the execution.
+				if isAITriage(action) {
+					triage = true
+					executed = append(executed, executedAction)
+					continue
+				}
 				if err := e.conversationStore.ApplyAction(action, conversation, umodels.User{}); err != nil {
 					e.lo.Error("error applying action on conversation", "action", action, "conversation_uuid", conversation.UUID, "error", err)
+					executedAction.Error = err.Error()
 				}
+				executed = append(executed, executedAction)
 			}
 			e.unsuppress(conversation.UUID)
+			if triage {
+				e.queueAITriage(aiTriageTask{rule: rule, conversation: conversation, matched: matchedConditions(groups), trigger: trigger, executed: executed})
+			} else {
+				e.recordExecution(rule, conversation, matchedConditions(groups), trigger, executed)
+			}
 			if rule.ExecutionMode == models.ExecutionModeFirstMatch {
 				e.lo.Debug("automation is first match rule execution mode, breaking out of rule evaluation", "conversation_uuid", conversation.UUID)
 				break
 			}
-		} else {
-			e.lo.Debug("rule evaluation failed, skipping actions", "group_eval_results", groupEvalResults, "conversation_uuid", conversation.UUID)
 		}
 	}
 }
 
+// ruleMatches evaluates the groups of a rule against a conversation and combines their results with the rule's group operator.
+// Every condition is evaluated and the result of each group and condition is returned along with whether the rule matched.
+func (e *Engine) ruleMatches(rule models.Rule, conversation cmodels.Conversation, previousValues map[string]string) (bool, []models.GroupResult) {
+	var groups = make([]models.GroupResult, 0, len(rule.Groups))
+	if len(rule.Groups) > 2 {
+		e.lo.Warn("WARNING: more than 2 groups found for rules skipping evaluation")
+		return false, groups
+	}
+
+	var (
+		groupEvalResults []bool
+		stats            = &conversationStats{}
+	)
+	for idx, group := range rule.Groups {
+		if len(group.Rules) == 0 {
+			e.lo.Debug("no rules found in group, skipping rule group evaluation", "group_num", idx+1, "conversation_uuid", conversation.UUID)
+			continue
+		}
+		result := e.evaluateGroup(group, conversation, previousValues, stats)
+		e.lo.Debug("group rule evaluation complete", "logical_op", group.LogicalOp, "result", result.Result, "conversation_uuid", conversation.UUID)
+		groupEvalResults = append(groupEvalResults, result.Result)
+		groups = append(groups, result)
+	}
+
+	if !evaluateFinalResult(groupEvalResults, rule.GroupOperator) {
+		e.lo.Debug("rule evaluation failed, skipping actions", "group_eval_results", groupEvalResults, "conversation_uuid", conversation.UUID)
+		return false, groups
+	}
+	return true, groups
+}
+
+// conversationStats holds the automation stats of a conversation fetched during a rule evaluation, so the conditions
+// of a rule query them at most once.
+type conversationStats struct {
+	fetched bool
+	stats   cmodels.AutomationStats
+	err     error
+}
+
+// getConversationStats returns the automation stats of a conversation, fetching them on first use within a rule evaluation.
+func (e *Engine) getConversationStats(stats *conversationStats, conversation cmodels.Conversation) (cmodels.AutomationStats, error) {
+	if !stats.fetched {
+		stats.stats, stats.err = e.conversationStore.GetConversationAutomationStats(conversation.ID)
+		stats.fetched = true
+	}
+	return stats.stats, stats.err
+}
+
+// matchedConditions returns the conditions that made a rule match, those met within the groups that matched.
+func matchedConditions(groups []models.GroupResult) []models.RuleDetail {
+	var matched = make([]models.RuleDetail, 0)
+	for _, group := range groups {
+		if !group.Result {
+			continue
+		}
+		for _, condition := range group.Conditions {
+			if condition.Result {
+				matched = append(matched, condition.RuleDetail)
+			}
+		}
+	}
+	return matched
+}
+
 // evaluateFinalResult computes the final result of multiple group evaluations
 // based on the specified logical operator (AND/OR).
 func evaluateFinalResult(results []bool, operator string) bool {
@@ -74,33 +152,31 @@ func evaluateFinalResult(results []bool, operator string) bool {
 	return false
 }
 
-// evaluateGroup evaluates a set of rules within a group against a given conversation
-// based on the specified logical operator (AND/OR).
-func (e *Engine) evaluateGroup(rules []models.RuleDetail, operator string, conversation cmodels.Conversation, previousValues map[string]string) bool {
-	switch operator {
-	case models.OperatorAnd:
-		for _, rule := range rules {
-			if !e.evaluateRule(rule, conversation, previousValues) {
-				return false
-			}
-		}
-		return true
-	case models.OperatorOR:
-		for _, rule := range rules {
-			if e.evaluateRule(rule, conversation, previousValues) {
-				return true
-			}
+// evaluateGroup evaluates each condition of a group against a given conversation and combines their results
+// based on the group's logical operator (AND/OR).
+func (e *Engine) evaluateGroup(group models.RuleGroup, conversation cmodels.Conversation, previousValues map[string]string, stats *conversationStats) models.GroupResult {
+	var (
+		out = models.GroupResult{
+			LogicalOp:  group.LogicalOp,
+			Conditions: make([]models.ConditionResult, 0, len(group.Rules)),
 		}
-		return false
-	default:
-		e.lo.Error("invalid group operator", "operator", operator)
+		results = make([]bool, 0, len(group.Rules))
+	)
+	for _, rule := range group.Rules {
+		result := e.evaluateRule(rule, conversation, previousValues, stats)
+		out.Conditions = append(out.Conditions, models.ConditionResult{RuleDetail: rule, Result: result})
+		results = append(results, result)
 	}
-	return false
+	if group.LogicalOp != models.OperatorAnd && group.LogicalOp != models.OperatorOR {
+		e.lo.Error("invalid group operator", "operator", group.LogicalOp)
+	}
+	out.Result = evaluateFinalResult(results, group.LogicalOp)
+	return out
 }
 
 // evaluateRule evaluates a single rule against a given conversation by extracting the field value and comparing it with the rule's value.
 // Returns true if the rule condition is met, false otherwise.
-func (e *Engine) evaluateRule(rule models.RuleDetail, conversation cmodels.Conversation, previousValues map[string]string) bool {
+func (e *Engine) evaluateRule(rule models.RuleDetail, conversation cmodels.Conversation, previousValues map[string]string, stats *conversationStats) bool {
 	var (
 		valueToCompare   string
 		ruleValues       []string
@@ -119,6 +195,11 @@ func (e *Engine) evaluateRule(rule models.RuleDetail, conversation cmodels.Conve
 		switch rule.Field {
 		case models.ContactEmail:
 			valueToCompare = conversation.Contact.Email.String
+		case models.ContactCompany:
+			// The company of a contact is the domain of their email address.
+			if _, domain, ok := strings.Cut(conversation.Contact.Email.String, "@"); ok {
+				valueToCompare = strings.ToLower(domain)
+			}
 		case models.ConversationSubject:
 			valueToCompare = conversation.Subject.String
 		case models.ConversationContent:
@@ -151,6 +232,35 @@ func (e *Engine) evaluateRule(rule models.RuleDetail, conversation cmodels.Conve
 			}
 		case models.ConversationInbox:
 			valueToCompare = strconv.Itoa(conversation.InboxID)
+		case models.ConversationChannel:
+			valueToCompare = conversation.InboxChannel
+		case models.ConversationTags:
+			return e.evaluateTagsRule(rule, conversation)
+		case models.ConversationWithinBusinessHours:
+			if e.businessHours == nil {
+				e.lo.Warn("business hours store not set, skipping rule", "field", rule.Field, "conversation_uuid", conversation.UUID)
+				return false
+			}
+			within, err := e.businessHours.IsWithinBusinessHours(conversation.AssignedTeamID.Int, conversation.InboxID, time.Now())
+			if err != nil {
+				e.lo.Error("error checking business hours", "conversation_uuid", conversation.UUID, "error", err)
+				return false
+			}
+			valueToCompare = strconv.FormatBool(within)
+		case models.ConversationContactMessageCount, models.ConversationHasAttachment, models.ContactReturning:
+			s, err := e.getConversationStats(stats, conversation)
+			if err != nil {
+				e.lo.Error("error fetching conversation automation stats", "conversation_uuid", conversation.UUID, "error", err)
+				return false
+			}
+			switch rule.Field {
+			case models.ConversationContactMessageCount:
+				valueToCompare = strconv.Itoa(s.ContactMessageCount)
+			case models.ConversationHasAttachment:
+				valueToCompare = strconv.FormatBool(s.HasAttachment)
+			case models.ContactReturning:
+				valueToCompare = strconv.FormatBool(s.PreviousConversationCount > 0)
+			}
 		case models.ConversationPreviousStatus, models.ConversationPreviousPriority,
 			models.ConversationPreviousAssignedUser, models.ConversationPreviousAssignedTeam:
 			// An absent key is not the same as an empty previous value.
@@ -201,6 +311,7 @@ func (e *Engine) evaluateRule(rule models.RuleDetail, conversation cmodels.Conve
 	}
 
 	// Case sensitive match?
+	pattern := rule.Value
 	if !rule.CaseSensitiveMatch {
 		valueToCompare = strings.ToLower(valueToCompare)
 		rule.Value = strings.ToLower(rule.Value)
@@ -293,6 +404,9 @@ func (e *Engine) evaluateRule(rule models.RuleDetail, conversation cmodels.Conve
 		conditionMet = value1 < value2
 	case models.RuleOperatorStartsWith:
 		conditionMet = strings.HasPrefix(valueToCompare, rule.Value)
+	case models.RuleOperatorMatchesRegex:
+		// The pattern is matched as written, lowercasing it could change its meaning (e.g. \D vs \d).
+		conditionMet = e.matchRegex(pattern, valueToCompare, rule.CaseSensitiveMatch)
 	default:
 		e.lo.Error("error unrecognized rule logical operator", "operator", rule.Operator)
 		return false
@@ -300,3 +414,48 @@ func (e *Engine) evaluateRule(rule models.RuleDetail, conversation cmodels.Conve
 	e.lo.Debug("conversation automation rule status", "has_met", conditionMet, "conversation_uuid", conversation.UUID)
 	return conditionMet
 }
+
+// evaluateTagsRule evaluates a tags condition, matching whole tag names rather than substrings.
+func (e *Engine) evaluateTagsRule(rule models.RuleDetail, conversation cmodels.Conversation) bool {
+	var tags []string
+	if conversation.Tags.Valid && len(conversation.Tags.JSON) > 0 {
+		if err := json.Unmarshal(conversation.Tags.JSON, &tags); err != nil {
+			e.lo.Error("error unmarshalling conversation tags", "conversation_uuid", conversation.UUID, "error", err)
+			return false
+		}
+	}
+
+	hasTag := func(name string) bool {
+		name = strings.TrimSpace(name)
+		for _, tag := range tags {
+			if tag == name || (!rule.CaseSensitiveMatch && strings.EqualFold(tag, name)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch rule.Operator {
+	case models.RuleOperatorSet:
+		return len(tags) > 0
+	case models.RuleOperatorNotSet:
+		return len(tags) == 0
+	case models.RuleOperatorContains, models.RuleOperatorEquals:
+		for _, name := range strings.Split(rule.Value, ",") {
+			if hasTag(name) {
+				return true
+			}
+		}
+		return false
+	case models.RuleOperatorNotContains, models.RuleOperatorNotEqual:
+		for _, name := range strings.Split(rule.Value, ",") {
+			if hasTag(name) {
+				return false
+			}
+		}
+		return true
+	default:
+		e.lo.Error("error unsupported operator for tags", "operator", rule.Operator)
+		return false
+	}
+}
@@ -2,6 +2,8 @@ package automation
 
 import (
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -38,6 +40,36 @@ func (m *mockConversationStore) GetConversationsCreatedAfter(t time.Time) ([]cmo
 	return args.Get(0).([]cmodels.Conversation), args.Error(1)
 }
 
+func (m *mockConversationStore) GetRecentConversations(limit int) ([]cmodels.Conversation, error) {
+	args := m.Called(limit)
+	return args.Get(0).([]cmodels.Conversation), args.Error(1)
+}
+
+func (m *mockConversationStore) GetConversationAutomationStats(conversationID int) (cmodels.AutomationStats, error) {
+	args := m.Called(conversationID)
+	return args.Get(0).(cmodels.AutomationStats), args.Error(1)
+}
+
+func (m *mockConversationStore) GetConversationsByFilters(filtersJSON string, limit int) ([]cmodels.Conversation, error) {
+	args := m.Called(filtersJSON, limit)
+	return args.Get(0).([]cmodels.Conversation), args.Error(1)
+}
+
+func (m *mockConversationStore) ValidateListFilters(filtersJSON string) error {
+	args := m.Called(filtersJSON)
+	return args.Error(0)
+}
+
+func (m *mockConversationStore) SendOutOfOfficeReply(conversation cmodels.Conversation) error {
+	args := m.Called(conversation)
+	return args.Error(0)
+}
+
+func (m *mockConversationStore) SendAutomationDigest(subject, message string, recipients []string, conversations []cmodels.Conversation) error {
+	args := m.Called(subject, message, recipients, conversations)
+	return args.Error(0)
+}
+
 // Test Helpers
 func createTestEngine(store *mockConversationStore) *Engine {
 	logger := logf.New(logf.Opts{Level: logf.DebugLevel})
@@ -107,7 +139,7 @@ func TestEvaluateGroup_AND_AllTrue(t *testing.T) {
 		),
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	assert.Equal(t, 1, mockStore.callCount, "ApplyAction should be called once")
 	assert.Equal(t, models.ActionSetStatus, mockStore.appliedActions[0].Type)
@@ -141,7 +173,7 @@ func TestEvaluateGroup_AND_ShortCircuit(t *testing.T) {
 		),
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	assert.Equal(t, 0, mockStore.callCount, "ApplyAction should not be called when AND conditions fail")
 }
@@ -175,7 +207,7 @@ func TestEvaluateGroup_OR_OnlyOneTrue(t *testing.T) {
 		),
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	assert.Equal(t, 1, mockStore.callCount, "ApplyAction should be called once for OR condition")
 }
@@ -216,7 +248,7 @@ func TestTwoGroups_AND_BothMustPass(t *testing.T) {
 		},
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	assert.Equal(t, 1, mockStore.callCount, "ApplyAction should be called when both groups pass")
 }
@@ -253,7 +285,7 @@ func TestEmptyGroup_Skipped(t *testing.T) {
 		},
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	assert.Equal(t, 1, mockStore.callCount, "ApplyAction should be called, empty group is skipped")
 }
@@ -301,7 +333,7 @@ func TestExecutionMode_FirstMatch(t *testing.T) {
 		},
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	assert.Equal(t, 1, mockStore.callCount, "Only first matching rule should execute in first_match mode")
 	assert.Equal(t, models.ActionSetStatus, mockStore.appliedActions[0].Type)
@@ -350,7 +382,7 @@ func TestExecutionMode_All(t *testing.T) {
 		},
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	assert.Equal(t, 2, mockStore.callCount, "All matching rules should execute in 'all' mode")
 }
@@ -385,7 +417,7 @@ func TestNullFields_SetNotSet(t *testing.T) {
 		},
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	assert.Equal(t, 1, mockStore.callCount, "Should handle null fields with set/not set operators")
 }
@@ -425,7 +457,7 @@ func TestCustomAttributes_StringComparison(t *testing.T) {
 		},
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	assert.Equal(t, 1, mockStore.callCount, "Custom attributes should be compared correctly")
 	assert.Equal(t, models.ActionSendCSAT, mockStore.appliedActions[0].Type)
@@ -463,7 +495,7 @@ func TestCustomAttributes_MissingField(t *testing.T) {
 		},
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	assert.Equal(t, 0, mockStore.callCount, "Missing custom attribute should fail the rule")
 }
@@ -496,7 +528,7 @@ func TestContainsOperator_MultipleValues(t *testing.T) {
 		},
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	assert.Equal(t, 1, mockStore.callCount, "Contains operator should match with comma-separated values")
 }
@@ -529,7 +561,7 @@ func TestNotContainsOperator(t *testing.T) {
 		},
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	assert.Equal(t, 1, mockStore.callCount, "Not contains operator should pass when values are not present")
 }
@@ -562,7 +594,7 @@ func TestNumericComparisons(t *testing.T) {
 		},
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	assert.Equal(t, 1, mockStore.callCount, "Greater than operator should work with numeric comparisons")
 }
@@ -610,7 +642,7 @@ func TestRealWorld_CSATAutomation(t *testing.T) {
 		},
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	assert.Equal(t, 1, mockStore.callCount, "CSAT should be sent when status is resolved and client_id matches")
 	assert.Equal(t, models.ActionSendCSAT, mockStore.appliedActions[0].Type)
@@ -646,7 +678,7 @@ func TestRealWorld_NewTicketAutomation(t *testing.T) {
 		},
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	assert.Equal(t, 2, mockStore.callCount, "Both actions should be executed for new ticket")
 	assert.Equal(t, models.ActionSendPrivateNote, mockStore.appliedActions[0].Type)
@@ -733,7 +765,7 @@ func TestCaseSensitivity_ContainsOperators(t *testing.T) {
 				},
 			}
 
-			engine.evalConversationRules(rules, conversation, nil)
+			engine.evalConversationRules(rules, conversation, nil, "")
 			
 			if tc.shouldMatch {
 				assert.Equal(t, 1, mockStore.callCount, "Expected action to be triggered")
@@ -778,7 +810,7 @@ func TestCaseSensitivity_Equals(t *testing.T) {
 		},
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	assert.Equal(t, 1, mockStore.callCount, "Case insensitive comparison should match")
 }
@@ -808,7 +840,7 @@ func TestInvalidOperator(t *testing.T) {
 		},
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	assert.Equal(t, 0, mockStore.callCount, "Invalid operator should not trigger action")
 }
@@ -841,7 +873,7 @@ func TestContradictoryConditions(t *testing.T) {
 		},
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	assert.Equal(t, 0, mockStore.callCount, "Contradictory conditions should never match")
 }
@@ -875,7 +907,7 @@ func TestTautologyCondition(t *testing.T) {
 		},
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	assert.Equal(t, 1, mockStore.callCount, "Tautology condition should always match")
 }
@@ -918,7 +950,7 @@ func TestCustomAttributeTypes(t *testing.T) {
 		}
 		mockStore.appliedActions = nil
 		mockStore.callCount = 0
-		engine.evalConversationRules(rules, conversation, nil)
+		engine.evalConversationRules(rules, conversation, nil, "")
 		assert.Equal(t, 1, mockStore.callCount, "Integer custom attribute should be compared correctly")
 	})
 
@@ -942,7 +974,7 @@ func TestCustomAttributeTypes(t *testing.T) {
 		}
 		mockStore.appliedActions = nil
 		mockStore.callCount = 0
-		engine.evalConversationRules(rules, conversation, nil)
+		engine.evalConversationRules(rules, conversation, nil, "")
 		assert.Equal(t, 1, mockStore.callCount, "Float custom attribute should be converted to int for comparison")
 	})
 
@@ -966,7 +998,7 @@ func TestCustomAttributeTypes(t *testing.T) {
 		}
 		mockStore.appliedActions = nil
 		mockStore.callCount = 0
-		engine.evalConversationRules(rules, conversation, nil)
+		engine.evalConversationRules(rules, conversation, nil, "")
 		assert.Equal(t, 1, mockStore.callCount, "Boolean custom attribute should be compared correctly")
 	})
 }
@@ -998,7 +1030,7 @@ func TestHoursSinceFields_NullHandling(t *testing.T) {
 		},
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	assert.Equal(t, 0, mockStore.callCount, "Should not trigger action when time field is null")
 }
@@ -1034,7 +1066,7 @@ func TestMultipleActions(t *testing.T) {
 		},
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	assert.Equal(t, 4, mockStore.callCount, "All actions should be executed")
 	assert.Equal(t, models.ActionSetStatus, mockStore.appliedActions[0].Type)
@@ -1099,7 +1131,7 @@ func TestContainsOperator_TextNormalization(t *testing.T) {
 		},
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	assert.Equal(t, 1, mockStore.callCount, "Contains should normalize whitespace and match")
 }
@@ -1138,7 +1170,7 @@ func TestMockVerificationPrecision(t *testing.T) {
 		},
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 	
 	// This will verify the exact parameters were passed
 	mockStore.AssertExpectations(t)
@@ -1193,7 +1225,7 @@ func TestComplexRealWorldScenario(t *testing.T) {
 		},
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 
 	assert.Equal(t, 2, mockStore.callCount, "Complex conditions met, both actions should trigger")
 	assert.Equal(t, models.ActionSendCSAT, mockStore.appliedActions[0].Type)
@@ -1224,7 +1256,7 @@ func TestPreviousStatus_Equals(t *testing.T) {
 		),
 	}
 
-	engine.evalConversationRules(rules, conversation, previousValues)
+	engine.evalConversationRules(rules, conversation, previousValues, "")
 
 	assert.Equal(t, 1, mockStore.callCount, "Status transition 1→2 should fire action")
 }
@@ -1251,7 +1283,7 @@ func TestPreviousStatus_NotEqual(t *testing.T) {
 		),
 	}
 
-	engine.evalConversationRules(rules, conversation, previousValues)
+	engine.evalConversationRules(rules, conversation, previousValues, "")
 
 	assert.Equal(t, 0, mockStore.callCount, "previous_status != 1 should not match when previous was 1")
 }
@@ -1280,7 +1312,7 @@ func TestStatusTransition_OpenToResolved(t *testing.T) {
 		),
 	}
 
-	engine.evalConversationRules(rules, conversation, previousValues)
+	engine.evalConversationRules(rules, conversation, previousValues, "")
 
 	assert.Equal(t, 1, mockStore.callCount, "Open→Resolved transition should fire CSAT")
 }
@@ -1308,7 +1340,7 @@ func TestPreviousPriority_Equals(t *testing.T) {
 		),
 	}
 
-	engine.evalConversationRules(rules, conversation, previousValues)
+	engine.evalConversationRules(rules, conversation, previousValues, "")
 
 	assert.Equal(t, 1, mockStore.callCount, "Previous priority filter should fire")
 }
@@ -1337,7 +1369,7 @@ func TestPreviousAssignedUser_Equals(t *testing.T) {
 		),
 	}
 
-	engine.evalConversationRules(rules, conversation, previousValues)
+	engine.evalConversationRules(rules, conversation, previousValues, "")
 
 	assert.Equal(t, 1, mockStore.callCount, "Previous assigned user filter should fire on reassignment")
 }
@@ -1365,7 +1397,7 @@ func TestPreviousAssignedTeam_Equals(t *testing.T) {
 		),
 	}
 
-	engine.evalConversationRules(rules, conversation, previousValues)
+	engine.evalConversationRules(rules, conversation, previousValues, "")
 
 	assert.Equal(t, 1, mockStore.callCount, "Previous assigned team filter should fire")
 }
@@ -1393,7 +1425,7 @@ func TestPreviousValue_NotSet(t *testing.T) {
 		),
 	}
 
-	engine.evalConversationRules(rules, conversation, map[string]string{models.ConversationPreviousAssignedUser: ""})
+	engine.evalConversationRules(rules, conversation, map[string]string{models.ConversationPreviousAssignedUser: ""}, "")
 
 	assert.Equal(t, 1, mockStore.callCount, "First assignment (unassigned→assigned) should match")
 }
@@ -1419,7 +1451,7 @@ func TestPreviousValue_MissingKeyReturnsFalse(t *testing.T) {
 		),
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 
 	assert.Equal(t, 0, mockStore.callCount, "Missing previousValues key should not match equals comparison")
 }
@@ -1446,7 +1478,7 @@ func TestPreviousAssignedUser_PreviouslyNull(t *testing.T) {
 		),
 	}
 
-	engine.evalConversationRules(rules, conversation, map[string]string{models.ConversationPreviousAssignedUser: ""})
+	engine.evalConversationRules(rules, conversation, map[string]string{models.ConversationPreviousAssignedUser: ""}, "")
 
 	assert.Equal(t, 1, mockStore.callCount, "previous_assigned_user not_set matches when it was empty")
 }
@@ -1473,7 +1505,7 @@ func TestStartsWithOperator(t *testing.T) {
 		),
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 
 	assert.Equal(t, 1, mockStore.callCount, "starts_with should match prefix case-insensitively by default")
 }
@@ -1501,7 +1533,7 @@ func TestNotifyAction_PassedThrough(t *testing.T) {
 		),
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 
 	mockStore.AssertExpectations(t)
 	assert.Equal(t, 1, mockStore.callCount)
@@ -1533,7 +1565,7 @@ func TestMultipleNotifyActions(t *testing.T) {
 		),
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 
 	assert.Equal(t, 2, mockStore.callCount, "Both notify actions should dispatch")
 }
@@ -1561,7 +1593,7 @@ func TestSnoozeAction_PassedThrough(t *testing.T) {
 		),
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 
 	mockStore.AssertExpectations(t)
 	assert.Equal(t, 1, mockStore.callCount)
@@ -1590,7 +1622,7 @@ func TestTriggerWebhookAction_PassedThrough(t *testing.T) {
 		),
 	}
 
-	engine.evalConversationRules(rules, conversation, nil)
+	engine.evalConversationRules(rules, conversation, nil, "")
 
 	mockStore.AssertExpectations(t)
 	assert.Equal(t, 1, mockStore.callCount)
@@ -1610,7 +1642,7 @@ func runSingleRule(t *testing.T, conv cmodels.Conversation, detail models.RuleDe
 	mockStore := new(mockConversationStore)
 	mockStore.On("ApplyAction", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	engine := createTestEngine(mockStore)
-	engine.evalConversationRules(singleRule(detail), conv, nil)
+	engine.evalConversationRules(singleRule(detail), conv, nil, "")
 	return mockStore.callCount
 }
 
@@ -1761,7 +1793,7 @@ func TestInvalidGroupOperator_NoMatch(t *testing.T) {
 		),
 	}
 
-	engine.evalConversationRules(rules, createTestConversation(), nil)
+	engine.evalConversationRules(rules, createTestConversation(), nil, "")
 
 	assert.Equal(t, 0, mockStore.callCount, "invalid group logical operator must not run actions")
 }
@@ -1782,7 +1814,234 @@ func TestMoreThanTwoGroups_RuleSkipped(t *testing.T) {
 		),
 	}
 
-	engine.evalConversationRules(rules, createTestConversation(), nil)
+	engine.evalConversationRules(rules, createTestConversation(), nil, "")
 
 	assert.Equal(t, 0, mockStore.callCount, "rules with more than 2 groups must be skipped entirely")
 }
+
+// mockBusinessHoursStore reports a fixed business hours state.
+type mockBusinessHoursStore struct {
+	within bool
+	err    error
+}
+
+func (m mockBusinessHoursStore) IsWithinBusinessHours(assignedTeamID, inboxID int, t time.Time) (bool, error) {
+	return m.within, m.err
+}
+
+func TestChannelField(t *testing.T) {
+	conv := createTestConversation(func(c *cmodels.Conversation) {
+		c.InboxChannel = "livechat"
+	})
+
+	match := runSingleRule(t, conv, models.RuleDetail{
+		Field: models.ConversationChannel, Operator: models.RuleOperatorEquals, Value: "livechat", FieldType: models.FieldTypeConversationField,
+	})
+	assert.Equal(t, 1, match, "channel should match the inbox channel")
+
+	noMatch := runSingleRule(t, conv, models.RuleDetail{
+		Field: models.ConversationChannel, Operator: models.RuleOperatorEquals, Value: "email", FieldType: models.FieldTypeConversationField,
+	})
+	assert.Equal(t, 0, noMatch, "channel should not match another channel")
+}
+
+func TestTagsField(t *testing.T) {
+	conv := createTestConversation(func(c *cmodels.Conversation) {
+		c.Tags = null.JSONFrom([]byte(`["VIP", "billing"]`))
+	})
+
+	tests := []struct {
+		name     string
+		operator string
+		value    string
+		expected int
+	}{
+		{"contains any of the tags", models.RuleOperatorContains, "refund, vip", 1},
+		{"contains matches whole tag names only", models.RuleOperatorContains, "bill", 0},
+		{"not contains", models.RuleOperatorNotContains, "refund", 1},
+		{"not contains with a present tag", models.RuleOperatorNotContains, "billing", 0},
+		{"set", models.RuleOperatorSet, "", 1},
+		{"not set", models.RuleOperatorNotSet, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := runSingleRule(t, conv, models.RuleDetail{
+				Field: models.ConversationTags, Operator: tt.operator, Value: tt.value, FieldType: models.FieldTypeConversationField,
+			})
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+
+	caseSensitive := runSingleRule(t, conv, models.RuleDetail{
+		Field: models.ConversationTags, Operator: models.RuleOperatorContains, Value: "vip", FieldType: models.FieldTypeConversationField, CaseSensitiveMatch: true,
+	})
+	assert.Equal(t, 0, caseSensitive, "case-sensitive tag match with wrong case should not match")
+
+	untagged := runSingleRule(t, createTestConversation(), models.RuleDetail{
+		Field: models.ConversationTags, Operator: models.RuleOperatorNotSet, FieldType: models.FieldTypeConversationField,
+	})
+	assert.Equal(t, 1, untagged, "conversation without tags should match not set")
+}
+
+func TestWithinBusinessHoursField(t *testing.T) {
+	rule := models.RuleDetail{
+		Field: models.ConversationWithinBusinessHours, Operator: models.RuleOperatorEquals, Value: "true", FieldType: models.FieldTypeConversationField,
+	}
+
+	for _, tt := range []struct {
+		name     string
+		store    businessHoursStore
+		expected int
+	}{
+		{"within business hours", mockBusinessHoursStore{within: true}, 1},
+		{"outside business hours", mockBusinessHoursStore{within: false}, 0},
+		{"business hours not configured", mockBusinessHoursStore{err: errors.New("business hours or timezone not configured")}, 0},
+		{"no business hours store", nil, 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := new(mockConversationStore)
+			mockStore.On("ApplyAction", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			engine := createTestEngine(mockStore)
+			engine.businessHours = tt.store
+
+			engine.evalConversationRules(singleRule(rule), createTestConversation(), nil, "")
+
+			assert.Equal(t, tt.expected, mockStore.callCount)
+		})
+	}
+}
+
+func TestConversationStatsFields(t *testing.T) {
+	stats := cmodels.AutomationStats{ContactMessageCount: 3, HasAttachment: true, PreviousConversationCount: 0}
+
+	tests := []struct {
+		name     string
+		rule     models.RuleDetail
+		expected int
+	}{
+		{"contact message count greater than", models.RuleDetail{Field: models.ConversationContactMessageCount, Operator: models.RuleOperatorGreaterThan, Value: "2"}, 1},
+		{"contact message count equals", models.RuleDetail{Field: models.ConversationContactMessageCount, Operator: models.RuleOperatorEquals, Value: "4"}, 0},
+		{"has attachment", models.RuleDetail{Field: models.ConversationHasAttachment, Operator: models.RuleOperatorEquals, Value: "true"}, 1},
+		{"new contact is not returning", models.RuleDetail{Field: models.ContactReturning, Operator: models.RuleOperatorEquals, Value: "true"}, 0},
+		{"new contact", models.RuleDetail{Field: models.ContactReturning, Operator: models.RuleOperatorEquals, Value: "false"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := new(mockConversationStore)
+			mockStore.On("ApplyAction", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			mockStore.On("GetConversationAutomationStats", 1).Return(stats, nil)
+			engine := createTestEngine(mockStore)
+
+			tt.rule.FieldType = models.FieldTypeConversationField
+			engine.evalConversationRules(singleRule(tt.rule), createTestConversation(), nil, "")
+
+			assert.Equal(t, tt.expected, mockStore.callCount)
+		})
+	}
+}
+
+func TestConversationStatsFields_FetchedOncePerRule(t *testing.T) {
+	mockStore := new(mockConversationStore)
+	mockStore.On("ApplyAction", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockStore.On("GetConversationAutomationStats", 1).Return(cmodels.AutomationStats{ContactMessageCount: 3, HasAttachment: true}, nil)
+	engine := createTestEngine(mockStore)
+
+	rule := createTestRule(
+		[]models.RuleGroup{
+			{
+				LogicalOp: models.OperatorAnd,
+				Rules: []models.RuleDetail{
+					{Field: models.ConversationContactMessageCount, Operator: models.RuleOperatorGreaterThan, Value: "2", FieldType: models.FieldTypeConversationField},
+					{Field: models.ConversationHasAttachment, Operator: models.RuleOperatorEquals, Value: "true", FieldType: models.FieldTypeConversationField},
+				},
+			},
+			{
+				LogicalOp: models.OperatorAnd,
+				Rules: []models.RuleDetail{
+					{Field: models.ContactReturning, Operator: models.RuleOperatorEquals, Value: "false", FieldType: models.FieldTypeConversationField},
+				},
+			},
+		},
+		[]models.RuleAction{{Type: models.ActionSetStatus, Value: []string{"2"}}},
+		models.OperatorAnd,
+	)
+
+	engine.evalConversationRules([]models.Rule{rule, rule}, createTestConversation(), nil, "")
+
+	assert.Equal(t, 2, mockStore.callCount)
+	mockStore.AssertNumberOfCalls(t, "GetConversationAutomationStats", 2)
+}
+
+func TestConversationStatsFields_StoreError(t *testing.T) {
+	mockStore := new(mockConversationStore)
+	mockStore.On("GetConversationAutomationStats", 1).Return(cmodels.AutomationStats{}, errors.New("db down"))
+	engine := createTestEngine(mockStore)
+
+	engine.evalConversationRules(singleRule(models.RuleDetail{
+		Field: models.ContactReturning, Operator: models.RuleOperatorEquals, Value: "false", FieldType: models.FieldTypeConversationField,
+	}), createTestConversation(), nil, "")
+
+	assert.Equal(t, 0, mockStore.callCount, "stats lookup failure must evaluate false")
+}
+
+func TestMatchesRegexOperator(t *testing.T) {
+	conv := createTestConversation(func(c *cmodels.Conversation) {
+		c.Subject = null.StringFrom("Order #48213 not delivered")
+	})
+
+	tests := []struct {
+		name          string
+		pattern       string
+		caseSensitive bool
+		expected      int
+	}{
+		{"matches", `order #\d{5}`, false, 1},
+		{"pattern is not lowercased", `#\D{5}`, false, 0},
+		{"case-sensitive with wrong case", `order #\d+`, true, 0},
+		{"case-sensitive with exact case", `^Order #\d+`, true, 1},
+		{"anchored no match", `^not delivered`, false, 0},
+		{"invalid pattern", `order (\d+`, false, 0},
+		{"pattern too long", strings.Repeat("a", maxRegexPatternLength+1), false, 0},
+		{"empty pattern", "", false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := runSingleRule(t, conv, models.RuleDetail{
+				Field: models.ConversationSubject, Operator: models.RuleOperatorMatchesRegex, Value: tt.pattern,
+				FieldType: models.FieldTypeConversationField, CaseSensitiveMatch: tt.caseSensitive,
+			})
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestMatchesRegexOperator_LargeInput(t *testing.T) {
+	engine := createTestEngine(new(mockConversationStore))
+	input := strings.Repeat("a", maxRegexInputLength) + "needle"
+
+	assert.False(t, engine.matchRegex("needle", input, true), "input past the length cap is not searched")
+	assert.True(t, engine.matchRegex("^a+$", input, true), "pathological patterns still run in linear time")
+}
+
+func TestMatchesRegexOperator_CachesPatterns(t *testing.T) {
+	engine := createTestEngine(new(mockConversationStore))
+
+	re := engine.compileRegex(`order #\d+`)
+	assert.NotNil(t, re)
+	assert.Same(t, re, engine.compileRegex(`order #\d+`), "the compiled pattern is reused")
+	assert.Nil(t, engine.compileRegex(`order (\d+`))
+}
+
+func TestMatchesRegexOperator_AllSlotsTaken(t *testing.T) {
+	engine := createTestEngine(new(mockConversationStore))
+	for range maxRegexMatches {
+		regexSlots <- struct{}{}
+	}
+	defer func() {
+		for range maxRegexMatches {
+			<-regexSlots
+		}
+	}()
+
+	assert.False(t, engine.matchRegex("order", "order", true), "matches are skipped while every slot is taken")
+}
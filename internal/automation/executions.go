@@ -0,0 +1,108 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/abhinavxd/libredesk/internal/automation/models"
+	cmodels "github.com/abhinavxd/libredesk/internal/conversation/models"
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	"github.com/volatiletech/null/v9"
+)
+
+const (
+	// DefaultExecutionLogRetention is how long rule execution records are kept when no retention is configured.
+	DefaultExecutionLogRetention = 30 * 24 * time.Hour
+
+	executionPruneInterval = time.Hour
+)
+
+// GetRuleExecutions returns the execution records of a rule, most recent first.
+func (e *Engine) GetRuleExecutions(ruleID, page, pageSize int) ([]models.Execution, error) {
+	var executions = make([]models.Execution, 0)
+	if err := e.q.GetRuleExecutions.Select(&executions, ruleID, pageSize, (page-1)*pageSize); err != nil {
+		e.lo.Error("error fetching rule executions", "rule_id", ruleID, "error", err)
+		return nil, envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return executions, nil
+}
+
+// GetConversationExecutions returns the execution records of all rules that fired on a conversation, most recent first.
+func (e *Engine) GetConversationExecutions(conversationUUID string, page, pageSize int) ([]models.Execution, error) {
+	var executions = make([]models.Execution, 0)
+	if err := e.q.GetConversationExecutions.Select(&executions, conversationUUID, pageSize, (page-1)*pageSize); err != nil {
+		e.lo.Error("error fetching conversation rule executions", "conversation_uuid", conversationUUID, "error", err)
+		return nil, envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return executions, nil
+}
+
+// recordExecution queues an execution record for a rule that fired on a conversation, matched are the conditions
+// that made it fire as evaluated before its actions ran. Unsaved rules have no ID and are not recorded.
+func (e *Engine) recordExecution(rule models.Rule, conversation cmodels.Conversation, matched []models.RuleDetail, trigger string, executed []models.ExecutedAction) {
+	if rule.ID == 0 {
+		return
+	}
+
+	var errs []error
+	for _, action := range executed {
+		if action.Error != "" {
+			errs = append(errs, errors.New(action.Type+": "+action.Error))
+		}
+	}
+
+	execution := models.Execution{
+		RuleID:         rule.ID,
+		RuleVersion:    rule.Version,
+		ConversationID: conversation.ID,
+		Trigger:        trigger,
+	}
+	execution.MatchedConditions, _ = json.Marshal(matched)
+	execution.Actions, _ = json.Marshal(executed)
+	if err := errors.Join(errs...); err != nil {
+		execution.Error = null.StringFrom(err.Error())
+	}
+
+	select {
+	case e.executions <- execution:
+	default:
+		e.lo.Warn("execution log queue is full, dropping rule execution record", "rule_id", rule.ID, "conversation_uuid", conversation.UUID)
+	}
+}
+
+// runExecutionLog writes queued execution records and periodically deletes the ones past retention, along with
+// finished delayed actions.
+func (e *Engine) runExecutionLog(ctx context.Context) {
+	ticker := time.NewTicker(executionPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case execution := <-e.executions:
+			if _, err := e.q.InsertExecution.Exec(execution.RuleID, execution.RuleVersion, execution.ConversationID, execution.Trigger,
+				execution.MatchedConditions, execution.Actions, execution.Error); err != nil {
+				e.lo.Error("error inserting rule execution", "rule_id", execution.RuleID, "conversation_id", execution.ConversationID, "error", err)
+			}
+		case <-ticker.C:
+			retention := e.executionRetention
+			if retention <= 0 {
+				retention = DefaultExecutionLogRetention
+			}
+			res, err := e.q.DeleteExecutionsBefore.Exec(time.Now().Add(-retention))
+			if err != nil {
+				e.lo.Error("error deleting old rule executions", "error", err)
+				continue
+			}
+			if n, _ := res.RowsAffected(); n > 0 {
+				e.lo.Info("deleted old rule executions", "count", n)
+			}
+			if _, err := e.q.DeleteFinishedScheduledActionsBefore.Exec(time.Now().Add(-retention)); err != nil {
+				e.lo.Error("error deleting finished scheduled actions", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package automation
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/abhinavxd/libredesk/internal/automation/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEvalConversationRules_RecordsExecution(t *testing.T) {
+	mockStore := new(mockConversationStore)
+	mockStore.On("ApplyAction", mock.MatchedBy(func(a models.RuleAction) bool { return a.Type == models.ActionSetStatus }), mock.Anything, mock.Anything).Return(nil)
+	mockStore.On("ApplyAction", mock.MatchedBy(func(a models.RuleAction) bool { return a.Type == models.ActionAddTags }), mock.Anything, mock.Anything).Return(errors.New("tag not found"))
+	engine := createTestEngine(mockStore)
+	engine.executions = make(chan models.Execution, 1)
+
+	rule := createTestRule(
+		[]models.RuleGroup{
+			{
+				LogicalOp: models.OperatorOR,
+				Rules: []models.RuleDetail{
+					{Field: models.ConversationStatus, Operator: models.RuleOperatorEquals, Value: "1", FieldType: models.FieldTypeConversationField},
+					{Field: models.ConversationInbox, Operator: models.RuleOperatorEquals, Value: "9", FieldType: models.FieldTypeConversationField},
+				},
+			},
+		},
+		[]models.RuleAction{
+			{Type: models.ActionSetStatus, Value: []string{"2"}},
+			{Type: models.ActionAddTags, Value: []string{"vip"}},
+		},
+		models.OperatorAnd,
+	)
+	rule.ID = 5
+	rule.Version = 3
+
+	engine.evalConversationRules([]models.Rule{rule}, createTestConversation(), nil, models.RuleTypeNewConversation)
+
+	assert.Len(t, engine.executions, 1)
+	execution := <-engine.executions
+	assert.Equal(t, 5, execution.RuleID)
+	assert.Equal(t, 3, execution.RuleVersion)
+	assert.Equal(t, models.RuleTypeNewConversation, execution.Trigger)
+
+	var matched []models.RuleDetail
+	assert.NoError(t, json.Unmarshal(execution.MatchedConditions, &matched))
+	assert.Len(t, matched, 1, "only the status condition matched")
+	assert.Equal(t, models.ConversationStatus, matched[0].Field)
+
+	var actions []models.ExecutedAction
+	assert.NoError(t, json.Unmarshal(execution.Actions, &actions))
+	assert.Len(t, actions, 2)
+	assert.Empty(t, actions[0].Error)
+	assert.Equal(t, "tag not found", actions[1].Error)
+	assert.True(t, execution.Error.Valid)
+
+	for _, call := range mockStore.Calls {
+		assert.Equal(t, 5, call.Arguments.Get(0).(models.RuleAction).RuleID, "actions carry the rule ID")
+	}
+}
+
+func TestEvalConversationRules_RecordsConditionsOfMatchedGroups(t *testing.T) {
+	mockStore := new(mockConversationStore)
+	mockStore.On("ApplyAction", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	engine := createTestEngine(mockStore)
+	engine.executions = make(chan models.Execution, 1)
+
+	rule := createTestRule(
+		[]models.RuleGroup{
+			{
+				// Fails on the inbox, so its met status condition did not make the rule fire.
+				LogicalOp: models.OperatorAnd,
+				Rules: []models.RuleDetail{
+					{Field: models.ConversationStatus, Operator: models.RuleOperatorEquals, Value: "1", FieldType: models.FieldTypeConversationField},
+					{Field: models.ConversationInbox, Operator: models.RuleOperatorEquals, Value: "9", FieldType: models.FieldTypeConversationField},
+				},
+			},
+			{
+				LogicalOp: models.OperatorOR,
+				Rules: []models.RuleDetail{
+					{Field: models.ConversationInbox, Operator: models.RuleOperatorEquals, Value: "1", FieldType: models.FieldTypeConversationField},
+					{Field: models.ConversationPriority, Operator: models.RuleOperatorEquals, Value: "3", FieldType: models.FieldTypeConversationField},
+				},
+			},
+		},
+		[]models.RuleAction{{Type: models.ActionSetStatus, Value: []string{"2"}}},
+		models.OperatorOR,
+	)
+	rule.ID = 5
+
+	engine.evalConversationRules([]models.Rule{rule}, createTestConversation(), nil, models.RuleTypeNewConversation)
+
+	execution := <-engine.executions
+	var matched []models.RuleDetail
+	assert.NoError(t, json.Unmarshal(execution.MatchedConditions, &matched))
+	assert.Equal(t, []models.RuleDetail{rule.Groups[1].Rules[0]}, matched)
+}
+
+func TestEvalConversationRules_UnsavedRuleNotRecorded(t *testing.T) {
+	mockStore := new(mockConversationStore)
+	mockStore.On("ApplyAction", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	engine := createTestEngine(mockStore)
+	engine.executions = make(chan models.Execution, 1)
+
+	rule := createTestRule(
+		[]models.RuleGroup{
+			{
+				LogicalOp: models.OperatorAnd,
+				Rules: []models.RuleDetail{
+					{Field: models.ConversationStatus, Operator: models.RuleOperatorEquals, Value: "1", FieldType: models.FieldTypeConversationField},
+				},
+			},
+		},
+		[]models.RuleAction{{Type: models.ActionSetStatus, Value: []string{"2"}}},
+		models.OperatorAnd,
+	)
+
+	engine.evalConversationRules([]models.Rule{rule}, createTestConversation(), nil, models.RuleTypeNewConversation)
+
+	assert.Len(t, engine.executions, 0)
+}
@@ -8,6 +8,7 @@ import (
 	authzModels "github.com/abhinavxd/libredesk/internal/authz/models"
 	cmodels "github.com/abhinavxd/libredesk/internal/conversation/models"
 	"github.com/lib/pq"
+	"github.com/volatiletech/null/v9"
 )
 
 const (
@@ -26,18 +27,26 @@ const (
 	ActionSnooze          = "snooze"
 	ActionTriggerWebhook  = "trigger_webhook"
 
+	ActionSetConversationAttribute = "set_conversation_attribute"
+	ActionSetContactAttribute      = "set_contact_attribute"
+	ActionAddParticipant           = "add_participant"
+	ActionChangeInbox              = "change_inbox"
+	ActionAITriage                 = "ai_triage"
+	ActionRequireSkill             = "require_skill"
+
 	OperatorAnd = "AND"
 	OperatorOR  = "OR"
 
-	RuleOperatorContains    = "contains"
-	RuleOperatorNotContains = "not contains"
-	RuleOperatorEquals      = "equals"
-	RuleOperatorNotEqual    = "not equals"
-	RuleOperatorSet         = "set"
-	RuleOperatorNotSet      = "not set"
-	RuleOperatorGreaterThan = "greater than"
-	RuleOperatorLessThan    = "less than"
-	RuleOperatorStartsWith  = "starts with"
+	RuleOperatorContains     = "contains"
+	RuleOperatorNotContains  = "not contains"
+	RuleOperatorEquals       = "equals"
+	RuleOperatorNotEqual     = "not equals"
+	RuleOperatorSet          = "set"
+	RuleOperatorNotSet       = "not set"
+	RuleOperatorGreaterThan  = "greater than"
+	RuleOperatorLessThan     = "less than"
+	RuleOperatorStartsWith   = "starts with"
+	RuleOperatorMatchesRegex = "matches regex"
 
 	RuleTypeNewConversation    = "new_conversation"
 	RuleTypeConversationUpdate = "conversation_update"
@@ -55,6 +64,14 @@ const (
 	ConversationHoursSinceResolved   = "hours_since_resolved"
 	ConversationInbox                = "inbox"
 	ContactEmail                     = "contact_email"
+	ContactCompany                   = "contact_company"
+
+	ConversationChannel             = "channel"
+	ConversationTags                = "tags"
+	ConversationWithinBusinessHours = "within_business_hours"
+	ConversationContactMessageCount = "contact_message_count"
+	ConversationHasAttachment       = "has_attachment"
+	ContactReturning                = "returning_contact"
 
 	ConversationPreviousStatus       = "previous_status"
 	ConversationPreviousPriority     = "previous_priority"
@@ -80,6 +97,15 @@ const (
 
 	FieldTypeContactCustomAttribute = "contact_custom_attribute"
 	FieldTypeConversationField      = "conversation"
+
+	// TriggerScheduledAction is the execution trigger of delayed actions.
+	TriggerScheduledAction = "scheduled_action"
+
+	ScheduledActionStatusPending   = "pending"
+	ScheduledActionStatusRunning   = "running"
+	ScheduledActionStatusCompleted = "completed"
+	ScheduledActionStatusFailed    = "failed"
+	ScheduledActionStatusCancelled = "cancelled"
 )
 
 // ActionPermissions maps actions to permissions
@@ -94,6 +120,13 @@ var ActionPermissions = map[string]string{
 	ActionSetTags:         authzModels.PermConversationsUpdateTags,
 	ActionRemoveTags:      authzModels.PermConversationsUpdateTags,
 	ActionSnooze:          authzModels.PermConversationsUpdateStatus,
+
+	ActionSetConversationAttribute: authzModels.PermConversationWrite,
+	ActionSetContactAttribute:      authzModels.PermContactsWrite,
+	ActionAddParticipant:           authzModels.PermConversationWrite,
+	ActionChangeInbox:              authzModels.PermConversationWrite,
+	ActionAITriage:                 authzModels.PermConversationWrite,
+	ActionRequireSkill:             authzModels.PermConversationWrite,
 }
 
 // RuleRecord represents a rule record in the database
@@ -108,10 +141,14 @@ type RuleRecord struct {
 	Enabled       bool            `db:"enabled" json:"enabled"`
 	Weight        int             `db:"weight" json:"weight"`
 	ExecutionMode string          `db:"execution_mode" json:"execution_mode"`
+	Version       int             `db:"version" json:"version"`
 	Rules         json.RawMessage `db:"rules" json:"rules"`
 }
 
 type Rule struct {
+	// ID and Version are set from the rule record.
+	ID            int          `json:"-"`
+	Version       int          `json:"-"`
 	Type          string       `json:"type"`
 	ExecutionMode string       `json:"execution_mode"`
 	Events        []string     `json:"event"`
@@ -138,6 +175,12 @@ type RuleAction struct {
 	Value        []string `json:"value" db:"value"`
 	DisplayValue []string `json:"display_value" db:"-"`
 
+	// Delay postpones the action after the rule fires, as a duration such as 48h.
+	Delay string `json:"delay,omitempty"`
+
+	// RuleID is the rule applying the action, used to link conversation activity back to the rule.
+	RuleID int `json:"-" db:"-"`
+
 	// Set only for the notify action.
 	Subject    string   `json:"subject,omitempty"`
 	Message    string   `json:"message,omitempty"`
@@ -166,3 +209,139 @@ func PreviousValues(conv cmodels.Conversation) map[string]string {
 	}
 	return values
 }
+
+// SimulationResult is the outcome of evaluating a rule against a sample of conversations without applying its actions.
+type SimulationResult struct {
+	Evaluated     int                     `json:"evaluated"`
+	Matched       int                     `json:"matched"`
+	Conversations []SimulatedConversation `json:"conversations"`
+}
+
+// SimulatedConversation is the evaluation of a rule against a single conversation.
+type SimulatedConversation struct {
+	UUID            string        `json:"uuid"`
+	ReferenceNumber string        `json:"reference_number"`
+	Subject         string        `json:"subject"`
+	Matched         bool          `json:"matched"`
+	Groups          []GroupResult `json:"groups"`
+	// Actions that would run, empty when the rule does not match.
+	Actions []RuleAction `json:"actions"`
+}
+
+// GroupResult is the evaluation of a rule group and each of its conditions.
+type GroupResult struct {
+	LogicalOp  string            `json:"logical_op"`
+	Result     bool              `json:"result"`
+	Conditions []ConditionResult `json:"conditions"`
+}
+
+// ConditionResult is the evaluation of a single condition.
+type ConditionResult struct {
+	RuleDetail
+	Result bool `json:"result"`
+}
+
+// Execution is a record of a rule firing on a conversation.
+type Execution struct {
+	ID                int64           `db:"id" json:"id"`
+	CreatedAt         time.Time       `db:"created_at" json:"created_at"`
+	RuleID            int             `db:"rule_id" json:"rule_id"`
+	RuleName          string          `db:"rule_name" json:"rule_name"`
+	RuleVersion       int             `db:"rule_version" json:"rule_version"`
+	ConversationID    int             `db:"conversation_id" json:"conversation_id"`
+	ConversationUUID  string          `db:"conversation_uuid" json:"conversation_uuid"`
+	ReferenceNumber   string          `db:"reference_number" json:"reference_number"`
+	Trigger           string          `db:"trigger" json:"trigger"`
+	MatchedConditions json.RawMessage `db:"matched_conditions" json:"matched_conditions"`
+	Actions           json.RawMessage `db:"actions" json:"actions"`
+	Error             null.String     `db:"error" json:"error"`
+	Total             int             `db:"total" json:"-"`
+}
+
+// ExecutedAction is an action applied by a rule and the error it returned, if any.
+type ExecutedAction struct {
+	RuleAction
+	Error string `json:"error,omitempty"`
+	// RunAt is set for delayed actions, which are scheduled instead of applied.
+	RunAt null.Time `json:"run_at"`
+}
+
+// ScheduledAction is a delayed rule action waiting to be applied on a conversation.
+type ScheduledAction struct {
+	ID             int64           `db:"id" json:"id"`
+	CreatedAt      time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time       `db:"updated_at" json:"updated_at"`
+	RuleID         int             `db:"rule_id" json:"rule_id"`
+	RuleName       string          `db:"rule_name" json:"rule_name"`
+	RuleVersion    int             `db:"rule_version" json:"rule_version"`
+	ConversationID int             `db:"conversation_id" json:"conversation_id"`
+	Action         json.RawMessage `db:"action" json:"action"`
+	PreviousValues json.RawMessage `db:"previous_values" json:"-"`
+	RunAt          time.Time       `db:"run_at" json:"run_at"`
+	Status         string          `db:"status" json:"status"`
+	Error          null.String     `db:"error" json:"error"`
+}
+
+// RuleVersion is a snapshot of a rule saved on every change, with the fields changed from the previous version.
+type RuleVersion struct {
+	ID          int64           `db:"id" json:"id"`
+	CreatedAt   time.Time       `db:"created_at" json:"created_at"`
+	RuleID      int             `db:"rule_id" json:"rule_id"`
+	Version     int             `db:"version" json:"version"`
+	AuthorID    null.Int        `db:"author_id" json:"author_id"`
+	AuthorName  string          `db:"author_name" json:"author_name"`
+	Name        string          `db:"name" json:"name"`
+	Description string          `db:"description" json:"description"`
+	Type        string          `db:"type" json:"type"`
+	Events      pq.StringArray  `db:"events" json:"events"`
+	Rules       json.RawMessage `db:"rules" json:"rules"`
+	Enabled     bool            `db:"enabled" json:"enabled"`
+	Diff        json.RawMessage `db:"diff" json:"diff"`
+	Total       int             `db:"total" json:"-"`
+}
+
+// RuleChange is a rule field changed by a version.
+type RuleChange struct {
+	Field string          `json:"field"`
+	From  json.RawMessage `json:"from"`
+	To    json.RawMessage `json:"to"`
+}
+
+// RuleExport is a rule in the export format, referring to teams, agents, statuses and other records by name instead of ID
+// so that it can be imported on another instance.
+type RuleExport struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Type        string   `json:"type"`
+	Events      []string `json:"events"`
+	Enabled     bool     `json:"enabled"`
+	Weight      int      `json:"weight"`
+	// ExecutionMode is shared by all rules of the type, empty keeps the mode of the instance.
+	ExecutionMode string `json:"execution_mode,omitempty"`
+	Rules         []Rule `json:"rules"`
+}
+
+// ImportResult is the outcome of importing a set of exported rules.
+type ImportResult struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+// Schedule is an automation that applies actions to the conversations matching its filters at the times of a cron expression.
+type Schedule struct {
+	ID             int             `db:"id" json:"id"`
+	CreatedAt      time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time       `db:"updated_at" json:"updated_at"`
+	Name           string          `db:"name" json:"name"`
+	Description    string          `db:"description" json:"description"`
+	Enabled        bool            `db:"enabled" json:"enabled"`
+	CronExpression string          `db:"cron_expression" json:"cron_expression"`
+	Timezone       string          `db:"timezone" json:"timezone"`
+	Filters        json.RawMessage `db:"filters" json:"filters"`
+	Actions        json.RawMessage `db:"actions" json:"actions"`
+	NextRunAt      null.Time       `db:"next_run_at" json:"next_run_at"`
+	LastRunAt      null.Time       `db:"last_run_at" json:"last_run_at"`
+	// Number of conversations the actions were applied to on the last run.
+	LastRunConversations int         `db:"last_run_conversations" json:"last_run_conversations"`
+	LastRunError         null.String `db:"last_run_error" json:"last_run_error"`
+}
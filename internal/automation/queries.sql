@@ -1,5 +1,7 @@
 -- name: get-enabled-rules
 select
+    id,
+    version,
     type,
     events,
     rules,
@@ -7,10 +9,13 @@ select
 from automation_rules where enabled is TRUE ORDER BY weight ASC;
 
 -- name: get-all
-SELECT id, created_at, updated_at, "name", description, "type", rules, events, enabled, weight, execution_mode from automation_rules where type = $1 ORDER BY weight ASC;
+SELECT id, created_at, updated_at, "name", description, "type", rules, events, enabled, weight, execution_mode, version from automation_rules where type = $1 ORDER BY weight ASC;
 
 -- name: get-rule
-SELECT id, created_at, updated_at, "name", description, "type", rules, events, enabled, weight, execution_mode from automation_rules where id = $1;
+SELECT id, created_at, updated_at, "name", description, "type", rules, events, enabled, weight, execution_mode, version from automation_rules where id = $1;
+
+-- name: get-all-rules
+SELECT id, created_at, updated_at, "name", description, "type", rules, events, enabled, weight, execution_mode, version from automation_rules ORDER BY "type", weight ASC, id ASC;
 
 -- name: update-rule
 INSERT INTO automation_rules(id, name, description, type, events, rules, enabled)
@@ -23,13 +28,14 @@ DO UPDATE SET
     events = EXCLUDED.events,
     rules = EXCLUDED.rules,
     enabled = EXCLUDED.enabled,
+    version = automation_rules.version + 1,
     updated_at = now()
 WHERE $1 > 0
 RETURNING *;
 
 -- name: insert-rule
-INSERT into automation_rules (name, description, type, events, rules) 
-values ($1, $2, $3, $4, $5)
+INSERT into automation_rules (name, description, type, events, rules, enabled) 
+values ($1, $2, $3, $4, $5, $6)
 RETURNING *;
 
 -- name: delete-rule
@@ -49,4 +55,164 @@ WHERE id = $1;
 -- name: update-rule-execution-mode
 UPDATE automation_rules
 SET execution_mode = $2, updated_at = NOW()
-WHERE type = $1;
\ No newline at end of file
+WHERE type = $1;
+
+-- name: insert-execution
+INSERT INTO automation_rule_executions (rule_id, rule_version, conversation_id, "trigger", matched_conditions, actions, error)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: get-rule-executions
+SELECT COUNT(*) OVER() AS total, e.id, e.created_at, e.rule_id, r.name AS rule_name, e.rule_version, e.conversation_id,
+    c.uuid AS conversation_uuid, c.reference_number, e.trigger, e.matched_conditions, e.actions, e.error
+FROM automation_rule_executions e
+JOIN automation_rules r ON r.id = e.rule_id
+JOIN conversations c ON c.id = e.conversation_id
+WHERE e.rule_id = $1
+ORDER BY e.created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: get-conversation-executions
+SELECT COUNT(*) OVER() AS total, e.id, e.created_at, e.rule_id, r.name AS rule_name, e.rule_version, e.conversation_id,
+    c.uuid AS conversation_uuid, c.reference_number, e.trigger, e.matched_conditions, e.actions, e.error
+FROM automation_rule_executions e
+JOIN automation_rules r ON r.id = e.rule_id
+JOIN conversations c ON c.id = e.conversation_id
+WHERE c.uuid = $1
+ORDER BY e.created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: delete-executions-before
+DELETE FROM automation_rule_executions WHERE created_at < $1;
+
+-- name: insert-scheduled-action
+INSERT INTO automation_scheduled_actions (rule_id, rule_version, conversation_id, action, previous_values, run_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT DO NOTHING;
+
+-- name: claim-due-scheduled-actions
+-- Marks due actions as running so that each is picked up once.
+UPDATE automation_scheduled_actions
+SET status = 'running', updated_at = NOW()
+WHERE id IN (
+    SELECT id FROM automation_scheduled_actions
+    WHERE status = 'pending' AND run_at <= NOW()
+    ORDER BY run_at
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, created_at, updated_at, rule_id, rule_version, conversation_id, action, previous_values, run_at, status, error;
+
+-- name: reset-running-scheduled-actions
+-- Actions left running by a shutdown are retried.
+UPDATE automation_scheduled_actions SET status = 'pending', updated_at = NOW() WHERE status = 'running';
+
+-- name: get-pending-scheduled-actions
+SELECT id, created_at, updated_at, rule_id, rule_version, conversation_id, action, previous_values, run_at, status, error
+FROM automation_scheduled_actions
+WHERE conversation_id = $1 AND status = 'pending';
+
+-- name: get-conversation-scheduled-actions
+SELECT s.id, s.created_at, s.updated_at, s.rule_id, r.name AS rule_name, s.rule_version, s.conversation_id, s.action, s.previous_values,
+    s.run_at, s.status, s.error
+FROM automation_scheduled_actions s
+JOIN automation_rules r ON r.id = s.rule_id
+JOIN conversations c ON c.id = s.conversation_id
+WHERE c.uuid = $1
+ORDER BY s.run_at DESC;
+
+-- name: update-scheduled-action-status
+UPDATE automation_scheduled_actions
+SET status = $2, error = $3, updated_at = NOW()
+WHERE id = $1;
+
+-- name: update-scheduled-action-previous-values
+UPDATE automation_scheduled_actions
+SET previous_values = $2, updated_at = NOW()
+WHERE id = $1 AND status = 'pending';
+
+-- name: delete-finished-scheduled-actions-before
+DELETE FROM automation_scheduled_actions WHERE status NOT IN ('pending', 'running') AND updated_at < $1;
+
+-- name: insert-rule-version
+INSERT INTO automation_rule_versions (rule_id, "version", author_id, "name", description, "type", events, rules, enabled, diff)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+ON CONFLICT (rule_id, "version") DO NOTHING;
+
+-- name: get-rule-versions
+SELECT COUNT(*) OVER() AS total, v.id, v.created_at, v.rule_id, v.version, v.author_id,
+    COALESCE(TRIM(CONCAT(u.first_name, ' ', COALESCE(u.last_name, ''))), '') AS author_name,
+    v.name, COALESCE(v.description, '') AS description, v.type, v.events, v.rules, v.enabled, v.diff
+FROM automation_rule_versions v
+LEFT JOIN users u ON u.id = v.author_id
+WHERE v.rule_id = $1
+ORDER BY v.version DESC
+LIMIT $2 OFFSET $3;
+
+-- name: get-rule-version
+SELECT v.id, v.created_at, v.rule_id, v.version, v.author_id,
+    COALESCE(TRIM(CONCAT(u.first_name, ' ', COALESCE(u.last_name, ''))), '') AS author_name,
+    v.name, COALESCE(v.description, '') AS description, v.type, v.events, v.rules, v.enabled, v.diff
+FROM automation_rule_versions v
+LEFT JOIN users u ON u.id = v.author_id
+WHERE v.rule_id = $1 AND v.version = $2;
+
+-- name: get-rule-references
+-- Records that rule conditions and actions refer to by ID, with the name used for them in exports.
+SELECT 'team' AS kind, id, "name" FROM teams
+UNION ALL
+SELECT 'user', id, email FROM users WHERE type = 'agent' AND deleted_at IS NULL AND email IS NOT NULL
+UNION ALL
+SELECT 'status', id, "name" FROM conversation_statuses
+UNION ALL
+SELECT 'priority', id, "name" FROM conversation_priorities
+UNION ALL
+SELECT 'inbox', id, "name" FROM inboxes WHERE deleted_at IS NULL
+UNION ALL
+SELECT 'sla', id, "name" FROM sla_policies
+UNION ALL
+SELECT 'webhook', id, "name" FROM webhooks
+UNION ALL
+SELECT 'skill', id, "name" FROM skills;
+
+-- name: get-schedules
+SELECT id, created_at, updated_at, "name", description, enabled, cron_expression, timezone, filters, actions, next_run_at, last_run_at,
+    last_run_conversations, last_run_error
+FROM automation_schedules ORDER BY id;
+
+-- name: get-schedule
+SELECT id, created_at, updated_at, "name", description, enabled, cron_expression, timezone, filters, actions, next_run_at, last_run_at,
+    last_run_conversations, last_run_error
+FROM automation_schedules WHERE id = $1;
+
+-- name: insert-schedule
+INSERT INTO automation_schedules ("name", description, enabled, cron_expression, timezone, filters, actions, next_run_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: update-schedule
+UPDATE automation_schedules
+SET "name" = $2, description = $3, enabled = $4, cron_expression = $5, timezone = $6, filters = $7, actions = $8, next_run_at = $9,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: delete-schedule
+DELETE FROM automation_schedules WHERE id = $1;
+
+-- name: get-due-schedules
+SELECT id, created_at, updated_at, "name", description, enabled, cron_expression, timezone, filters, actions, next_run_at, last_run_at,
+    last_run_conversations, last_run_error
+FROM automation_schedules
+WHERE enabled = TRUE AND next_run_at <= NOW()
+ORDER BY next_run_at;
+
+-- name: claim-schedule-run
+-- Moves a due schedule to its next run, only if no other instance has done so already.
+UPDATE automation_schedules
+SET next_run_at = $2, last_run_at = NOW()
+WHERE id = $1 AND next_run_at = $3;
+
+-- name: update-schedule-run-result
+UPDATE automation_schedules
+SET last_run_conversations = $2, last_run_error = $3
+WHERE id = $1;
@@ -0,0 +1,87 @@
+package automation
+
+import (
+	"regexp"
+	"sync"
+	"time"
+)
+
+const (
+	maxRegexPatternLength = 1000
+	maxRegexInputLength   = 64 * 1024
+	regexMatchTimeout     = 100 * time.Millisecond
+	// maxRegexMatches caps the matches running at once, including timed out ones that have not finished yet.
+	maxRegexMatches = 32
+	// maxCachedRegexes caps the number of compiled patterns kept.
+	maxCachedRegexes = 1000
+)
+
+var (
+	// regexCache holds compiled patterns, with nil for patterns that failed to compile.
+	regexCache   = make(map[string]*regexp.Regexp)
+	regexCacheMu sync.RWMutex
+
+	// regexSlots is taken by each running match and released when the match finishes.
+	regexSlots = make(chan struct{}, maxRegexMatches)
+)
+
+// matchRegex reports whether input matches pattern. Go's RE2 engine runs in time linear in the input, so patterns
+// cannot backtrack catastrophically; the length caps and the timeout bound the work done on very large messages.
+// Invalid patterns, timed out matches and matches that find all slots taken never match.
+func (e *Engine) matchRegex(pattern, input string, caseSensitive bool) bool {
+	if pattern == "" || len(pattern) > maxRegexPatternLength {
+		e.lo.Warn("regex pattern empty or too long, skipping rule", "length", len(pattern), "max", maxRegexPatternLength)
+		return false
+	}
+	if !caseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	re := e.compileRegex(pattern)
+	if re == nil {
+		return false
+	}
+	if len(input) > maxRegexInputLength {
+		input = input[:maxRegexInputLength]
+	}
+
+	select {
+	case regexSlots <- struct{}{}:
+	default:
+		e.lo.Warn("too many regex matches running, skipping rule", "pattern", pattern, "max", maxRegexMatches)
+		return false
+	}
+	result := make(chan bool, 1)
+	go func() {
+		defer func() { <-regexSlots }()
+		result <- re.MatchString(input)
+	}()
+	select {
+	case matched := <-result:
+		return matched
+	case <-time.After(regexMatchTimeout):
+		e.lo.Warn("regex match timed out", "pattern", pattern, "timeout", regexMatchTimeout)
+		return false
+	}
+}
+
+// compileRegex returns the compiled pattern from the cache, compiling and caching it if needed. Returns nil for invalid patterns.
+func (e *Engine) compileRegex(pattern string) *regexp.Regexp {
+	regexCacheMu.RLock()
+	re, ok := regexCache[pattern]
+	regexCacheMu.RUnlock()
+	if ok {
+		return re
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		e.lo.Warn("invalid regex pattern in rule", "pattern", pattern, "error", err)
+		re = nil
+	}
+	regexCacheMu.Lock()
+	if len(regexCache) < maxCachedRegexes {
+		regexCache[pattern] = re
+	}
+	regexCacheMu.Unlock()
+	return re
+}
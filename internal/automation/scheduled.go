@@ -0,0 +1,196 @@
+package automation
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/abhinavxd/libredesk/internal/automation/models"
+	cmodels "github.com/abhinavxd/libredesk/internal/conversation/models"
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	umodels "github.com/abhinavxd/libredesk/internal/user/models"
+	"github.com/volatiletech/null/v9"
+)
+
+const (
+	scheduledActionsInterval = time.Minute
+	scheduledActionsBatch    = 100
+)
+
+// GetConversationScheduledActions returns the delayed actions of a conversation, latest first.
+func (e *Engine) GetConversationScheduledActions(conversationUUID string) ([]models.ScheduledAction, error) {
+	var actions = make([]models.ScheduledAction, 0)
+	if err := e.q.GetConversationScheduledActions.Select(&actions, conversationUUID); err != nil {
+		e.lo.Error("error fetching conversation scheduled actions", "conversation_uuid", conversationUUID, "error", err)
+		return nil, envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return actions, nil
+}
+
+// scheduleAction queues a delayed action of a rule that fired on a conversation and returns when it is due.
+func (e *Engine) scheduleAction(rule models.Rule, conversation cmodels.Conversation, previousValues map[string]string, action models.RuleAction) (time.Time, error) {
+	delay, err := time.ParseDuration(action.Delay)
+	if err != nil || delay <= 0 {
+		return time.Time{}, fmt.Errorf("invalid delay %q", action.Delay)
+	}
+	runAt := time.Now().Add(delay)
+
+	actionJSON, err := json.Marshal(action)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("marshalling action: %w", err)
+	}
+	previousJSON, err := json.Marshal(previousValues)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("marshalling previous values: %w", err)
+	}
+	if _, err := e.q.InsertScheduledAction.Exec(rule.ID, rule.Version, conversation.ID, actionJSON, previousJSON, runAt); err != nil {
+		e.lo.Error("error scheduling action", "rule_id", rule.ID, "conversation_uuid", conversation.UUID, "action", action.Type, "error", err)
+		return time.Time{}, fmt.Errorf("scheduling action: %w", err)
+	}
+	e.lo.Debug("scheduled delayed action", "rule_id", rule.ID, "conversation_uuid", conversation.UUID, "action", action.Type, "run_at", runAt)
+	return runAt, nil
+}
+
+// handleScheduledActions applies delayed actions that are due, after re-checking that their rule still matches.
+func (e *Engine) handleScheduledActions() {
+	var due []models.ScheduledAction
+	if err := e.q.ClaimDueScheduledActions.Select(&due, scheduledActionsBatch); err != nil {
+		e.lo.Error("error fetching due scheduled actions", "error", err)
+		return
+	}
+	for _, scheduled := range due {
+		e.runScheduledAction(scheduled)
+	}
+}
+
+// runScheduledAction applies a due delayed action, or cancels it if its rule changed or no longer matches the conversation.
+func (e *Engine) runScheduledAction(scheduled models.ScheduledAction) {
+	var (
+		action         models.RuleAction
+		previousValues map[string]string
+	)
+	if err := json.Unmarshal(scheduled.Action, &action); err != nil {
+		e.setScheduledActionStatus(scheduled.ID, models.ScheduledActionStatusFailed, err.Error())
+		return
+	}
+	_ = json.Unmarshal(scheduled.PreviousValues, &previousValues)
+
+	conversation, err := e.conversationStore.GetConversation(scheduled.ConversationID, "", "")
+	if err != nil {
+		e.lo.Error("error fetching conversation for scheduled action", "conversation_id", scheduled.ConversationID, "error", err)
+		e.setScheduledActionStatus(scheduled.ID, models.ScheduledActionStatusFailed, err.Error())
+		return
+	}
+
+	rule, matched, ok := e.scheduledActionRule(scheduled, conversation, previousValues)
+	if !ok {
+		e.setScheduledActionStatus(scheduled.ID, models.ScheduledActionStatusCancelled, "")
+		return
+	}
+
+	action.RuleID = rule.ID
+	executedAction := models.ExecutedAction{RuleAction: action}
+	e.suppress(conversation.UUID)
+	err = e.conversationStore.ApplyAction(action, conversation, umodels.User{})
+	e.unsuppress(conversation.UUID)
+	if err != nil {
+		e.lo.Error("error applying scheduled action on conversation", "action", action, "conversation_uuid", conversation.UUID, "error", err)
+		executedAction.Error = err.Error()
+		e.setScheduledActionStatus(scheduled.ID, models.ScheduledActionStatusFailed, err.Error())
+	} else {
+		e.setScheduledActionStatus(scheduled.ID, models.ScheduledActionStatusCompleted, "")
+	}
+	e.recordExecution(rule, conversation, matched, models.TriggerScheduledAction, []models.ExecutedAction{executedAction})
+}
+
+// recheckScheduledActions cancels the pending delayed actions of a conversation whose rule changed or no longer matches it.
+// previousValues are the values from before the update, which replace the ones saved with each action before it is rechecked.
+func (e *Engine) recheckScheduledActions(conversation cmodels.Conversation, previousValues map[string]string) {
+	var pending []models.ScheduledAction
+	if err := e.q.GetPendingScheduledActions.Select(&pending, conversation.ID); err != nil {
+		e.lo.Error("error fetching pending scheduled actions", "conversation_uuid", conversation.UUID, "error", err)
+		return
+	}
+	for _, scheduled := range pending {
+		var saved map[string]string
+		_ = json.Unmarshal(scheduled.PreviousValues, &saved)
+		refreshed, changed := refreshPreviousValues(saved, previousValues)
+		if _, _, ok := e.scheduledActionRule(scheduled, conversation, refreshed); !ok {
+			e.setScheduledActionStatus(scheduled.ID, models.ScheduledActionStatusCancelled, "")
+			continue
+		}
+		if changed {
+			e.setScheduledActionPreviousValues(scheduled.ID, refreshed)
+		}
+	}
+}
+
+// refreshPreviousValues returns the saved previous values of a delayed action updated with those of a later update,
+// and whether any of them changed.
+func refreshPreviousValues(saved, update map[string]string) (map[string]string, bool) {
+	var (
+		refreshed = make(map[string]string, len(saved)+len(update))
+		changed   bool
+	)
+	for k, v := range saved {
+		refreshed[k] = v
+	}
+	for k, v := range update {
+		if old, ok := refreshed[k]; !ok || old != v {
+			changed = true
+		}
+		refreshed[k] = v
+	}
+	return refreshed, changed
+}
+
+// setScheduledActionPreviousValues saves the refreshed previous values of a pending delayed action.
+func (e *Engine) setScheduledActionPreviousValues(id int64, previousValues map[string]string) {
+	previousJSON, err := json.Marshal(previousValues)
+	if err != nil {
+		e.lo.Error("error marshalling previous values", "id", id, "error", err)
+		return
+	}
+	if _, err := e.q.UpdateScheduledActionPreviousValues.Exec(id, previousJSON); err != nil {
+		e.lo.Error("error updating scheduled action previous values", "id", id, "error", err)
+	}
+}
+
+// scheduledActionRule returns the rule of a delayed action if it is still enabled, unchanged since the action was scheduled,
+// and its conditions still match the conversation, along with the conditions that matched.
+func (e *Engine) scheduledActionRule(scheduled models.ScheduledAction, conversation cmodels.Conversation, previousValues map[string]string) (models.Rule, []models.RuleDetail, bool) {
+	e.rulesMu.RLock()
+	var (
+		rule  models.Rule
+		found bool
+	)
+	for _, r := range e.rules {
+		if r.ID == scheduled.RuleID {
+			rule, found = r, true
+			break
+		}
+	}
+	e.rulesMu.RUnlock()
+
+	if !found || rule.Version != scheduled.RuleVersion {
+		e.lo.Debug("rule of scheduled action disabled or changed, cancelling", "scheduled_action_id", scheduled.ID, "rule_id", scheduled.RuleID)
+		return rule, nil, false
+	}
+	matched, groups := e.ruleMatches(rule, conversation, previousValues)
+	if !matched {
+		e.lo.Debug("rule of scheduled action no longer matches, cancelling", "scheduled_action_id", scheduled.ID, "conversation_uuid", conversation.UUID)
+		return rule, nil, false
+	}
+	return rule, matchedConditions(groups), true
+}
+
+// setScheduledActionStatus updates the status of a delayed action.
+func (e *Engine) setScheduledActionStatus(id int64, status, errMsg string) {
+	var errStr null.String
+	if errMsg != "" {
+		errStr = null.StringFrom(errMsg)
+	}
+	if _, err := e.q.UpdateScheduledActionStatus.Exec(id, status, errStr); err != nil {
+		e.lo.Error("error updating scheduled action status", "id", id, "status", status, "error", err)
+	}
+}
@@ -0,0 +1,74 @@
+package automation
+
+import (
+	"testing"
+
+	"github.com/abhinavxd/libredesk/internal/automation/models"
+	cmodels "github.com/abhinavxd/libredesk/internal/conversation/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/null/v9"
+)
+
+func pendingStatusRule() models.Rule {
+	rule := createTestRule(
+		[]models.RuleGroup{
+			{
+				LogicalOp: models.OperatorAnd,
+				Rules: []models.RuleDetail{
+					{Field: models.ConversationStatus, Operator: models.RuleOperatorEquals, Value: "3", FieldType: models.FieldTypeConversationField},
+				},
+			},
+		},
+		[]models.RuleAction{{Type: models.ActionReply, Value: []string{"Any update?"}, Delay: "48h"}},
+		models.OperatorAnd,
+	)
+	rule.ID = 7
+	rule.Version = 2
+	return rule
+}
+
+func TestScheduledActionRule_StillMatching(t *testing.T) {
+	engine := createTestEngine(new(mockConversationStore))
+	engine.rules = []models.Rule{pendingStatusRule()}
+	conversation := createTestConversation(func(c *cmodels.Conversation) { c.StatusID = null.IntFrom(3) })
+
+	rule, _, ok := engine.scheduledActionRule(models.ScheduledAction{RuleID: 7, RuleVersion: 2}, conversation, nil)
+
+	assert.True(t, ok)
+	assert.Equal(t, 7, rule.ID)
+}
+
+func TestScheduledActionRule_ConversationChanged(t *testing.T) {
+	engine := createTestEngine(new(mockConversationStore))
+	engine.rules = []models.Rule{pendingStatusRule()}
+	conversation := createTestConversation(func(c *cmodels.Conversation) { c.StatusID = null.IntFrom(1) })
+
+	_, _, ok := engine.scheduledActionRule(models.ScheduledAction{RuleID: 7, RuleVersion: 2}, conversation, nil)
+
+	assert.False(t, ok, "action must be cancelled once the conversation no longer matches")
+}
+
+func TestScheduledActionRule_RuleChangedOrDisabled(t *testing.T) {
+	engine := createTestEngine(new(mockConversationStore))
+	engine.rules = []models.Rule{pendingStatusRule()}
+	conversation := createTestConversation(func(c *cmodels.Conversation) { c.StatusID = null.IntFrom(3) })
+
+	_, _, ok := engine.scheduledActionRule(models.ScheduledAction{RuleID: 7, RuleVersion: 1}, conversation, nil)
+	assert.False(t, ok, "action scheduled by an older rule version must be cancelled")
+
+	_, _, ok = engine.scheduledActionRule(models.ScheduledAction{RuleID: 8, RuleVersion: 1}, conversation, nil)
+	assert.False(t, ok, "action of a disabled or deleted rule must be cancelled")
+}
+
+func TestRefreshPreviousValues(t *testing.T) {
+	saved := map[string]string{"status": "Open", "priority": "Low"}
+
+	refreshed, changed := refreshPreviousValues(saved, map[string]string{"status": "Open"})
+	assert.False(t, changed)
+	assert.Equal(t, saved, refreshed)
+
+	refreshed, changed = refreshPreviousValues(saved, map[string]string{"status": "Replied"})
+	assert.True(t, changed)
+	assert.Equal(t, map[string]string{"status": "Replied", "priority": "Low"}, refreshed)
+	assert.Equal(t, "Open", saved["status"], "the saved values are not modified")
+}
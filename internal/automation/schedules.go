@@ -0,0 +1,300 @@
+package automation
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/abhinavxd/libredesk/internal/automation/models"
+	cmodels "github.com/abhinavxd/libredesk/internal/conversation/models"
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	umodels "github.com/abhinavxd/libredesk/internal/user/models"
+	"github.com/volatiletech/null/v9"
+)
+
+// scheduleMaxConversations is the maximum number of conversations a schedule applies its actions to on a run.
+const scheduleMaxConversations = 1000
+
+// relativeTimeRe matches filter values relative to the time of a schedule run, e.g. now-2h.
+var relativeTimeRe = regexp.MustCompile(`^now(?:[+-][0-9].*)?$`)
+
+// GetSchedules returns all scheduled automations.
+func (e *Engine) GetSchedules() ([]models.Schedule, error) {
+	var schedules = make([]models.Schedule, 0)
+	if err := e.q.GetSchedules.Select(&schedules); err != nil {
+		e.lo.Error("error fetching automation schedules", "error", err)
+		return nil, envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return schedules, nil
+}
+
+// GetSchedule returns a scheduled automation by ID.
+func (e *Engine) GetSchedule(id int) (models.Schedule, error) {
+	var schedule models.Schedule
+	if err := e.q.GetSchedule.Get(&schedule, id); err != nil {
+		if err == sql.ErrNoRows {
+			return schedule, envelope.NewError(envelope.NotFoundError, e.i18n.T("automation.notFoundSchedule"), nil)
+		}
+		e.lo.Error("error fetching automation schedule", "id", id, "error", err)
+		return schedule, envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return schedule, nil
+}
+
+// CreateSchedule creates a scheduled automation.
+func (e *Engine) CreateSchedule(schedule models.Schedule) (models.Schedule, error) {
+	nextRun, err := e.validateSchedule(&schedule)
+	if err != nil {
+		return models.Schedule{}, err
+	}
+	var result models.Schedule
+	if err := e.q.InsertSchedule.Get(&result, schedule.Name, schedule.Description, schedule.Enabled, schedule.CronExpression,
+		schedule.Timezone, schedule.Filters, schedule.Actions, nextRun); err != nil {
+		e.lo.Error("error creating automation schedule", "error", err)
+		return models.Schedule{}, envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return result, nil
+}
+
+// UpdateSchedule updates a scheduled automation, moving its next run to match the new cron expression.
+func (e *Engine) UpdateSchedule(id int, schedule models.Schedule) (models.Schedule, error) {
+	nextRun, err := e.validateSchedule(&schedule)
+	if err != nil {
+		return models.Schedule{}, err
+	}
+	var result models.Schedule
+	if err := e.q.UpdateSchedule.Get(&result, id, schedule.Name, schedule.Description, schedule.Enabled, schedule.CronExpression,
+		schedule.Timezone, schedule.Filters, schedule.Actions, nextRun); err != nil {
+		if err == sql.ErrNoRows {
+			return models.Schedule{}, envelope.NewError(envelope.NotFoundError, e.i18n.T("automation.notFoundSchedule"), nil)
+		}
+		e.lo.Error("error updating automation schedule", "id", id, "error", err)
+		return models.Schedule{}, envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return result, nil
+}
+
+// DeleteSchedule deletes a scheduled automation.
+func (e *Engine) DeleteSchedule(id int) error {
+	if _, err := e.q.DeleteSchedule.Exec(id); err != nil {
+		e.lo.Error("error deleting automation schedule", "id", id, "error", err)
+		return envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return nil
+}
+
+// validateSchedule validates a schedule, filling in defaults, and returns when it should next run.
+func (e *Engine) validateSchedule(schedule *models.Schedule) (time.Time, error) {
+	schedule.Name = strings.TrimSpace(schedule.Name)
+	if schedule.Name == "" {
+		return time.Time{}, envelope.NewError(envelope.InputError, e.i18n.Ts("globals.messages.empty", "name", "`name`"), nil)
+	}
+	if schedule.Timezone == "" {
+		schedule.Timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return time.Time{}, envelope.NewError(envelope.InputError, e.i18n.T("automation.invalidTimezone"), nil)
+	}
+	cron, err := parseCron(schedule.CronExpression)
+	if err != nil {
+		return time.Time{}, envelope.NewError(envelope.InputError, e.i18n.Ts("automation.invalidCronExpression", "error", err.Error()), nil)
+	}
+	nextRun := cron.next(time.Now().In(loc))
+	if nextRun.IsZero() {
+		return time.Time{}, envelope.NewError(envelope.InputError, e.i18n.Ts("automation.invalidCronExpression", "error", "never runs"), nil)
+	}
+
+	if len(schedule.Filters) == 0 {
+		schedule.Filters = json.RawMessage("[]")
+	}
+	filters, err := resolveRelativeFilterTimes(schedule.Filters, time.Now())
+	if err != nil {
+		return time.Time{}, envelope.NewError(envelope.InputError, e.i18n.T("globals.messages.invalidFilters"), nil)
+	}
+	if err := e.conversationStore.ValidateListFilters(filters); err != nil {
+		return time.Time{}, err
+	}
+
+	var actions []models.RuleAction
+	if err := json.Unmarshal(schedule.Actions, &actions); err != nil || len(actions) == 0 {
+		return time.Time{}, envelope.NewError(envelope.InputError, e.i18n.T("automation.invalidRule"), nil)
+	}
+	for _, action := range actions {
+		if err := validateAction(action); err != nil {
+			return time.Time{}, envelope.NewError(envelope.InputError, e.i18n.Ts("automation.invalidAction", "error", err.Error()), nil)
+		}
+	}
+	return nextRun, nil
+}
+
+// handleSchedules runs the scheduled automations that are due.
+func (e *Engine) handleSchedules() {
+	var due []models.Schedule
+	if err := e.q.GetDueSchedules.Select(&due); err != nil {
+		e.lo.Error("error fetching due automation schedules", "error", err)
+		return
+	}
+	for _, schedule := range due {
+		loc, err := time.LoadLocation(schedule.Timezone)
+		if err != nil {
+			loc = time.UTC
+		}
+		cron, err := parseCron(schedule.CronExpression)
+		if err != nil {
+			e.lo.Error("invalid cron expression on automation schedule", "id", schedule.ID, "error", err)
+			continue
+		}
+		// Runs missed while the app was down are not caught up, the schedule moves on to its next run from now.
+		nextRun := null.NewTime(cron.next(time.Now().In(loc)), true)
+		if nextRun.Time.IsZero() {
+			nextRun = null.Time{}
+		}
+		res, err := e.q.ClaimScheduleRun.Exec(schedule.ID, nextRun, schedule.NextRunAt)
+		if err != nil {
+			e.lo.Error("error claiming automation schedule run", "id", schedule.ID, "error", err)
+			continue
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			continue
+		}
+		e.runSchedule(schedule)
+	}
+}
+
+// runSchedule applies the actions of a schedule to the conversations matching its filters and saves the outcome.
+func (e *Engine) runSchedule(schedule models.Schedule) {
+	count, err := e.applySchedule(schedule)
+	var errStr null.String
+	if err != nil {
+		e.lo.Error("error running automation schedule", "id", schedule.ID, "error", err)
+		errStr = null.StringFrom(err.Error())
+	}
+	e.lo.Info("ran automation schedule", "id", schedule.ID, "name", schedule.Name, "conversations", count)
+	if _, err := e.q.UpdateScheduleRunResult.Exec(schedule.ID, count, errStr); err != nil {
+		e.lo.Error("error saving automation schedule run result", "id", schedule.ID, "error", err)
+	}
+}
+
+// applySchedule applies the actions of a schedule to the conversations matching its filters.
+// It returns the number of conversations the actions were applied to and the errors of those that failed.
+func (e *Engine) applySchedule(schedule models.Schedule) (int, error) {
+	var actions []models.RuleAction
+	if err := json.Unmarshal(schedule.Actions, &actions); err != nil {
+		return 0, fmt.Errorf("unmarshalling actions: %w", err)
+	}
+	filters, err := resolveRelativeFilterTimes(schedule.Filters, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("resolving filters: %w", err)
+	}
+	// Matching conversations are fetched upfront as the actions may change which conversations match.
+	conversations, err := e.conversationStore.GetConversationsByFilters(filters, scheduleMaxConversations)
+	if err != nil {
+		return 0, fmt.Errorf("fetching conversations: %w", err)
+	}
+
+	// Notify actions are sent once per run as a digest of the matched conversations.
+	var notifies []models.RuleAction
+	actions = slices.DeleteFunc(actions, func(action models.RuleAction) bool {
+		if action.Type == models.ActionNotify {
+			notifies = append(notifies, action)
+			return true
+		}
+		return false
+	})
+
+	var (
+		applied = make([]cmodels.Conversation, 0, len(conversations))
+		errs    []error
+	)
+	for _, c := range conversations {
+		conversation, err := e.conversationStore.GetConversation(0, c.UUID, "")
+		if err != nil {
+			errs = append(errs, fmt.Errorf("fetching conversation %s: %w", c.UUID, err))
+			continue
+		}
+		var triage []models.ExecutedAction
+		e.suppress(conversation.UUID)
+		for _, action := range actions {
+			if isAITriage(action) {
+				triage = append(triage, models.ExecutedAction{RuleAction: action})
+				continue
+			}
+			if err := e.conversationStore.ApplyAction(action, conversation, umodels.User{}); err != nil {
+				errs = append(errs, fmt.Errorf("%s on conversation %s: %w", action.Type, conversation.UUID, err))
+			}
+		}
+		e.unsuppress(conversation.UUID)
+		if len(triage) > 0 {
+			e.queueAITriage(aiTriageTask{conversation: conversation, executed: triage})
+		}
+		applied = append(applied, conversation)
+	}
+
+	if len(applied) > 0 {
+		for _, action := range notifies {
+			if err := e.conversationStore.SendAutomationDigest(action.Subject, action.Message, action.Recipients, applied); err != nil {
+				errs = append(errs, fmt.Errorf("%s digest: %w", action.Type, err))
+			}
+		}
+	}
+	return len(applied), errors.Join(errs...)
+}
+
+// resolveRelativeFilterTimes replaces filter values relative to the time of the run, such as now-2h, with the absolute time,
+// so that a schedule can select e.g. conversations created more than two hours ago.
+func resolveRelativeFilterTimes(filters json.RawMessage, now time.Time) (string, error) {
+	var node any
+	if err := json.Unmarshal(filters, &node); err != nil {
+		return "", err
+	}
+	var resolve func(v any) error
+	resolve = func(v any) error {
+		switch v := v.(type) {
+		case []any:
+			for _, child := range v {
+				if err := resolve(child); err != nil {
+					return err
+				}
+			}
+		case map[string]any:
+			if value, ok := v["value"].(string); ok && relativeTimeRe.MatchString(value) {
+				t, err := relativeTime(value, now)
+				if err != nil {
+					return err
+				}
+				v["value"] = t.UTC().Format(time.RFC3339)
+			}
+			if rules, ok := v["rules"]; ok {
+				return resolve(rules)
+			}
+		}
+		return nil
+	}
+	if err := resolve(node); err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(node)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// relativeTime parses now, now-<duration> or now+<duration>, e.g. now-2h, relative to now.
+func relativeTime(value string, now time.Time) (time.Time, error) {
+	offset := strings.TrimSpace(strings.TrimPrefix(value, "now"))
+	if offset == "" {
+		return now, nil
+	}
+	d, err := time.ParseDuration(offset)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid relative time %q", value)
+	}
+	return now.Add(d), nil
+}
@@ -0,0 +1,95 @@
+package automation
+
+import (
+	"encoding/json"
+
+	"github.com/abhinavxd/libredesk/internal/automation/models"
+	cmodels "github.com/abhinavxd/libredesk/internal/conversation/models"
+	"github.com/abhinavxd/libredesk/internal/envelope"
+)
+
+const (
+	DefaultSimulationLimit = 50
+	MaxSimulationLimit     = 200
+)
+
+// SimulateRule evaluates a rule record against the most recent conversations without applying any actions.
+// If the record has an ID and no rules, the saved rule is simulated.
+func (e *Engine) SimulateRule(record models.RuleRecord, limit int) (models.SimulationResult, error) {
+	if record.ID > 0 && len(record.Rules) == 0 {
+		saved, err := e.GetRule(record.ID)
+		if err != nil {
+			return models.SimulationResult{}, err
+		}
+		record = saved
+	}
+
+	var rules []models.Rule
+	if err := json.Unmarshal(record.Rules, &rules); err != nil || len(rules) == 0 {
+		return models.SimulationResult{}, envelope.NewError(envelope.InputError, e.i18n.T("automation.invalidRule"), nil)
+	}
+	for i := range rules {
+		rules[i].Type = record.Type
+		rules[i].Events = record.Events
+		rules[i].ExecutionMode = record.ExecutionMode
+	}
+
+	if limit <= 0 {
+		limit = DefaultSimulationLimit
+	}
+	limit = min(limit, MaxSimulationLimit)
+
+	recent, err := e.conversationStore.GetRecentConversations(limit)
+	if err != nil {
+		return models.SimulationResult{}, envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+
+	var result = models.SimulationResult{Conversations: make([]models.SimulatedConversation, 0, len(recent))}
+	for _, c := range recent {
+		// Fetch entire conversation.
+		conversation, err := e.conversationStore.GetConversation(0, c.UUID, "")
+		if err != nil {
+			e.lo.Error("error fetching conversation for rule simulation", "uuid", c.UUID, "error", err)
+			continue
+		}
+		simulated := e.simulateConversationRules(rules, conversation)
+		result.Evaluated++
+		if simulated.Matched {
+			result.Matched++
+		}
+		result.Conversations = append(result.Conversations, simulated)
+	}
+	return result, nil
+}
+
+// simulateConversationRules evaluates rules against a conversation the same way evalConversationRules does,
+// recording the result of every condition instead of applying actions.
+func (e *Engine) simulateConversationRules(rules []models.Rule, conversation cmodels.Conversation) models.SimulatedConversation {
+	var (
+		out = models.SimulatedConversation{
+			UUID:            conversation.UUID,
+			ReferenceNumber: conversation.ReferenceNumber,
+			Subject:         conversation.Subject.String,
+			Groups:          make([]models.GroupResult, 0),
+			Actions:         make([]models.RuleAction, 0),
+		}
+		previousValues map[string]string
+	)
+	for _, rule := range rules {
+		// Update rules are evaluated against the current values as the previous values.
+		if rule.Type == models.RuleTypeConversationUpdate {
+			previousValues = models.PreviousValues(conversation)
+		}
+		matched, groups := e.ruleMatches(rule, conversation, previousValues)
+		out.Groups = append(out.Groups, groups...)
+		if matched {
+			out.Matched = true
+			out.Actions = append(out.Actions, rule.Actions...)
+			if rule.ExecutionMode == models.ExecutionModeFirstMatch {
+				break
+			}
+		}
+	}
+	e.lo.Debug("simulated automation rule", "conversation_uuid", conversation.UUID, "matched", out.Matched, "actions", len(out.Actions))
+	return out
+}
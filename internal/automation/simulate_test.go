@@ -0,0 +1,106 @@
+package automation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/abhinavxd/libredesk/internal/automation/models"
+	cmodels "github.com/abhinavxd/libredesk/internal/conversation/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/volatiletech/null/v9"
+)
+
+func TestSimulateRule_ReportsMatchesWithoutApplyingActions(t *testing.T) {
+	mockStore := new(mockConversationStore)
+	engine := createTestEngine(mockStore)
+
+	matching := createTestConversation(func(c *cmodels.Conversation) {
+		c.UUID = "match"
+		c.PriorityID = null.IntFrom(2)
+	})
+	other := createTestConversation(func(c *cmodels.Conversation) {
+		c.UUID = "other"
+		c.PriorityID = null.IntFrom(3)
+	})
+	mockStore.On("GetRecentConversations", DefaultSimulationLimit).Return([]cmodels.Conversation{{UUID: "match"}, {UUID: "other"}}, nil)
+	mockStore.On("GetConversation", 0, "match", "").Return(matching, nil)
+	mockStore.On("GetConversation", 0, "other", "").Return(other, nil)
+
+	rule := createTestRule(
+		[]models.RuleGroup{
+			{
+				LogicalOp: models.OperatorAnd,
+				Rules: []models.RuleDetail{
+					{Field: models.ConversationStatus, Operator: models.RuleOperatorEquals, Value: "1", FieldType: models.FieldTypeConversationField},
+					{Field: models.ConversationPriority, Operator: models.RuleOperatorEquals, Value: "2", FieldType: models.FieldTypeConversationField},
+				},
+			},
+		},
+		[]models.RuleAction{{Type: models.ActionSetStatus, Value: []string{"2"}}},
+		models.OperatorAnd,
+	)
+	raw, _ := json.Marshal([]models.Rule{rule})
+
+	result, err := engine.SimulateRule(models.RuleRecord{Type: models.RuleTypeNewConversation, Rules: raw}, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Evaluated)
+	assert.Equal(t, 1, result.Matched)
+	assert.True(t, result.Conversations[0].Matched)
+	assert.Equal(t, models.ActionSetStatus, result.Conversations[0].Actions[0].Type)
+	assert.False(t, result.Conversations[1].Matched)
+	assert.Empty(t, result.Conversations[1].Actions)
+	assert.True(t, result.Conversations[1].Groups[0].Conditions[0].Result, "status condition should pass")
+	assert.False(t, result.Conversations[1].Groups[0].Conditions[1].Result, "priority condition should fail")
+	mockStore.AssertNotCalled(t, "ApplyAction", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSimulateConversationRules_RecordsEveryCondition(t *testing.T) {
+	engine := createTestEngine(new(mockConversationStore))
+	conversation := createTestConversation()
+
+	rule := createTestRule(
+		[]models.RuleGroup{
+			{
+				LogicalOp: models.OperatorOR,
+				Rules: []models.RuleDetail{
+					{Field: models.ConversationStatus, Operator: models.RuleOperatorEquals, Value: "1", FieldType: models.FieldTypeConversationField},
+					{Field: models.ContactEmail, Operator: models.RuleOperatorContains, Value: "nomatch.com", FieldType: models.FieldTypeConversationField},
+				},
+			},
+		},
+		[]models.RuleAction{{Type: models.ActionAddTags, Value: []string{"vip"}}},
+		models.OperatorAnd,
+	)
+
+	out := engine.simulateConversationRules([]models.Rule{rule}, conversation)
+
+	assert.True(t, out.Matched)
+	assert.Len(t, out.Groups[0].Conditions, 2, "OR groups must not short-circuit in a simulation")
+	assert.True(t, out.Groups[0].Conditions[0].Result)
+	assert.False(t, out.Groups[0].Conditions[1].Result)
+}
+
+func TestSimulateConversationRules_PreviousValuesForUpdateRules(t *testing.T) {
+	engine := createTestEngine(new(mockConversationStore))
+	conversation := createTestConversation()
+
+	rule := createTestRule(
+		[]models.RuleGroup{
+			{
+				LogicalOp: models.OperatorAnd,
+				Rules: []models.RuleDetail{
+					{Field: models.ConversationPreviousStatus, Operator: models.RuleOperatorEquals, Value: "1", FieldType: models.FieldTypeConversationField},
+				},
+			},
+		},
+		nil,
+		models.OperatorAnd,
+	)
+	rule.Type = models.RuleTypeConversationUpdate
+
+	out := engine.simulateConversationRules([]models.Rule{rule}, conversation)
+
+	assert.True(t, out.Matched)
+}
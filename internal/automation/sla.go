@@ -0,0 +1,114 @@
+package automation
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/abhinavxd/libredesk/internal/automation/models"
+	cmodels "github.com/abhinavxd/libredesk/internal/conversation/models"
+	smodels "github.com/abhinavxd/libredesk/internal/conversation/status/models"
+	slamodels "github.com/abhinavxd/libredesk/internal/sla/models"
+	umodels "github.com/abhinavxd/libredesk/internal/user/models"
+)
+
+// SetSLAStore sets the store of SLA policies that are selected automatically by their conditions.
+func (e *Engine) SetSLAStore(store slaStore) {
+	e.slaStore = store
+	e.ReloadSLAPolicies()
+}
+
+// ReloadSLAPolicies drops the cached SLA policies, they are fetched again on the next selection.
+// Call it whenever a policy is created, updated or deleted.
+func (e *Engine) ReloadSLAPolicies() {
+	e.slaPoliciesMu.Lock()
+	defer e.slaPoliciesMu.Unlock()
+	e.slaPolicies = nil
+	e.slaPoliciesLoaded = false
+}
+
+// getSLAPolicies returns the cached SLA policies, fetching them from the store if they aren't loaded.
+func (e *Engine) getSLAPolicies() ([]slamodels.SLAPolicy, error) {
+	e.slaPoliciesMu.RLock()
+	if e.slaPoliciesLoaded {
+		defer e.slaPoliciesMu.RUnlock()
+		return e.slaPolicies, nil
+	}
+	e.slaPoliciesMu.RUnlock()
+
+	e.slaPoliciesMu.Lock()
+	defer e.slaPoliciesMu.Unlock()
+	if e.slaPoliciesLoaded {
+		return e.slaPolicies, nil
+	}
+	policies, err := e.slaStore.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	e.slaPolicies, e.slaPoliciesLoaded = policies, true
+	return policies, nil
+}
+
+// EvaluateSLAPolicies enqueues a conversation for SLA policy selection after a change that SLA conditions can match on,
+// such as its tags, inbox or contact attributes.
+func (e *Engine) EvaluateSLAPolicies(conversation cmodels.Conversation) {
+	e.closedMu.RLock()
+	defer e.closedMu.RUnlock()
+	if e.closed {
+		return
+	}
+	select {
+	case e.taskQueue <- ConversationTask{
+		taskType:     SLASelection,
+		conversation: conversation,
+	}:
+	default:
+		e.lo.Warn("EvaluateSLAPolicies: task queue is full, unable to enqueue conversation")
+	}
+}
+
+// selectSLAPolicy applies the matching SLA policy with the highest match weight, ties go to the oldest policy.
+// A policy applied explicitly, i.e. one without conditions, is never replaced, and the applied policy is kept when none match.
+func (e *Engine) selectSLAPolicy(conversation cmodels.Conversation) {
+	if e.slaStore == nil || conversation.StatusCategory.String == smodels.CategoryResolved {
+		return
+	}
+	policies, err := e.getSLAPolicies()
+	if err != nil {
+		e.lo.Error("error fetching SLA policies for selection", "error", err)
+		return
+	}
+
+	var candidates []slamodels.SLAPolicy
+	for _, policy := range policies {
+		if policy.ID == conversation.SLAPolicyID.Int && policy.Conditions.IsEmpty() {
+			return
+		}
+		if !policy.Conditions.IsEmpty() {
+			candidates = append(candidates, policy)
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b slamodels.SLAPolicy) int {
+		if a.MatchWeight != b.MatchWeight {
+			return b.MatchWeight - a.MatchWeight
+		}
+		return a.ID - b.ID
+	})
+
+	for _, policy := range candidates {
+		rule := models.Rule{GroupOperator: policy.Conditions.GroupOperator, Groups: policy.Conditions.Groups}
+		if matched, _ := e.ruleMatches(rule, conversation, nil); !matched {
+			continue
+		}
+		if policy.ID == conversation.SLAPolicyID.Int {
+			return
+		}
+		e.lo.Info("applying matching SLA policy", "conversation_uuid", conversation.UUID, "sla_policy_id", policy.ID)
+		e.suppress(conversation.UUID)
+		action := models.RuleAction{Type: models.ActionSetSLA, Value: []string{strconv.Itoa(policy.ID)}}
+		if err := e.conversationStore.ApplyAction(action, conversation, umodels.User{}); err != nil {
+			e.lo.Error("error applying matching SLA policy", "conversation_uuid", conversation.UUID, "sla_policy_id", policy.ID, "error", err)
+		}
+		e.unsuppress(conversation.UUID)
+		return
+	}
+}
@@ -0,0 +1,133 @@
+package automation
+
+import (
+	"testing"
+
+	"github.com/abhinavxd/libredesk/internal/automation/models"
+	cmodels "github.com/abhinavxd/libredesk/internal/conversation/models"
+	slamodels "github.com/abhinavxd/libredesk/internal/sla/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/volatiletech/null/v9"
+)
+
+type mockSLAStore struct {
+	policies []slamodels.SLAPolicy
+	calls    int
+}
+
+func (m *mockSLAStore) GetAll() ([]slamodels.SLAPolicy, error) {
+	m.calls++
+	return m.policies, nil
+}
+
+func inboxConditions(inboxID string) slamodels.SlaConditions {
+	return slamodels.SlaConditions{
+		GroupOperator: models.OperatorAnd,
+		Groups: []models.RuleGroup{
+			{
+				LogicalOp: models.OperatorAnd,
+				Rules: []models.RuleDetail{
+					{Field: models.ConversationInbox, Operator: models.RuleOperatorEquals, Value: inboxID, FieldType: models.FieldTypeConversationField},
+				},
+			},
+		},
+	}
+}
+
+func TestSelectSLAPolicy_AppliesBestMatch(t *testing.T) {
+	mockStore := new(mockConversationStore)
+	mockStore.On("ApplyAction", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	engine := createTestEngine(mockStore)
+	engine.SetSLAStore(&mockSLAStore{policies: []slamodels.SLAPolicy{
+		{ID: 1},
+		{ID: 2, Conditions: inboxConditions("2"), MatchWeight: 0},
+		{ID: 3, Conditions: inboxConditions("1"), MatchWeight: 5},
+		{ID: 4, Conditions: inboxConditions("1"), MatchWeight: 1},
+	}})
+
+	engine.selectSLAPolicy(createTestConversation())
+
+	assert.Equal(t, 1, mockStore.callCount)
+	assert.Equal(t, models.ActionSetSLA, mockStore.appliedActions[0].Type)
+	assert.Equal(t, []string{"3"}, mockStore.appliedActions[0].Value, "the highest match weight must win")
+}
+
+func TestSelectSLAPolicy_CachesPolicies(t *testing.T) {
+	mockStore := new(mockConversationStore)
+	mockStore.On("ApplyAction", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	engine := createTestEngine(mockStore)
+	store := &mockSLAStore{policies: []slamodels.SLAPolicy{
+		{ID: 2, Conditions: inboxConditions("2")},
+	}}
+	engine.SetSLAStore(store)
+
+	engine.selectSLAPolicy(createTestConversation())
+	engine.selectSLAPolicy(createTestConversation())
+	assert.Equal(t, 1, store.calls, "policies must be fetched once")
+	assert.Equal(t, 0, mockStore.callCount)
+
+	store.policies = []slamodels.SLAPolicy{{ID: 3, Conditions: inboxConditions("1")}}
+	engine.ReloadSLAPolicies()
+	engine.selectSLAPolicy(createTestConversation())
+	assert.Equal(t, 2, store.calls)
+	assert.Equal(t, []string{"3"}, mockStore.appliedActions[0].Value)
+}
+
+func TestSelectSLAPolicy_KeepsExplicitPolicy(t *testing.T) {
+	mockStore := new(mockConversationStore)
+	engine := createTestEngine(mockStore)
+	engine.SetSLAStore(&mockSLAStore{policies: []slamodels.SLAPolicy{
+		{ID: 1},
+		{ID: 2, Conditions: inboxConditions("1")},
+	}})
+
+	engine.selectSLAPolicy(createTestConversation(func(c *cmodels.Conversation) {
+		c.SLAPolicyID = null.IntFrom(1)
+	}))
+
+	assert.Equal(t, 0, mockStore.callCount, "a policy without conditions must not be replaced")
+}
+
+func TestSelectSLAPolicy_AlreadyApplied(t *testing.T) {
+	mockStore := new(mockConversationStore)
+	engine := createTestEngine(mockStore)
+	engine.SetSLAStore(&mockSLAStore{policies: []slamodels.SLAPolicy{
+		{ID: 2, Conditions: inboxConditions("1")},
+	}})
+
+	engine.selectSLAPolicy(createTestConversation(func(c *cmodels.Conversation) {
+		c.SLAPolicyID = null.IntFrom(2)
+	}))
+
+	assert.Equal(t, 0, mockStore.callCount)
+}
+
+func TestSelectSLAPolicy_MatchesCompany(t *testing.T) {
+	mockStore := new(mockConversationStore)
+	mockStore.On("ApplyAction", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	engine := createTestEngine(mockStore)
+	companyConditions := slamodels.SlaConditions{
+		GroupOperator: models.OperatorAnd,
+		Groups: []models.RuleGroup{
+			{
+				LogicalOp: models.OperatorAnd,
+				Rules: []models.RuleDetail{
+					{Field: models.ContactCompany, Operator: models.RuleOperatorEquals, Value: "acme.com", FieldType: models.FieldTypeConversationField},
+				},
+			},
+		},
+	}
+	engine.SetSLAStore(&mockSLAStore{policies: []slamodels.SLAPolicy{
+		{ID: 5, Conditions: companyConditions},
+	}})
+
+	engine.selectSLAPolicy(createTestConversation())
+	assert.Equal(t, 0, mockStore.callCount, "a contact of another company must not match")
+
+	engine.selectSLAPolicy(createTestConversation(func(c *cmodels.Conversation) {
+		c.Contact.Email = null.StringFrom("jane@ACME.com")
+	}))
+	assert.Equal(t, 1, mockStore.callCount)
+	assert.Equal(t, []string{"5"}, mockStore.appliedActions[0].Value)
+}
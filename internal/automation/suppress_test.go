@@ -120,7 +120,7 @@ func TestActionEcho_DoesNotRequeue(t *testing.T) {
 		engine.EvaluateConversationUpdateRules(conv, models.EventConversationStatusChange, nil, systemActor())
 	}).Return(nil)
 
-	engine.evalConversationRules(rules, conv, nil)
+	engine.evalConversationRules(rules, conv, nil, "")
 
 	assert.Equal(t, 1, mockStore.callCount, "action must run exactly once")
 	assert.Equal(t, 0, len(engine.taskQueue), "the action's own echo must not re-enter the queue")
@@ -166,7 +166,7 @@ func TestActionEcho_PingPongRulesDoNotLoop(t *testing.T) {
 		}
 	}).Return(nil)
 
-	engine.evalConversationRules([]models.Rule{ruleSetPriority, ruleSetStatus}, conv, nil)
+	engine.evalConversationRules([]models.Rule{ruleSetPriority, ruleSetStatus}, conv, nil, "")
 
 	assert.Equal(t, 2, mockStore.callCount, "each rule's action runs once")
 	assert.Equal(t, 0, len(engine.taskQueue), "no echo may re-enter the queue, so no ping-pong is possible")
@@ -0,0 +1,328 @@
+package automation
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/abhinavxd/libredesk/internal/automation/models"
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	"github.com/jmoiron/sqlx"
+)
+
+// Kinds of records that rules refer to by ID.
+const (
+	refTeam     = "team"
+	refUser     = "user"
+	refStatus   = "status"
+	refPriority = "priority"
+	refInbox    = "inbox"
+	refSLA      = "sla"
+	refWebhook  = "webhook"
+	refSkill    = "skill"
+)
+
+// conditionReferences maps condition fields to the kind of record their value refers to.
+var conditionReferences = map[string]string{
+	models.ConversationStatus:               refStatus,
+	models.ConversationPreviousStatus:       refStatus,
+	models.ConversationPriority:             refPriority,
+	models.ConversationPreviousPriority:     refPriority,
+	models.ConversationAssignedUser:         refUser,
+	models.ConversationPreviousAssignedUser: refUser,
+	models.ConversationAssignedTeam:         refTeam,
+	models.ConversationPreviousAssignedTeam: refTeam,
+	models.ConversationInbox:                refInbox,
+}
+
+// actionReferences maps action types to the kind of record their first value refers to.
+var actionReferences = map[string]string{
+	models.ActionAssignTeam:     refTeam,
+	models.ActionAssignUser:     refUser,
+	models.ActionSetStatus:      refStatus,
+	models.ActionSetPriority:    refPriority,
+	models.ActionSetSLA:         refSLA,
+	models.ActionAddParticipant: refUser,
+	models.ActionChangeInbox:    refInbox,
+	models.ActionTriggerWebhook: refWebhook,
+	models.ActionRequireSkill:   refSkill,
+}
+
+// notifyRecipientReferences maps notify recipient kinds to the kind of record they refer to.
+var notifyRecipientReferences = map[string]string{
+	models.NotifyRecipientTeam: refTeam,
+	models.NotifyRecipientUser: refUser,
+}
+
+// reference is a record that rules can refer to, with the name used for it in exports.
+// Agents are named by their email.
+type reference struct {
+	Kind string `db:"kind"`
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+// references resolves the records referred to by rules from ID to name and back.
+type references struct {
+	names map[string]map[int]string
+	ids   map[string]map[string]int
+}
+
+func newReferences(rows []reference) references {
+	refs := references{
+		names: make(map[string]map[int]string),
+		ids:   make(map[string]map[string]int),
+	}
+	for _, row := range rows {
+		if refs.names[row.Kind] == nil {
+			refs.names[row.Kind] = make(map[int]string)
+			refs.ids[row.Kind] = make(map[string]int)
+		}
+		refs.names[row.Kind][row.ID] = row.Name
+		// Names are matched case-insensitively, the first record wins on duplicates.
+		key := strings.ToLower(row.Name)
+		if _, ok := refs.ids[row.Kind][key]; !ok {
+			refs.ids[row.Kind][key] = row.ID
+		}
+	}
+	return refs
+}
+
+// toName returns the name of the record with the given ID. IDs of deleted records are kept as they are.
+func (r references) toName(kind, value string) (string, bool) {
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return value, true
+	}
+	if name, ok := r.names[kind][id]; ok {
+		return name, true
+	}
+	return value, true
+}
+
+// toID returns the ID of the record with the given name.
+func (r references) toID(kind, value string) (string, bool) {
+	if id, ok := r.ids[kind][strings.ToLower(value)]; ok {
+		return strconv.Itoa(id), true
+	}
+	return value, false
+}
+
+// mapRuleReferences replaces the values in rules that refer to records with the result of fn.
+// It stops at the first value fn cannot resolve and returns its kind and value.
+func mapRuleReferences(rules []models.Rule, fn func(kind, value string) (string, bool)) (string, string, bool) {
+	for i := range rules {
+		for j := range rules[i].Groups {
+			for k := range rules[i].Groups[j].Rules {
+				condition := &rules[i].Groups[j].Rules[k]
+				kind, ok := conditionReferences[condition.Field]
+				if !ok || condition.FieldType != models.FieldTypeConversationField || condition.Value == "" {
+					continue
+				}
+				value, ok := fn(kind, condition.Value)
+				if !ok {
+					return kind, condition.Value, false
+				}
+				condition.Value = value
+			}
+		}
+		for j := range rules[i].Actions {
+			action := &rules[i].Actions[j]
+			if kind, ok := actionReferences[action.Type]; ok && len(action.Value) > 0 && action.Value[0] != "" {
+				value, ok := fn(kind, action.Value[0])
+				if !ok {
+					return kind, action.Value[0], false
+				}
+				action.Value[0] = value
+			}
+			for n, recipient := range action.Recipients {
+				parts := strings.SplitN(recipient, ":", 2)
+				kind, ok := notifyRecipientReferences[strings.ToLower(strings.TrimSpace(parts[0]))]
+				if !ok || len(parts) < 2 {
+					continue
+				}
+				value, ok := fn(kind, strings.TrimSpace(parts[1]))
+				if !ok {
+					return kind, parts[1], false
+				}
+				action.Recipients[n] = parts[0] + ":" + value
+			}
+		}
+	}
+	return "", "", true
+}
+
+// ExportRules returns all rules in the export format.
+func (e *Engine) ExportRules() ([]models.RuleExport, error) {
+	var records = make([]models.RuleRecord, 0)
+	if err := e.q.GetAllRules.Select(&records); err != nil {
+		e.lo.Error("error fetching rules", "error", err)
+		return nil, envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	refs, err := e.loadReferences()
+	if err != nil {
+		return nil, err
+	}
+
+	var out = make([]models.RuleExport, 0, len(records))
+	for _, record := range records {
+		var rules []models.Rule
+		if len(record.Rules) > 0 {
+			if err := json.Unmarshal(record.Rules, &rules); err != nil {
+				e.lo.Error("error unmarshalling rule JSON", "rule_id", record.ID, "error", err)
+				return nil, envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+			}
+		}
+		mapRuleReferences(rules, refs.toName)
+		out = append(out, models.RuleExport{
+			Name:          record.Name,
+			Description:   record.Description,
+			Type:          record.Type,
+			Events:        record.Events,
+			Enabled:       record.Enabled,
+			Weight:        record.Weight,
+			ExecutionMode: record.ExecutionMode,
+			Rules:         rules,
+		})
+	}
+	return out, nil
+}
+
+// ImportRules creates the exported rules, updating existing rules with the same name and type as a new version.
+// Every referenced record must exist by name and no two rules may share a name and type, otherwise nothing is imported.
+func (e *Engine) ImportRules(rules []models.RuleExport, authorID int) (models.ImportResult, error) {
+	var result models.ImportResult
+	if name, ok := duplicateRuleName(rules); ok {
+		return result, envelope.NewError(envelope.InputError, e.i18n.Ts("automation.duplicateRuleName", "name", name), nil)
+	}
+	modes, ok := ruleExecutionModes(rules)
+	if !ok {
+		return result, envelope.NewError(envelope.InputError, e.i18n.T("automation.invalidRuleExecutionMode"), nil)
+	}
+	refs, err := e.loadReferences()
+	if err != nil {
+		return result, err
+	}
+
+	var records = make([]models.RuleRecord, 0, len(rules))
+	for _, rule := range rules {
+		if strings.TrimSpace(rule.Name) == "" || !isValidRuleType(rule.Type) {
+			return result, envelope.NewError(envelope.InputError, e.i18n.T("automation.invalidRule"), nil)
+		}
+		if kind, name, ok := mapRuleReferences(rule.Rules, refs.toID); !ok {
+			return result, envelope.NewError(envelope.InputError, e.i18n.Ts("automation.referenceNotFound", "name", kind+" "+name), nil)
+		}
+		rulesJSON, err := json.Marshal(rule.Rules)
+		if err != nil {
+			e.lo.Error("error marshalling rule JSON", "name", rule.Name, "error", err)
+			return result, envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+		}
+		records = append(records, models.RuleRecord{
+			Name:        rule.Name,
+			Description: rule.Description,
+			Type:        rule.Type,
+			Events:      rule.Events,
+			Enabled:     rule.Enabled,
+			Weight:      rule.Weight,
+			Rules:       rulesJSON,
+		})
+	}
+
+	var existing = make([]models.RuleRecord, 0)
+	if err := e.q.GetAllRules.Select(&existing); err != nil {
+		e.lo.Error("error fetching rules", "error", err)
+		return result, envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	var ids = make(map[string]int, len(existing))
+	for _, rule := range existing {
+		if _, ok := ids[rule.Type+":"+rule.Name]; !ok {
+			ids[rule.Type+":"+rule.Name] = rule.ID
+		}
+	}
+
+	if err := e.withTx(func(tx *sqlx.Tx) error {
+		for _, record := range records {
+			var (
+				saved models.RuleRecord
+				err   error
+			)
+			if id, ok := ids[record.Type+":"+record.Name]; ok {
+				saved, err = e.updateRule(tx, id, record, authorID)
+				result.Updated++
+			} else {
+				saved, err = e.createRule(tx, record, authorID)
+				result.Created++
+			}
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Stmtx(e.q.UpdateRuleWeight).Exec(saved.ID, record.Weight); err != nil {
+				e.lo.Error("error updating rule weight", "error", err)
+				return envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+			}
+		}
+		for ruleType, mode := range modes {
+			if _, err := tx.Stmtx(e.q.UpdateRuleExecutionMode).Exec(ruleType, mode); err != nil {
+				e.lo.Error("error updating rule execution mode", "error", err)
+				return envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+			}
+		}
+		return nil
+	}); err != nil {
+		return models.ImportResult{}, err
+	}
+	// Reload rules.
+	e.ReloadRules()
+	return result, nil
+}
+
+// duplicateRuleName returns the first name used by more than one rule of the same type.
+func duplicateRuleName(rules []models.RuleExport) (string, bool) {
+	var seen = make(map[string]struct{}, len(rules))
+	for _, rule := range rules {
+		key := rule.Type + ":" + strings.TrimSpace(rule.Name)
+		if _, ok := seen[key]; ok {
+			return rule.Name, true
+		}
+		seen[key] = struct{}{}
+	}
+	return "", false
+}
+
+// ruleExecutionModes returns the execution mode to set for each rule type, false if a mode is invalid or rules of
+// the same type have different modes.
+func ruleExecutionModes(rules []models.RuleExport) (map[string]string, bool) {
+	var modes = make(map[string]string)
+	for _, rule := range rules {
+		switch rule.ExecutionMode {
+		case "":
+			continue
+		case models.ExecutionModeAll, models.ExecutionModeFirstMatch:
+		default:
+			return nil, false
+		}
+		if mode, ok := modes[rule.Type]; ok && mode != rule.ExecutionMode {
+			return nil, false
+		}
+		modes[rule.Type] = rule.ExecutionMode
+	}
+	return modes, true
+}
+
+// loadReferences fetches the records rules can refer to.
+func (e *Engine) loadReferences() (references, error) {
+	var rows []reference
+	if err := e.q.GetRuleReferences.Select(&rows); err != nil {
+		e.lo.Error("error fetching rule references", "error", err)
+		return references{}, envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return newReferences(rows), nil
+}
+
+func isValidRuleType(typ string) bool {
+	switch typ {
+	case models.RuleTypeNewConversation, models.RuleTypeConversationUpdate, models.RuleTypeTimeTrigger:
+		return true
+	}
+	return false
+}
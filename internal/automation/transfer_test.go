@@ -0,0 +1,90 @@
+package automation
+
+import (
+	"testing"
+
+	"github.com/abhinavxd/libredesk/internal/automation/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func transferTestRules() []models.Rule {
+	return []models.Rule{{
+		Groups: []models.RuleGroup{{
+			LogicalOp: models.OperatorAnd,
+			Rules: []models.RuleDetail{
+				{Field: models.ConversationAssignedTeam, FieldType: models.FieldTypeConversationField, Operator: models.RuleOperatorEquals, Value: "3"},
+				{Field: models.ConversationSubject, FieldType: models.FieldTypeConversationField, Operator: models.RuleOperatorEquals, Value: "3"},
+			},
+		}},
+		Actions: []models.RuleAction{
+			{Type: models.ActionSetStatus, Value: []string{"2"}},
+			{Type: models.ActionAddTags, Value: []string{"vip"}},
+			{Type: models.ActionNotify, Recipients: []string{"assignee", "user:7"}},
+		},
+	}}
+}
+
+func TestRuleReferences_RoundTrip(t *testing.T) {
+	refs := newReferences([]reference{
+		{Kind: refTeam, ID: 3, Name: "Billing"},
+		{Kind: refStatus, ID: 2, Name: "Open"},
+		{Kind: refUser, ID: 7, Name: "agent@example.com"},
+	})
+	rules := transferTestRules()
+
+	_, _, ok := mapRuleReferences(rules, refs.toName)
+	assert.True(t, ok)
+	assert.Equal(t, "Billing", rules[0].Groups[0].Rules[0].Value)
+	assert.Equal(t, "3", rules[0].Groups[0].Rules[1].Value, "non-reference fields are left as they are")
+	assert.Equal(t, "Open", rules[0].Actions[0].Value[0])
+	assert.Equal(t, "vip", rules[0].Actions[1].Value[0])
+	assert.Equal(t, []string{"assignee", "user:agent@example.com"}, rules[0].Actions[2].Recipients)
+
+	_, _, ok = mapRuleReferences(rules, refs.toID)
+	assert.True(t, ok)
+	assert.Equal(t, transferTestRules(), rules)
+}
+
+func TestRuleReferences_MissingOnImport(t *testing.T) {
+	refs := newReferences([]reference{{Kind: refStatus, ID: 2, Name: "Open"}})
+	rules := transferTestRules()
+	rules[0].Groups[0].Rules[0].Value = "Support"
+
+	kind, name, ok := mapRuleReferences(rules, refs.toID)
+	assert.False(t, ok)
+	assert.Equal(t, refTeam, kind)
+	assert.Equal(t, "Support", name)
+}
+
+func TestDuplicateRuleName(t *testing.T) {
+	rules := []models.RuleExport{
+		{Name: "Escalate", Type: models.RuleTypeNewConversation},
+		{Name: "Escalate", Type: models.RuleTypeConversationUpdate},
+	}
+	_, ok := duplicateRuleName(rules)
+	assert.False(t, ok, "the same name is allowed for different rule types")
+
+	rules = append(rules, models.RuleExport{Name: "Escalate ", Type: models.RuleTypeNewConversation})
+	name, ok := duplicateRuleName(rules)
+	assert.True(t, ok)
+	assert.Equal(t, "Escalate ", name)
+}
+
+func TestRuleExecutionModes(t *testing.T) {
+	modes, ok := ruleExecutionModes([]models.RuleExport{
+		{Name: "a", Type: models.RuleTypeNewConversation, ExecutionMode: models.ExecutionModeFirstMatch},
+		{Name: "b", Type: models.RuleTypeNewConversation, ExecutionMode: models.ExecutionModeFirstMatch},
+		{Name: "c", Type: models.RuleTypeConversationUpdate},
+	})
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{models.RuleTypeNewConversation: models.ExecutionModeFirstMatch}, modes, "rules without a mode keep the mode of the instance")
+
+	_, ok = ruleExecutionModes([]models.RuleExport{
+		{Name: "a", Type: models.RuleTypeNewConversation, ExecutionMode: models.ExecutionModeFirstMatch},
+		{Name: "b", Type: models.RuleTypeNewConversation, ExecutionMode: models.ExecutionModeAll},
+	})
+	assert.False(t, ok, "rules of one type share the execution mode")
+
+	_, ok = ruleExecutionModes([]models.RuleExport{{Name: "a", Type: models.RuleTypeNewConversation, ExecutionMode: "some"}})
+	assert.False(t, ok)
+}
@@ -0,0 +1,87 @@
+package automation
+
+import (
+	"context"
+	"errors"
+
+	"github.com/abhinavxd/libredesk/internal/automation/models"
+	cmodels "github.com/abhinavxd/libredesk/internal/conversation/models"
+	umodels "github.com/abhinavxd/libredesk/internal/user/models"
+)
+
+const (
+	// aiTriageQueueSize is the number of rule executions that can wait for the AI triage worker.
+	aiTriageQueueSize = 1000
+)
+
+// aiTriageTask is a rule execution whose AI triage actions wait for the triage worker. The execution is recorded once
+// they're applied, schedules pass a zero rule which isn't recorded.
+type aiTriageTask struct {
+	rule         models.Rule
+	conversation cmodels.Conversation
+	matched      []models.RuleDetail
+	trigger      string
+	executed     []models.ExecutedAction
+}
+
+// isAITriage reports whether the action is an AI triage action applied right away rather than delayed.
+func isAITriage(action models.RuleAction) bool {
+	return action.Type == models.ActionAITriage && action.Delay == ""
+}
+
+// queueAITriage hands a rule execution with AI triage actions to the triage worker, as the completion request is too
+// slow to make from an automation worker. If the queue is closed or full, the actions fail and the execution is recorded.
+func (e *Engine) queueAITriage(task aiTriageTask) {
+	e.aiTriageMu.RLock()
+	defer e.aiTriageMu.RUnlock()
+
+	err := errors.New("ai triage queue is closed")
+	if !e.aiTriageClosed {
+		select {
+		case e.aiTriageQueue <- task:
+			return
+		default:
+			e.lo.Warn("WARNING: ai triage queue is full, dropping actions", "conversation_uuid", task.conversation.UUID)
+			err = errors.New("ai triage queue is full")
+		}
+	}
+	for i, executed := range task.executed {
+		if isAITriage(executed.RuleAction) {
+			task.executed[i].Error = err.Error()
+		}
+	}
+	e.recordExecution(task.rule, task.conversation, task.matched, task.trigger, task.executed)
+}
+
+// aiTriageWorker runs the queued AI triage actions until the queue is closed or context is done.
+func (e *Engine) aiTriageWorker(ctx context.Context) {
+	defer e.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case task, ok := <-e.aiTriageQueue:
+			if !ok {
+				return
+			}
+			e.runAITriage(task)
+		}
+	}
+}
+
+// runAITriage applies the AI triage actions of a rule execution with the conversation suppressed, so the changes the
+// triage makes don't trigger rules, and records the execution with their outcome.
+func (e *Engine) runAITriage(task aiTriageTask) {
+	e.suppress(task.conversation.UUID)
+	for i, executed := range task.executed {
+		if !isAITriage(executed.RuleAction) {
+			continue
+		}
+		if err := e.conversationStore.ApplyAction(executed.RuleAction, task.conversation, umodels.User{}); err != nil {
+			e.lo.Error("error applying action on conversation", "action", executed.RuleAction, "conversation_uuid", task.conversation.UUID, "error", err)
+			task.executed[i].Error = err.Error()
+		}
+	}
+	e.unsuppress(task.conversation.UUID)
+	e.recordExecution(task.rule, task.conversation, task.matched, task.trigger, task.executed)
+}
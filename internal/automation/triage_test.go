@@ -0,0 +1,106 @@
+package automation
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/abhinavxd/libredesk/internal/automation/models"
+	cmodels "github.com/abhinavxd/libredesk/internal/conversation/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func createTriageRule() models.Rule {
+	rule := createTestRule(
+		[]models.RuleGroup{
+			{
+				LogicalOp: models.OperatorAnd,
+				Rules: []models.RuleDetail{
+					{Field: models.ConversationStatus, Operator: models.RuleOperatorEquals, Value: "1", FieldType: models.FieldTypeConversationField},
+				},
+			},
+		},
+		[]models.RuleAction{
+			{Type: models.ActionSetStatus, Value: []string{"2"}},
+			{Type: models.ActionAITriage, Value: []string{"Route billing questions to the billing team"}},
+		},
+		models.OperatorAnd,
+	)
+	rule.ID = 5
+	return rule
+}
+
+func TestEvalConversationRules_RunsAITriageUnderSuppression(t *testing.T) {
+	var (
+		mockStore  = new(mockConversationStore)
+		engine     = createTestEngine(mockStore)
+		suppressed []bool
+	)
+	mockStore.On("ApplyAction", mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		suppressed = append(suppressed, engine.isSuppressed(args.Get(1).(cmodels.Conversation).UUID))
+	})
+	engine.executions = make(chan models.Execution, 1)
+	engine.aiTriageQueue = make(chan aiTriageTask, 1)
+
+	engine.evalConversationRules([]models.Rule{createTriageRule()}, createTestConversation(), nil, models.RuleTypeNewConversation)
+
+	// The triage waits for its worker, the execution is recorded once it has run.
+	require.Len(t, mockStore.appliedActions, 1)
+	assert.Equal(t, models.ActionSetStatus, mockStore.appliedActions[0].Type)
+	assert.Len(t, engine.executions, 0)
+	require.Len(t, engine.aiTriageQueue, 1)
+
+	engine.runAITriage(<-engine.aiTriageQueue)
+
+	require.Len(t, mockStore.appliedActions, 2)
+	assert.Equal(t, models.ActionAITriage, mockStore.appliedActions[1].Type)
+	assert.Equal(t, []bool{true, true}, suppressed)
+	assert.False(t, engine.isSuppressed(createTestConversation().UUID))
+
+	require.Len(t, engine.executions, 1)
+	execution := <-engine.executions
+	var actions []models.ExecutedAction
+	require.NoError(t, json.Unmarshal(execution.Actions, &actions))
+	assert.Len(t, actions, 2)
+	assert.False(t, execution.Error.Valid)
+}
+
+func TestRunAITriage_RecordsError(t *testing.T) {
+	mockStore := new(mockConversationStore)
+	mockStore.On("ApplyAction", mock.MatchedBy(func(a models.RuleAction) bool { return a.Type == models.ActionSetStatus }), mock.Anything, mock.Anything).Return(nil)
+	mockStore.On("ApplyAction", mock.MatchedBy(func(a models.RuleAction) bool { return a.Type == models.ActionAITriage }), mock.Anything, mock.Anything).Return(errors.New("ai triage is not available"))
+	engine := createTestEngine(mockStore)
+	engine.executions = make(chan models.Execution, 1)
+	engine.aiTriageQueue = make(chan aiTriageTask, 1)
+
+	engine.evalConversationRules([]models.Rule{createTriageRule()}, createTestConversation(), nil, models.RuleTypeNewConversation)
+	engine.runAITriage(<-engine.aiTriageQueue)
+
+	execution := <-engine.executions
+	var actions []models.ExecutedAction
+	require.NoError(t, json.Unmarshal(execution.Actions, &actions))
+	require.Len(t, actions, 2)
+	assert.Empty(t, actions[0].Error)
+	assert.Equal(t, "ai triage is not available", actions[1].Error)
+	assert.True(t, execution.Error.Valid)
+}
+
+func TestQueueAITriage_FullQueueRecordsError(t *testing.T) {
+	mockStore := new(mockConversationStore)
+	mockStore.On("ApplyAction", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	engine := createTestEngine(mockStore)
+	engine.executions = make(chan models.Execution, 1)
+	engine.aiTriageQueue = make(chan aiTriageTask)
+
+	engine.evalConversationRules([]models.Rule{createTriageRule()}, createTestConversation(), nil, models.RuleTypeNewConversation)
+
+	require.Len(t, engine.executions, 1)
+	execution := <-engine.executions
+	var actions []models.ExecutedAction
+	require.NoError(t, json.Unmarshal(execution.Actions, &actions))
+	require.Len(t, actions, 2)
+	assert.Equal(t, "ai triage queue is full", actions[1].Error)
+	assert.Len(t, mockStore.appliedActions, 1)
+}
@@ -0,0 +1,68 @@
+package automation
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/abhinavxd/libredesk/internal/automation/models"
+)
+
+// validateAction checks that a scheduled automation action has a known type and the values it is applied with.
+// Schedule actions run once per run, so they cannot be delayed.
+func validateAction(action models.RuleAction) error {
+	if action.Delay != "" {
+		return fmt.Errorf("%s cannot be delayed", action.Type)
+	}
+
+	switch action.Type {
+	case models.ActionAssignTeam, models.ActionAssignUser, models.ActionSetStatus, models.ActionSetPriority,
+		models.ActionSetSLA, models.ActionAddParticipant, models.ActionChangeInbox:
+		return requireIDValue(action, 0)
+	case models.ActionRequireSkill:
+		if err := requireIDValue(action, 0); err != nil {
+			return err
+		}
+		if len(action.Value) > 1 && action.Value[1] != "" {
+			if _, err := strconv.Atoi(action.Value[1]); err != nil {
+				return fmt.Errorf("invalid skill proficiency %q", action.Value[1])
+			}
+		}
+	case models.ActionSendPrivateNote, models.ActionReply, models.ActionAITriage, models.ActionSnooze,
+		models.ActionSetConversationAttribute, models.ActionSetContactAttribute:
+		if len(action.Value) == 0 || strings.TrimSpace(action.Value[0]) == "" {
+			return fmt.Errorf("%s requires a value", action.Type)
+		}
+	case models.ActionAddTags, models.ActionSetTags, models.ActionRemoveTags:
+		if len(action.Value) == 0 {
+			return fmt.Errorf("%s requires at least one tag", action.Type)
+		}
+	case models.ActionTriggerWebhook:
+		if err := requireIDValue(action, 0); err != nil {
+			return err
+		}
+		if len(action.Value) < 2 || strings.TrimSpace(action.Value[1]) == "" {
+			return errors.New("trigger_webhook requires an event name")
+		}
+	case models.ActionNotify:
+		if strings.TrimSpace(action.Subject) == "" || strings.TrimSpace(action.Message) == "" || len(action.Recipients) == 0 {
+			return errors.New("notify requires a subject, a message and at least one recipient")
+		}
+	case models.ActionSendCSAT:
+	default:
+		return fmt.Errorf("unknown action %q", action.Type)
+	}
+	return nil
+}
+
+// requireIDValue checks that the value at index i of an action is a record ID.
+func requireIDValue(action models.RuleAction, i int) error {
+	if len(action.Value) <= i {
+		return fmt.Errorf("%s requires a value", action.Type)
+	}
+	if id, err := strconv.Atoi(action.Value[i]); err != nil || id <= 0 {
+		return fmt.Errorf("invalid ID %q for %s", action.Value[i], action.Type)
+	}
+	return nil
+}
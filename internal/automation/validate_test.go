@@ -0,0 +1,40 @@
+package automation
+
+import (
+	"testing"
+
+	"github.com/abhinavxd/libredesk/internal/automation/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAction(t *testing.T) {
+	tests := []struct {
+		name   string
+		action models.RuleAction
+		valid  bool
+	}{
+		{"assign team", models.RuleAction{Type: models.ActionAssignTeam, Value: []string{"3"}}, true},
+		{"assign team without value", models.RuleAction{Type: models.ActionAssignTeam}, false},
+		{"assign team with a name", models.RuleAction{Type: models.ActionAssignTeam, Value: []string{"Billing"}}, false},
+		{"reply", models.RuleAction{Type: models.ActionReply, Value: []string{"Thanks"}}, true},
+		{"blank reply", models.RuleAction{Type: models.ActionReply, Value: []string{"  "}}, false},
+		{"tags", models.RuleAction{Type: models.ActionAddTags, Value: []string{"vip"}}, true},
+		{"no tags", models.RuleAction{Type: models.ActionSetTags}, false},
+		{"csat", models.RuleAction{Type: models.ActionSendCSAT}, true},
+		{"notify", models.RuleAction{Type: models.ActionNotify, Subject: "s", Message: "m", Recipients: []string{"assignee"}}, true},
+		{"notify without recipients", models.RuleAction{Type: models.ActionNotify, Subject: "s", Message: "m"}, false},
+		{"webhook", models.RuleAction{Type: models.ActionTriggerWebhook, Value: []string{"1", "escalated"}}, true},
+		{"webhook without event", models.RuleAction{Type: models.ActionTriggerWebhook, Value: []string{"1"}}, false},
+		{"skill with proficiency", models.RuleAction{Type: models.ActionRequireSkill, Value: []string{"2", "3"}}, true},
+		{"skill with bad proficiency", models.RuleAction{Type: models.ActionRequireSkill, Value: []string{"2", "high"}}, false},
+		{"unknown type", models.RuleAction{Type: "delete_everything", Value: []string{"1"}}, false},
+		{"empty type", models.RuleAction{}, false},
+		{"delayed", models.RuleAction{Type: models.ActionSetStatus, Value: []string{"2"}, Delay: "48h"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAction(tt.action)
+			assert.Equal(t, tt.valid, err == nil, "error: %v", err)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package automation
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"reflect"
+
+	"github.com/abhinavxd/libredesk/internal/automation/models"
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	"github.com/jmoiron/sqlx"
+	"github.com/volatiletech/null/v9"
+)
+
+// GetRuleVersions returns the saved versions of a rule, most recent first.
+func (e *Engine) GetRuleVersions(ruleID, page, pageSize int) ([]models.RuleVersion, error) {
+	var versions = make([]models.RuleVersion, 0)
+	if err := e.q.GetRuleVersions.Select(&versions, ruleID, pageSize, (page-1)*pageSize); err != nil {
+		e.lo.Error("error fetching rule versions", "rule_id", ruleID, "error", err)
+		return nil, envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return versions, nil
+}
+
+// RollbackRule restores a rule to an earlier version. The restored rule is saved as a new version so that the
+// rollback itself shows up in the history. Toggling a rule does not save a version, so the rule keeps its current
+// enabled state rather than the one in the snapshot.
+func (e *Engine) RollbackRule(ruleID, version, authorID int) (models.RuleRecord, error) {
+	current, err := e.GetRule(ruleID)
+	if err != nil {
+		return models.RuleRecord{}, err
+	}
+	var snapshot models.RuleVersion
+	if err := e.q.GetRuleVersion.Get(&snapshot, ruleID, version); err != nil {
+		if err == sql.ErrNoRows {
+			return models.RuleRecord{}, envelope.NewError(envelope.NotFoundError, e.i18n.T("automation.notFoundRuleVersion"), nil)
+		}
+		e.lo.Error("error fetching rule version", "rule_id", ruleID, "version", version, "error", err)
+		return models.RuleRecord{}, envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return e.UpdateRule(ruleID, models.RuleRecord{
+		Name:        snapshot.Name,
+		Description: snapshot.Description,
+		Type:        snapshot.Type,
+		Events:      snapshot.Events,
+		Enabled:     current.Enabled,
+		Rules:       snapshot.Rules,
+	}, authorID)
+}
+
+// saveVersion saves a snapshot of a rule as it is after a change within tx.
+func (e *Engine) saveVersion(tx *sqlx.Tx, rule models.RuleRecord, diff []models.RuleChange, authorID int) error {
+	if diff == nil {
+		diff = []models.RuleChange{}
+	}
+	diffJSON, err := json.Marshal(diff)
+	if err != nil {
+		e.lo.Error("error marshalling rule diff", "rule_id", rule.ID, "error", err)
+		return envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	if _, err := tx.Stmtx(e.q.InsertRuleVersion).Exec(rule.ID, rule.Version, null.NewInt(authorID, authorID > 0), rule.Name, rule.Description,
+		rule.Type, rule.Events, rule.Rules, rule.Enabled, diffJSON); err != nil {
+		e.lo.Error("error saving rule version", "rule_id", rule.ID, "version", rule.Version, "error", err)
+		return envelope.NewError(envelope.GeneralError, e.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return nil
+}
+
+// ruleDiff returns the fields changed between two saved states of a rule. A previous rule without an ID has nothing to compare against.
+func ruleDiff(previous, next models.RuleRecord) []models.RuleChange {
+	if previous.ID == 0 {
+		return nil
+	}
+	var (
+		changes = make([]models.RuleChange, 0)
+		fields  = []struct {
+			name     string
+			from, to any
+		}{
+			{"name", previous.Name, next.Name},
+			{"description", previous.Description, next.Description},
+			{"type", previous.Type, next.Type},
+			{"events", []string(previous.Events), []string(next.Events)},
+			{"enabled", previous.Enabled, next.Enabled},
+			{"rules", previous.Rules, next.Rules},
+		}
+	)
+	for _, f := range fields {
+		from, _ := json.Marshal(f.from)
+		to, _ := json.Marshal(f.to)
+		if jsonEqual(from, to) {
+			continue
+		}
+		changes = append(changes, models.RuleChange{Field: f.name, From: from, To: to})
+	}
+	return changes
+}
+
+// jsonEqual reports whether two JSON documents hold the same value regardless of formatting and key order.
+func jsonEqual(a, b []byte) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
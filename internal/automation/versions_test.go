@@ -0,0 +1,48 @@
+package automation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/abhinavxd/libredesk/internal/automation/models"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleDiff(t *testing.T) {
+	previous := models.RuleRecord{
+		ID:      1,
+		Name:    "Escalate",
+		Type:    models.RuleTypeNewConversation,
+		Events:  pq.StringArray{},
+		Enabled: true,
+		Rules:   json.RawMessage(`[{"groups": [], "actions": [{"type": "set_priority", "value": ["1"]}]}]`),
+	}
+
+	t.Run("no changes", func(t *testing.T) {
+		next := previous
+		// Formatting and key order of the rules JSON are not changes.
+		next.Rules = json.RawMessage(`[{"actions":[{"value":["1"],"type":"set_priority"}],"groups":[]}]`)
+		assert.Empty(t, ruleDiff(previous, next))
+	})
+
+	t.Run("changed fields", func(t *testing.T) {
+		next := previous
+		next.Name = "Escalate urgent"
+		next.Enabled = false
+		next.Rules = json.RawMessage(`[{"groups": [], "actions": [{"type": "set_priority", "value": ["2"]}]}]`)
+
+		changes := ruleDiff(previous, next)
+		fields := make([]string, 0, len(changes))
+		for _, c := range changes {
+			fields = append(fields, c.Field)
+		}
+		assert.Equal(t, []string{"name", "enabled", "rules"}, fields)
+		assert.JSONEq(t, `"Escalate"`, string(changes[0].From))
+		assert.JSONEq(t, `"Escalate urgent"`, string(changes[0].To))
+	})
+
+	t.Run("new rule", func(t *testing.T) {
+		assert.Nil(t, ruleDiff(models.RuleRecord{}, previous))
+	})
+}
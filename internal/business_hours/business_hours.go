@@ -2,13 +2,23 @@
 package businesshours
 
 import (
+	"context"
 	"database/sql"
 	"embed"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/abhinavxd/libredesk/internal/business_hours/models"
 	"github.com/abhinavxd/libredesk/internal/dbutil"
 	"github.com/abhinavxd/libredesk/internal/envelope"
+	"github.com/abhinavxd/libredesk/internal/ssrf"
 	"github.com/jmoiron/sqlx"
 	"github.com/jmoiron/sqlx/types"
 	"github.com/knadh/go-i18n"
@@ -22,17 +32,24 @@ var (
 	ErrBusinessHoursNotFound = errors.New("business hours not found")
 )
 
+const (
+	calendarFetchTimeout  = 20 * time.Second
+	calendarFetchMaxBytes = 2 << 20
+)
+
 type Manager struct {
-	q    queries
-	lo   *logf.Logger
-	i18n *i18n.I18n
+	q          queries
+	lo         *logf.Logger
+	i18n       *i18n.I18n
+	httpClient *http.Client
 }
 
 // Opts contains options for initializing the Manager.
 type Opts struct {
-	DB   *sqlx.DB
-	Lo   *logf.Logger
-	I18n *i18n.I18n
+	DB          *sqlx.DB
+	Lo          *logf.Logger
+	I18n        *i18n.I18n
+	DialControl ssrf.Control
 }
 
 // queries contains prepared SQL queries.
@@ -42,6 +59,9 @@ type queries struct {
 	InsertBusinessHours *sqlx.Stmt `query:"insert-business-hours"`
 	DeleteBusinessHours *sqlx.Stmt `query:"delete-business-hours"`
 	UpdateBusinessHours *sqlx.Stmt `query:"update-business-hours"`
+	UpdateHolidays      *sqlx.Stmt `query:"update-business-hours-holidays"`
+	GetTimezone         *sqlx.Stmt `query:"get-business-hours-timezone"`
+	GetWithCalendar     *sqlx.Stmt `query:"get-business-hours-with-calendar"`
 }
 
 // New creates and returns a new instance of the Manager.
@@ -54,6 +74,10 @@ func New(opts Opts) (*Manager, error) {
 		q:    q,
 		lo:   opts.Lo,
 		i18n: opts.I18n,
+		httpClient: &http.Client{
+			Timeout:   calendarFetchTimeout,
+			Transport: ssrf.NewTransport(opts.DialControl, 3*time.Second),
+		},
 	}, nil
 }
 
@@ -80,9 +104,9 @@ func (m *Manager) GetAll() ([]models.BusinessHours, error) {
 }
 
 // Create creates new business hours.
-func (m *Manager) Create(name string, description null.String, isAlwaysOpen bool, workingHrs, holidays types.JSONText) (models.BusinessHours, error) {
+func (m *Manager) Create(name string, description null.String, isAlwaysOpen bool, workingHrs, holidays types.JSONText, holidayCalendarURL null.String) (models.BusinessHours, error) {
 	var result models.BusinessHours
-	if err := m.q.InsertBusinessHours.Get(&result, name, description, isAlwaysOpen, workingHrs, holidays); err != nil {
+	if err := m.q.InsertBusinessHours.Get(&result, name, description, isAlwaysOpen, workingHrs, holidays, holidayCalendarURL); err != nil {
 		m.lo.Error("error inserting business hours", "error", err)
 		return models.BusinessHours{}, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
 	}
@@ -102,11 +126,174 @@ func (m *Manager) Delete(id int) error {
 }
 
 // Update updates business hours by ID.
-func (m *Manager) Update(id int, name string, description null.String, isAlwaysOpen bool, workingHrs, holidays types.JSONText) (models.BusinessHours, error) {
+func (m *Manager) Update(id int, name string, description null.String, isAlwaysOpen bool, workingHrs, holidays types.JSONText, holidayCalendarURL null.String) (models.BusinessHours, error) {
 	var result models.BusinessHours
-	if err := m.q.UpdateBusinessHours.Get(&result, id, name, description, isAlwaysOpen, workingHrs, holidays); err != nil {
+	if err := m.q.UpdateBusinessHours.Get(&result, id, name, description, isAlwaysOpen, workingHrs, holidays, holidayCalendarURL); err != nil {
 		m.lo.Error("error updating business hours", "error", err)
 		return models.BusinessHours{}, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
 	}
 	return result, nil
 }
+
+// Timezone returns the timezone business hours are used in, that of the teams using them when they all share one
+// and no inbox uses them, else the system timezone. Holidays that close part of a day are stored in it.
+func (m *Manager) Timezone(id int) (*time.Location, error) {
+	var tz string
+	if err := m.q.GetTimezone.Get(&tz, id); err != nil {
+		return nil, err
+	}
+	if tz == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(tz)
+}
+
+// ImportHolidays imports the holidays of an iCalendar file into business hours, replacing existing holidays on the same dates and times.
+// Times in the calendar are converted to the timezone the business hours are used in.
+func (m *Manager) ImportHolidays(id int, ics io.Reader) (models.BusinessHours, error) {
+	businessHours, err := m.Get(id)
+	if err != nil {
+		if err == ErrBusinessHoursNotFound {
+			return businessHours, envelope.NewError(envelope.NotFoundError, err.Error(), nil)
+		}
+		m.lo.Error("error fetching business hours", "id", id, "error", err)
+		return businessHours, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	loc, err := m.Timezone(id)
+	if err != nil {
+		m.lo.Error("error fetching business hours timezone", "id", id, "error", err)
+		return businessHours, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+
+	imported, err := ParseICS(ics, loc)
+	if err != nil {
+		return businessHours, envelope.NewError(envelope.InputError, m.i18n.T("validation.invalidCalendarFile"), nil)
+	}
+
+	// Existing holidays are kept unless an imported holiday falls on the same date and time.
+	var holidays []models.Holiday
+	if len(businessHours.Holidays) > 0 {
+		// Holidays default to an empty object, which holds no holidays.
+		_ = json.Unmarshal(businessHours.Holidays, &holidays)
+	}
+	holidays = mergeHolidays(holidays, imported)
+
+	b, err := json.Marshal(holidays)
+	if err != nil {
+		m.lo.Error("error marshalling holidays", "error", err)
+		return businessHours, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	var result models.BusinessHours
+	if err := m.q.UpdateHolidays.Get(&result, id, types.JSONText(b)); err != nil {
+		m.lo.Error("error updating holidays", "id", id, "error", err)
+		return businessHours, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return result, nil
+}
+
+// ImportHolidaysFromURL fetches the holiday calendar URL of business hours and imports its holidays.
+func (m *Manager) ImportHolidaysFromURL(id int) (models.BusinessHours, error) {
+	businessHours, err := m.Get(id)
+	if err != nil {
+		if err == ErrBusinessHoursNotFound {
+			return businessHours, envelope.NewError(envelope.NotFoundError, err.Error(), nil)
+		}
+		m.lo.Error("error fetching business hours", "id", id, "error", err)
+		return businessHours, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	if !IsValidCalendarURL(businessHours.HolidayCalendarURL.String) {
+		return businessHours, envelope.NewError(envelope.InputError, m.i18n.T("validation.invalidUrl"), nil)
+	}
+
+	body, err := m.fetchCalendar(businessHours.HolidayCalendarURL.String)
+	if err != nil {
+		m.lo.Error("error fetching holiday calendar", "id", id, "error", err)
+		return businessHours, envelope.NewError(envelope.InputError, m.i18n.T("validation.invalidCalendarFile"), nil)
+	}
+	return m.ImportHolidays(id, strings.NewReader(body))
+}
+
+// RunCalendarRefresh imports the holidays of every holiday calendar URL once per interval, so holidays added to the
+// calendar are picked up without importing them by hand.
+func (m *Manager) RunCalendarRefresh(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.refreshCalendars(ctx)
+		}
+	}
+}
+
+// refreshCalendars imports the holidays of the calendar URL of each business hours.
+func (m *Manager) refreshCalendars(ctx context.Context) {
+	var ids []int
+	if err := m.q.GetWithCalendar.Select(&ids); err != nil {
+		m.lo.Error("error fetching business hours with holiday calendars", "error", err)
+		return
+	}
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := m.ImportHolidaysFromURL(id); err != nil {
+			m.lo.Error("error refreshing holiday calendar", "id", id, "error", err)
+			continue
+		}
+		m.lo.Info("refreshed holiday calendar", "id", id)
+	}
+}
+
+// IsValidCalendarURL reports whether u is an absolute http or https URL.
+func IsValidCalendarURL(u string) bool {
+	parsed, err := url.Parse(strings.TrimSpace(u))
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+func (m *Manager) fetchCalendar(calendarURL string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), calendarFetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSpace(calendarURL), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "libredesk")
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, calendarFetchMaxBytes))
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// mergeHolidays adds imported holidays to existing ones, replacing those on the same date and time, sorted by date.
+func mergeHolidays(existing, imported []models.Holiday) []models.Holiday {
+	key := func(h models.Holiday) string { return h.Date + " " + h.From + " " + h.To }
+	var (
+		merged = make([]models.Holiday, 0, len(existing)+len(imported))
+		seen   = make(map[string]int, len(existing)+len(imported))
+	)
+	for _, h := range append(existing, imported...) {
+		if i, ok := seen[key(h)]; ok {
+			merged[i] = h
+			continue
+		}
+		seen[key(h)] = len(merged)
+		merged = append(merged, h)
+	}
+	slices.SortStableFunc(merged, func(a, b models.Holiday) int {
+		return strings.Compare(key(a), key(b))
+	})
+	return merged
+}
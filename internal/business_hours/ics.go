@@ -0,0 +1,173 @@
+package businesshours
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/abhinavxd/libredesk/internal/business_hours/models"
+)
+
+const (
+	icsDate     = "20060102"
+	icsDateTime = "20060102T150405"
+
+	// maxHolidayDays is the maximum number of days a single all-day event is expanded to.
+	maxHolidayDays = 366
+)
+
+var ErrInvalidCalendar = errors.New("invalid iCalendar file")
+
+// icsProperty is a content line of an iCalendar file, e.g. `DTSTART;VALUE=DATE:20251225`.
+type icsProperty struct {
+	params map[string]string
+	value  string
+}
+
+// ParseICS parses the events of an iCalendar file into holidays.
+// All-day events close every day they span, events with a start and end time close part of a day.
+// Events that repeat yearly become recurring holidays, other recurrence rules only keep the first occurrence.
+// Times are converted to loc, which is also used for floating times without a time zone.
+func ParseICS(r io.Reader, loc *time.Location) ([]models.Holiday, error) {
+	lines, err := unfoldICS(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, ErrInvalidCalendar
+	}
+
+	var (
+		holidays = make([]models.Holiday, 0)
+		event    map[string]icsProperty
+	)
+	for _, line := range lines {
+		name, prop, ok := parseICSLine(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT"):
+			event = make(map[string]icsProperty)
+		case name == "END" && strings.EqualFold(prop.value, "VEVENT"):
+			if event != nil {
+				holidays = append(holidays, eventHolidays(event, loc)...)
+			}
+			event = nil
+		case event != nil:
+			if _, exists := event[name]; !exists {
+				event[name] = prop
+			}
+		}
+	}
+	return holidays, nil
+}
+
+// eventHolidays converts an event into holidays, skipping cancelled events and events without a valid start.
+func eventHolidays(event map[string]icsProperty, loc *time.Location) []models.Holiday {
+	if strings.EqualFold(event["STATUS"].value, "CANCELLED") {
+		return nil
+	}
+	start, allDay, err := parseICSTime(event["DTSTART"], loc)
+	if err != nil {
+		return nil
+	}
+
+	var (
+		name      = unescapeICS(event["SUMMARY"].value)
+		recurring = strings.Contains(strings.ToUpper(event["RRULE"].value), "FREQ=YEARLY")
+	)
+	end, _, err := parseICSTime(event["DTEND"], loc)
+	if err != nil || !end.After(start) {
+		end = time.Time{}
+	}
+
+	if !allDay {
+		// Close the rest of the day when the event has no end or ends on a later day.
+		to := "23:59"
+		if !end.IsZero() && end.Format(time.DateOnly) == start.Format(time.DateOnly) {
+			to = end.Format("15:04")
+		}
+		return []models.Holiday{{Name: name, Date: start.Format(time.DateOnly), Recurring: recurring, From: start.Format("15:04"), To: to}}
+	}
+
+	// The end date of all-day events is exclusive.
+	if end.IsZero() {
+		end = start.AddDate(0, 0, 1)
+	}
+	var holidays []models.Holiday
+	for day := start; day.Before(end) && len(holidays) < maxHolidayDays; day = day.AddDate(0, 0, 1) {
+		holidays = append(holidays, models.Holiday{Name: name, Date: day.Format(time.DateOnly), Recurring: recurring})
+	}
+	return holidays
+}
+
+// unfoldICS reads the content lines of an iCalendar file, joining lines folded onto the next line.
+func unfoldICS(r io.Reader) ([]string, error) {
+	var (
+		lines   []string
+		scanner = bufio.NewScanner(r)
+	)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// parseICSLine splits a content line into its upper cased name, parameters and value.
+func parseICSLine(line string) (string, icsProperty, bool) {
+	head, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", icsProperty{}, false
+	}
+	parts := strings.Split(head, ";")
+	prop := icsProperty{params: make(map[string]string, len(parts)-1), value: value}
+	for _, p := range parts[1:] {
+		if k, v, ok := strings.Cut(p, "="); ok {
+			prop.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), prop, true
+}
+
+// parseICSTime parses a DATE or DATE-TIME value in loc and reports whether it is a date.
+func parseICSTime(prop icsProperty, loc *time.Location) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.value)
+	if strings.EqualFold(prop.params["VALUE"], "DATE") || len(value) == len(icsDate) {
+		t, err := time.ParseInLocation(icsDate, value, loc)
+		return t, true, err
+	}
+
+	tzLoc := loc
+	if strings.HasSuffix(value, "Z") {
+		value = strings.TrimSuffix(value, "Z")
+		tzLoc = time.UTC
+	} else if tzid := prop.params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			tzLoc = l
+		}
+	}
+	t, err := time.ParseInLocation(icsDateTime, value, tzLoc)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t.In(loc), false, nil
+}
+
+// unescapeICS unescapes a TEXT value.
+func unescapeICS(s string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(strings.TrimSpace(s))
+}
@@ -0,0 +1,73 @@
+package businesshours
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abhinavxd/libredesk/internal/business_hours/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const testCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Christmas Day\r\n" +
+	"DTSTART;VALUE=DATE:20251225\r\n" +
+	"DTEND;VALUE=DATE:20251226\r\n" +
+	"RRULE:FREQ=YEARLY\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Company\r\n" +
+	"  offsite\r\n" +
+	"DTSTART;VALUE=DATE:20250310\r\n" +
+	"DTEND;VALUE=DATE:20250312\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:New Year's Eve\\, half day\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20251231T130000\r\n" +
+	"DTEND;TZID=Europe/Berlin:20251231T180000\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Maintenance\r\n" +
+	"DTSTART:20250601T080000Z\r\n" +
+	"DTEND:20250601T100000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Cancelled\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"DTSTART;VALUE=DATE:20250701\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICS(t *testing.T) {
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	holidays, err := ParseICS(strings.NewReader(testCalendar), loc)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Holiday{
+		{Name: "Christmas Day", Date: "2025-12-25", Recurring: true},
+		{Name: "Company offsite", Date: "2025-03-10"},
+		{Name: "Company offsite", Date: "2025-03-11"},
+		{Name: "New Year's Eve, half day", Date: "2025-12-31", From: "13:00", To: "18:00"},
+		{Name: "Maintenance", Date: "2025-06-01", From: "10:00", To: "12:00"},
+	}, holidays)
+
+	_, err = ParseICS(strings.NewReader("not a calendar"), loc)
+	assert.ErrorIs(t, err, ErrInvalidCalendar)
+}
+
+func TestMergeHolidays(t *testing.T) {
+	existing := []models.Holiday{
+		{Name: "Manual", Date: "2025-05-01"},
+		{Name: "Old name", Date: "2025-12-25"},
+	}
+	imported := []models.Holiday{
+		{Name: "Christmas Day", Date: "2025-12-25", Recurring: true},
+		{Name: "Half day", Date: "2025-05-01", From: "13:00", To: "17:00"},
+	}
+	assert.Equal(t, []models.Holiday{
+		{Name: "Manual", Date: "2025-05-01"},
+		{Name: "Half day", Date: "2025-05-01", From: "13:00", To: "17:00"},
+		{Name: "Christmas Day", Date: "2025-12-25", Recurring: true},
+	}, mergeHolidays(existing, imported))
+}
@@ -18,16 +18,56 @@ type BusinessHours struct {
 	IsAlwaysOpen bool           `db:"is_always_open" json:"is_always_open"`
 	Holidays     types.JSONText `db:"holidays" json:"holidays"`
 	Hours        types.JSONText `db:"hours" json:"hours"`
+	// HolidayCalendarURL is an ICS calendar that holidays are imported from.
+	HolidayCalendarURL null.String `db:"holiday_calendar_url" json:"holiday_calendar_url"`
 }
 
 // WorkingHours represents the working hours for a specific day.
+// Shifts split the day into several working windows, e.g. 09:00-13:00 and 14:00-18:00, Open and Close are used without them.
 type WorkingHours struct {
-	Open         string `json:"open"`
-	Close        string `json:"close"`
+	Open   string  `json:"open"`
+	Close  string  `json:"close"`
+	Shifts []Shift `json:"shifts,omitempty"`
+}
+
+// Shift is a working window of a day in "HH:MM" format.
+type Shift struct {
+	Open  string `json:"open"`
+	Close string `json:"close"`
+}
+
+// Intervals returns the working windows of the day, its shifts or else its open and close times.
+func (w WorkingHours) Intervals() []Shift {
+	if len(w.Shifts) > 0 {
+		return w.Shifts
+	}
+	return []Shift{{Open: w.Open, Close: w.Close}}
 }
 
 // Holiday represents a holiday.
+// Recurring holidays repeat every year on the month and day of Date.
+// From and To, in "HH:MM" format, close only part of the day, the whole day is closed when they are empty.
 type Holiday struct {
-	Name string `json:"name"`
-	Date string `json:"date"`
+	Name      string `json:"name"`
+	Date      string `json:"date"`
+	Recurring bool   `json:"recurring,omitempty"`
+	From      string `json:"from,omitempty"`
+	To        string `json:"to,omitempty"`
+}
+
+// OccursOn reports whether the holiday falls on the date of t.
+func (h Holiday) OccursOn(t time.Time) bool {
+	if !h.Recurring {
+		return h.Date == t.Format(time.DateOnly)
+	}
+	date, err := time.Parse(time.DateOnly, h.Date)
+	if err != nil {
+		return false
+	}
+	return date.Month() == t.Month() && date.Day() == t.Day()
+}
+
+// IsPartial reports whether the holiday closes only part of the day.
+func (h Holiday) IsPartial() bool {
+	return h.From != "" && h.To != ""
 }
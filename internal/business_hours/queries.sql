@@ -6,7 +6,8 @@ SELECT id,
     description,
     is_always_open,
     hours,
-    holidays
+    holidays,
+    holiday_calendar_url
 FROM business_hours
 WHERE id = $1;
 
@@ -18,7 +19,8 @@ SELECT id,
     description,
     is_always_open,
     hours,
-    holidays
+    holidays,
+    holiday_calendar_url
 FROM business_hours
 ORDER BY updated_at DESC;
 
@@ -28,9 +30,10 @@ INSERT INTO business_hours (
         description,
         is_always_open,
         hours,
-        holidays
+        holidays,
+        holiday_calendar_url
     )
-VALUES ($1, $2, $3, $4, $5)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING *;
 
 -- name: delete-business-hours
@@ -44,6 +47,37 @@ SET "name" = $2,
     is_always_open = $4,
     hours = $5,
     holidays = $6,
+    holiday_calendar_url = $7,
     updated_at = NOW()
 WHERE id = $1
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: update-business-hours-holidays
+UPDATE business_hours
+SET holidays = $2,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+-- name: get-business-hours-timezone
+-- Business hours are used in the timezone of the teams using them when they all share one and no inbox uses them,
+-- else in the system timezone.
+SELECT COALESCE(
+    (
+        SELECT MIN(timezone)
+        FROM teams
+        WHERE business_hours_id = $1
+            AND COALESCE(timezone, '') <> ''
+            AND NOT EXISTS (
+                SELECT 1 FROM inboxes WHERE business_hours_id = $1 AND deleted_at IS NULL
+            )
+        HAVING COUNT(DISTINCT timezone) = 1
+    ),
+    (SELECT value #>> '{}' FROM settings WHERE "key" = 'app.timezone'),
+    ''
+);
+
+-- name: get-business-hours-with-calendar
+SELECT id
+FROM business_hours
+WHERE COALESCE(holiday_calendar_url, '') <> ''
+ORDER BY id;
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"html"
 	"io"
 	"slices"
 	"strconv"
@@ -15,15 +16,19 @@ import (
 	"sync"
 	"time"
 
+	aimodels "github.com/abhinavxd/libredesk/internal/ai/models"
 	"github.com/abhinavxd/libredesk/internal/authz"
 	authzmodels "github.com/abhinavxd/libredesk/internal/authz/models"
 	"github.com/abhinavxd/libredesk/internal/automation"
 	amodels "github.com/abhinavxd/libredesk/internal/automation/models"
 	"github.com/abhinavxd/libredesk/internal/conversation/models"
 	pmodels "github.com/abhinavxd/libredesk/internal/conversation/priority/models"
+	rmodels "github.com/abhinavxd/libredesk/internal/conversation/resolution/models"
 	smodels "github.com/abhinavxd/libredesk/internal/conversation/status/models"
 	"github.com/abhinavxd/libredesk/internal/csat"
 	csatModels "github.com/abhinavxd/libredesk/internal/csat/models"
+	customAttribute "github.com/abhinavxd/libredesk/internal/custom_attribute"
+	camodels "github.com/abhinavxd/libredesk/internal/custom_attribute/models"
 	"github.com/abhinavxd/libredesk/internal/dbutil"
 	"github.com/abhinavxd/libredesk/internal/envelope"
 	"github.com/abhinavxd/libredesk/internal/inbox"
@@ -31,6 +36,7 @@ import (
 	mmodels "github.com/abhinavxd/libredesk/internal/media/models"
 	notifier "github.com/abhinavxd/libredesk/internal/notification"
 	nmodels "github.com/abhinavxd/libredesk/internal/notification/models"
+	skmodels "github.com/abhinavxd/libredesk/internal/skill/models"
 	slaModels "github.com/abhinavxd/libredesk/internal/sla/models"
 	"github.com/abhinavxd/libredesk/internal/stringutil"
 	tmodels "github.com/abhinavxd/libredesk/internal/team/models"
@@ -60,11 +66,22 @@ var (
 const (
 	conversationsListMaxPageSize = 500
 
+	// aiTriageTimeout bounds the completion request made by the AI triage automation action.
+	aiTriageTimeout = time.Minute
+	// aiTriageTranscriptMessages is the number of latest messages sent for AI triage.
+	aiTriageTranscriptMessages = 50
+
 	automationNotifyEmailContent = `<p style="white-space: pre-line;">{{ .Message }}</p>
 
 <p>
 <a href="{{ RootURL }}/inboxes/all/conversation/{{ .Conversation.UUID }}">#{{ .Conversation.ReferenceNumber }}</a>
 </p>`
+
+	automationDigestEmailContent = `<p style="white-space: pre-line;">{{ .Message }}</p>
+
+<ul>
+{{ range .Conversations }}<li><a href="{{ RootURL }}/inboxes/all/conversation/{{ .UUID }}">#{{ .ReferenceNumber }}</a> {{ .Subject }}</li>
+{{ end }}</ul>`
 )
 
 var conversationFilterRenderers = dbutil.FieldRenderers{
@@ -89,6 +106,8 @@ type Manager struct {
 	mediaStore                 mediaStore
 	statusStore                statusStore
 	priorityStore              priorityStore
+	resolutionStore            resolutionStore
+	customAttributeStore       customAttributeStore
 	slaStore                   slaStore
 	settingsStore              settingsStore
 	csatStore                  csatStore
@@ -109,6 +128,8 @@ type Manager struct {
 	continuityConfig           ContinuityConfig
 	subjectRefFormat           string
 	aiAgent                    AIAgentEngine
+	aiTriager                  AITriager
+	outOfOfficeStore           outOfOfficeStore
 }
 
 // AIAgentEngine is notified when a conversation assigned to an AI assistant may need a response.
@@ -123,6 +144,16 @@ func (c *Manager) SetAIAgent(e AIAgentEngine) {
 	c.aiAgent = e
 }
 
+// AITriager classifies a conversation for the AI triage automation action.
+type AITriager interface {
+	Triage(ctx context.Context, instructions, transcript string, priorities, teams []string) (aimodels.Triage, error)
+}
+
+// SetAITriager wires the AI manager used by the AI triage automation action.
+func (c *Manager) SetAITriager(t AITriager) {
+	c.aiTriager = t
+}
+
 // WidgetConversationView represents the conversation data for widget clients
 type WidgetConversationView struct {
 	UUID                  string      `json:"uuid"`
@@ -141,21 +172,35 @@ type WidgetConversationResponse struct {
 }
 
 type slaStore interface {
-	ApplySLA(startTime time.Time, conversationID, assignedTeamID, slaID int) (slaModels.SLAPolicy, error)
-	CreateNextResponseSLAEvent(conversationID, appliedSLAID, slaPolicyID, assignedTeamID int) (time.Time, error)
+	ApplySLA(startTime time.Time, conversationID, assignedTeamID, inboxID, priorityID, slaID int) (slaModels.SLAPolicy, error)
+	CreateNextResponseSLAEvent(conversationID, appliedSLAID, slaPolicyID, assignedTeamID, inboxID, priorityID int) (time.Time, error)
 	SetLatestSLAEventMetAt(appliedSLAID int, metric string) (time.Time, error)
+	SyncPauses(conversationID int)
+	RecalculateDeadlines(conversationID int) (bool, error)
+	NextOpenTime(assignedTeamID, inboxID int, t time.Time) (time.Time, error)
 }
 
 type statusStore interface {
 	Get(int) (smodels.Status, error)
+	GetAll() ([]smodels.Status, error)
+}
+
+type resolutionStore interface {
+	Check(inboxID, teamID int, tags []string, customAttributes json.RawMessage, actor umodels.User) ([]rmodels.MissingField, error)
 }
 
 type priorityStore interface {
 	Get(int) (pmodels.Priority, error)
+	GetAll() ([]pmodels.Priority, error)
+}
+
+type customAttributeStore interface {
+	GetByKey(key, appliesTo string) (camodels.CustomAttribute, error)
 }
 
 type teamStore interface {
 	Get(int) (tmodels.Team, error)
+	GetAll() ([]tmodels.Team, error)
 	UserBelongsToTeam(userID, teamID int) (bool, error)
 	GetMembers(int) ([]tmodels.TeamMember, error)
 }
@@ -167,6 +212,7 @@ type userStore interface {
 	GetSystemUser() (umodels.User, error)
 	CreateContact(user *umodels.User) error
 	UpgradeVisitorToContact(visitorID int) error
+	SaveCustomAttributes(id int, customAttributes map[string]any, replace bool) error
 }
 
 type mediaStore interface {
@@ -229,6 +275,8 @@ func New(
 	slaStore slaStore,
 	statusStore statusStore,
 	priorityStore priorityStore,
+	resolutionStore resolutionStore,
+	customAttributeStore customAttributeStore,
 	inboxStore inboxStore,
 	userStore userStore,
 	teamStore teamStore,
@@ -280,6 +328,8 @@ func New(
 		slaStore:                   slaStore,
 		statusStore:                statusStore,
 		priorityStore:              priorityStore,
+		resolutionStore:            resolutionStore,
+		customAttributeStore:       customAttributeStore,
 		automation:                 automation,
 		template:                   template,
 		db:                         opts.DB,
@@ -290,6 +340,7 @@ func New(
 		continuityConfig:           continuityConfig,
 		subjectRefFormat:           subjectRefFormat,
 	}
+	c.outOfOfficeStore = &dbOutOfOfficeStore{m: c}
 
 	return c, nil
 }
@@ -300,6 +351,9 @@ type queries struct {
 	GetConversation                     *sqlx.Stmt `query:"get-conversation"`
 	GetConversationListItem             *sqlx.Stmt `query:"get-conversation-list-item"`
 	GetConversationsCreatedAfter        *sqlx.Stmt `query:"get-conversations-created-after"`
+	GetRecentConversations              *sqlx.Stmt `query:"get-recent-conversations"`
+	GetConversationsByFilters           string     `query:"get-conversations-by-filters"`
+	GetConversationAutomationStats      *sqlx.Stmt `query:"get-conversation-automation-stats"`
 	GetUnassignedConversations          *sqlx.Stmt `query:"get-unassigned-conversations"`
 	GetConversations                    string     `query:"get-conversations"`
 	GetContactChatConversations         *sqlx.Stmt `query:"get-contact-chat-conversations"`
@@ -309,6 +363,7 @@ type queries struct {
 	GetConversationsByContactEmailForAI *sqlx.Stmt `query:"get-conversations-by-contact-email-for-ai"`
 	GetConversationParticipants         *sqlx.Stmt `query:"get-conversation-participants"`
 	GetUserActiveConversationsCount     *sqlx.Stmt `query:"get-user-active-conversations-count"`
+	GetUserActiveCountByChannel         *sqlx.Stmt `query:"get-user-active-conversations-count-by-channel"`
 	UpdateConversationWaitingSince      *sqlx.Stmt `query:"update-conversation-waiting-since"`
 	UpdateConversationReplyTimestamps   *sqlx.Stmt `query:"update-conversation-reply-timestamps"`
 	UpdateConversationContactLastSeen   *sqlx.Stmt `query:"update-conversation-contact-last-seen"`
@@ -319,6 +374,7 @@ type queries struct {
 	UpdateConversationAssignedTeam      *sqlx.Stmt `query:"update-conversation-assigned-team"`
 	UpdateConversationCustomAttributes  *sqlx.Stmt `query:"update-conversation-custom-attributes"`
 	UpdateConversationPriority          *sqlx.Stmt `query:"update-conversation-priority"`
+	UpdateConversationInbox             *sqlx.Stmt `query:"update-conversation-inbox"`
 	UpdateConversationStatus            *sqlx.Stmt `query:"update-conversation-status"`
 	UpdateConversationLastMessage       *sqlx.Stmt `query:"update-conversation-last-message"`
 	InsertConversationParticipant       *sqlx.Stmt `query:"insert-conversation-participant"`
@@ -327,11 +383,16 @@ type queries struct {
 	SetConversationTags                 *sqlx.Stmt `query:"set-conversation-tags"`
 	RemoveConversationTags              *sqlx.Stmt `query:"remove-conversation-tags"`
 	GetConversationTags                 *sqlx.Stmt `query:"get-conversation-tags"`
+	GetConversationSkills               *sqlx.Stmt `query:"get-conversation-skills"`
+	SetConversationSkills               *sqlx.Stmt `query:"set-conversation-skills"`
+	AddConversationSkill                *sqlx.Stmt `query:"add-conversation-skill"`
 	UnassignOpenConversations           *sqlx.Stmt `query:"unassign-open-conversations"`
 	ReOpenConversation                  *sqlx.Stmt `query:"re-open-conversation"`
 	UnsnoozeAll                         *sqlx.Stmt `query:"unsnooze-all"`
 	DeleteConversation                  *sqlx.Stmt `query:"delete-conversation"`
 	RemoveConversationAssignee          *sqlx.Stmt `query:"remove-conversation-assignee"`
+	ClaimOutOfOfficeReply               *sqlx.Stmt `query:"claim-out-of-office-reply"`
+	ReleaseOutOfOfficeReply             *sqlx.Stmt `query:"release-out-of-office-reply"`
 
 	// Draft queries.
 	UpsertConversationDraft *sqlx.Stmt `query:"upsert-conversation-draft"`
@@ -339,6 +400,27 @@ type queries struct {
 	DeleteConversationDraft *sqlx.Stmt `query:"delete-conversation-draft"`
 	DeleteStaleDrafts       *sqlx.Stmt `query:"delete-stale-drafts"`
 
+	// Task queries.
+	GetConversationTasks        *sqlx.Stmt `query:"get-conversation-tasks"`
+	GetUserTasks                *sqlx.Stmt `query:"get-user-tasks"`
+	GetTask                     *sqlx.Stmt `query:"get-task"`
+	InsertTask                  *sqlx.Stmt `query:"insert-task"`
+	UpdateTask                  *sqlx.Stmt `query:"update-task"`
+	SetTaskCompleted            *sqlx.Stmt `query:"set-task-completed"`
+	DeleteTask                  *sqlx.Stmt `query:"delete-task"`
+	GetTasksDueSoon             *sqlx.Stmt `query:"get-tasks-due-soon"`
+	GetTasksOverdue             *sqlx.Stmt `query:"get-tasks-overdue"`
+	MarkTaskDueReminderSent     *sqlx.Stmt `query:"mark-task-due-reminder-sent"`
+	MarkTaskOverdueReminderSent *sqlx.Stmt `query:"mark-task-overdue-reminder-sent"`
+
+	// Time entry queries.
+	GetTimeEntries     *sqlx.Stmt `query:"get-time-entries"`
+	GetTimeEntry       *sqlx.Stmt `query:"get-time-entry"`
+	InsertTimeEntry    *sqlx.Stmt `query:"insert-time-entry"`
+	UpdateTimeEntry    *sqlx.Stmt `query:"update-time-entry"`
+	StopTimeEntryTimer *sqlx.Stmt `query:"stop-time-entry-timer"`
+	DeleteTimeEntry    *sqlx.Stmt `query:"delete-time-entry"`
+
 	// Message queries.
 	GetMessage                         *sqlx.Stmt `query:"get-message"`
 	GetMessages                        string     `query:"get-messages"`
@@ -368,6 +450,9 @@ type queries struct {
 	// WS list-subscribe authz.
 	FilterAuthorizedListUUIDs     *sqlx.Stmt `query:"filter-authorized-list-uuids"`
 	GetConversationUUIDsByContact *sqlx.Stmt `query:"get-conversation-uuids-by-contact"`
+
+	// SLA selection.
+	GetOpenConversationUUIDsByContact *sqlx.Stmt `query:"get-open-conversation-uuids-by-contact"`
 }
 
 // CreateConversation creates a new conversation. If maxConversations > 0, the insert is
@@ -456,6 +541,23 @@ func (c *Manager) GetContactPreviousConversations(contactID int, limit int) ([]m
 	return conversations, nil
 }
 
+// EvaluateContactSLAPolicies re-evaluates the SLA policy of every open conversation of a contact,
+// as SLA policy conditions can match on contact fields such as custom attributes.
+func (c *Manager) EvaluateContactSLAPolicies(contactID int) {
+	var uuids []string
+	if err := c.q.GetOpenConversationUUIDsByContact.Select(&uuids, contactID); err != nil {
+		c.lo.Error("error fetching contact's open conversations for SLA selection", "contact_id", contactID, "error", err)
+		return
+	}
+	for _, uuid := range uuids {
+		conversation, err := c.GetConversation(0, uuid, "")
+		if err != nil {
+			continue
+		}
+		c.automation.EvaluateSLAPolicies(conversation)
+	}
+}
+
 // GetContactConversationsForAI returns recent conversations of a contact, excluding excludeID.
 func (c *Manager) GetContactConversationsForAI(contactID, excludeID int) ([]models.AIConversationSummary, error) {
 	conversations := make([]models.AIConversationSummary, 0)
@@ -532,6 +634,47 @@ func (c *Manager) GetConversationsCreatedAfter(time time.Time) ([]models.Convers
 	return conversations, nil
 }
 
+// GetRecentConversations retrieves the most recently created conversations.
+func (c *Manager) GetRecentConversations(limit int) ([]models.Conversation, error) {
+	var conversations = make([]models.Conversation, 0)
+	if err := c.q.GetRecentConversations.Select(&conversations, limit); err != nil {
+		c.lo.Error("error fetching recent conversations", "error", err)
+		return conversations, err
+	}
+	return conversations, nil
+}
+
+// GetConversationsByFilters retrieves up to limit conversations matching the list filters, oldest first.
+func (c *Manager) GetConversationsByFilters(filtersJSON string, limit int) ([]models.Conversation, error) {
+	var conversations = make([]models.Conversation, 0)
+	query, qArgs, err := dbutil.BuildPaginatedQuery(c.q.GetConversationsByFilters, []any{}, dbutil.PaginationOptions{
+		Order:    dbutil.ASC,
+		OrderBy:  "conversations.created_at",
+		Page:     1,
+		PageSize: limit,
+		Location: c.filterLocation(),
+	}, filtersJSON, conversationListAllowedFields, conversationFilterRenderers)
+	if err != nil {
+		c.lo.Error("error making conversations by filters query", "error", err)
+		return conversations, err
+	}
+	if err := c.db.Select(&conversations, query, qArgs...); err != nil {
+		c.lo.Error("error fetching conversations by filters", "error", err)
+		return conversations, err
+	}
+	return conversations, nil
+}
+
+// GetConversationAutomationStats returns the message and contact history of a conversation evaluated by automation rules.
+func (c *Manager) GetConversationAutomationStats(conversationID int) (models.AutomationStats, error) {
+	var stats models.AutomationStats
+	if err := c.q.GetConversationAutomationStats.Get(&stats, conversationID); err != nil {
+		c.lo.Error("error fetching conversation automation stats", "conversation_id", conversationID, "error", err)
+		return stats, err
+	}
+	return stats, nil
+}
+
 // UpdateUserLastSeen updates the last seen timestamp for a specific user on a conversation.
 func (c *Manager) UpdateUserLastSeen(uuid string, userID int) error {
 	if _, err := c.q.UpsertUserLastSeen.Exec(userID, uuid); err != nil {
@@ -689,6 +832,11 @@ func (c *Manager) ReOpenConversation(conversationUUID string, actor umodels.User
 		// Broadcast update using WS
 		c.BroadcastConversationUpdate(conversationUUID, map[string]any{"status": models.StatusOpen})
 
+		// Resume the SLA clocks paused while waiting on the contact.
+		if conversation, err := c.GetConversation(0, conversationUUID, ""); err == nil {
+			c.slaStore.SyncPauses(conversation.ID)
+		}
+
 		// Record the status change as an activity.
 		if err := c.RecordStatusChange(models.StatusOpen, conversationUUID, actor); err != nil {
 			return err
@@ -707,6 +855,23 @@ func (c *Manager) ActiveUserConversationsCount(userID int) (int, error) {
 	return count, nil
 }
 
+// ActiveUserConversationsCountByChannel returns the count of active conversations for a user per inbox channel.
+func (c *Manager) ActiveUserConversationsCountByChannel(userID int) (map[string]int, error) {
+	var rows []struct {
+		Channel string `db:"channel"`
+		Count   int    `db:"count"`
+	}
+	if err := c.q.GetUserActiveCountByChannel.Select(&rows, userID); err != nil {
+		c.lo.Error("error fetching active conversation count by channel", "error", err)
+		return nil, envelope.NewError(envelope.GeneralError, c.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Channel] = row.Count
+	}
+	return counts, nil
+}
+
 // UpdateConversationLastMessage updates the last message details for a conversation.
 // Also conditionally updates last_interaction fields if messageType != 'activity' and !private.
 func (c *Manager) UpdateConversationLastMessage(conversation int, conversationUUID, lastMessage, lastMessageSenderType, messageType string, private bool, lastMessageAt time.Time, senderID int) error {
@@ -939,9 +1104,36 @@ func (c *Manager) UpdateConversationPriority(uuid string, priorityID int, priori
 		return envelope.NewError(envelope.GeneralError, c.i18n.T("globals.messages.somethingWentWrong"), nil)
 	}
 	c.BroadcastConversationUpdate(uuid, map[string]any{"priority": priority})
+
+	// SLA targets can differ per priority, recalculate the deadlines of the applied SLA.
+	if conversation.SLAPolicyID.Valid {
+		c.recalculateSLADeadlines(conversation, priority, actor)
+	}
 	return nil
 }
 
+// recalculateSLADeadlines recalculates the SLA deadlines of a conversation after its priority changed and records an activity if they moved.
+func (c *Manager) recalculateSLADeadlines(conversation models.Conversation, priority string, actor umodels.User) {
+	changed, err := c.slaStore.RecalculateDeadlines(conversation.ID)
+	if err != nil {
+		c.lo.Error("error recalculating SLA deadlines", "conversation_id", conversation.ID, "error", err)
+		return
+	}
+	if !changed {
+		return
+	}
+	if updated, err := c.GetConversation(0, conversation.UUID, ""); err == nil {
+		c.BroadcastConversationUpdate(conversation.UUID, map[string]any{
+			"first_response_deadline_at": nullTimeOrNil(updated.FirstResponseDueAt),
+			"resolution_deadline_at":     nullTimeOrNil(updated.ResolutionDueAt),
+			"next_response_deadline_at":  nullTimeOrNil(updated.NextResponseDueAt),
+		})
+	}
+	if err := c.RecordSLARecalculated(conversation.UUID, priority, actor); err != nil {
+		c.lo.Error("error recording SLA recalculation activity", "conversation_id", conversation.ID, "error", err)
+	}
+}
+
 // UpdateConversationStatus updates the status of a conversation.
 func (c *Manager) UpdateConversationStatus(uuid string, statusID int, status, snoozeDur string, actor umodels.User) error {
 	// Fetch the status name if status ID is provided.
@@ -975,6 +1167,13 @@ func (c *Manager) UpdateConversationStatus(uuid string, statusID int, status, sn
 	}
 	oldStatus := conversationBeforeChange.Status.String
 
+	// Enforce resolution requirements when moving the conversation into a resolved status.
+	if conversationBeforeChange.StatusCategory.String != smodels.CategoryResolved {
+		if err := c.enforceResolutionRequirements(conversationBeforeChange, status, actor); err != nil {
+			return err
+		}
+	}
+
 	// Status not changed and not snoozed. Return early.
 	if oldStatus == status && status != models.StatusSnoozed {
 		c.lo.Debug("no status update: conversation status unchanged and not snoozed", "uuid", uuid, "old_status", oldStatus, "new_status", status)
@@ -993,6 +1192,9 @@ func (c *Manager) UpdateConversationStatus(uuid string, statusID int, status, sn
 		c.lo.Error("error fetching conversation after status change", "uuid", uuid, "error", err)
 	}
 
+	// Pause or resume the SLA clocks for the new status.
+	c.slaStore.SyncPauses(conversationBeforeChange.ID)
+
 	// Trigger webhook for conversation status change
 	var snoozeUntilStr string
 	if !snoozeUntil.IsZero() {
@@ -1104,6 +1306,9 @@ func (c *Manager) SetConversationTags(uuid string, action string, tagNames []str
 	conversation, err := c.GetConversation(0, uuid, "")
 	if err != nil {
 		c.lo.Error("error fetching conversation after tags change", "uuid", uuid, "error", err)
+	} else {
+		// SLA policy conditions can match on tags.
+		c.automation.EvaluateSLAPolicies(conversation)
 	}
 
 	// Trigger webhook for conversation tags changed.
@@ -1384,7 +1589,7 @@ func (m *Manager) UnassignOpen(userID int) error {
 
 // ApplySLA applies the SLA policy to a conversation.
 func (m *Manager) ApplySLA(conversation models.Conversation, policyID int, actor umodels.User) error {
-	policy, err := m.slaStore.ApplySLA(conversation.CreatedAt, conversation.ID, conversation.AssignedTeamID.Int, policyID)
+	policy, err := m.slaStore.ApplySLA(conversation.CreatedAt, conversation.ID, conversation.AssignedTeamID.Int, conversation.InboxID, conversation.PriorityID.Int, policyID)
 	if err != nil {
 		m.lo.Error("error applying SLA to conversation", "conversation_id", conversation.ID, "policy_id", policyID, "error", err)
 		return envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
@@ -1419,6 +1624,9 @@ func (m *Manager) ApplyAction(action amodels.RuleAction, conv models.Conversatio
 		}
 	}
 
+	// Link activities raised by the action back to the rule.
+	user.AutomationRuleID = action.RuleID
+
 	m.lo.Debug("executing action",
 		"type", action.Type,
 		"value", action.Value,
@@ -1519,6 +1727,61 @@ func (m *Manager) ApplyAction(action amodels.RuleAction, conv models.Conversatio
 			return fmt.Errorf("notify action requires a subject, a message and at least one recipient")
 		}
 		return m.notifyAutomation(subject, message, action.Recipients, conv)
+	case amodels.ActionSetConversationAttribute:
+		var value string
+		if len(action.Value) > 1 {
+			value = action.Value[1]
+		}
+		attrs, err := m.withCustomAttribute(conv.CustomAttributes, action.Value[0], value, camodels.AppliesToConversation)
+		if err != nil {
+			return err
+		}
+		return m.UpdateConversationCustomAttributes(conv.UUID, attrs)
+	case amodels.ActionSetContactAttribute:
+		var value string
+		if len(action.Value) > 1 {
+			value = action.Value[1]
+		}
+		attrs, err := m.withCustomAttribute(conv.Contact.CustomAttributes, action.Value[0], value, camodels.AppliesToContact)
+		if err != nil {
+			return err
+		}
+		if err := m.userStore.SaveCustomAttributes(conv.ContactID, attrs, true); err != nil {
+			return err
+		}
+		m.EvaluateContactSLAPolicies(conv.ContactID)
+		return nil
+	case amodels.ActionAddParticipant:
+		agentID, err := strconv.Atoi(action.Value[0])
+		if err != nil {
+			return fmt.Errorf("invalid agent ID %q: %w", action.Value[0], err)
+		}
+		agent, err := m.userStore.GetAgent(agentID, "")
+		if err != nil {
+			return fmt.Errorf("fetching agent %d: %w", agentID, err)
+		}
+		return m.addConversationParticipant(agent.ID, conv.UUID)
+	case amodels.ActionChangeInbox:
+		inboxID, err := strconv.Atoi(action.Value[0])
+		if err != nil {
+			return fmt.Errorf("invalid inbox ID %q: %w", action.Value[0], err)
+		}
+		return m.UpdateConversationInbox(conv.UUID, inboxID, user)
+	case amodels.ActionAITriage:
+		return m.aiTriage(conv, action.Value[0], user)
+	case amodels.ActionRequireSkill:
+		skillID, err := strconv.Atoi(action.Value[0])
+		if err != nil {
+			return fmt.Errorf("invalid skill ID %q: %w", action.Value[0], err)
+		}
+		// The minimum proficiency is optional and defaults to the lowest.
+		minProficiency := skmodels.MinProficiency
+		if len(action.Value) > 1 && action.Value[1] != "" {
+			if minProficiency, err = strconv.Atoi(action.Value[1]); err != nil {
+				return fmt.Errorf("invalid skill proficiency %q: %w", action.Value[1], err)
+			}
+		}
+		return m.AddConversationSkill(conv.UUID, skillID, minProficiency)
 	default:
 		return fmt.Errorf("unknown action: %s", action.Type)
 	}
@@ -1580,6 +1843,70 @@ func (m *Manager) notifyAutomation(subject, message string, entries []string, co
 	return nil
 }
 
+// SendAutomationDigest sends the notify action of a scheduled automation run as one digest per recipient,
+// listing the conversations of the run the recipient resolves for, instead of one notification per conversation.
+func (m *Manager) SendAutomationDigest(subject, message string, entries []string, convs []models.Conversation) error {
+	subject, message = strings.TrimSpace(subject), strings.TrimSpace(message)
+	if subject == "" || message == "" || len(entries) == 0 {
+		return fmt.Errorf("notify action requires a subject, a message and at least one recipient")
+	}
+
+	// Recipients such as the assignee differ by conversation, so each recipient gets the conversations they resolve for.
+	var (
+		order   []int
+		byAgent = make(map[int][]models.Conversation)
+	)
+	for _, conv := range convs {
+		for _, id := range m.resolveNotifyRecipients(entries, conv) {
+			if _, ok := byAgent[id]; !ok {
+				order = append(order, id)
+			}
+			byAgent[id] = append(byAgent[id], conv)
+		}
+	}
+	if len(order) > amodels.MaxNotifyRecipients {
+		m.lo.Warn("notify digest: recipient cap reached, truncating", "original", len(order), "cap", amodels.MaxNotifyRecipients)
+		order = order[:amodels.MaxNotifyRecipients]
+	}
+
+	for _, id := range order {
+		notification := notifier.Notification{
+			Type:         nmodels.NotificationTypeMention,
+			RecipientIDs: []int{id},
+			Title:        subject,
+			Body:         null.StringFrom(message),
+		}
+		conversations := make([]map[string]any, 0, len(byAgent[id]))
+		for _, conv := range byAgent[id] {
+			conversations = append(conversations, map[string]any{
+				"ReferenceNumber": conv.ReferenceNumber,
+				"UUID":            conv.UUID,
+				// Emails are rendered with text/template, the subject is set by the contact.
+				"Subject": html.EscapeString(conv.Subject.String),
+			})
+		}
+		content, err := m.template.RenderEmailWithTemplate(
+			map[string]any{
+				"Conversations": conversations,
+				"Message":       message,
+			},
+			automationDigestEmailContent)
+		if err != nil {
+			m.lo.Error("error rendering automation digest email", "user_id", id, "error", err)
+		} else if agent, err := m.userStore.GetAgent(id, ""); err != nil {
+			m.lo.Error("notify digest: error fetching agent for email", "user_id", id, "error", err)
+		} else if agent.Email.String != "" {
+			notification.Email = &notifier.EmailNotification{
+				Recipients: []string{agent.Email.String},
+				Subject:    subject,
+				Content:    content,
+			}
+		}
+		m.dispatcher.Send(notification)
+	}
+	return nil
+}
+
 func (m *Manager) resolveNotifyRecipients(entries []string, conv models.Conversation) []int {
 	seen := make(map[int]bool)
 	ids := make([]int, 0, len(entries))
@@ -1755,6 +2082,133 @@ func (c *Manager) UpdateConversationCustomAttributes(uuid string, customAttribut
 	return nil
 }
 
+// UpdateConversationInbox moves a conversation to another inbox of the same channel.
+func (c *Manager) UpdateConversationInbox(uuid string, inboxID int, actor umodels.User) error {
+	conversation, err := c.GetConversation(0, uuid, "")
+	if err != nil {
+		return err
+	}
+	if conversation.InboxID == inboxID {
+		return nil
+	}
+
+	inbox, err := c.inboxStore.GetDBRecord(inboxID)
+	if err != nil {
+		return err
+	}
+	// Replies go out through the conversation's inbox, so it can only move between inboxes of the same channel.
+	if inbox.Channel != conversation.InboxChannel {
+		return fmt.Errorf("cannot move %s conversation to %s inbox %d", conversation.InboxChannel, inbox.Channel, inboxID)
+	}
+
+	if _, err := c.q.UpdateConversationInbox.Exec(uuid, inboxID); err != nil {
+		c.lo.Error("error updating conversation inbox", "uuid", uuid, "inbox_id", inboxID, "error", err)
+		return envelope.NewError(envelope.GeneralError, c.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+
+	if err := c.RecordInboxChange(inbox.Name, uuid, actor); err != nil {
+		return envelope.NewError(envelope.GeneralError, c.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	c.BroadcastConversationUpdate(uuid, map[string]any{"inbox_id": inboxID, "inbox_name": inbox.Name})
+
+	// SLA policy conditions can match on the inbox.
+	if updated, err := c.GetConversation(0, uuid, ""); err == nil {
+		c.automation.EvaluateSLAPolicies(updated)
+	}
+	return nil
+}
+
+// withCustomAttribute returns the decoded custom attributes with key set to value after validating it against the
+// attribute definition, or with key removed when value is empty.
+func (c *Manager) withCustomAttribute(current json.RawMessage, key, value, appliesTo string) (map[string]any, error) {
+	attr, err := c.customAttributeStore.GetByKey(key, appliesTo)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s custom attribute %q: %w", appliesTo, key, err)
+	}
+
+	var attrs map[string]any
+	if len(current) > 0 {
+		if err := json.Unmarshal(current, &attrs); err != nil {
+			return nil, fmt.Errorf("decoding custom attributes: %w", err)
+		}
+	}
+	if attrs == nil {
+		attrs = make(map[string]any)
+	}
+
+	if strings.TrimSpace(value) == "" {
+		delete(attrs, attr.Key)
+		return attrs, nil
+	}
+	parsed, err := customAttribute.ParseValue(attr, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for custom attribute %q: %w", key, err)
+	}
+	attrs[attr.Key] = parsed
+	return attrs, nil
+}
+
+// aiTriage classifies a conversation with the given instructions and applies the priority, team and tags the AI picked.
+func (c *Manager) aiTriage(conv models.Conversation, instructions string, actor umodels.User) error {
+	if c.aiTriager == nil {
+		return fmt.Errorf("ai triage is not available")
+	}
+	if strings.TrimSpace(instructions) == "" {
+		return fmt.Errorf("ai triage action requires instructions")
+	}
+
+	private := false
+	msgs, err := c.GetAllConversationMessages(conv.UUID, &private, []string{models.MessageIncoming, models.MessageOutgoing}, aiTriageTranscriptMessages)
+	if err != nil {
+		return fmt.Errorf("fetching conversation messages: %w", err)
+	}
+	transcript := models.Transcript(msgs, aiTriageTranscriptMessages)
+	if strings.TrimSpace(transcript) == "" {
+		return fmt.Errorf("ai triage skipped: conversation has no messages")
+	}
+
+	priorities, err := c.priorityStore.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching priorities: %w", err)
+	}
+	teams, err := c.teamStore.GetAll()
+	if err != nil {
+		return fmt.Errorf("fetching teams: %w", err)
+	}
+	var (
+		priorityNames = make([]string, 0, len(priorities))
+		teamNames     = make([]string, 0, len(teams))
+		teamIDs       = make(map[string]int, len(teams))
+	)
+	for _, p := range priorities {
+		priorityNames = append(priorityNames, p.Name)
+	}
+	for _, t := range teams {
+		teamNames = append(teamNames, t.Name)
+		teamIDs[t.Name] = t.ID
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), aiTriageTimeout)
+	defer cancel()
+	triage, err := c.aiTriager.Triage(ctx, instructions, transcript, priorityNames, teamNames)
+	if err != nil {
+		return fmt.Errorf("ai triage: %w", err)
+	}
+	c.lo.Debug("ai triage result", "conversation_uuid", conv.UUID, "priority", triage.Priority, "team", triage.Team, "tags", triage.Tags)
+
+	var errs []error
+	if triage.Priority != "" {
+		errs = append(errs, c.UpdateConversationPriority(conv.UUID, 0, triage.Priority, actor))
+	}
+	if triage.Team != "" {
+		errs = append(errs, c.UpdateConversationTeamAssignee(conv.UUID, teamIDs[triage.Team], actor))
+	}
+	if len(triage.Tags) > 0 {
+		errs = append(errs, c.SetConversationTags(conv.UUID, amodels.ActionAddTags, triage.Tags, actor))
+	}
+	return errors.Join(errs...)
+}
+
 // addConversationParticipant adds a user as participant to a conversation.
 func (c *Manager) addConversationParticipant(userID int, conversationUUID string) error {
 	_, err := c.q.InsertConversationParticipant.Exec(userID, conversationUUID)
@@ -2035,7 +2489,8 @@ func (m *Manager) BuildWidgetConversationResponse(conversation models.Conversati
 	return resp, nil
 }
 
-// calculateBusinessHoursInfo calculates business hours ID and UTC offset for a conversation
+// calculateBusinessHoursInfo calculates business hours ID and UTC offset for a conversation,
+// from its inbox's business hours, then its team's, then the default.
 func (m *Manager) calculateBusinessHoursInfo(conversation models.Conversation) (*int, *int) {
 	var (
 		businessHoursID *int
@@ -2043,8 +2498,18 @@ func (m *Manager) calculateBusinessHoursInfo(conversation models.Conversation) (
 		utcOffset       *int
 	)
 
-	// Check if conversation is assigned to a team with business hours
-	if conversation.AssignedTeamID.Valid {
+	// Business hours of the inbox come first, e.g. separate hours for phone and chat inboxes, in the system timezone.
+	if conversation.InboxID > 0 {
+		inbox, err := m.inboxStore.GetDBRecord(conversation.InboxID)
+		if err != nil {
+			m.lo.Error("error fetching inbox for business hours info", "inbox_id", conversation.InboxID, "error", err)
+		} else if inbox.BusinessHoursID.Valid {
+			businessHoursID = &inbox.BusinessHoursID.Int
+		}
+	}
+
+	// Fallback to the business hours of the assigned team, the team timezone goes with the team business hours only.
+	if businessHoursID == nil && conversation.AssignedTeamID.Valid {
 		team, err := m.teamStore.Get(conversation.AssignedTeamID.Int)
 		if err != nil {
 			m.lo.Error("error fetching team for business hours info", "team_id", conversation.AssignedTeamID.Int, "error", err)
@@ -2054,7 +2519,7 @@ func (m *Manager) calculateBusinessHoursInfo(conversation models.Conversation) (
 		}
 	}
 
-	// Fallback to general settings if no team business hours or no timezone
+	// Fallback to general settings if no team or inbox business hours or no timezone
 	if businessHoursID == nil || timezone == "" {
 		out, err := m.settingsStore.GetByPrefix("app")
 		if err == nil {
@@ -2233,3 +2698,31 @@ func renderTagFilter(operator, value string, paramIndex int) (string, []any, err
 		return "", nil, fmt.Errorf("invalid operator for tags: %s", operator)
 	}
 }
+
+// enforceResolutionRequirements returns an input error listing the missing fields if the conversation does not meet the
+// resolution requirements of its inbox and team for moving into the given status.
+func (c *Manager) enforceResolutionRequirements(conversation models.Conversation, status string, actor umodels.User) error {
+	statuses, err := c.statusStore.GetAll()
+	if err != nil {
+		return err
+	}
+	idx := slices.IndexFunc(statuses, func(s smodels.Status) bool { return s.Name == status })
+	if idx < 0 || statuses[idx].Category != smodels.CategoryResolved {
+		return nil
+	}
+
+	var tags []string
+	if conversation.Tags.Valid {
+		if err := json.Unmarshal(conversation.Tags.JSON, &tags); err != nil {
+			c.lo.Error("error unmarshalling conversation tags", "uuid", conversation.UUID, "error", err)
+		}
+	}
+	missing, err := c.resolutionStore.Check(conversation.InboxID, conversation.AssignedTeamID.Int, tags, conversation.CustomAttributes, actor)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return envelope.NewError(envelope.InputError, c.i18n.T("resolution.missingFields"), map[string]any{"missing_fields": missing})
+	}
+	return nil
+}
@@ -700,11 +700,30 @@ func (m *Manager) RecordStatusChange(status, conversationUUID string, actor umod
 	return m.InsertConversationActivity(models.ActivityStatusChange, conversationUUID, status, actor)
 }
 
+// RecordInboxChange records an activity for an inbox change.
+func (m *Manager) RecordInboxChange(inboxName, conversationUUID string, actor umodels.User) error {
+	return m.InsertConversationActivity(models.ActivityInboxChange, conversationUUID, inboxName, actor)
+}
+
 // RecordSLASet records an activity for an SLA set.
 func (m *Manager) RecordSLASet(conversationUUID string, slaName string, actor umodels.User) error {
 	return m.InsertConversationActivity(models.ActivitySLASet, conversationUUID, slaName, actor)
 }
 
+// RecordSLARecalculated records an activity for SLA deadlines recalculated after a priority change.
+func (m *Manager) RecordSLARecalculated(conversationUUID string, priority string, actor umodels.User) error {
+	return m.InsertConversationActivity(models.ActivitySLARecalculated, conversationUUID, priority, actor)
+}
+
+// RecordSLAEscalation records an activity for an SLA escalation, escalations run as the system user.
+func (m *Manager) RecordSLAEscalation(conversationUUID, reason string) error {
+	systemUser, err := m.userStore.GetSystemUser()
+	if err != nil {
+		return err
+	}
+	return m.InsertConversationActivity(models.ActivitySLAEscalated, conversationUUID, reason, systemUser)
+}
+
 // RecordTagAddition records an activity for a tag addition.
 func (m *Manager) RecordTagAddition(conversationUUID string, tag string, actor umodels.User) error {
 	return m.InsertConversationActivity(models.ActivityTagAdded, conversationUUID, tag, actor)
@@ -724,7 +743,11 @@ func (m *Manager) InsertConversationActivity(activityType, conversationUUID, new
 	}
 
 	// Store the activity type structurally so callers can filter activities without parsing i18n content.
-	meta, _ := json.Marshal(map[string]string{"activity_type": activityType})
+	metaMap := map[string]any{"activity_type": activityType}
+	if actor.AutomationRuleID > 0 && actor.IsSystemUser() {
+		metaMap["automation_rule_id"] = actor.AutomationRuleID
+	}
+	meta, _ := json.Marshal(metaMap)
 
 	message := models.Message{
 		Type:             models.MessageActivity,
@@ -777,6 +800,18 @@ func (m *Manager) getMessageActivityContent(activityType, newValue, actorName st
 		content = fmt.Sprintf("%s set %s SLA policy", actorName, newValue)
 	case models.ActivityParticipantAdded:
 		content = fmt.Sprintf("%s joined the conversation", newValue)
+	case models.ActivityTaskCreated:
+		content = fmt.Sprintf("%s added task %s", actorName, newValue)
+	case models.ActivityTaskCompleted:
+		content = fmt.Sprintf("%s completed task %s", actorName, newValue)
+	case models.ActivityTaskUpdated:
+		content = fmt.Sprintf("%s updated task %s", actorName, newValue)
+	case models.ActivityInboxChange:
+		content = fmt.Sprintf("%s moved the conversation to %s inbox", actorName, newValue)
+	case models.ActivitySLARecalculated:
+		content = fmt.Sprintf("SLA deadlines recalculated for %s priority", newValue)
+	case models.ActivitySLAEscalated:
+		content = fmt.Sprintf("SLA escalation: %s", newValue)
 	default:
 		return "", fmt.Errorf("invalid activity type %s", activityType)
 	}
@@ -1369,9 +1404,17 @@ func (m *Manager) ProcessIncomingMessageHooks(conversationUUID string, isNewConv
 	// Handle new conversation events.
 	if isNewConversation {
 		conversation, err := m.GetConversation(0, conversationUUID, "")
-		if err == nil {
-			m.webhookStore.TriggerEvent(wmodels.EventConversationCreated, conversation)
-			m.automation.EvaluateNewConversationRules(conversation)
+		if err != nil {
+			return nil
+		}
+		m.webhookStore.TriggerEvent(wmodels.EventConversationCreated, conversation)
+
+		// Automation sends the out of office reply once new conversation rules have assigned a team, it is sent
+		// right away when the conversation could not be queued for the rules.
+		if !m.automation.EvaluateNewConversationRules(conversation) {
+			if err := m.SendOutOfOfficeReply(conversation); err != nil {
+				m.lo.Error("error sending out of office reply", "conversation_uuid", conversationUUID, "error", err)
+			}
 		}
 		return nil
 	}
@@ -1409,11 +1452,16 @@ func (m *Manager) ProcessIncomingMessageHooks(conversationUUID string, isNewConv
 			m.aiAgent.HandleConversationEvent(conversation.ID, conversation.AssignedUserID.Int)
 		}
 
+		// Acknowledge messages received outside business hours.
+		if err := m.SendOutOfOfficeReply(conversation); err != nil {
+			m.lo.Error("error sending out of office reply", "conversation_uuid", conversationUUID, "error", err)
+		}
+
 		if conversation.SLAPolicyID.Int == 0 {
 			m.lo.Info("no SLA policy applied to conversation, skipping next response SLA event creation")
 			return nil
 		}
-		if deadline, err := m.slaStore.CreateNextResponseSLAEvent(conversation.ID, conversation.AppliedSLAID.Int, conversation.SLAPolicyID.Int, conversation.AssignedTeamID.Int); err != nil && !errors.Is(err, sla.ErrUnmetSLAEventAlreadyExists) {
+		if deadline, err := m.slaStore.CreateNextResponseSLAEvent(conversation.ID, conversation.AppliedSLAID.Int, conversation.SLAPolicyID.Int, conversation.AssignedTeamID.Int, conversation.InboxID, conversation.PriorityID.Int); err != nil && !errors.Is(err, sla.ErrUnmetSLAEventAlreadyExists) {
 			m.lo.Error("error creating next response SLA event", "conversation_id", conversation.ID, "error", err)
 		} else if !deadline.IsZero() {
 			m.lo.Info("next response SLA event created for conversation", "conversation_id", conversation.ID, "deadline", deadline, "sla_policy_id", conversation.SLAPolicyID.Int)
@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 	"time"
 
@@ -54,6 +55,12 @@ var (
 	ActivityTagRemoved         = "tag_removed"
 	ActivitySLASet             = "sla_set"
 	ActivityParticipantAdded   = "participant_added"
+	ActivityTaskCreated        = "task_created"
+	ActivityTaskCompleted      = "task_completed"
+	ActivityTaskUpdated        = "task_updated"
+	ActivityInboxChange        = "inbox_change"
+	ActivitySLARecalculated    = "sla_recalculated"
+	ActivitySLAEscalated       = "sla_escalated"
 
 	ContentTypeText = "text"
 	ContentTypeHTML = "html"
@@ -204,7 +211,35 @@ type Conversation struct {
 	CSATRating                null.Int               `db:"csat_rating" json:"csat_rating"`
 	CSATFeedback              null.String            `db:"csat_feedback" json:"csat_feedback"`
 	CSATRespondedAt           null.Time              `db:"csat_responded_at" json:"csat_responded_at"`
+	TimeSpentSeconds          int                    `db:"time_spent_seconds" json:"time_spent_seconds"`
+	BillableTimeSeconds       int                    `db:"billable_time_seconds" json:"billable_time_seconds"`
 	PreviousConversations     []PreviousConversation `db:"-" json:"previous_conversations"`
+	RequiredSkills            RequiredSkills         `db:"required_skills" json:"-"`
+	SkillsRequiredAt          null.Time              `db:"skills_required_at" json:"-"`
+}
+
+// RequiredSkill is a skill that agents need at a minimum proficiency to be auto assigned a conversation.
+type RequiredSkill struct {
+	SkillID        int    `db:"skill_id" json:"skill_id"`
+	Name           string `db:"name" json:"name"`
+	MinProficiency int    `db:"min_proficiency" json:"min_proficiency"`
+}
+
+type RequiredSkills []RequiredSkill
+
+// Scan implements the sql.Scanner interface for RequiredSkills
+func (rs *RequiredSkills) Scan(src any) error {
+	if src == nil {
+		*rs = nil
+		return nil
+	}
+
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, rs)
+	default:
+		return fmt.Errorf("unsupported type for RequiredSkills: %T", src)
+	}
 }
 
 type ConversationContact struct {
@@ -234,15 +269,23 @@ func (c *ConversationContact) FullName() string {
 	return c.FirstName + " " + c.LastName
 }
 
+// AutomationStats holds the message and contact history of a conversation used by automation rule conditions.
+type AutomationStats struct {
+	ContactMessageCount       int  `db:"contact_message_count"`
+	HasAttachment             bool `db:"has_attachment"`
+	PreviousConversationCount int  `db:"previous_conversation_count"`
+}
+
 type PreviousConversation struct {
-	ID            int                         `db:"id" json:"id"`
-	CreatedAt     time.Time                   `db:"created_at" json:"created_at"`
-	UpdatedAt     time.Time                   `db:"updated_at" json:"updated_at"`
-	UUID          string                      `db:"uuid" json:"uuid"`
-	Subject       string                      `db:"subject" json:"subject"`
-	Contact       PreviousConversationContact `db:"contact" json:"contact"`
-	LastMessage   null.String                 `db:"last_message" json:"last_message"`
-	LastMessageAt null.Time                   `db:"last_message_at" json:"last_message_at"`
+	ID             int                         `db:"id" json:"id"`
+	CreatedAt      time.Time                   `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time                   `db:"updated_at" json:"updated_at"`
+	UUID           string                      `db:"uuid" json:"uuid"`
+	Subject        string                      `db:"subject" json:"subject"`
+	Contact        PreviousConversationContact `db:"contact" json:"contact"`
+	LastMessage    null.String                 `db:"last_message" json:"last_message"`
+	LastMessageAt  null.Time                   `db:"last_message_at" json:"last_message_at"`
+	AssignedUserID null.Int                    `db:"assigned_user_id" json:"-"`
 }
 
 type PreviousConversationContact struct {
@@ -489,6 +532,16 @@ func (m *Message) ToOutbound() OutboundMessage {
 	}
 }
 
+// IsOutOfOfficeReply returns true if the message is an automatic out of office reply.
+func (m *OutboundMessage) IsOutOfOfficeReply() bool {
+	var meta map[string]any
+	if err := json.Unmarshal([]byte(m.Meta), &meta); err != nil {
+		return false
+	}
+	isOutOfOffice, _ := meta["is_out_of_office"].(bool)
+	return isOutOfOffice
+}
+
 type IncomingContact struct {
 	ID        int
 	FirstName string
@@ -564,6 +617,41 @@ type ConversationDraft struct {
 	Meta             json.RawMessage `db:"meta" json:"meta"`
 }
 
+// ConversationTask is a follow-up to-do attached to a conversation.
+type ConversationTask struct {
+	ID                    int         `db:"id" json:"id"`
+	CreatedAt             time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt             time.Time   `db:"updated_at" json:"updated_at"`
+	ConversationID        int         `db:"conversation_id" json:"conversation_id"`
+	ConversationUUID      string      `db:"conversation_uuid" json:"conversation_uuid"`
+	ConversationRefNumber string      `db:"conversation_reference_number" json:"conversation_reference_number"`
+	Title                 string      `db:"title" json:"title"`
+	AssigneeID            null.Int    `db:"assignee_id" json:"assignee_id"`
+	CreatedBy             null.Int    `db:"created_by" json:"created_by"`
+	DueAt                 null.Time   `db:"due_at" json:"due_at"`
+	CompletedAt           null.Time   `db:"completed_at" json:"completed_at"`
+	CompletedBy           null.Int    `db:"completed_by" json:"completed_by"`
+	DueReminderSentAt     null.Time   `db:"due_reminder_sent_at" json:"-"`
+	OverdueReminderSentAt null.Time   `db:"overdue_reminder_sent_at" json:"-"`
+	AssigneeFirstName     null.String `db:"assignee_first_name" json:"assignee_first_name"`
+	AssigneeLastName      null.String `db:"assignee_last_name" json:"assignee_last_name"`
+}
+
+// TimeEntry is time an agent spent working on a conversation. A running timer has no duration yet.
+type TimeEntry struct {
+	ID              int         `db:"id" json:"id"`
+	CreatedAt       time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time   `db:"updated_at" json:"updated_at"`
+	ConversationID  int         `db:"conversation_id" json:"conversation_id"`
+	UserID          null.Int    `db:"user_id" json:"user_id"`
+	StartedAt       time.Time   `db:"started_at" json:"started_at"`
+	DurationSeconds null.Int    `db:"duration_seconds" json:"duration_seconds"`
+	Note            string      `db:"note" json:"note"`
+	Billable        bool        `db:"billable" json:"billable"`
+	UserFirstName   null.String `db:"user_first_name" json:"user_first_name"`
+	UserLastName    null.String `db:"user_last_name" json:"user_last_name"`
+}
+
 // MentionInput represents a mention in a private note from frontend.
 type MentionInput struct {
 	Type string `json:"type"` // "agent" or "team"
@@ -0,0 +1,120 @@
+package conversation
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/abhinavxd/libredesk/internal/conversation/models"
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	"github.com/abhinavxd/libredesk/internal/template"
+)
+
+// outOfOfficeStore claims the closed period an out of office reply is sent for and queues the reply.
+type outOfOfficeStore interface {
+	// Claim records that a reply is sent for the closed period ending at until, false if one was already sent for
+	// the current closed period or the latest incoming message was auto-submitted.
+	Claim(conversationID int, until time.Time) (bool, error)
+	Release(conversationID int) error
+	QueueReply(conversation models.Conversation, nextOpen time.Time) error
+}
+
+// SendOutOfOfficeReply sends the out of office template to the contact when they write to an inbox with out of office replies enabled
+// outside business hours. Only one reply is sent per conversation per closed period, and none if the latest incoming message was auto-submitted.
+// Business hours are those of the inbox, falling back to the team the conversation is assigned to.
+func (m *Manager) SendOutOfOfficeReply(conversation models.Conversation) error {
+	inboxRecord, err := m.inboxStore.GetDBRecord(conversation.InboxID)
+	if err != nil {
+		return err
+	}
+	if !inboxRecord.OutOfOfficeEnabled {
+		return nil
+	}
+
+	now := time.Now()
+	nextOpen, err := m.slaStore.NextOpenTime(conversation.AssignedTeamID.Int, conversation.InboxID, now)
+	if err != nil {
+		m.lo.Error("error fetching next open time for out of office reply", "conversation_uuid", conversation.UUID, "error", err)
+		return envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	if !nextOpen.IsZero() && !nextOpen.After(now) {
+		return nil
+	}
+
+	// Claim the closed period, when business hours never open the reply is sent once a year at most.
+	until := nextOpen
+	if until.IsZero() {
+		until = now.AddDate(1, 0, 0)
+	}
+	claimed, err := m.outOfOfficeStore.Claim(conversation.ID, until)
+	if err != nil {
+		m.lo.Error("error claiming out of office reply", "conversation_uuid", conversation.UUID, "error", err)
+		return envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	if !claimed {
+		return nil
+	}
+
+	// Release the claim when the reply cannot be queued so the next incoming message retries it.
+	if err := m.outOfOfficeStore.QueueReply(conversation, nextOpen); err != nil {
+		m.lo.Error("error sending out of office reply", "conversation_uuid", conversation.UUID, "error", err)
+		if err := m.outOfOfficeStore.Release(conversation.ID); err != nil {
+			m.lo.Error("error releasing out of office reply", "conversation_uuid", conversation.UUID, "error", err)
+		}
+		return envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	m.lo.Info("out of office reply sent", "conversation_uuid", conversation.UUID, "next_open_at", nextOpen)
+	return nil
+}
+
+// dbOutOfOfficeStore claims closed periods in the conversation meta and queues the reply as the system user.
+type dbOutOfOfficeStore struct {
+	m *Manager
+}
+
+// Claim records the closed period ending at until in the conversation meta.
+func (s *dbOutOfOfficeStore) Claim(conversationID int, until time.Time) (bool, error) {
+	var id int
+	if err := s.m.q.ClaimOutOfOfficeReply.Get(&id, conversationID, until); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Release removes the claimed closed period from the conversation meta.
+func (s *dbOutOfOfficeStore) Release(conversationID int) error {
+	_, err := s.m.q.ReleaseOutOfOfficeReply.Exec(conversationID)
+	return err
+}
+
+// QueueReply renders the out of office template and queues it as a reply to the contact, the next open time is
+// empty if business hours do not open within the next year.
+func (s *dbOutOfOfficeStore) QueueReply(conversation models.Conversation, nextOpen time.Time) error {
+	systemUser, err := s.m.userStore.GetSystemUser()
+	if err != nil {
+		return err
+	}
+	data, err := s.m.BuildTemplateData(conversation.UUID, systemUser.ID)
+	if err != nil {
+		return err
+	}
+	data["NextOpenAt"] = nextOpen
+	data["NextOpenTime"] = ""
+	if !nextOpen.IsZero() {
+		data["NextOpenTime"] = nextOpen.Format("Monday, 02 January 2006 at 15:04 MST")
+	}
+	message, err := s.m.template.RenderStoredTemplate(template.TmplOutOfOffice, data)
+	if err != nil {
+		return err
+	}
+
+	meta := map[string]any{
+		"is_automated":     true,
+		"is_out_of_office": true,
+	}
+	_, err = s.m.QueueReply(nil /**media**/, conversation.InboxID, systemUser.ID, conversation.ContactID, conversation.UUID, message, []string{conversation.Contact.Email.String}, nil, nil, meta)
+	return err
+}
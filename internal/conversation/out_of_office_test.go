@@ -0,0 +1,164 @@
+package conversation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abhinavxd/libredesk/internal/conversation/models"
+	imodels "github.com/abhinavxd/libredesk/internal/inbox/models"
+	"github.com/volatiletech/null/v9"
+	"github.com/zerodha/logf"
+)
+
+// The test stores embed the interfaces so any method a test does not expect panics.
+type testInboxStore struct {
+	inboxStore
+	outOfOffice bool
+}
+
+func (s *testInboxStore) GetDBRecord(any) (imodels.Inbox, error) {
+	return imodels.Inbox{OutOfOfficeEnabled: s.outOfOffice}, nil
+}
+
+type testSLAStore struct {
+	slaStore
+	nextOpen time.Time
+	teamIDs  []int
+}
+
+func (s *testSLAStore) NextOpenTime(assignedTeamID, inboxID int, t time.Time) (time.Time, error) {
+	s.teamIDs = append(s.teamIDs, assignedTeamID)
+	return s.nextOpen, nil
+}
+
+// fakeOutOfOfficeStore claims closed periods in memory and records the queued replies.
+type fakeOutOfOfficeStore struct {
+	until     map[int]time.Time
+	replies   []time.Time
+	queueFail bool
+}
+
+func (f *fakeOutOfOfficeStore) Claim(conversationID int, until time.Time) (bool, error) {
+	if claimed, ok := f.until[conversationID]; ok && claimed.After(time.Now()) {
+		return false, nil
+	}
+	f.until[conversationID] = until
+	return true, nil
+}
+
+func (f *fakeOutOfOfficeStore) Release(conversationID int) error {
+	delete(f.until, conversationID)
+	return nil
+}
+
+func (f *fakeOutOfOfficeStore) QueueReply(conversation models.Conversation, nextOpen time.Time) error {
+	if f.queueFail {
+		return errors.New("queue failed")
+	}
+	f.replies = append(f.replies, nextOpen)
+	return nil
+}
+
+func newOutOfOfficeManager(t *testing.T, outOfOffice bool, nextOpen time.Time) (*Manager, *testSLAStore, *fakeOutOfOfficeStore) {
+	t.Helper()
+	lo := logf.New(logf.Opts{})
+	slas := &testSLAStore{nextOpen: nextOpen}
+	replies := &fakeOutOfOfficeStore{until: make(map[int]time.Time)}
+	m := &Manager{
+		inboxStore:       &testInboxStore{outOfOffice: outOfOffice},
+		slaStore:         slas,
+		outOfOfficeStore: replies,
+		lo:               &lo,
+		i18n:             newTestI18n(t),
+	}
+	return m, slas, replies
+}
+
+func TestSendOutOfOfficeReply(t *testing.T) {
+	now := time.Now()
+	conversation := models.Conversation{ID: 1, UUID: "a", InboxID: 1, AssignedTeamID: null.IntFrom(2)}
+	tests := []struct {
+		name        string
+		outOfOffice bool
+		nextOpen    time.Time
+		wantChecked bool
+		wantReply   bool
+	}{
+		{name: "disabled", outOfOffice: false, nextOpen: now.Add(time.Hour)},
+		{name: "open", outOfOffice: true, nextOpen: now.Add(-time.Minute), wantChecked: true},
+		{name: "closed", outOfOffice: true, nextOpen: now.Add(time.Hour), wantChecked: true, wantReply: true},
+		{name: "never_opens", outOfOffice: true, wantChecked: true, wantReply: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, slas, replies := newOutOfOfficeManager(t, tt.outOfOffice, tt.nextOpen)
+
+			if err := m.SendOutOfOfficeReply(conversation); err != nil {
+				t.Fatalf("SendOutOfOfficeReply() error = %v", err)
+			}
+			if got := len(replies.replies) == 1; got != tt.wantReply {
+				t.Fatalf("replies = %v, want a reply %v", replies.replies, tt.wantReply)
+			}
+			if tt.wantReply && !replies.replies[0].Equal(tt.nextOpen) {
+				t.Errorf("reply next open time = %v, want %v", replies.replies[0], tt.nextOpen)
+			}
+			if !tt.wantChecked {
+				if len(slas.teamIDs) != 0 {
+					t.Errorf("business hours checked with out of office replies disabled")
+				}
+				return
+			}
+
+			// The assigned team is passed on to the business hours lookup, which falls back to it without inbox hours.
+			if len(slas.teamIDs) != 1 || slas.teamIDs[0] != 2 {
+				t.Errorf("NextOpenTime() called with teams %v, want [2]", slas.teamIDs)
+			}
+		})
+	}
+}
+
+func TestSendOutOfOfficeReplyOncePerClosedPeriod(t *testing.T) {
+	nextOpen := time.Now().Add(time.Hour)
+	m, _, replies := newOutOfOfficeManager(t, true, nextOpen)
+	conversation := models.Conversation{ID: 1, UUID: "a", InboxID: 1}
+
+	for range 3 {
+		if err := m.SendOutOfOfficeReply(conversation); err != nil {
+			t.Fatalf("SendOutOfOfficeReply() error = %v", err)
+		}
+	}
+	if len(replies.replies) != 1 {
+		t.Errorf("sent %d replies in one closed period, want 1", len(replies.replies))
+	}
+
+	// Another conversation gets its own reply.
+	if err := m.SendOutOfOfficeReply(models.Conversation{ID: 2, UUID: "b", InboxID: 1}); err != nil {
+		t.Fatalf("SendOutOfOfficeReply() error = %v", err)
+	}
+	if len(replies.replies) != 2 {
+		t.Errorf("sent %d replies for two conversations, want 2", len(replies.replies))
+	}
+}
+
+func TestSendOutOfOfficeReplyReleasesFailedClaim(t *testing.T) {
+	m, _, replies := newOutOfOfficeManager(t, true, time.Now().Add(time.Hour))
+	conversation := models.Conversation{ID: 1, UUID: "a", InboxID: 1}
+
+	replies.queueFail = true
+	if err := m.SendOutOfOfficeReply(conversation); err == nil {
+		t.Fatal("SendOutOfOfficeReply() succeeded with a failing queue")
+	}
+	if _, ok := replies.until[conversation.ID]; ok {
+		t.Error("closed period still claimed after the reply failed to queue")
+	}
+
+	// The next incoming message retries the reply.
+	replies.queueFail = false
+	if err := m.SendOutOfOfficeReply(conversation); err != nil {
+		t.Fatalf("SendOutOfOfficeReply() error = %v", err)
+	}
+	if len(replies.replies) != 1 {
+		t.Errorf("sent %d replies after the retry, want 1", len(replies.replies))
+	}
+}
@@ -248,10 +248,18 @@ SELECT
    c.last_continuity_email_sent_at,
    csat.rating as csat_rating,
    csat.feedback as csat_feedback,
-   csat.response_timestamp as csat_responded_at
+   csat.response_timestamp as csat_responded_at,
+   COALESCE(te.time_spent_seconds, 0) as time_spent_seconds,
+   COALESCE(te.billable_time_seconds, 0) as billable_time_seconds
 FROM conversations c
 JOIN users ct ON c.contact_id = ct.id
 JOIN inboxes inb ON c.inbox_id = inb.id
+LEFT JOIN LATERAL (
+    SELECT SUM(duration_seconds) AS time_spent_seconds,
+        SUM(duration_seconds) FILTER (WHERE billable) AS billable_time_seconds
+    FROM conversation_time_entries
+    WHERE conversation_id = c.id
+) te ON true
 LEFT JOIN LATERAL (
     SELECT rating, feedback, response_timestamp
     FROM csat_responses
@@ -292,6 +300,39 @@ SELECT
 FROM conversations c
 WHERE c.created_at > $1;
 
+-- name: get-recent-conversations
+SELECT
+    c.id,
+    c.uuid
+FROM conversations c
+ORDER BY c.created_at DESC
+LIMIT $1;
+
+-- name: get-conversations-by-filters
+-- Conversations matching list filters, with the same joins as the conversations list.
+SELECT
+    conversations.id,
+    conversations.uuid
+FROM conversations
+JOIN users ON contact_id = users.id
+JOIN inboxes ON inbox_id = inboxes.id
+LEFT JOIN conversation_statuses ON status_id = conversation_statuses.id
+WHERE 1=1
+
+-- name: get-conversation-automation-stats
+SELECT
+    (SELECT COUNT(*) FROM conversation_messages
+     WHERE conversation_id = c.id AND type = 'incoming' AND sender_type = 'contact') AS contact_message_count,
+    EXISTS (
+        SELECT 1 FROM conversation_messages m
+        JOIN media ON media.model_type = 'messages' AND media.model_id = m.id
+        WHERE m.conversation_id = c.id AND media.disposition = 'attachment'
+    ) AS has_attachment,
+    (SELECT COUNT(*) FROM conversations
+     WHERE contact_id = c.contact_id AND id <> c.id AND created_at < c.created_at) AS previous_conversation_count
+FROM conversations c
+WHERE c.id = $1;
+
 -- name: get-contact-previous-conversations
 SELECT
     c.id,
@@ -303,7 +344,8 @@ SELECT
     u.last_name AS "contact.last_name",
     u.avatar_url AS "contact.avatar_url",
     c.last_message as last_message,
-    c.last_message_at as last_message_at
+    c.last_message_at as last_message_at,
+    c.assigned_user_id
 FROM users u
 JOIN conversations c ON c.contact_id = u.id
 WHERE c.contact_id = $1
@@ -433,10 +475,17 @@ updated_at = NOW()
 WHERE uuid = $1;
 
 -- name: claim-unassigned-conversation
-UPDATE conversations
-SET assigned_user_id = $2,
-updated_at = NOW()
-WHERE uuid = $1 AND assigned_user_id IS NULL AND assigned_team_id = $3;
+-- Records the auto assignment time on the agent, which is updated only when the conversation is claimed.
+WITH claimed AS (
+    UPDATE conversations
+    SET assigned_user_id = $2,
+    updated_at = NOW()
+    WHERE uuid = $1 AND assigned_user_id IS NULL AND assigned_team_id = $3
+    RETURNING id
+)
+UPDATE users
+SET last_auto_assigned_at = NOW()
+WHERE id = $2 AND EXISTS (SELECT 1 FROM claimed);
 
 -- name: update-conversation-contact-last-seen
 UPDATE conversations
@@ -451,6 +500,12 @@ updated_at = NOW()
 WHERE uuid = $1;
 
 
+-- name: update-conversation-inbox
+UPDATE conversations
+SET inbox_id = $2,
+    updated_at = NOW()
+WHERE uuid = $1;
+
 -- name: update-conversation-status
 WITH new_status AS (
     SELECT id, category FROM conversation_statuses WHERE name = $2
@@ -466,6 +521,13 @@ WHERE uuid = $1;
 -- name: get-user-active-conversations-count
 SELECT COUNT(*) FROM conversations WHERE status_id IN (SELECT id FROM conversation_statuses WHERE category = 'open') AND assigned_user_id = $1;
 
+-- name: get-user-active-conversations-count-by-channel
+SELECT inb.channel, COUNT(*) AS count
+FROM conversations c
+JOIN inboxes inb ON inb.id = c.inbox_id
+WHERE c.status_id IN (SELECT id FROM conversation_statuses WHERE category = 'open') AND c.assigned_user_id = $1
+GROUP BY inb.channel;
+
 -- name: update-conversation-priority
 UPDATE conversations 
 SET priority_id = (SELECT id FROM conversation_priorities WHERE name = $2),
@@ -514,11 +576,20 @@ SELECT
     c.created_at,
     c.updated_at,
     c.uuid,
+    c.contact_id,
     c.assigned_team_id,
     inb.channel as inbox_channel,
-    inb.name as inbox_name
+    inb.name as inbox_name,
+    cs.required_skills,
+    cs.skills_required_at
 FROM conversations c
     JOIN inboxes inb ON c.inbox_id = inb.id 
+    LEFT JOIN LATERAL (
+        SELECT json_agg(json_build_object('skill_id', skill_id, 'min_proficiency', min_proficiency)) AS required_skills,
+            MAX(created_at) AS skills_required_at
+        FROM conversation_skills
+        WHERE conversation_id = c.id
+    ) cs ON true
 WHERE assigned_user_id IS NULL AND assigned_team_id IS NOT NULL
 ORDER BY c.created_at ASC;
 
@@ -1020,3 +1091,176 @@ FROM conversations
 WHERE contact_id = $1
 ORDER BY last_message_at DESC NULLS LAST
 LIMIT 200;
+
+-- name: get-open-conversation-uuids-by-contact
+SELECT uuid::text
+FROM conversations
+WHERE contact_id = $1 AND status_id IN (SELECT id FROM conversation_statuses WHERE category != 'resolved');
+
+-- name: get-conversation-tasks
+SELECT t.id, t.created_at, t.updated_at, t.conversation_id, c.uuid AS conversation_uuid, c.reference_number AS conversation_reference_number,
+    t.title, t.assignee_id, t.created_by, t.due_at, t.completed_at, t.completed_by, t.due_reminder_sent_at, t.overdue_reminder_sent_at,
+    u.first_name AS assignee_first_name, u.last_name AS assignee_last_name
+FROM conversation_tasks t
+INNER JOIN conversations c ON c.id = t.conversation_id
+LEFT JOIN users u ON u.id = t.assignee_id
+WHERE t.conversation_id = $1
+ORDER BY t.completed_at IS NOT NULL, t.due_at ASC NULLS LAST, t.created_at ASC;
+
+-- name: get-user-tasks
+-- Returns tasks assigned to a user, open tasks only unless $2 is true.
+SELECT t.id, t.created_at, t.updated_at, t.conversation_id, c.uuid AS conversation_uuid, c.reference_number AS conversation_reference_number,
+    t.title, t.assignee_id, t.created_by, t.due_at, t.completed_at, t.completed_by, t.due_reminder_sent_at, t.overdue_reminder_sent_at,
+    u.first_name AS assignee_first_name, u.last_name AS assignee_last_name
+FROM conversation_tasks t
+INNER JOIN conversations c ON c.id = t.conversation_id
+LEFT JOIN users u ON u.id = t.assignee_id
+WHERE t.assignee_id = $1 AND ($2::BOOLEAN OR t.completed_at IS NULL)
+ORDER BY t.completed_at IS NOT NULL, t.due_at ASC NULLS LAST, t.created_at ASC;
+
+-- name: get-task
+SELECT t.id, t.created_at, t.updated_at, t.conversation_id, c.uuid AS conversation_uuid, c.reference_number AS conversation_reference_number,
+    t.title, t.assignee_id, t.created_by, t.due_at, t.completed_at, t.completed_by, t.due_reminder_sent_at, t.overdue_reminder_sent_at,
+    u.first_name AS assignee_first_name, u.last_name AS assignee_last_name
+FROM conversation_tasks t
+INNER JOIN conversations c ON c.id = t.conversation_id
+LEFT JOIN users u ON u.id = t.assignee_id
+WHERE t.id = $1;
+
+-- name: insert-task
+INSERT INTO conversation_tasks (conversation_id, title, assignee_id, created_by, due_at)
+VALUES ($1, $2, NULLIF($3, 0), NULLIF($4, 0), $5)
+RETURNING id;
+
+-- name: update-task
+-- Reminders are re-armed when the due date changes.
+UPDATE conversation_tasks
+SET title = $2,
+    assignee_id = NULLIF($3, 0),
+    due_reminder_sent_at = CASE WHEN due_at IS DISTINCT FROM $4::TIMESTAMPTZ THEN NULL ELSE due_reminder_sent_at END,
+    overdue_reminder_sent_at = CASE WHEN due_at IS DISTINCT FROM $4::TIMESTAMPTZ THEN NULL ELSE overdue_reminder_sent_at END,
+    due_at = $4,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: set-task-completed
+UPDATE conversation_tasks
+SET completed_at = CASE WHEN $2::BOOLEAN THEN NOW() ELSE NULL END,
+    completed_by = CASE WHEN $2::BOOLEAN THEN NULLIF($3, 0) ELSE NULL END,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: delete-task
+DELETE FROM conversation_tasks WHERE id = $1;
+
+-- name: get-tasks-due-soon
+-- Open tasks falling due before $1 that have not been reminded yet.
+SELECT t.id, t.created_at, t.updated_at, t.conversation_id, c.uuid AS conversation_uuid, c.reference_number AS conversation_reference_number,
+    t.title, t.assignee_id, t.created_by, t.due_at, t.completed_at, t.completed_by, t.due_reminder_sent_at, t.overdue_reminder_sent_at,
+    u.first_name AS assignee_first_name, u.last_name AS assignee_last_name
+FROM conversation_tasks t
+INNER JOIN conversations c ON c.id = t.conversation_id
+LEFT JOIN users u ON u.id = t.assignee_id
+WHERE t.completed_at IS NULL AND t.due_reminder_sent_at IS NULL
+    AND t.due_at > NOW() AND t.due_at <= $1
+ORDER BY t.due_at ASC
+LIMIT 500;
+
+-- name: get-tasks-overdue
+SELECT t.id, t.created_at, t.updated_at, t.conversation_id, c.uuid AS conversation_uuid, c.reference_number AS conversation_reference_number,
+    t.title, t.assignee_id, t.created_by, t.due_at, t.completed_at, t.completed_by, t.due_reminder_sent_at, t.overdue_reminder_sent_at,
+    u.first_name AS assignee_first_name, u.last_name AS assignee_last_name
+FROM conversation_tasks t
+INNER JOIN conversations c ON c.id = t.conversation_id
+LEFT JOIN users u ON u.id = t.assignee_id
+WHERE t.completed_at IS NULL AND t.overdue_reminder_sent_at IS NULL AND t.due_at <= NOW()
+ORDER BY t.due_at ASC
+LIMIT 500;
+
+-- name: mark-task-due-reminder-sent
+UPDATE conversation_tasks SET due_reminder_sent_at = NOW() WHERE id = $1;
+
+-- name: mark-task-overdue-reminder-sent
+UPDATE conversation_tasks SET overdue_reminder_sent_at = NOW(), due_reminder_sent_at = COALESCE(due_reminder_sent_at, NOW()) WHERE id = $1;
+
+-- name: get-time-entries
+SELECT te.id, te.created_at, te.updated_at, te.conversation_id, te.user_id, te.started_at, te.duration_seconds, te.note, te.billable,
+    u.first_name AS user_first_name, u.last_name AS user_last_name
+FROM conversation_time_entries te
+LEFT JOIN users u ON u.id = te.user_id
+WHERE te.conversation_id = $1
+ORDER BY te.started_at DESC;
+
+-- name: get-time-entry
+SELECT te.id, te.created_at, te.updated_at, te.conversation_id, te.user_id, te.started_at, te.duration_seconds, te.note, te.billable,
+    u.first_name AS user_first_name, u.last_name AS user_last_name
+FROM conversation_time_entries te
+LEFT JOIN users u ON u.id = te.user_id
+WHERE te.id = $1;
+
+-- name: insert-time-entry
+-- A NULL duration starts a running timer.
+INSERT INTO conversation_time_entries (conversation_id, user_id, started_at, duration_seconds, note, billable)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id;
+
+-- name: update-time-entry
+UPDATE conversation_time_entries
+SET started_at = $2, duration_seconds = $3, note = $4, billable = $5, updated_at = NOW()
+WHERE id = $1 AND duration_seconds IS NOT NULL;
+
+-- name: stop-time-entry-timer
+UPDATE conversation_time_entries
+SET duration_seconds = GREATEST(EXTRACT(EPOCH FROM NOW() - started_at)::INT, 0), updated_at = NOW()
+WHERE conversation_id = $1 AND user_id = $2 AND duration_seconds IS NULL
+RETURNING id;
+
+-- name: delete-time-entry
+DELETE FROM conversation_time_entries WHERE id = $1;
+
+-- name: claim-out-of-office-reply
+-- Records that an out of office reply is sent for the closed period ending at $2, returns no rows if one was already sent for the
+-- current closed period or the latest incoming message was auto-submitted.
+UPDATE conversations
+SET meta = COALESCE(meta, '{}'::jsonb) || jsonb_build_object('out_of_office_until', $2::timestamptz)
+WHERE id = $1
+  AND COALESCE((meta->>'out_of_office_until')::timestamptz, '-infinity'::timestamptz) <= NOW()
+  AND NOT COALESCE((
+    SELECT (m.meta->>'auto_submitted')::boolean
+    FROM conversation_messages m
+    WHERE m.conversation_id = $1 AND m.type = 'incoming'
+    ORDER BY m.id DESC
+    LIMIT 1
+  ), false)
+RETURNING id;
+
+-- name: release-out-of-office-reply
+-- Releases the closed period claimed for an out of office reply that could not be queued.
+UPDATE conversations SET meta = meta - 'out_of_office_until' WHERE id = $1;
+
+-- name: get-conversation-skills
+SELECT cs.skill_id, s."name", cs.min_proficiency
+FROM conversation_skills cs
+JOIN skills s ON s.id = cs.skill_id
+JOIN conversations c ON c.id = cs.conversation_id
+WHERE c.uuid = $1
+ORDER BY s."name";
+
+-- name: set-conversation-skills
+-- Replaces the skills required by a conversation with the skill IDs in $2 at the minimum proficiencies in $3.
+WITH conv AS (
+    SELECT id FROM conversations WHERE uuid = $1
+),
+delete_old_skills AS (
+    DELETE FROM conversation_skills
+    WHERE conversation_id = (SELECT id FROM conv) AND skill_id <> ALL($2::INT[])
+)
+INSERT INTO conversation_skills (conversation_id, skill_id, min_proficiency)
+SELECT (SELECT id FROM conv), skill_id, min_proficiency
+FROM unnest($2::INT[], $3::INT[]) AS s(skill_id, min_proficiency)
+ON CONFLICT (conversation_id, skill_id) DO UPDATE SET min_proficiency = EXCLUDED.min_proficiency;
+
+-- name: add-conversation-skill
+INSERT INTO conversation_skills (conversation_id, skill_id, min_proficiency)
+SELECT id, $2, $3 FROM conversations WHERE uuid = $1
+ON CONFLICT (conversation_id, skill_id) DO UPDATE SET min_proficiency = EXCLUDED.min_proficiency;
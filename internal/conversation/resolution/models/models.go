@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/volatiletech/null/v9"
+)
+
+const (
+	MissingCustomAttribute = "custom_attribute"
+	MissingTag             = "tag"
+)
+
+// Requirement lists the fields a conversation of an inbox or a team must have filled in before it can be resolved or closed.
+type Requirement struct {
+	ID                 int           `db:"id" json:"id"`
+	CreatedAt          time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time     `db:"updated_at" json:"updated_at"`
+	InboxID            null.Int      `db:"inbox_id" json:"inbox_id"`
+	TeamID             null.Int      `db:"team_id" json:"team_id"`
+	CustomAttributeIDs pq.Int64Array `db:"custom_attribute_ids" json:"custom_attribute_ids"`
+	RequireTag         bool          `db:"require_tag" json:"require_tag"`
+	RequiredTagIDs     pq.Int64Array `db:"required_tag_ids" json:"required_tag_ids"`
+	BypassAutomation   bool          `db:"bypass_automation" json:"bypass_automation"`
+	BypassAIAssistant  bool          `db:"bypass_ai_assistant" json:"bypass_ai_assistant"`
+	Enabled            bool          `db:"enabled" json:"enabled"`
+}
+
+// MissingField is a field a conversation is missing before it can be resolved.
+type MissingField struct {
+	Type string `json:"type"`
+	// Key is the custom attribute key or tag name, empty when any tag is required.
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+// Field is a custom attribute definition or tag referenced by a requirement.
+type Field struct {
+	ID   int    `db:"id"`
+	Key  string `db:"key"`
+	Name string `db:"name"`
+}
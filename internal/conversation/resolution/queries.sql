@@ -0,0 +1,35 @@
+-- name: get-all-requirements
+SELECT id, created_at, updated_at, inbox_id, team_id, custom_attribute_ids, require_tag, required_tag_ids, bypass_automation, bypass_ai_assistant, enabled
+FROM resolution_requirements
+ORDER BY created_at;
+
+-- name: get-requirement
+SELECT id, created_at, updated_at, inbox_id, team_id, custom_attribute_ids, require_tag, required_tag_ids, bypass_automation, bypass_ai_assistant, enabled
+FROM resolution_requirements
+WHERE id = $1;
+
+-- name: get-applicable-requirements
+-- Enabled requirements of the conversation inbox and assigned team.
+SELECT id, created_at, updated_at, inbox_id, team_id, custom_attribute_ids, require_tag, required_tag_ids, bypass_automation, bypass_ai_assistant, enabled
+FROM resolution_requirements
+WHERE enabled AND (inbox_id = $1 OR team_id = $2);
+
+-- name: insert-requirement
+INSERT INTO resolution_requirements (inbox_id, team_id, custom_attribute_ids, require_tag, required_tag_ids, bypass_automation, bypass_ai_assistant, enabled)
+VALUES (NULLIF($1, 0), NULLIF($2, 0), $3, $4, $5, $6, $7, $8)
+RETURNING id;
+
+-- name: update-requirement
+UPDATE resolution_requirements
+SET inbox_id = NULLIF($2, 0), team_id = NULLIF($3, 0), custom_attribute_ids = $4, require_tag = $5, required_tag_ids = $6,
+    bypass_automation = $7, bypass_ai_assistant = $8, enabled = $9, updated_at = NOW()
+WHERE id = $1;
+
+-- name: delete-requirement
+DELETE FROM resolution_requirements WHERE id = $1;
+
+-- name: get-custom-attributes
+SELECT id, key, name FROM custom_attribute_definitions WHERE id = ANY($1::INT[]) AND applies_to = 'conversation' ORDER BY name;
+
+-- name: get-tags
+SELECT id, name AS key, name FROM tags WHERE id = ANY($1::INT[]) ORDER BY name;
@@ -0,0 +1,219 @@
+// Package resolution manages resolution requirements, the custom attributes and tags a conversation of an inbox or
+// team must have before it can be resolved or closed.
+package resolution
+
+import (
+	"database/sql"
+	"embed"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/abhinavxd/libredesk/internal/conversation/resolution/models"
+	"github.com/abhinavxd/libredesk/internal/dbutil"
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	umodels "github.com/abhinavxd/libredesk/internal/user/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/knadh/go-i18n"
+	"github.com/lib/pq"
+	"github.com/zerodha/logf"
+)
+
+var (
+	//go:embed queries.sql
+	efs embed.FS
+)
+
+// Manager handles resolution requirements.
+type Manager struct {
+	q    queries
+	lo   *logf.Logger
+	i18n *i18n.I18n
+}
+
+// Opts contains options for initializing the Manager.
+type Opts struct {
+	DB   *sqlx.DB
+	Lo   *logf.Logger
+	I18n *i18n.I18n
+}
+
+// queries contains prepared SQL queries.
+type queries struct {
+	GetAllRequirements        *sqlx.Stmt `query:"get-all-requirements"`
+	GetRequirement            *sqlx.Stmt `query:"get-requirement"`
+	GetApplicableRequirements *sqlx.Stmt `query:"get-applicable-requirements"`
+	InsertRequirement         *sqlx.Stmt `query:"insert-requirement"`
+	UpdateRequirement         *sqlx.Stmt `query:"update-requirement"`
+	DeleteRequirement         *sqlx.Stmt `query:"delete-requirement"`
+	GetCustomAttributes       *sqlx.Stmt `query:"get-custom-attributes"`
+	GetTags                   *sqlx.Stmt `query:"get-tags"`
+}
+
+// New creates and returns a new instance of the Manager.
+func New(opts Opts) (*Manager, error) {
+	var q queries
+	if err := dbutil.ScanSQLFile("queries.sql", &q, opts.DB, efs); err != nil {
+		return nil, err
+	}
+	return &Manager{
+		q:    q,
+		lo:   opts.Lo,
+		i18n: opts.I18n,
+	}, nil
+}
+
+// GetAll retrieves all resolution requirements.
+func (m *Manager) GetAll() ([]models.Requirement, error) {
+	var requirements = make([]models.Requirement, 0)
+	if err := m.q.GetAllRequirements.Select(&requirements); err != nil {
+		m.lo.Error("error fetching resolution requirements", "error", err)
+		return nil, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return requirements, nil
+}
+
+// Get retrieves a resolution requirement by ID.
+func (m *Manager) Get(id int) (models.Requirement, error) {
+	var requirement models.Requirement
+	if err := m.q.GetRequirement.Get(&requirement, id); err != nil {
+		if err == sql.ErrNoRows {
+			return requirement, envelope.NewError(envelope.NotFoundError, m.i18n.T("globals.messages.notFound"), nil)
+		}
+		m.lo.Error("error fetching resolution requirement", "id", id, "error", err)
+		return requirement, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return requirement, nil
+}
+
+// Create creates a resolution requirement.
+func (m *Manager) Create(r models.Requirement) (models.Requirement, error) {
+	if err := m.validate(r); err != nil {
+		return models.Requirement{}, err
+	}
+	var id int
+	if err := m.q.InsertRequirement.Get(&id, r.InboxID.Int, r.TeamID.Int, pq.Array(r.CustomAttributeIDs), r.RequireTag, pq.Array(r.RequiredTagIDs), r.BypassAutomation, r.BypassAIAssistant, r.Enabled); err != nil {
+		return models.Requirement{}, m.dbError(err)
+	}
+	return m.Get(id)
+}
+
+// Update updates a resolution requirement.
+func (m *Manager) Update(id int, r models.Requirement) (models.Requirement, error) {
+	if err := m.validate(r); err != nil {
+		return models.Requirement{}, err
+	}
+	if _, err := m.q.UpdateRequirement.Exec(id, r.InboxID.Int, r.TeamID.Int, pq.Array(r.CustomAttributeIDs), r.RequireTag, pq.Array(r.RequiredTagIDs), r.BypassAutomation, r.BypassAIAssistant, r.Enabled); err != nil {
+		return models.Requirement{}, m.dbError(err)
+	}
+	return m.Get(id)
+}
+
+// Delete deletes a resolution requirement.
+func (m *Manager) Delete(id int) error {
+	if _, err := m.q.DeleteRequirement.Exec(id); err != nil {
+		m.lo.Error("error deleting resolution requirement", "id", id, "error", err)
+		return envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return nil
+}
+
+// Check returns the fields a conversation in the given inbox and team is missing before it can be resolved by the actor.
+// Requirements that bypass automation or AI assistants are skipped for those actors.
+func (m *Manager) Check(inboxID, teamID int, tags []string, customAttributes json.RawMessage, actor umodels.User) ([]models.MissingField, error) {
+	var requirements []models.Requirement
+	if err := m.q.GetApplicableRequirements.Select(&requirements, inboxID, teamID); err != nil {
+		m.lo.Error("error fetching applicable resolution requirements", "inbox_id", inboxID, "team_id", teamID, "error", err)
+		return nil, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+
+	var (
+		attrIDs    []int64
+		tagIDs     []int64
+		requireTag bool
+	)
+	for _, r := range requirements {
+		if r.BypassAutomation && actor.IsSystemUser() {
+			continue
+		}
+		if r.BypassAIAssistant && actor.Type == umodels.UserTypeAIAssistant {
+			continue
+		}
+		attrIDs = append(attrIDs, r.CustomAttributeIDs...)
+		tagIDs = append(tagIDs, r.RequiredTagIDs...)
+		requireTag = requireTag || r.RequireTag
+	}
+
+	var missing = make([]models.MissingField, 0)
+	if len(attrIDs) > 0 {
+		var attrs []models.Field
+		if err := m.q.GetCustomAttributes.Select(&attrs, pq.Array(attrIDs)); err != nil {
+			m.lo.Error("error fetching required custom attributes", "error", err)
+			return nil, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+		}
+		var values map[string]any
+		if len(customAttributes) > 0 {
+			if err := json.Unmarshal(customAttributes, &values); err != nil {
+				m.lo.Error("error unmarshalling conversation custom attributes", "error", err)
+			}
+		}
+		for _, a := range attrs {
+			if isEmpty(values[a.Key]) {
+				missing = append(missing, models.MissingField{Type: models.MissingCustomAttribute, Key: a.Key, Name: a.Name})
+			}
+		}
+	}
+
+	if len(tagIDs) > 0 {
+		var requiredTags []models.Field
+		if err := m.q.GetTags.Select(&requiredTags, pq.Array(tagIDs)); err != nil {
+			m.lo.Error("error fetching required tags", "error", err)
+			return nil, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+		}
+		for _, t := range requiredTags {
+			if !slices.Contains(tags, t.Name) {
+				missing = append(missing, models.MissingField{Type: models.MissingTag, Key: t.Key, Name: t.Name})
+			}
+		}
+	}
+	if requireTag && len(tags) == 0 && len(tagIDs) == 0 {
+		missing = append(missing, models.MissingField{Type: models.MissingTag, Name: m.i18n.T("resolution.anyTag")})
+	}
+	return missing, nil
+}
+
+// validate validates a resolution requirement.
+func (m *Manager) validate(r models.Requirement) error {
+	if r.InboxID.Int > 0 == (r.TeamID.Int > 0) {
+		return envelope.NewError(envelope.InputError, m.i18n.T("resolution.inboxOrTeamRequired"), nil)
+	}
+	if len(r.CustomAttributeIDs) == 0 && len(r.RequiredTagIDs) == 0 && !r.RequireTag {
+		return envelope.NewError(envelope.InputError, m.i18n.T("resolution.noFieldsRequired"), nil)
+	}
+	return nil
+}
+
+// dbError maps insert and update errors to envelope errors.
+func (m *Manager) dbError(err error) error {
+	if dbutil.IsForeignKeyError(err) {
+		return envelope.NewError(envelope.InputError, m.i18n.T("validation.invalidValue"), nil)
+	}
+	if dbutil.IsUniqueViolationError(err) {
+		return envelope.NewError(envelope.ConflictError, m.i18n.T("globals.messages.errorAlreadyExists"), nil)
+	}
+	m.lo.Error("error saving resolution requirement", "error", err)
+	return envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+}
+
+// isEmpty reports whether a custom attribute value counts as not filled in.
+func isEmpty(v any) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(val) == ""
+	case []any:
+		return len(val) == 0
+	}
+	return false
+}
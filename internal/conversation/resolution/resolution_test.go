@@ -0,0 +1,101 @@
+package resolution
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/abhinavxd/libredesk/internal/conversation/resolution/models"
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	"github.com/knadh/go-i18n"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volatiletech/null/v9"
+	"github.com/zerodha/logf"
+)
+
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+	tr, err := i18n.New([]byte(`{"_.code":"en","_.name":"English"}`))
+	require.NoError(t, err)
+	lo := logf.New(logf.Opts{})
+	return &Manager{lo: &lo, i18n: tr}
+}
+
+func TestValidate(t *testing.T) {
+	m := newTestManager(t)
+	tests := []struct {
+		name  string
+		r     models.Requirement
+		valid bool
+	}{
+		{"inbox with attributes", models.Requirement{InboxID: null.IntFrom(1), CustomAttributeIDs: pq.Int64Array{1}}, true},
+		{"team with tags", models.Requirement{TeamID: null.IntFrom(1), RequiredTagIDs: pq.Int64Array{2}}, true},
+		{"team with any tag", models.Requirement{TeamID: null.IntFrom(1), RequireTag: true}, true},
+		{"neither inbox nor team", models.Requirement{RequireTag: true}, false},
+		{"both inbox and team", models.Requirement{InboxID: null.IntFrom(1), TeamID: null.IntFrom(1), RequireTag: true}, false},
+		{"nothing required", models.Requirement{InboxID: null.IntFrom(1)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := m.validate(tt.r)
+			if tt.valid {
+				assert.NoError(t, err)
+				return
+			}
+			e, ok := err.(envelope.Error)
+			require.True(t, ok)
+			assert.Equal(t, envelope.InputError, e.ErrorType)
+		})
+	}
+}
+
+func TestCreate_InvalidRequirement(t *testing.T) {
+	m := newTestManager(t)
+
+	// Invalid requirements are rejected before reaching the DB.
+	_, err := m.Create(models.Requirement{InboxID: null.IntFrom(1)})
+	require.Error(t, err)
+	_, err = m.Update(1, models.Requirement{RequireTag: true})
+	require.Error(t, err)
+}
+
+func TestDBError(t *testing.T) {
+	m := newTestManager(t)
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"unknown inbox or team", &pq.Error{Code: "23503"}, envelope.InputError},
+		{"requirement already exists", fmt.Errorf("inserting: %w", &pq.Error{Code: "23505"}), envelope.ConflictError},
+		{"other error", errors.New("connection reset"), envelope.GeneralError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, ok := m.dbError(tt.err).(envelope.Error)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, e.ErrorType)
+		})
+	}
+}
+
+func TestIsEmpty(t *testing.T) {
+	tests := []struct {
+		value any
+		empty bool
+	}{
+		{nil, true},
+		{"", true},
+		{"  ", true},
+		{[]any{}, true},
+		{"billing", false},
+		{[]any{"a"}, false},
+		{float64(0), false},
+		{false, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.empty, isEmpty(tt.value), "%#v", tt.value)
+	}
+}
@@ -0,0 +1,57 @@
+package conversation
+
+import (
+	"github.com/abhinavxd/libredesk/internal/conversation/models"
+	"github.com/abhinavxd/libredesk/internal/dbutil"
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	skmodels "github.com/abhinavxd/libredesk/internal/skill/models"
+	"github.com/lib/pq"
+)
+
+// GetConversationSkills returns the skills a conversation requires of the agent it is auto assigned to.
+func (m *Manager) GetConversationSkills(uuid string) ([]models.RequiredSkill, error) {
+	var skills = make([]models.RequiredSkill, 0)
+	if err := m.q.GetConversationSkills.Select(&skills, uuid); err != nil {
+		m.lo.Error("error fetching conversation skills", "uuid", uuid, "error", err)
+		return nil, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return skills, nil
+}
+
+// SetConversationSkills replaces the skills required by a conversation.
+func (m *Manager) SetConversationSkills(uuid string, skills []models.RequiredSkill) error {
+	var (
+		skillIDs         = make(pq.Int64Array, 0, len(skills))
+		minProficiencies = make(pq.Int64Array, 0, len(skills))
+	)
+	for _, s := range skills {
+		if s.SkillID <= 0 {
+			return envelope.NewError(envelope.InputError, m.i18n.Ts("validation.invalidValue", "name", "`skill_id`"), nil)
+		}
+		if s.MinProficiency < skmodels.MinProficiency || s.MinProficiency > skmodels.MaxProficiency {
+			return envelope.NewError(envelope.InputError, m.i18n.Ts("validation.invalidValue", "name", "`min_proficiency`"), nil)
+		}
+		skillIDs = append(skillIDs, int64(s.SkillID))
+		minProficiencies = append(minProficiencies, int64(s.MinProficiency))
+	}
+	if _, err := m.q.SetConversationSkills.Exec(uuid, skillIDs, minProficiencies); err != nil {
+		if dbutil.IsForeignKeyError(err) {
+			return envelope.NewError(envelope.InputError, m.i18n.Ts("validation.invalidValue", "name", "`skill_id`"), nil)
+		}
+		m.lo.Error("error setting conversation skills", "uuid", uuid, "error", err)
+		return envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return nil
+}
+
+// AddConversationSkill requires a skill at the given minimum proficiency for a conversation, keeping its other required skills.
+func (m *Manager) AddConversationSkill(uuid string, skillID, minProficiency int) error {
+	if minProficiency < skmodels.MinProficiency || minProficiency > skmodels.MaxProficiency {
+		return envelope.NewError(envelope.InputError, m.i18n.Ts("validation.invalidValue", "name", "`min_proficiency`"), nil)
+	}
+	if _, err := m.q.AddConversationSkill.Exec(uuid, skillID, minProficiency); err != nil {
+		m.lo.Error("error adding conversation skill", "uuid", uuid, "skill_id", skillID, "error", err)
+		return envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return nil
+}
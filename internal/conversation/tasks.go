@@ -0,0 +1,207 @@
+package conversation
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abhinavxd/libredesk/internal/conversation/models"
+	"github.com/abhinavxd/libredesk/internal/dbutil"
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	notifier "github.com/abhinavxd/libredesk/internal/notification"
+	nmodels "github.com/abhinavxd/libredesk/internal/notification/models"
+	umodels "github.com/abhinavxd/libredesk/internal/user/models"
+	"github.com/volatiletech/null/v9"
+)
+
+const (
+	maxTaskTitleLength = 500
+
+	// taskDueSoonWindow is how long before a task falls due its due reminder is sent.
+	taskDueSoonWindow = time.Hour
+)
+
+// GetConversationTasks returns all tasks of a conversation, open tasks first.
+func (m *Manager) GetConversationTasks(conversationID int) ([]models.ConversationTask, error) {
+	var tasks = make([]models.ConversationTask, 0)
+	if err := m.q.GetConversationTasks.Select(&tasks, conversationID); err != nil {
+		m.lo.Error("error fetching conversation tasks", "conversation_id", conversationID, "error", err)
+		return nil, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return tasks, nil
+}
+
+// GetUserTasks returns the tasks assigned to a user across all conversations.
+func (m *Manager) GetUserTasks(userID int, includeCompleted bool) ([]models.ConversationTask, error) {
+	var tasks = make([]models.ConversationTask, 0)
+	if err := m.q.GetUserTasks.Select(&tasks, userID, includeCompleted); err != nil {
+		m.lo.Error("error fetching user tasks", "user_id", userID, "error", err)
+		return nil, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return tasks, nil
+}
+
+// GetTask returns a task by ID.
+func (m *Manager) GetTask(id int) (models.ConversationTask, error) {
+	var task models.ConversationTask
+	if err := m.q.GetTask.Get(&task, id); err != nil {
+		if err == sql.ErrNoRows {
+			return task, envelope.NewError(envelope.NotFoundError, m.i18n.T("globals.messages.notFound"), nil)
+		}
+		m.lo.Error("error fetching task", "id", id, "error", err)
+		return task, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return task, nil
+}
+
+// CreateTask adds a task to a conversation and records it as conversation activity.
+func (m *Manager) CreateTask(conversationID int, conversationUUID, title string, assigneeID int, dueAt null.Time, actor umodels.User) (models.ConversationTask, error) {
+	title = strings.TrimSpace(title)
+	if err := m.validateTask(title, assigneeID); err != nil {
+		return models.ConversationTask{}, err
+	}
+
+	var id int
+	if err := m.q.InsertTask.Get(&id, conversationID, title, assigneeID, actor.ID, dueAt); err != nil {
+		if dbutil.IsForeignKeyError(err) {
+			return models.ConversationTask{}, envelope.NewError(envelope.InputError, m.i18n.T("validation.notFoundUser"), nil)
+		}
+		m.lo.Error("error inserting task", "conversation_id", conversationID, "error", err)
+		return models.ConversationTask{}, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+
+	if err := m.InsertConversationActivity(models.ActivityTaskCreated, conversationUUID, title, actor); err != nil {
+		m.lo.Error("error recording task creation activity", "task_id", id, "error", err)
+	}
+	return m.GetTask(id)
+}
+
+// UpdateTask updates the title, assignee and due date of a task and records it as conversation activity.
+func (m *Manager) UpdateTask(id int, conversationUUID, title string, assigneeID int, dueAt null.Time, actor umodels.User) (models.ConversationTask, error) {
+	title = strings.TrimSpace(title)
+	if err := m.validateTask(title, assigneeID); err != nil {
+		return models.ConversationTask{}, err
+	}
+	if _, err := m.q.UpdateTask.Exec(id, title, assigneeID, dueAt); err != nil {
+		if dbutil.IsForeignKeyError(err) {
+			return models.ConversationTask{}, envelope.NewError(envelope.InputError, m.i18n.T("validation.notFoundUser"), nil)
+		}
+		m.lo.Error("error updating task", "id", id, "error", err)
+		return models.ConversationTask{}, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+
+	if err := m.InsertConversationActivity(models.ActivityTaskUpdated, conversationUUID, title, actor); err != nil {
+		m.lo.Error("error recording task update activity", "task_id", id, "error", err)
+	}
+	return m.GetTask(id)
+}
+
+// SetTaskCompleted marks a task as completed or reopens it. Completion is recorded as conversation activity.
+func (m *Manager) SetTaskCompleted(id int, completed bool, actor umodels.User) (models.ConversationTask, error) {
+	task, err := m.GetTask(id)
+	if err != nil {
+		return task, err
+	}
+	if task.CompletedAt.Valid == completed {
+		return task, nil
+	}
+	if _, err := m.q.SetTaskCompleted.Exec(id, completed, actor.ID); err != nil {
+		m.lo.Error("error updating task completion", "id", id, "error", err)
+		return task, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	if completed {
+		if err := m.InsertConversationActivity(models.ActivityTaskCompleted, task.ConversationUUID, task.Title, actor); err != nil {
+			m.lo.Error("error recording task completion activity", "task_id", id, "error", err)
+		}
+	}
+	return m.GetTask(id)
+}
+
+// DeleteTask deletes a task.
+func (m *Manager) DeleteTask(id int) error {
+	if _, err := m.q.DeleteTask.Exec(id); err != nil {
+		m.lo.Error("error deleting task", "id", id, "error", err)
+		return envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return nil
+}
+
+// RunTaskReminder periodically sends reminders for tasks that are about to fall due or are overdue.
+func (m *Manager) RunTaskReminder(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.sendTaskReminders()
+		}
+	}
+}
+
+// sendTaskReminders notifies task assignees, or task creators for unassigned tasks, of due and overdue tasks.
+func (m *Manager) sendTaskReminders() {
+	var overdue []models.ConversationTask
+	if err := m.q.GetTasksOverdue.Select(&overdue); err != nil {
+		m.lo.Error("error fetching overdue tasks", "error", err)
+		return
+	}
+	for _, task := range overdue {
+		m.notifyTask(task, nmodels.NotificationTypeTaskOverdue, m.i18n.Ts("notification.taskOverdue", "referenceNumber", task.ConversationRefNumber))
+		if _, err := m.q.MarkTaskOverdueReminderSent.Exec(task.ID); err != nil {
+			m.lo.Error("error marking task overdue reminder sent", "task_id", task.ID, "error", err)
+		}
+	}
+
+	var dueSoon []models.ConversationTask
+	if err := m.q.GetTasksDueSoon.Select(&dueSoon, time.Now().Add(taskDueSoonWindow)); err != nil {
+		m.lo.Error("error fetching tasks due soon", "error", err)
+		return
+	}
+	for _, task := range dueSoon {
+		m.notifyTask(task, nmodels.NotificationTypeTaskDue, m.i18n.Ts("notification.taskDue", "referenceNumber", task.ConversationRefNumber))
+		if _, err := m.q.MarkTaskDueReminderSent.Exec(task.ID); err != nil {
+			m.lo.Error("error marking task due reminder sent", "task_id", task.ID, "error", err)
+		}
+	}
+}
+
+// notifyTask sends a task reminder to the task assignee, or to its creator when unassigned.
+func (m *Manager) notifyTask(task models.ConversationTask, notifType nmodels.NotificationType, title string) {
+	recipientID := task.AssigneeID
+	if !recipientID.Valid {
+		recipientID = task.CreatedBy
+	}
+	if !recipientID.Valid {
+		return
+	}
+	m.dispatcher.Send(notifier.Notification{
+		Type:             notifType,
+		RecipientIDs:     []int{recipientID.Int},
+		Title:            title,
+		Body:             null.StringFrom(task.Title),
+		ConversationID:   null.IntFrom(task.ConversationID),
+		ConversationUUID: task.ConversationUUID,
+	})
+}
+
+// validateTask validates a task title and checks that the assignee, if any, is an active agent.
+func (m *Manager) validateTask(title string, assigneeID int) error {
+	if title == "" {
+		return envelope.NewError(envelope.InputError, m.i18n.Ts("globals.messages.empty", "name", m.i18n.P("globals.terms.title")), nil)
+	}
+	if len(title) > maxTaskTitleLength {
+		return envelope.NewError(envelope.InputError, m.i18n.Ts("globals.messages.maxLength", "max", strconv.Itoa(maxTaskTitleLength)), nil)
+	}
+	if assigneeID == 0 {
+		return nil
+	}
+	assignee, err := m.userStore.GetAgent(assigneeID, "")
+	if err != nil || assignee.Type != umodels.UserTypeAgent || !assignee.Enabled {
+		return envelope.NewError(envelope.InputError, m.i18n.T("validation.notFoundUser"), nil)
+	}
+	return nil
+}
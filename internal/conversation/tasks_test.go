@@ -0,0 +1,95 @@
+package conversation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/abhinavxd/libredesk/internal/conversation/models"
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	umodels "github.com/abhinavxd/libredesk/internal/user/models"
+	"github.com/volatiletech/null/v9"
+	"github.com/zerodha/logf"
+)
+
+type testUserStore struct {
+	userStore
+	agents map[int]umodels.User
+}
+
+func (s *testUserStore) GetAgent(id int, email string) (umodels.User, error) {
+	agent, ok := s.agents[id]
+	if !ok {
+		return umodels.User{}, errors.New("user not found")
+	}
+	return agent, nil
+}
+
+func newTaskManager(t *testing.T) *Manager {
+	t.Helper()
+	lo := logf.New(logf.Opts{})
+	return &Manager{
+		userStore: &testUserStore{agents: map[int]umodels.User{
+			1: {ID: 1, Type: umodels.UserTypeAgent, Enabled: true},
+			2: {ID: 2, Type: umodels.UserTypeAgent, Enabled: false},
+			3: {ID: 3, Type: umodels.UserTypeContact, Enabled: true},
+		}},
+		lo:   &lo,
+		i18n: newTestI18n(t),
+	}
+}
+
+func TestValidateTask(t *testing.T) {
+	m := newTaskManager(t)
+	tests := []struct {
+		name       string
+		title      string
+		assigneeID int
+		valid      bool
+	}{
+		{"unassigned", "Call back the customer", 0, true},
+		{"assigned to an agent", "Call back the customer", 1, true},
+		{"title at max length", strings.Repeat("a", maxTaskTitleLength), 0, true},
+		{"empty title", "", 0, false},
+		{"title too long", strings.Repeat("a", maxTaskTitleLength+1), 0, false},
+		{"disabled agent", "Call back the customer", 2, false},
+		{"contact as assignee", "Call back the customer", 3, false},
+		{"unknown assignee", "Call back the customer", 9, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := m.validateTask(tt.title, tt.assigneeID)
+			if tt.valid {
+				if err != nil {
+					t.Fatalf("expected valid task, got %v", err)
+				}
+				return
+			}
+			e, ok := err.(envelope.Error)
+			if !ok || e.ErrorType != envelope.InputError {
+				t.Fatalf("expected input error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCreateTask_BlankTitle(t *testing.T) {
+	m := newTaskManager(t)
+
+	// The title is trimmed before validation, so it's rejected before reaching the DB.
+	_, err := m.CreateTask(1, "a", "   ", 0, null.Time{}, umodels.User{ID: 1})
+	if e, ok := err.(envelope.Error); !ok || e.ErrorType != envelope.InputError {
+		t.Fatalf("expected input error, got %v", err)
+	}
+	_, err = m.UpdateTask(1, "a", "\n\t", 0, null.Time{}, umodels.User{ID: 1})
+	if e, ok := err.(envelope.Error); !ok || e.ErrorType != envelope.InputError {
+		t.Fatalf("expected input error, got %v", err)
+	}
+}
+
+func TestNotifyTask_NoRecipient(t *testing.T) {
+	m := newTaskManager(t)
+
+	// A task without an assignee or a creator has no one to remind, the dispatcher isn't used.
+	m.notifyTask(models.ConversationTask{ID: 1, Title: "Follow up"}, "", "")
+}
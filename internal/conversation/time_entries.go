@@ -0,0 +1,136 @@
+package conversation
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abhinavxd/libredesk/internal/conversation/models"
+	"github.com/abhinavxd/libredesk/internal/dbutil"
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	"github.com/volatiletech/null/v9"
+)
+
+const (
+	maxTimeEntryNoteLength = 1000
+
+	// maxTimeEntryDuration caps a single manually logged time entry.
+	maxTimeEntryDuration = 24 * time.Hour
+)
+
+// GetTimeEntries returns all time entries logged on a conversation.
+func (m *Manager) GetTimeEntries(conversationID int) ([]models.TimeEntry, error) {
+	var entries = make([]models.TimeEntry, 0)
+	if err := m.q.GetTimeEntries.Select(&entries, conversationID); err != nil {
+		m.lo.Error("error fetching time entries", "conversation_id", conversationID, "error", err)
+		return nil, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return entries, nil
+}
+
+// GetTimeEntry returns a time entry by ID.
+func (m *Manager) GetTimeEntry(id int) (models.TimeEntry, error) {
+	var entry models.TimeEntry
+	if err := m.q.GetTimeEntry.Get(&entry, id); err != nil {
+		if err == sql.ErrNoRows {
+			return entry, envelope.NewError(envelope.NotFoundError, m.i18n.T("globals.messages.notFound"), nil)
+		}
+		m.lo.Error("error fetching time entry", "id", id, "error", err)
+		return entry, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return entry, nil
+}
+
+// CreateTimeEntry logs time spent by an agent on a conversation.
+func (m *Manager) CreateTimeEntry(conversationID, userID int, startedAt time.Time, durationSeconds int, note string, billable bool) (models.TimeEntry, error) {
+	note = strings.TrimSpace(note)
+	if err := m.validateTimeEntry(durationSeconds, note); err != nil {
+		return models.TimeEntry{}, err
+	}
+	if startedAt.IsZero() {
+		startedAt = time.Now().Add(-time.Duration(durationSeconds) * time.Second)
+	}
+
+	var id int
+	if err := m.q.InsertTimeEntry.Get(&id, conversationID, userID, startedAt, durationSeconds, note, billable); err != nil {
+		m.lo.Error("error inserting time entry", "conversation_id", conversationID, "user_id", userID, "error", err)
+		return models.TimeEntry{}, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return m.GetTimeEntry(id)
+}
+
+// UpdateTimeEntry updates a logged time entry. Running timers can only be stopped.
+func (m *Manager) UpdateTimeEntry(id int, startedAt time.Time, durationSeconds int, note string, billable bool) (models.TimeEntry, error) {
+	note = strings.TrimSpace(note)
+	if err := m.validateTimeEntry(durationSeconds, note); err != nil {
+		return models.TimeEntry{}, err
+	}
+	entry, err := m.GetTimeEntry(id)
+	if err != nil {
+		return entry, err
+	}
+	if startedAt.IsZero() {
+		startedAt = entry.StartedAt
+	}
+	res, err := m.q.UpdateTimeEntry.Exec(id, startedAt, durationSeconds, note, billable)
+	if err != nil {
+		m.lo.Error("error updating time entry", "id", id, "error", err)
+		return models.TimeEntry{}, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return models.TimeEntry{}, envelope.NewError(envelope.ConflictError, m.i18n.T("conversation.runningTimerNotEditable"), nil)
+	}
+	return m.GetTimeEntry(id)
+}
+
+// DeleteTimeEntry deletes a time entry.
+func (m *Manager) DeleteTimeEntry(id int) error {
+	if _, err := m.q.DeleteTimeEntry.Exec(id); err != nil {
+		m.lo.Error("error deleting time entry", "id", id, "error", err)
+		return envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return nil
+}
+
+// StartTimer starts a running timer for an agent on a conversation. An agent can have only one running timer at a time.
+func (m *Manager) StartTimer(conversationID, userID int, note string, billable bool) (models.TimeEntry, error) {
+	note = strings.TrimSpace(note)
+	if len(note) > maxTimeEntryNoteLength {
+		return models.TimeEntry{}, envelope.NewError(envelope.InputError, m.i18n.Ts("globals.messages.maxLength", "max", strconv.Itoa(maxTimeEntryNoteLength)), nil)
+	}
+
+	var id int
+	if err := m.q.InsertTimeEntry.Get(&id, conversationID, userID, time.Now(), null.Int{}, note, billable); err != nil {
+		if dbutil.IsUniqueViolationError(err) {
+			return models.TimeEntry{}, envelope.NewError(envelope.ConflictError, m.i18n.T("conversation.timerAlreadyRunning"), nil)
+		}
+		m.lo.Error("error starting timer", "conversation_id", conversationID, "user_id", userID, "error", err)
+		return models.TimeEntry{}, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return m.GetTimeEntry(id)
+}
+
+// StopTimer stops the running timer of an agent on a conversation and records the elapsed time.
+func (m *Manager) StopTimer(conversationID, userID int) (models.TimeEntry, error) {
+	var id int
+	if err := m.q.StopTimeEntryTimer.Get(&id, conversationID, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return models.TimeEntry{}, envelope.NewError(envelope.NotFoundError, m.i18n.T("conversation.noRunningTimer"), nil)
+		}
+		m.lo.Error("error stopping timer", "conversation_id", conversationID, "user_id", userID, "error", err)
+		return models.TimeEntry{}, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return m.GetTimeEntry(id)
+}
+
+// validateTimeEntry validates the duration and note of a time entry.
+func (m *Manager) validateTimeEntry(durationSeconds int, note string) error {
+	if durationSeconds <= 0 || durationSeconds > int(maxTimeEntryDuration.Seconds()) {
+		return envelope.NewError(envelope.InputError, m.i18n.Ts("validation.minmaxNumber", "min", "1", "max", strconv.Itoa(int(maxTimeEntryDuration.Seconds()))), nil)
+	}
+	if len(note) > maxTimeEntryNoteLength {
+		return envelope.NewError(envelope.InputError, m.i18n.Ts("globals.messages.maxLength", "max", strconv.Itoa(maxTimeEntryNoteLength)), nil)
+	}
+	return nil
+}
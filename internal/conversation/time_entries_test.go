@@ -0,0 +1,79 @@
+package conversation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	"github.com/zerodha/logf"
+)
+
+func newTimeEntryManager(t *testing.T) *Manager {
+	t.Helper()
+	lo := logf.New(logf.Opts{})
+	return &Manager{lo: &lo, i18n: newTestI18n(t)}
+}
+
+func TestValidateTimeEntry(t *testing.T) {
+	m := newTimeEntryManager(t)
+	maxSeconds := int(maxTimeEntryDuration.Seconds())
+	tests := []struct {
+		name     string
+		duration int
+		note     string
+		valid    bool
+	}{
+		{"a minute", 60, "Investigated the billing issue", true},
+		{"no note", 60, "", true},
+		{"a full day", maxSeconds, "", true},
+		{"note at max length", 60, strings.Repeat("a", maxTimeEntryNoteLength), true},
+		{"no duration", 0, "", false},
+		{"negative duration", -60, "", false},
+		{"longer than a day", maxSeconds + 1, "", false},
+		{"note too long", 60, strings.Repeat("a", maxTimeEntryNoteLength+1), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := m.validateTimeEntry(tt.duration, tt.note)
+			if tt.valid {
+				if err != nil {
+					t.Fatalf("expected valid time entry, got %v", err)
+				}
+				return
+			}
+			e, ok := err.(envelope.Error)
+			if !ok || e.ErrorType != envelope.InputError {
+				t.Fatalf("expected input error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTimeEntries_InvalidInputNotStored(t *testing.T) {
+	m := newTimeEntryManager(t)
+	longNote := strings.Repeat("a", maxTimeEntryNoteLength+1)
+
+	// Invalid entries are rejected before reaching the DB.
+	for name, fn := range map[string]func() error{
+		"create without duration": func() error {
+			_, err := m.CreateTimeEntry(1, 1, time.Time{}, 0, "", true)
+			return err
+		},
+		"update with a long note": func() error {
+			_, err := m.UpdateTimeEntry(1, time.Time{}, 60, longNote, true)
+			return err
+		},
+		"timer with a long note": func() error {
+			_, err := m.StartTimer(1, 1, longNote, true)
+			return err
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			e, ok := fn().(envelope.Error)
+			if !ok || e.ErrorType != envelope.InputError {
+				t.Fatalf("expected input error, got %v", e)
+			}
+		})
+	}
+}
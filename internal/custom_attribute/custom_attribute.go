@@ -4,6 +4,14 @@ package customAttribute
 import (
 	"database/sql"
 	"embed"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/abhinavxd/libredesk/internal/custom_attribute/models"
 	"github.com/abhinavxd/libredesk/internal/dbutil"
@@ -34,11 +42,12 @@ type Opts struct {
 
 // queries contains prepared SQL queries.
 type queries struct {
-	GetCustomAttribute     *sqlx.Stmt `query:"get-custom-attribute"`
-	GetAllCustomAttributes *sqlx.Stmt `query:"get-all-custom-attributes"`
-	InsertCustomAttribute  *sqlx.Stmt `query:"insert-custom-attribute"`
-	DeleteCustomAttribute  *sqlx.Stmt `query:"delete-custom-attribute"`
-	UpdateCustomAttribute  *sqlx.Stmt `query:"update-custom-attribute"`
+	GetCustomAttribute      *sqlx.Stmt `query:"get-custom-attribute"`
+	GetCustomAttributeByKey *sqlx.Stmt `query:"get-custom-attribute-by-key"`
+	GetAllCustomAttributes  *sqlx.Stmt `query:"get-all-custom-attributes"`
+	InsertCustomAttribute   *sqlx.Stmt `query:"insert-custom-attribute"`
+	DeleteCustomAttribute   *sqlx.Stmt `query:"delete-custom-attribute"`
+	UpdateCustomAttribute   *sqlx.Stmt `query:"update-custom-attribute"`
 }
 
 // New creates and returns a new instance of the Manager.
@@ -67,6 +76,67 @@ func (m *Manager) Get(id int) (models.CustomAttribute, error) {
 	return customAttribute, nil
 }
 
+// GetByKey retrieves a custom attribute by key for contacts or conversations.
+func (m *Manager) GetByKey(key, appliesTo string) (models.CustomAttribute, error) {
+	var customAttribute models.CustomAttribute
+	if err := m.q.GetCustomAttributeByKey.Get(&customAttribute, key, appliesTo); err != nil {
+		if err == sql.ErrNoRows {
+			return customAttribute, envelope.NewError(envelope.NotFoundError, m.i18n.T("validation.notFoundCustomAttribute"), nil)
+		}
+		m.lo.Error("error fetching custom attribute by key", "key", key, "error", err)
+		return customAttribute, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return customAttribute, nil
+}
+
+// ParseValue converts a raw string to the value stored for the attribute, validating it against the attribute's data type.
+func ParseValue(attr models.CustomAttribute, raw string) (any, error) {
+	raw = strings.TrimSpace(raw)
+	switch attr.DataType {
+	case models.DataTypeNumber:
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("invalid number %q", raw)
+		}
+		return v, nil
+	case models.DataTypeCheckbox:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid checkbox value %q", raw)
+		}
+		return v, nil
+	case models.DataTypeDate:
+		if _, err := time.Parse(time.DateOnly, raw); err != nil {
+			if _, err := time.Parse(time.RFC3339, raw); err != nil {
+				return nil, fmt.Errorf("invalid date %q", raw)
+			}
+		}
+		return raw, nil
+	case models.DataTypeLink:
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid link %q", raw)
+		}
+		return raw, nil
+	case models.DataTypeList:
+		if !slices.Contains(attr.Values, raw) {
+			return nil, fmt.Errorf("%q is not one of the allowed values", raw)
+		}
+		return raw, nil
+	default:
+		if attr.Regex != "" {
+			re, err := regexp.Compile(attr.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("invalid attribute regex: %w", err)
+			}
+			if !re.MatchString(raw) {
+				return nil, fmt.Errorf("%q does not match the attribute pattern", raw)
+			}
+		}
+		return raw, nil
+	}
+}
+
 // GetAll retrieves all custom attributes.
 func (m *Manager) GetAll(appliesTo string) ([]models.CustomAttribute, error) {
 	var customAttributes = make([]models.CustomAttribute, 0)
@@ -6,6 +6,18 @@ import (
 	"github.com/lib/pq"
 )
 
+const (
+	AppliesToContact      = "contact"
+	AppliesToConversation = "conversation"
+
+	DataTypeText     = "text"
+	DataTypeNumber   = "number"
+	DataTypeCheckbox = "checkbox"
+	DataTypeDate     = "date"
+	DataTypeLink     = "link"
+	DataTypeList     = "list"
+)
+
 type CustomAttribute struct {
 	ID          int            `db:"id" json:"id"`
 	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
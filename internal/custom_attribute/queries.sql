@@ -38,6 +38,24 @@ FROM
 WHERE
     id = $1;
 
+-- name: get-custom-attribute-by-key
+SELECT
+    id,
+    created_at,
+    updated_at,
+    name,
+    description,
+    applies_to,
+    key,
+    values,
+    data_type,
+    regex,
+    regex_hint
+FROM
+    custom_attribute_definitions
+WHERE
+    key = $1 AND applies_to = $2;
+
 -- name: insert-custom-attribute
 INSERT INTO
     custom_attribute_definitions (applies_to, name, description, key, values, data_type, regex, regex_hint)
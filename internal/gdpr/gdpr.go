@@ -0,0 +1,448 @@
+// Package gdpr handles data subject requests for contacts: exporting everything held about a contact as a
+// downloadable archive, and erasing a contact's personal data while keeping aggregate reporting intact.
+package gdpr
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/abhinavxd/libredesk/internal/dbutil"
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	"github.com/abhinavxd/libredesk/internal/gdpr/models"
+	mmodels "github.com/abhinavxd/libredesk/internal/media/models"
+	umodels "github.com/abhinavxd/libredesk/internal/user/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/knadh/go-i18n"
+	"github.com/volatiletech/null/v9"
+	"github.com/zerodha/logf"
+)
+
+var (
+	//go:embed queries.sql
+	efs embed.FS
+
+	// exportRetention is how long export archives are kept before they are deleted.
+	exportRetention = 7 * 24 * time.Hour
+)
+
+type userStore interface {
+	GetContactOrVisitor(id int, email string) (umodels.User, error)
+	GetNotes(id int) ([]umodels.Note, error)
+}
+
+type mediaStore interface {
+	GetConversationMedia(conversationID int) ([]mmodels.Media, error)
+	GetByModel(modelID int, model string) ([]mmodels.Media, error)
+	GetBlob(name string) ([]byte, error)
+	UploadAndInsert(srcFilename, contentType, contentID string, modelType null.String, modelID null.Int, content io.ReadSeeker, fileSize int, disposition null.String, meta []byte) (mmodels.Media, error)
+	Delete(name string) error
+}
+
+type conversationStore interface {
+	AnonymizeConversation(conversationID int) error
+}
+
+type pageVisitStore interface {
+	GetPageVisits(contactID int) []map[string]string
+	DeletePageVisits(contactID int) error
+}
+
+type activityLogStore interface {
+	ContactDataExported(actorID int, actorEmail string, contactID int) error
+	ContactDataErased(actorID int, actorEmail string, contactID int) error
+}
+
+// Manager handles contact data export and erasure requests.
+type Manager struct {
+	q                 queries
+	lo                *logf.Logger
+	i18n              *i18n.I18n
+	userStore         userStore
+	mediaStore        mediaStore
+	conversationStore conversationStore
+	pageVisitStore    pageVisitStore
+	activityStore     activityLogStore
+}
+
+// Opts contains options for initializing the Manager.
+type Opts struct {
+	DB   *sqlx.DB
+	Lo   *logf.Logger
+	I18n *i18n.I18n
+}
+
+// queries contains prepared SQL queries.
+type queries struct {
+	InsertRequest           *sqlx.Stmt `query:"insert-request"`
+	GetRequest              *sqlx.Stmt `query:"get-request"`
+	GetContactRequests      *sqlx.Stmt `query:"get-contact-requests"`
+	GetActiveRequest        *sqlx.Stmt `query:"get-active-request"`
+	ClaimNextRequest        *sqlx.Stmt `query:"claim-next-request"`
+	ResetStaleRequests      *sqlx.Stmt `query:"reset-stale-requests"`
+	CompleteRequest         *sqlx.Stmt `query:"complete-request"`
+	GetExpiredExports       *sqlx.Stmt `query:"get-expired-exports"`
+	ClearExport             *sqlx.Stmt `query:"clear-export"`
+	GetContactExports       *sqlx.Stmt `query:"get-contact-exports"`
+	GetContactConversations *sqlx.Stmt `query:"get-contact-conversations"`
+	GetContactMessages      *sqlx.Stmt `query:"get-contact-messages"`
+	GetContactCSATResponses *sqlx.Stmt `query:"get-contact-csat-responses"`
+	AnonymizeContact        *sqlx.Stmt `query:"anonymize-contact"`
+	DeleteContactNotes      *sqlx.Stmt `query:"delete-contact-notes"`
+}
+
+// New creates and returns a new instance of the Manager.
+func New(users userStore, media mediaStore, conversations conversationStore, pageVisits pageVisitStore, activity activityLogStore, opts Opts) (*Manager, error) {
+	var q queries
+	if err := dbutil.ScanSQLFile("queries.sql", &q, opts.DB, efs); err != nil {
+		return nil, err
+	}
+	return &Manager{
+		q:                 q,
+		lo:                opts.Lo,
+		i18n:              opts.I18n,
+		userStore:         users,
+		mediaStore:        media,
+		conversationStore: conversations,
+		pageVisitStore:    pageVisits,
+		activityStore:     activity,
+	}, nil
+}
+
+// CreateRequest queues a new export or erasure request for a contact.
+func (m *Manager) CreateRequest(contactID, requestedBy int, typ string) (models.Request, error) {
+	if typ != models.RequestTypeExport && typ != models.RequestTypeErasure {
+		return models.Request{}, envelope.NewError(envelope.InputError, m.i18n.T("validation.invalidValue"), nil)
+	}
+	if _, err := m.userStore.GetContactOrVisitor(contactID, ""); err != nil {
+		return models.Request{}, err
+	}
+
+	// Only one active request of a type per contact.
+	var active models.Request
+	if err := m.q.GetActiveRequest.Get(&active, contactID, typ); err == nil {
+		return models.Request{}, envelope.NewError(envelope.ConflictError, m.i18n.T("gdpr.requestAlreadyInProgress"), nil)
+	} else if err != sql.ErrNoRows {
+		m.lo.Error("error fetching active contact data request", "contact_id", contactID, "error", err)
+		return models.Request{}, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+
+	var request models.Request
+	if err := m.q.InsertRequest.Get(&request, contactID, requestedBy, typ); err != nil {
+		m.lo.Error("error inserting contact data request", "contact_id", contactID, "type", typ, "error", err)
+		return models.Request{}, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return request, nil
+}
+
+// GetRequests returns all data requests raised for a contact.
+func (m *Manager) GetRequests(contactID int) ([]models.Request, error) {
+	var requests = make([]models.Request, 0)
+	if err := m.q.GetContactRequests.Select(&requests, contactID); err != nil {
+		m.lo.Error("error fetching contact data requests", "contact_id", contactID, "error", err)
+		return nil, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	for i := range requests {
+		requests[i].Downloadable = requests[i].MediaUUID.Valid
+	}
+	return requests, nil
+}
+
+// GetExportArchive returns the file name and contents of a completed export archive.
+func (m *Manager) GetExportArchive(contactID, requestID int) (string, []byte, error) {
+	var request models.Request
+	if err := m.q.GetRequest.Get(&request, requestID, contactID); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil, envelope.NewError(envelope.NotFoundError, m.i18n.T("globals.messages.notFound"), nil)
+		}
+		m.lo.Error("error fetching contact data request", "id", requestID, "error", err)
+		return "", nil, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	if request.Type != models.RequestTypeExport || !request.MediaUUID.Valid {
+		return "", nil, envelope.NewError(envelope.NotFoundError, m.i18n.T("validation.notFoundFile"), nil)
+	}
+	blob, err := m.mediaStore.GetBlob(request.MediaUUID.String)
+	if err != nil {
+		m.lo.Error("error fetching export archive", "id", requestID, "error", err)
+		return "", nil, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return exportFileName(request), blob, nil
+}
+
+// Run is a blocking function that processes queued data requests and deletes expired export archives.
+func (m *Manager) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := m.q.ResetStaleRequests.Exec(); err != nil {
+				m.lo.Error("error resetting stale contact data requests", "error", err)
+			}
+			m.processRequests(ctx)
+			m.deleteExpiredExports()
+		}
+	}
+}
+
+// processRequests processes pending data requests one at a time until none are left.
+func (m *Manager) processRequests(ctx context.Context) {
+	for ctx.Err() == nil {
+		var request models.Request
+		if err := m.q.ClaimNextRequest.Get(&request); err != nil {
+			if err != sql.ErrNoRows {
+				m.lo.Error("error claiming contact data request", "error", err)
+			}
+			return
+		}
+
+		m.lo.Info("processing contact data request", "id", request.ID, "type", request.Type, "contact_id", request.ContactID)
+		var (
+			mediaUUID string
+			err       error
+		)
+		switch request.Type {
+		case models.RequestTypeExport:
+			mediaUUID, err = m.export(request)
+		case models.RequestTypeErasure:
+			err = m.erase(request)
+		}
+
+		var (
+			status = models.RequestStatusCompleted
+			errMsg string
+		)
+		if err != nil {
+			m.lo.Error("error processing contact data request", "id", request.ID, "type", request.Type, "error", err)
+			status = models.RequestStatusFailed
+			errMsg = err.Error()
+		}
+		if _, err := m.q.CompleteRequest.Exec(request.ID, status, mediaUUID, errMsg); err != nil {
+			m.lo.Error("error updating contact data request", "id", request.ID, "error", err)
+			continue
+		}
+		if status == models.RequestStatusCompleted {
+			m.audit(request)
+		}
+	}
+}
+
+// audit records a completed data request in the activity log.
+func (m *Manager) audit(request models.Request) {
+	var err error
+	switch request.Type {
+	case models.RequestTypeExport:
+		err = m.activityStore.ContactDataExported(request.RequestedBy.Int, request.RequestedByEmail.String, request.ContactID)
+	case models.RequestTypeErasure:
+		err = m.activityStore.ContactDataErased(request.RequestedBy.Int, request.RequestedByEmail.String, request.ContactID)
+	}
+	if err != nil {
+		m.lo.Error("error recording contact data request activity", "id", request.ID, "error", err)
+	}
+}
+
+// export builds the export archive of a contact, stores it in the media store and returns its media UUID.
+func (m *Manager) export(request models.Request) (string, error) {
+	contact, err := m.userStore.GetContactOrVisitor(request.ContactID, "")
+	if err != nil {
+		return "", fmt.Errorf("fetching contact: %w", err)
+	}
+	notes, err := m.userStore.GetNotes(request.ContactID)
+	if err != nil {
+		return "", fmt.Errorf("fetching contact notes: %w", err)
+	}
+
+	var conversations []models.Conversation
+	if err := m.q.GetContactConversations.Select(&conversations, request.ContactID); err != nil {
+		return "", fmt.Errorf("fetching conversations: %w", err)
+	}
+	var messages []models.Message
+	if err := m.q.GetContactMessages.Select(&messages, request.ContactID); err != nil {
+		return "", fmt.Errorf("fetching messages: %w", err)
+	}
+	var csatResponses = make([]models.CSATResponse, 0)
+	if err := m.q.GetContactCSATResponses.Select(&csatResponses, request.ContactID); err != nil {
+		return "", fmt.Errorf("fetching CSAT responses: %w", err)
+	}
+
+	// Group messages by conversation.
+	var convIdx = make(map[int]int, len(conversations))
+	for i := range conversations {
+		conversations[i].Messages = make([]models.Message, 0)
+		convIdx[conversations[i].ID] = i
+	}
+	for _, msg := range messages {
+		if i, ok := convIdx[msg.ConversationID]; ok {
+			conversations[i].Messages = append(conversations[i].Messages, msg)
+		}
+	}
+
+	var exportNotes = make([]models.Note, 0, len(notes))
+	for _, n := range notes {
+		exportNotes = append(exportNotes, models.Note{
+			CreatedAt: n.CreatedAt,
+			Note:      n.Note,
+			Author:    n.FirstName + " " + n.LastName,
+		})
+	}
+
+	var (
+		buf = &bytes.Buffer{}
+		zw  = zip.NewWriter(buf)
+	)
+	files := map[string]any{
+		"profile.json": models.Profile{
+			ID:                     contact.ID,
+			CreatedAt:              contact.CreatedAt,
+			Type:                   contact.Type,
+			FirstName:              contact.FirstName,
+			LastName:               contact.LastName,
+			Email:                  contact.Email,
+			PhoneNumberCountryCode: contact.PhoneNumberCountryCode,
+			PhoneNumber:            contact.PhoneNumber,
+			Country:                contact.Country,
+			AvatarURL:              contact.AvatarURL,
+			ExternalUserID:         contact.ExternalUserID,
+			CustomAttributes:       contact.CustomAttributes,
+		},
+		"notes.json":          exportNotes,
+		"conversations.json":  conversations,
+		"csat_responses.json": csatResponses,
+		"page_visits.json":    m.pageVisitStore.GetPageVisits(request.ContactID),
+	}
+	for name, data := range files {
+		if err := writeJSON(zw, name, data); err != nil {
+			return "", err
+		}
+	}
+
+	// Attachments are grouped by conversation reference number.
+	for _, c := range conversations {
+		media, err := m.mediaStore.GetConversationMedia(c.ID)
+		if err != nil {
+			return "", err
+		}
+		for _, mm := range media {
+			blob, err := m.mediaStore.GetBlob(mm.UUID)
+			if err != nil {
+				m.lo.Warn("skipping attachment missing from media store", "media_uuid", mm.UUID, "error", err)
+				continue
+			}
+			w, err := zw.Create(path.Join("attachments", c.ReferenceNumber, mm.UUID+"_"+path.Base(mm.Filename)))
+			if err != nil {
+				return "", fmt.Errorf("adding attachment to archive: %w", err)
+			}
+			if _, err := w.Write(blob); err != nil {
+				return "", fmt.Errorf("writing attachment to archive: %w", err)
+			}
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return "", fmt.Errorf("closing archive: %w", err)
+	}
+
+	media, err := m.mediaStore.UploadAndInsert(exportFileName(request), "application/zip", "",
+		null.StringFrom(models.MediaModelRequest), null.IntFrom(request.ID), bytes.NewReader(buf.Bytes()), buf.Len(),
+		null.StringFrom("attachment"), []byte("{}"))
+	if err != nil {
+		return "", fmt.Errorf("uploading archive: %w", err)
+	}
+	return media.UUID, nil
+}
+
+// erase anonymizes the contact profile, redacts the content of all of the contact's conversations, deletes their
+// media, export archives, notes and page visits. Conversation rows, ratings and timestamps are kept for aggregate reporting.
+func (m *Manager) erase(request models.Request) error {
+	var conversations []models.Conversation
+	if err := m.q.GetContactConversations.Select(&conversations, request.ContactID); err != nil {
+		return fmt.Errorf("fetching conversations: %w", err)
+	}
+	for _, c := range conversations {
+		if err := m.conversationStore.AnonymizeConversation(c.ID); err != nil {
+			return fmt.Errorf("anonymizing conversation %s: %w", c.UUID, err)
+		}
+	}
+
+	// Avatars uploaded for the contact.
+	avatars, err := m.mediaStore.GetByModel(request.ContactID, mmodels.ModelUser)
+	if err != nil {
+		return err
+	}
+	for _, a := range avatars {
+		if err := m.mediaStore.Delete(a.UUID); err != nil {
+			return fmt.Errorf("deleting contact media: %w", err)
+		}
+	}
+
+	// Archives of earlier exports hold the data being erased.
+	var exports []models.Request
+	if err := m.q.GetContactExports.Select(&exports, request.ContactID); err != nil {
+		return fmt.Errorf("fetching export archives: %w", err)
+	}
+	for _, e := range exports {
+		if err := m.mediaStore.Delete(e.MediaUUID.String); err != nil {
+			return fmt.Errorf("deleting export archive: %w", err)
+		}
+		if _, err := m.q.ClearExport.Exec(e.ID); err != nil {
+			return fmt.Errorf("clearing export archive: %w", err)
+		}
+	}
+
+	if _, err := m.q.DeleteContactNotes.Exec(request.ContactID); err != nil {
+		return fmt.Errorf("deleting contact notes: %w", err)
+	}
+	if _, err := m.q.AnonymizeContact.Exec(request.ContactID, m.i18n.T("gdpr.erasedContactName")); err != nil {
+		return fmt.Errorf("anonymizing contact: %w", err)
+	}
+	if err := m.pageVisitStore.DeletePageVisits(request.ContactID); err != nil {
+		return fmt.Errorf("deleting page visits: %w", err)
+	}
+	return nil
+}
+
+// deleteExpiredExports deletes export archives older than exportRetention.
+func (m *Manager) deleteExpiredExports() {
+	var requests []models.Request
+	if err := m.q.GetExpiredExports.Select(&requests, time.Now().Add(-exportRetention)); err != nil {
+		m.lo.Error("error fetching expired export archives", "error", err)
+		return
+	}
+	for _, r := range requests {
+		if err := m.mediaStore.Delete(r.MediaUUID.String); err != nil {
+			m.lo.Error("error deleting expired export archive", "id", r.ID, "error", err)
+			continue
+		}
+		if _, err := m.q.ClearExport.Exec(r.ID); err != nil {
+			m.lo.Error("error clearing expired export archive", "id", r.ID, "error", err)
+		}
+	}
+}
+
+// writeJSON writes data as an indented JSON file to the archive.
+func writeJSON(zw *zip.Writer, name string, data any) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("adding %s to archive: %w", name, err)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(data); err != nil {
+		return fmt.Errorf("writing %s to archive: %w", name, err)
+	}
+	return nil
+}
+
+// exportFileName returns the download file name of an export archive.
+func exportFileName(request models.Request) string {
+	return fmt.Sprintf("contact-%d-export-%d.zip", request.ContactID, request.ID)
+}
@@ -0,0 +1,104 @@
+package gdpr
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	"github.com/abhinavxd/libredesk/internal/gdpr/models"
+	umodels "github.com/abhinavxd/libredesk/internal/user/models"
+	"github.com/knadh/go-i18n"
+	"github.com/knadh/goyesql/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zerodha/logf"
+)
+
+type mockUserStore struct {
+	err error
+}
+
+func (m *mockUserStore) GetContactOrVisitor(id int, email string) (umodels.User, error) {
+	return umodels.User{ID: id}, m.err
+}
+
+func (m *mockUserStore) GetNotes(id int) ([]umodels.Note, error) {
+	return nil, nil
+}
+
+func newTestManager(t *testing.T, users userStore) *Manager {
+	t.Helper()
+	tr, err := i18n.New([]byte(`{"_.code":"en","_.name":"English"}`))
+	require.NoError(t, err)
+	lo := logf.New(logf.Opts{})
+	return &Manager{lo: &lo, i18n: tr, userStore: users}
+}
+
+func TestCreateRequest_InvalidType(t *testing.T) {
+	m := newTestManager(t, &mockUserStore{})
+	for _, typ := range []string{"", "delete", "Export"} {
+		_, err := m.CreateRequest(1, 2, typ)
+		require.Error(t, err, typ)
+		e, ok := err.(envelope.Error)
+		require.True(t, ok)
+		assert.Equal(t, envelope.InputError, e.ErrorType, typ)
+	}
+}
+
+func TestCreateRequest_UnknownContact(t *testing.T) {
+	notFound := envelope.NewError(envelope.NotFoundError, "contact not found", nil)
+	m := newTestManager(t, &mockUserStore{err: notFound})
+
+	_, err := m.CreateRequest(1, 2, models.RequestTypeErasure)
+	assert.Equal(t, notFound, err)
+}
+
+func TestWriteJSON(t *testing.T) {
+	var (
+		buf = &bytes.Buffer{}
+		zw  = zip.NewWriter(buf)
+	)
+	require.NoError(t, writeJSON(zw, "contact.json", map[string]string{"email": "jane@example.com"}))
+	require.NoError(t, zw.Close())
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, zr.File, 1)
+	assert.Equal(t, "contact.json", zr.File[0].Name)
+
+	f, err := zr.File[0].Open()
+	require.NoError(t, err)
+	defer f.Close()
+	b, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"email\": \"jane@example.com\"\n}\n", string(b))
+}
+
+func TestExportFileName(t *testing.T) {
+	assert.Equal(t, "contact-12-export-34.zip", exportFileName(models.Request{ID: 34, ContactID: 12}))
+}
+
+func TestMessageJSON(t *testing.T) {
+	b, err := json.Marshal(models.Message{ConversationID: 7, Type: "incoming"})
+	require.NoError(t, err)
+
+	var out map[string]any
+	require.NoError(t, json.Unmarshal(b, &out))
+	assert.NotContains(t, out, "conversation_id", "messages are nested under their conversation")
+	assert.NotContains(t, out, "private")
+}
+
+func TestQueries_ContactMessagesLeaveOutNotesAndActivity(t *testing.T) {
+	b, err := efs.ReadFile("queries.sql")
+	require.NoError(t, err)
+	parsed, err := goyesql.ParseBytes(b)
+	require.NoError(t, err)
+
+	query, ok := parsed["get-contact-messages"]
+	require.True(t, ok)
+	assert.Contains(t, query.Query, "m.type != 'activity'")
+	assert.Contains(t, query.Query, "NOT m.private")
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/volatiletech/null/v9"
+)
+
+const (
+	RequestTypeExport  = "export"
+	RequestTypeErasure = "erasure"
+
+	RequestStatusPending    = "pending"
+	RequestStatusProcessing = "processing"
+	RequestStatusCompleted  = "completed"
+	RequestStatusFailed     = "failed"
+
+	// MediaModelRequest is the media model type of export archives.
+	MediaModelRequest = "contact_data_requests"
+)
+
+// Request is a data subject request (export or erasure) raised for a contact.
+type Request struct {
+	ID               int         `db:"id" json:"id"`
+	CreatedAt        time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time   `db:"updated_at" json:"updated_at"`
+	ContactID        int         `db:"contact_id" json:"contact_id"`
+	RequestedBy      null.Int    `db:"requested_by" json:"requested_by"`
+	RequestedByEmail null.String `db:"requested_by_email" json:"-"`
+	Type             string      `db:"type" json:"type"`
+	Status           string      `db:"status" json:"status"`
+	MediaUUID        null.String `db:"media_uuid" json:"-"`
+	Error            null.String `db:"error" json:"error"`
+	CompletedAt      null.Time   `db:"completed_at" json:"completed_at"`
+
+	// Pseudo fields
+	Downloadable bool `db:"-" json:"downloadable"`
+}
+
+// Profile is the contact profile included in an export.
+type Profile struct {
+	ID                     int             `json:"id"`
+	CreatedAt              time.Time       `json:"created_at"`
+	Type                   string          `json:"type"`
+	FirstName              string          `json:"first_name"`
+	LastName               string          `json:"last_name"`
+	Email                  null.String     `json:"email"`
+	PhoneNumberCountryCode null.String     `json:"phone_number_country_code"`
+	PhoneNumber            null.String     `json:"phone_number"`
+	Country                null.String     `json:"country"`
+	AvatarURL              null.String     `json:"avatar_url"`
+	ExternalUserID         null.String     `json:"external_user_id"`
+	CustomAttributes       json.RawMessage `json:"custom_attributes"`
+}
+
+// Note is a contact note included in an export.
+type Note struct {
+	CreatedAt time.Time `json:"created_at"`
+	Note      string    `json:"note"`
+	Author    string    `json:"author"`
+}
+
+// Conversation is a conversation of the contact included in an export.
+type Conversation struct {
+	ID               int             `db:"id" json:"-"`
+	UUID             string          `db:"uuid" json:"uuid"`
+	ReferenceNumber  string          `db:"reference_number" json:"reference_number"`
+	CreatedAt        time.Time       `db:"created_at" json:"created_at"`
+	Subject          null.String     `db:"subject" json:"subject"`
+	Inbox            string          `db:"inbox_name" json:"inbox"`
+	Status           string          `db:"status" json:"status"`
+	ResolvedAt       null.Time       `db:"resolved_at" json:"resolved_at"`
+	ClosedAt         null.Time       `db:"closed_at" json:"closed_at"`
+	CustomAttributes json.RawMessage `db:"custom_attributes" json:"custom_attributes"`
+	Messages         []Message       `db:"-" json:"messages"`
+}
+
+// Message is a conversation message included in an export, private notes and activity messages are left out.
+type Message struct {
+	ConversationID int         `db:"conversation_id" json:"-"`
+	CreatedAt      time.Time   `db:"created_at" json:"created_at"`
+	Type           string      `db:"type" json:"type"`
+	SenderType     string      `db:"sender_type" json:"sender_type"`
+	SenderName     string      `db:"sender_name" json:"sender_name"`
+	ContentType    null.String `db:"content_type" json:"content_type"`
+	Content        null.String `db:"content" json:"content"`
+	TextContent    null.String `db:"text_content" json:"text_content"`
+}
+
+// CSATResponse is a CSAT response included in an export.
+type CSATResponse struct {
+	ConversationReferenceNumber string      `db:"reference_number" json:"conversation_reference_number"`
+	CreatedAt                   time.Time   `db:"created_at" json:"created_at"`
+	Rating                      int         `db:"rating" json:"rating"`
+	Feedback                    null.String `db:"feedback" json:"feedback"`
+	ResponseTimestamp           null.Time   `db:"response_timestamp" json:"response_timestamp"`
+}
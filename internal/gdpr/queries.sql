@@ -0,0 +1,106 @@
+-- name: insert-request
+INSERT INTO contact_data_requests (contact_id, requested_by, type)
+VALUES ($1, NULLIF($2, 0), $3)
+RETURNING id, created_at, updated_at, contact_id, requested_by, type, status, media_uuid, error, completed_at;
+
+-- name: get-request
+SELECT id, created_at, updated_at, contact_id, requested_by, type, status, media_uuid, error, completed_at
+FROM contact_data_requests
+WHERE id = $1 AND contact_id = $2;
+
+-- name: get-contact-requests
+SELECT id, created_at, updated_at, contact_id, requested_by, type, status, media_uuid, error, completed_at
+FROM contact_data_requests
+WHERE contact_id = $1
+ORDER BY created_at DESC;
+
+-- name: get-active-request
+SELECT id, created_at, updated_at, contact_id, requested_by, type, status, media_uuid, error, completed_at
+FROM contact_data_requests
+WHERE contact_id = $1 AND type = $2 AND status IN ('pending', 'processing')
+LIMIT 1;
+
+-- name: claim-next-request
+-- Picks the oldest pending request and marks it as processing.
+UPDATE contact_data_requests r
+SET status = 'processing', updated_at = NOW()
+FROM (
+    SELECT id FROM contact_data_requests
+    WHERE status = 'pending'
+    ORDER BY created_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+) next
+WHERE r.id = next.id
+RETURNING r.id, r.created_at, r.updated_at, r.contact_id, r.requested_by,
+    (SELECT email FROM users WHERE id = r.requested_by) AS requested_by_email,
+    r.type, r.status, r.media_uuid, r.error, r.completed_at;
+
+-- name: reset-stale-requests
+-- Requests left in processing state by a crash or restart are picked up again.
+UPDATE contact_data_requests
+SET status = 'pending', updated_at = NOW()
+WHERE status = 'processing' AND updated_at < NOW() - INTERVAL '1 hour';
+
+-- name: complete-request
+UPDATE contact_data_requests
+SET status = $2, media_uuid = NULLIF($3, '')::uuid, error = NULLIF($4, ''), completed_at = NOW(), updated_at = NOW()
+WHERE id = $1;
+
+-- name: get-expired-exports
+SELECT id, created_at, updated_at, contact_id, requested_by, type, status, media_uuid, error, completed_at
+FROM contact_data_requests
+WHERE type = 'export' AND media_uuid IS NOT NULL AND completed_at < $1;
+
+-- name: get-contact-exports
+SELECT id, created_at, updated_at, contact_id, requested_by, type, status, media_uuid, error, completed_at
+FROM contact_data_requests
+WHERE contact_id = $1 AND type = 'export' AND media_uuid IS NOT NULL;
+
+-- name: clear-export
+UPDATE contact_data_requests
+SET media_uuid = NULL, updated_at = NOW()
+WHERE id = $1;
+
+-- name: get-contact-conversations
+SELECT c.id, c.uuid, c.reference_number, c.created_at, c.subject, i.name AS inbox_name, s.name AS status,
+    c.resolved_at, c.closed_at, c.custom_attributes
+FROM conversations c
+INNER JOIN inboxes i ON i.id = c.inbox_id
+INNER JOIN conversation_statuses s ON s.id = c.status_id
+WHERE c.contact_id = $1
+ORDER BY c.created_at;
+
+-- name: get-contact-messages
+SELECT m.conversation_id, m.created_at, m.type, m.sender_type,
+    CONCAT_WS(' ', u.first_name, u.last_name) AS sender_name,
+    m.content_type, m.content, m.text_content
+FROM conversation_messages m
+INNER JOIN conversations c ON c.id = m.conversation_id
+INNER JOIN users u ON u.id = m.sender_id
+WHERE c.contact_id = $1 AND m.type != 'activity' AND NOT m.private
+ORDER BY m.conversation_id, m.created_at;
+
+-- name: get-contact-csat-responses
+SELECT c.reference_number, r.created_at, r.rating, r.feedback, r.response_timestamp
+FROM csat_responses r
+INNER JOIN conversations c ON c.id = r.conversation_id
+WHERE c.contact_id = $1
+ORDER BY r.created_at;
+
+-- name: anonymize-contact
+UPDATE users
+SET first_name = $2,
+    last_name = NULL,
+    email = NULL,
+    phone_number = NULL,
+    phone_number_country_code = NULL,
+    country = NULL,
+    avatar_url = NULL,
+    external_user_id = NULL,
+    custom_attributes = '{}'::jsonb,
+    updated_at = NOW()
+WHERE id = $1 AND type IN ('contact', 'visitor');
+
+-- name: delete-contact-notes
+DELETE FROM contact_notes WHERE contact_id = $1;
@@ -180,6 +180,7 @@ func (e *Email) fetchAndProcessMessages(ctx context.Context, client *imapclient.
 				HeaderFields: []string{
 					headerAutoSubmitted,
 					headerAutoreply,
+					headerPrecedence,
 					headerLibredeskLoopPrevention,
 					headerMessageID,
 				},
@@ -192,6 +193,7 @@ func (e *Email) fetchAndProcessMessages(ctx context.Context, client *imapclient.
 		env                *imap.Envelope
 		seqNum             uint32
 		autoReply          bool
+		isBulk             bool
 		isLoop             bool
 		extractedMessageID string
 	}
@@ -227,6 +229,7 @@ func (e *Email) fetchAndProcessMessages(ctx context.Context, client *imapclient.
 		var (
 			env                *imap.Envelope
 			autoReply          bool
+			isBulk             bool
 			isLoop             bool
 			extractedMessageID string
 		)
@@ -256,6 +259,9 @@ func (e *Email) fetchAndProcessMessages(ctx context.Context, client *imapclient.
 				if isAutoReply(envelope) {
 					autoReply = true
 				}
+				if isBulkMail(envelope) {
+					isBulk = true
+				}
 				if isLoopMessage(envelope, inboxEmail) {
 					isLoop = true
 				}
@@ -276,7 +282,7 @@ func (e *Email) fetchAndProcessMessages(ctx context.Context, client *imapclient.
 			continue
 		}
 
-		messages = append(messages, msgData{env: env, seqNum: msg.SeqNum, autoReply: autoReply, isLoop: isLoop, extractedMessageID: extractedMessageID})
+		messages = append(messages, msgData{env: env, seqNum: msg.SeqNum, autoReply: autoReply, isBulk: isBulk, isLoop: isLoop, extractedMessageID: extractedMessageID})
 	}
 
 	// Now process each collected message.
@@ -301,7 +307,7 @@ func (e *Email) fetchAndProcessMessages(ctx context.Context, client *imapclient.
 		}
 
 		// Process the envelope.
-		if err := e.processEnvelope(ctx, client, msgData.env, msgData.seqNum, inboxID, msgData.extractedMessageID); err != nil && err != context.Canceled {
+		if err := e.processEnvelope(ctx, client, msgData.env, msgData.seqNum, inboxID, msgData.extractedMessageID, msgData.isBulk); err != nil && err != context.Canceled {
 			e.lo.Error("error processing envelope", "error", err)
 		}
 	}
@@ -310,7 +316,8 @@ func (e *Email) fetchAndProcessMessages(ctx context.Context, client *imapclient.
 }
 
 // processEnvelope processes a single email envelope.
-func (e *Email) processEnvelope(ctx context.Context, client *imapclient.Client, env *imap.Envelope, seqNum uint32, inboxID int, extractedMessageID string) error {
+// Bulk mail is marked as auto-submitted in the message meta so that it is not automatically replied to.
+func (e *Email) processEnvelope(ctx context.Context, client *imapclient.Client, env *imap.Envelope, seqNum uint32, inboxID int, extractedMessageID string, isBulk bool) error {
 	if len(env.From) == 0 {
 		e.lo.Warn("no sender received for email", "message_id", env.MessageID)
 		return nil
@@ -388,11 +395,12 @@ func (e *Email) processEnvelope(ctx context.Context, client *imapclient.Client,
 	}
 
 	meta, err := json.Marshal(map[string]interface{}{
-		"from":    fromAddr,
-		"cc":      ccAddr,
-		"bcc":     bccAddr,
-		"to":      toAddr,
-		"subject": env.Subject,
+		"from":           fromAddr,
+		"cc":             ccAddr,
+		"bcc":            bccAddr,
+		"to":             toAddr,
+		"subject":        env.Subject,
+		"auto_submitted": isBulk,
 	})
 	if err != nil {
 		e.lo.Error("error marshalling meta", "error", err)
@@ -589,6 +597,15 @@ func isAutoReply(envelope *enmime.Envelope) bool {
 	return false
 }
 
+// isBulkMail checks if a given email envelope is bulk or mailing list mail, which must not be automatically replied to (RFC 3834).
+func isBulkMail(envelope *enmime.Envelope) bool {
+	switch strings.ToLower(strings.TrimSpace(envelope.GetHeader(headerPrecedence))) {
+	case "bulk", "list", "junk":
+		return true
+	}
+	return false
+}
+
 // isLoopMessage returns true if the email is a loop prevention message. i.e., it has the `X-Libredesk-Loop-Prevention` header with the inbox email address.
 func isLoopMessage(envelope *enmime.Envelope, inboxEmailaddress string) bool {
 	loopHeader := envelope.GetHeader(headerLibredeskLoopPrevention)
@@ -24,6 +24,7 @@ const (
 	headerLibredeskConversationID = "X-Libredesk-Conversation-UUID"
 	headerAutoreply               = "X-Autoreply"
 	headerAutoSubmitted           = "Auto-Submitted"
+	headerPrecedence              = "Precedence"
 
 	dispositionInline = "inline"
 )
@@ -177,6 +178,11 @@ func (e *Email) Send(m models.OutboundMessage) error {
 		email.Headers.Set(key, value)
 	}
 
+	// Mark automatic replies so that auto-responders on the other end do not reply back (RFC 3834).
+	if m.IsOutOfOfficeReply() {
+		email.Headers.Set(headerAutoSubmitted, "auto-replied")
+	}
+
 	// Set In-Reply-To header
 	if m.InReplyTo != "" {
 		email.Headers.Set(headerInReplyTo, "<"+m.InReplyTo+">")
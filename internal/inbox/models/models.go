@@ -28,11 +28,13 @@ type Inbox struct {
 	Enabled            bool            `db:"enabled" json:"enabled"`
 	CSATEnabled        bool            `db:"csat_enabled" json:"csat_enabled"`
 	PromptTagsOnReply  bool            `db:"prompt_tags_on_reply" json:"prompt_tags_on_reply"`
+	OutOfOfficeEnabled bool            `db:"out_of_office_enabled" json:"out_of_office_enabled"`
 	From               string          `db:"from" json:"from"`
 	FromNameTemplate   string          `db:"from_name_template" json:"from_name_template"`
 	Config             json.RawMessage `db:"config" json:"config"`
 	Secret             null.String     `db:"secret" json:"secret"`
 	LinkedEmailInboxID null.Int        `db:"linked_email_inbox_id" json:"linked_email_inbox_id"`
+	BusinessHoursID    null.Int        `db:"business_hours_id" json:"business_hours_id"`
 }
 
 // Config holds the email inbox configuration with multiple SMTP servers and IMAP clients.
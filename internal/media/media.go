@@ -92,6 +92,7 @@ type queries struct {
 	ContentIDExists         *sqlx.Stmt `query:"content-id-exists"`
 	GetByContentIDs         *sqlx.Stmt `query:"get-media-by-content-ids"`
 	GetDraftInlineMedia     *sqlx.Stmt `query:"get-draft-inline-media"`
+	GetConversationMedia    *sqlx.Stmt `query:"get-conversation-media"`
 }
 
 // UploadAndInsert uploads file on storage and inserts an entry in db.
@@ -318,6 +319,33 @@ func (m *Manager) Delete(name string) error {
 	return nil
 }
 
+// GetConversationMedia retrieves all media files attached to the messages of a conversation.
+func (m *Manager) GetConversationMedia(conversationID int) ([]models.Media, error) {
+	var media = make([]models.Media, 0)
+	if err := m.queries.GetConversationMedia.Select(&media, conversationID); err != nil {
+		m.lo.Error("error getting conversation media", "conversation_id", conversationID, "error", err)
+		return nil, fmt.Errorf("fetching media for conversation_id:%d: %w", conversationID, err)
+	}
+	return media, nil
+}
+
+// DeleteConversationMedia deletes all media files (and their thumbnails) attached to the messages of a conversation
+// from both the storage backend and the database. Returns the number of media files deleted.
+func (m *Manager) DeleteConversationMedia(conversationID int) (int, error) {
+	media, err := m.GetConversationMedia(conversationID)
+	if err != nil {
+		return 0, err
+	}
+	var deleted int
+	for _, mm := range media {
+		if err := m.deleteWithThumbnail(mm); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
 // DeleteUnlinkedMedia is a blocking function that periodically deletes media files that are not linked to any conversation message.
 func (m *Manager) DeleteUnlinkedMedia(ctx context.Context) {
 	m.deleteUnlinkedMessageMedia()
@@ -343,18 +371,24 @@ func (m *Manager) deleteUnlinkedMessageMedia() error {
 	}
 	for _, mm := range media {
 		m.lo.Debug("deleting media not linked to any message", "media_id", mm.ID)
-		if err := m.Delete(mm.UUID); err != nil {
+		if err := m.deleteWithThumbnail(mm); err != nil {
 			m.lo.Error("error deleting unlinked media", "error", err)
 			continue
 		}
+	}
+	return nil
+}
 
-		// If it's an image, also delete the `thumb_uuid` image from store.
-		if strings.HasPrefix(mm.ContentType, "image/") {
-			thumbUUID := image.ThumbPrefix + mm.UUID
-			m.lo.Debug("deleting thumbnail for unlinked media", "thumb_uuid", thumbUUID)
-			if err := m.Delete(thumbUUID); err != nil {
-				m.lo.Error("error deleting thumbnail for unlinked media", "error", err)
-			}
+// deleteWithThumbnail deletes a media file and, for images, its `thumb_uuid` thumbnail.
+func (m *Manager) deleteWithThumbnail(mm models.Media) error {
+	if err := m.Delete(mm.UUID); err != nil {
+		return err
+	}
+	if strings.HasPrefix(mm.ContentType, "image/") {
+		thumbUUID := image.ThumbPrefix + mm.UUID
+		m.lo.Debug("deleting media thumbnail", "thumb_uuid", thumbUUID)
+		if err := m.Delete(thumbUUID); err != nil {
+			m.lo.Error("error deleting media thumbnail", "thumb_uuid", thumbUUID, "error", err)
 		}
 	}
 	return nil
@@ -78,3 +78,10 @@ FROM media m
 LEFT JOIN conversation_messages cm ON cm.id = m.model_id AND m.model_type = 'messages'
 WHERE m.uuid = $1
   AND (COALESCE(m.model_id, 0) = 0 OR cm.conversation_id = $2);
+
+-- name: get-conversation-media
+SELECT m.id, m.created_at, m.updated_at, m."uuid", m.store, m.filename, m.content_type, m.content_id, m.model_id, m.model_type, m.disposition, m."size", m.meta
+FROM media m
+INNER JOIN conversation_messages cm ON cm.id = m.model_id
+WHERE m.model_type = 'messages'
+  AND cm.conversation_id = $1;
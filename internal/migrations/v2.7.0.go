@@ -0,0 +1,438 @@
+package migrations
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/knadh/koanf/v2"
+	"github.com/knadh/stuffbin"
+)
+
+func V2_7_0(db *sqlx.DB, fs stuffbin.FileSystem, ko *koanf.Koanf) error {
+	// Data retention policies.
+	if _, err := db.Exec(`
+		DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'retention_action') THEN
+				CREATE TYPE retention_action AS ENUM ('anonymize', 'delete');
+			END IF;
+		END$$;
+	`); err != nil {
+		return err
+	}
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS retention_policies (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMPTZ DEFAULT NOW(),
+			updated_at TIMESTAMPTZ DEFAULT NOW(),
+			inbox_id INT REFERENCES inboxes(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL UNIQUE,
+			retention_days INT NOT NULL,
+			action retention_action NOT NULL,
+			enabled BOOL DEFAULT TRUE NOT NULL,
+			CONSTRAINT constraint_retention_policies_on_retention_days CHECK (retention_days > 0)
+		);
+	`); err != nil {
+		return err
+	}
+	if _, err := db.Exec(`ALTER TYPE activity_log_type ADD VALUE IF NOT EXISTS 'conversations_purged';`); err != nil {
+		return err
+	}
+
+	// Contact data export and erasure requests.
+	if _, err := db.Exec(`
+		DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'contact_data_request_type') THEN
+				CREATE TYPE contact_data_request_type AS ENUM ('export', 'erasure');
+			END IF;
+			IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'contact_data_request_status') THEN
+				CREATE TYPE contact_data_request_status AS ENUM ('pending', 'processing', 'completed', 'failed');
+			END IF;
+		END$$;
+	`); err != nil {
+		return err
+	}
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS contact_data_requests (
+			id BIGSERIAL PRIMARY KEY,
+			created_at TIMESTAMPTZ DEFAULT NOW(),
+			updated_at TIMESTAMPTZ DEFAULT NOW(),
+			contact_id BIGINT REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+			requested_by BIGINT REFERENCES users(id) ON DELETE SET NULL ON UPDATE CASCADE NULL,
+			type contact_data_request_type NOT NULL,
+			status contact_data_request_status DEFAULT 'pending' NOT NULL,
+			media_uuid UUID NULL,
+			error TEXT NULL,
+			completed_at TIMESTAMPTZ NULL
+		);
+		CREATE INDEX IF NOT EXISTS index_contact_data_requests_on_contact_id ON contact_data_requests(contact_id);
+		CREATE INDEX IF NOT EXISTS index_contact_data_requests_on_status ON contact_data_requests(status);
+	`); err != nil {
+		return err
+	}
+	for _, typ := range []string{"contact_data_exported", "contact_data_erased"} {
+		if _, err := db.Exec(`ALTER TYPE activity_log_type ADD VALUE IF NOT EXISTS '` + typ + `';`); err != nil {
+			return err
+		}
+	}
+	for _, permission := range []string{"contacts:export", "contacts:erase"} {
+		if _, err := db.Exec(`
+			UPDATE roles
+			SET permissions = array_append(permissions, $1)
+			WHERE name = 'Admin' AND NOT ($1 = ANY(permissions));
+		`, permission); err != nil {
+			return err
+		}
+	}
+
+	// Conversation tasks.
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS conversation_tasks (
+			id BIGSERIAL PRIMARY KEY,
+			created_at TIMESTAMPTZ DEFAULT NOW(),
+			updated_at TIMESTAMPTZ DEFAULT NOW(),
+			conversation_id BIGINT REFERENCES conversations(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+			title TEXT NOT NULL,
+			assignee_id BIGINT REFERENCES users(id) ON DELETE SET NULL ON UPDATE CASCADE NULL,
+			created_by BIGINT REFERENCES users(id) ON DELETE SET NULL ON UPDATE CASCADE NULL,
+			due_at TIMESTAMPTZ NULL,
+			completed_at TIMESTAMPTZ NULL,
+			completed_by BIGINT REFERENCES users(id) ON DELETE SET NULL ON UPDATE CASCADE NULL,
+			due_reminder_sent_at TIMESTAMPTZ NULL,
+			overdue_reminder_sent_at TIMESTAMPTZ NULL,
+			CONSTRAINT constraint_conversation_tasks_on_title CHECK (length(title) <= 500)
+		);
+		CREATE INDEX IF NOT EXISTS index_conversation_tasks_on_conversation_id ON conversation_tasks(conversation_id);
+		CREATE INDEX IF NOT EXISTS index_conversation_tasks_on_assignee_id ON conversation_tasks(assignee_id);
+		CREATE INDEX IF NOT EXISTS index_conversation_tasks_on_due_at ON conversation_tasks(due_at) WHERE completed_at IS NULL;
+	`); err != nil {
+		return err
+	}
+	for _, typ := range []string{"task_due", "task_overdue"} {
+		if _, err := db.Exec(`ALTER TYPE user_notification_type ADD VALUE IF NOT EXISTS '` + typ + `';`); err != nil {
+			return err
+		}
+	}
+
+	// Conversation time tracking.
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS conversation_time_entries (
+			id BIGSERIAL PRIMARY KEY,
+			created_at TIMESTAMPTZ DEFAULT NOW(),
+			updated_at TIMESTAMPTZ DEFAULT NOW(),
+			conversation_id BIGINT REFERENCES conversations(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+			user_id BIGINT REFERENCES users(id) ON DELETE SET NULL ON UPDATE CASCADE NULL,
+			started_at TIMESTAMPTZ NOT NULL,
+			duration_seconds INT NULL,
+			note TEXT DEFAULT '' NOT NULL,
+			billable BOOLEAN DEFAULT FALSE NOT NULL,
+			CONSTRAINT constraint_conversation_time_entries_on_duration_seconds CHECK (duration_seconds IS NULL OR duration_seconds >= 0),
+			CONSTRAINT constraint_conversation_time_entries_on_note CHECK (length(note) <= 1000)
+		);
+		CREATE INDEX IF NOT EXISTS index_conversation_time_entries_on_conversation_id ON conversation_time_entries(conversation_id);
+		CREATE INDEX IF NOT EXISTS index_conversation_time_entries_on_started_at ON conversation_time_entries(started_at);
+		CREATE UNIQUE INDEX IF NOT EXISTS index_uniq_conversation_time_entries_on_user_id_running ON conversation_time_entries(user_id) WHERE duration_seconds IS NULL;
+	`); err != nil {
+		return err
+	}
+
+	// Resolution requirements.
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS resolution_requirements (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMPTZ DEFAULT NOW(),
+			updated_at TIMESTAMPTZ DEFAULT NOW(),
+			inbox_id INT REFERENCES inboxes(id) ON DELETE CASCADE ON UPDATE CASCADE NULL,
+			team_id INT REFERENCES teams(id) ON DELETE CASCADE ON UPDATE CASCADE NULL,
+			custom_attribute_ids INT[] DEFAULT '{}'::INT[] NOT NULL,
+			require_tag BOOLEAN DEFAULT FALSE NOT NULL,
+			required_tag_ids INT[] DEFAULT '{}'::INT[] NOT NULL,
+			bypass_automation BOOLEAN DEFAULT TRUE NOT NULL,
+			bypass_ai_assistant BOOLEAN DEFAULT TRUE NOT NULL,
+			enabled BOOLEAN DEFAULT TRUE NOT NULL,
+			CONSTRAINT constraint_resolution_requirements_on_inbox_id_team_id CHECK ((inbox_id IS NULL) <> (team_id IS NULL))
+		);
+		CREATE UNIQUE INDEX IF NOT EXISTS index_uniq_resolution_requirements_on_inbox_id ON resolution_requirements(inbox_id) WHERE inbox_id IS NOT NULL;
+		CREATE UNIQUE INDEX IF NOT EXISTS index_uniq_resolution_requirements_on_team_id ON resolution_requirements(team_id) WHERE team_id IS NOT NULL;
+	`); err != nil {
+		return err
+	}
+
+	// Automation rule versions and execution log.
+	if _, err := db.Exec(`
+		ALTER TABLE automation_rules ADD COLUMN IF NOT EXISTS version INT DEFAULT 1 NOT NULL;
+		CREATE TABLE IF NOT EXISTS automation_rule_executions (
+			id BIGSERIAL PRIMARY KEY,
+			created_at TIMESTAMPTZ DEFAULT NOW(),
+			rule_id INT REFERENCES automation_rules(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+			rule_version INT NOT NULL,
+			conversation_id BIGINT REFERENCES conversations(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+			"trigger" TEXT NOT NULL,
+			matched_conditions JSONB DEFAULT '[]'::JSONB NOT NULL,
+			actions JSONB DEFAULT '[]'::JSONB NOT NULL,
+			error TEXT NULL
+		);
+		CREATE INDEX IF NOT EXISTS index_automation_rule_executions_on_rule_id_and_created_at ON automation_rule_executions(rule_id, created_at);
+		CREATE INDEX IF NOT EXISTS index_automation_rule_executions_on_conversation_id ON automation_rule_executions(conversation_id);
+		CREATE INDEX IF NOT EXISTS index_automation_rule_executions_on_created_at ON automation_rule_executions(created_at);
+	`); err != nil {
+		return err
+	}
+
+	// Delayed automation actions.
+	if _, err := db.Exec(`
+		DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'automation_scheduled_action_status') THEN
+				CREATE TYPE automation_scheduled_action_status AS ENUM ('pending', 'running', 'completed', 'failed', 'cancelled');
+			END IF;
+		END$$;
+		CREATE TABLE IF NOT EXISTS automation_scheduled_actions (
+			id BIGSERIAL PRIMARY KEY,
+			created_at TIMESTAMPTZ DEFAULT NOW(),
+			updated_at TIMESTAMPTZ DEFAULT NOW(),
+			rule_id INT REFERENCES automation_rules(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+			rule_version INT NOT NULL,
+			conversation_id BIGINT REFERENCES conversations(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+			action JSONB NOT NULL,
+			previous_values JSONB DEFAULT '{}'::JSONB NOT NULL,
+			run_at TIMESTAMPTZ NOT NULL,
+			status automation_scheduled_action_status DEFAULT 'pending' NOT NULL,
+			error TEXT NULL
+		);
+		CREATE INDEX IF NOT EXISTS index_automation_scheduled_actions_on_run_at ON automation_scheduled_actions(run_at) WHERE status = 'pending';
+		CREATE INDEX IF NOT EXISTS index_automation_scheduled_actions_on_conversation_id ON automation_scheduled_actions(conversation_id);
+		CREATE UNIQUE INDEX IF NOT EXISTS index_uniq_automation_scheduled_actions_on_rule_id_conversation_id_action ON automation_scheduled_actions(rule_id, conversation_id, action) WHERE status = 'pending';
+	`); err != nil {
+		return err
+	}
+
+	// Automation rule version history.
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS automation_rule_versions (
+			id BIGSERIAL PRIMARY KEY,
+			created_at TIMESTAMPTZ DEFAULT NOW(),
+			rule_id INT REFERENCES automation_rules(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+			"version" INT NOT NULL,
+			author_id INT REFERENCES users(id) ON DELETE SET NULL ON UPDATE CASCADE NULL,
+			"name" TEXT NOT NULL,
+			description TEXT NULL,
+			"type" VARCHAR NOT NULL,
+			events TEXT[] DEFAULT '{}'::TEXT[] NOT NULL,
+			rules JSONB NULL,
+			enabled BOOL NOT NULL,
+			diff JSONB DEFAULT '[]'::JSONB NOT NULL
+		);
+		CREATE UNIQUE INDEX IF NOT EXISTS index_uniq_automation_rule_versions_on_rule_id_and_version ON automation_rule_versions(rule_id, "version");
+		INSERT INTO automation_rule_versions (rule_id, "version", "name", description, "type", events, rules, enabled)
+		SELECT id, "version", "name", description, "type", events, rules, enabled FROM automation_rules
+		ON CONFLICT DO NOTHING;
+	`); err != nil {
+		return err
+	}
+
+	// Cron scheduled automations.
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS automation_schedules (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMPTZ DEFAULT NOW(),
+			updated_at TIMESTAMPTZ DEFAULT NOW(),
+			"name" TEXT NOT NULL,
+			description TEXT DEFAULT '' NOT NULL,
+			enabled BOOL DEFAULT TRUE NOT NULL,
+			cron_expression TEXT NOT NULL,
+			timezone TEXT DEFAULT 'UTC' NOT NULL,
+			filters JSONB DEFAULT '[]'::JSONB NOT NULL,
+			actions JSONB DEFAULT '[]'::JSONB NOT NULL,
+			next_run_at TIMESTAMPTZ NULL,
+			last_run_at TIMESTAMPTZ NULL,
+			last_run_conversations INT DEFAULT 0 NOT NULL,
+			last_run_error TEXT NULL,
+			CONSTRAINT constraint_automation_schedules_on_name CHECK (length("name") <= 140),
+			CONSTRAINT constraint_automation_schedules_on_description CHECK (length(description) <= 300)
+		);
+		CREATE INDEX IF NOT EXISTS index_automation_schedules_on_next_run_at ON automation_schedules(next_run_at) WHERE enabled = TRUE;
+	`); err != nil {
+		return err
+	}
+
+	// SLA pause on configured conversation statuses.
+	if _, err := db.Exec(`
+		ALTER TABLE sla_policies ADD COLUMN IF NOT EXISTS pause_statuses JSONB DEFAULT '{}'::jsonb NOT NULL;
+		ALTER TABLE applied_slas ADD COLUMN IF NOT EXISTS resolution_paused_seconds INT DEFAULT 0 NOT NULL;
+		ALTER TABLE applied_slas ADD COLUMN IF NOT EXISTS next_response_paused_seconds INT DEFAULT 0 NOT NULL;
+		CREATE TABLE IF NOT EXISTS applied_sla_pauses (
+			id BIGSERIAL PRIMARY KEY,
+			created_at TIMESTAMPTZ DEFAULT NOW(),
+			applied_sla_id BIGINT REFERENCES applied_slas(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+			metric sla_metric NOT NULL,
+			paused_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+			resumed_at TIMESTAMPTZ NULL
+		);
+		CREATE INDEX IF NOT EXISTS index_applied_sla_pauses_on_applied_sla_id ON applied_sla_pauses(applied_sla_id);
+		CREATE UNIQUE INDEX IF NOT EXISTS index_uniq_applied_sla_pauses_open_per_metric ON applied_sla_pauses(applied_sla_id, metric) WHERE resumed_at IS NULL;
+	`); err != nil {
+		return err
+	}
+
+	// Priority based SLA targets.
+	if _, err := db.Exec(`
+		ALTER TABLE sla_policies ADD COLUMN IF NOT EXISTS priority_targets JSONB DEFAULT '[]'::jsonb NOT NULL;
+	`); err != nil {
+		return err
+	}
+
+	// SLA breach escalations.
+	if _, err := db.Exec(`
+		ALTER TABLE sla_policies ADD COLUMN IF NOT EXISTS escalations JSONB DEFAULT '[]'::jsonb NOT NULL;
+		CREATE TABLE IF NOT EXISTS applied_sla_escalations (
+			id BIGSERIAL PRIMARY KEY,
+			created_at TIMESTAMPTZ DEFAULT NOW(),
+			applied_sla_id BIGINT REFERENCES applied_slas(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+			sla_event_id BIGINT REFERENCES sla_events(id) ON DELETE CASCADE ON UPDATE CASCADE NULL,
+			metric sla_metric NOT NULL,
+			escalation_key TEXT NOT NULL
+		);
+		CREATE UNIQUE INDEX IF NOT EXISTS index_uniq_applied_sla_escalations ON applied_sla_escalations(applied_sla_id, COALESCE(sla_event_id, 0), escalation_key);
+	`); err != nil {
+		return err
+	}
+
+	// Automatic SLA policy selection.
+	if _, err := db.Exec(`
+		ALTER TABLE sla_policies ADD COLUMN IF NOT EXISTS conditions JSONB DEFAULT '{}'::jsonb NOT NULL;
+		ALTER TABLE sla_policies ADD COLUMN IF NOT EXISTS match_weight INT DEFAULT 0 NOT NULL;
+	`); err != nil {
+		return err
+	}
+
+	// Holiday calendar imports.
+	if _, err := db.Exec(`
+		ALTER TABLE business_hours ADD COLUMN IF NOT EXISTS holiday_calendar_url TEXT NULL;
+	`); err != nil {
+		return err
+	}
+
+	// Per-inbox business hours.
+	if _, err := db.Exec(`
+		ALTER TABLE inboxes ADD COLUMN IF NOT EXISTS business_hours_id INT REFERENCES business_hours(id) ON DELETE SET NULL ON UPDATE CASCADE NULL;
+	`); err != nil {
+		return err
+	}
+
+	// Out of office replies.
+	if _, err := db.Exec(`
+		ALTER TABLE inboxes ADD COLUMN IF NOT EXISTS out_of_office_enabled BOOL DEFAULT false NOT NULL;
+	`); err != nil {
+		return err
+	}
+	if _, err := db.Exec(`
+		INSERT INTO templates ("type", body, is_default, "name", subject, is_builtin)
+		SELECT
+			'email_notification'::template_type,
+			'
+<p>Hi {{ .Contact.FirstName }},</p>
+
+<p>Thanks for reaching out. We are currently closed{{ if .NextOpenTime }} and will be back {{ .NextOpenTime }}{{ end }}.</p>
+
+<p>Your message has been received under reference number {{ .Conversation.ReferenceNumber }} and we will get back to you as soon as we are open.</p>
+',
+			false,
+			'Out of office',
+			'',
+			true
+		WHERE NOT EXISTS (
+			SELECT 1 FROM templates WHERE name = 'Out of office' AND is_builtin = true
+		);
+	`); err != nil {
+		return err
+	}
+
+	// Least busy auto assignment.
+	if _, err := db.Exec(`ALTER TYPE conversation_assignment_type ADD VALUE IF NOT EXISTS 'Least busy';`); err != nil {
+		return err
+	}
+
+	// Skills based routing.
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS skills (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMPTZ DEFAULT NOW(),
+			updated_at TIMESTAMPTZ DEFAULT NOW(),
+			"name" TEXT NOT NULL UNIQUE,
+			description TEXT DEFAULT '' NOT NULL,
+			CONSTRAINT constraint_skills_on_name CHECK (length("name") <= 140),
+			CONSTRAINT constraint_skills_on_description CHECK (length(description) <= 300)
+		);
+		CREATE TABLE IF NOT EXISTS user_skills (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMPTZ DEFAULT NOW(),
+			user_id BIGINT REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+			skill_id INT REFERENCES skills(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+			proficiency INT DEFAULT 1 NOT NULL,
+			CONSTRAINT constraint_user_skills_on_proficiency CHECK (proficiency BETWEEN 1 AND 5)
+		);
+		CREATE UNIQUE INDEX IF NOT EXISTS index_user_skills_on_user_id_and_skill_id ON user_skills (user_id, skill_id);
+		CREATE TABLE IF NOT EXISTS conversation_skills (
+			id BIGSERIAL PRIMARY KEY,
+			created_at TIMESTAMPTZ DEFAULT NOW(),
+			conversation_id BIGINT REFERENCES conversations(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+			skill_id INT REFERENCES skills(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+			min_proficiency INT DEFAULT 1 NOT NULL,
+			CONSTRAINT constraint_conversation_skills_on_min_proficiency CHECK (min_proficiency BETWEEN 1 AND 5)
+		);
+		CREATE UNIQUE INDEX IF NOT EXISTS index_conversation_skills_on_conversation_id_and_skill_id ON conversation_skills (conversation_id, skill_id);
+		ALTER TABLE teams ADD COLUMN IF NOT EXISTS skill_fallback_minutes INT DEFAULT 0 NOT NULL;
+	`); err != nil {
+		return err
+	}
+
+	// Per channel agent capacity.
+	if _, err := db.Exec(`
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS capacity INT DEFAULT 0 NOT NULL;
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS channel_weights JSONB DEFAULT '{}'::jsonb NOT NULL;
+		ALTER TABLE users DROP CONSTRAINT IF EXISTS constraint_users_on_capacity;
+		ALTER TABLE users ADD CONSTRAINT constraint_users_on_capacity CHECK (capacity >= 0);
+	`); err != nil {
+		return err
+	}
+
+	// Sticky assignment of returning contacts.
+	if _, err := db.Exec(`
+		ALTER TABLE teams ADD COLUMN IF NOT EXISTS sticky_assignment_days INT DEFAULT 0 NOT NULL;
+	`); err != nil {
+		return err
+	}
+
+	// Agent shift schedules.
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS agent_schedules (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMPTZ DEFAULT NOW(),
+			updated_at TIMESTAMPTZ DEFAULT NOW(),
+			user_id BIGINT REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL UNIQUE,
+			enabled BOOL DEFAULT TRUE NOT NULL,
+			timezone TEXT NOT NULL,
+			hours JSONB DEFAULT '{}'::jsonb NOT NULL,
+			time_off JSONB DEFAULT '[]'::jsonb NOT NULL,
+			reassign_at_shift_end BOOL DEFAULT FALSE NOT NULL,
+			on_shift BOOL NULL
+		);
+	`); err != nil {
+		return err
+	}
+
+	// Away statuses set at the end of agent shifts.
+	if _, err := db.Exec(`
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS away_set_by_shift BOOL DEFAULT FALSE NOT NULL;
+	`); err != nil {
+		return err
+	}
+
+	// Last auto assignment times of agents.
+	if _, err := db.Exec(`
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS last_auto_assigned_at TIMESTAMPTZ NULL;
+	`); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -11,10 +11,12 @@ import (
 type NotificationType string
 
 const (
-	NotificationTypeMention    NotificationType = "mention"
-	NotificationTypeAssignment NotificationType = "assignment"
-	NotificationTypeSLAWarning NotificationType = "sla_warning"
-	NotificationTypeSLABreach  NotificationType = "sla_breach"
+	NotificationTypeMention     NotificationType = "mention"
+	NotificationTypeAssignment  NotificationType = "assignment"
+	NotificationTypeSLAWarning  NotificationType = "sla_warning"
+	NotificationTypeSLABreach   NotificationType = "sla_breach"
+	NotificationTypeTaskDue     NotificationType = "task_due"
+	NotificationTypeTaskOverdue NotificationType = "task_overdue"
 )
 
 // UserNotification represents an in-app notification for a user.
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type OverviewSLA struct {
 	FirstResponseMetCount         int     `json:"first_response_met_count" db:"first_response_met_count"`
 	FirstResponseBreachedCount    int     `json:"first_response_breached_count" db:"first_response_breached_count"`
@@ -10,7 +12,90 @@ type OverviewSLA struct {
 	ResolutionMetCount            int     `json:"resolution_met_count" db:"resolution_met_count"`
 	ResolutionBreachedCount       int     `json:"resolution_breached_count" db:"resolution_breached_count"`
 	AvgResolutionTimeSec          float64 `json:"avg_resolution_time_sec" db:"avg_resolution_time_sec"`
+	AvgResolutionPausedTimeSec    float64 `json:"avg_resolution_paused_time_sec" db:"avg_resolution_paused_time_sec"`
+	AvgNextResponsePausedTimeSec  float64 `json:"avg_next_response_paused_time_sec" db:"avg_next_response_paused_time_sec"`
 	FirstResponseCompliancePercent float64 `json:"first_response_compliance_percent" db:"first_response_compliance_percent"`
 	NextResponseCompliancePercent  float64 `json:"next_response_compliance_percent" db:"next_response_compliance_percent"`
 	ResolutionCompliancePercent    float64 `json:"resolution_compliance_percent" db:"resolution_compliance_percent"`
 }
+
+// Time report groupings.
+const (
+	TimeReportByAgent   = "agent"
+	TimeReportByTeam    = "team"
+	TimeReportByContact = "contact"
+	TimeReportByCompany = "company"
+)
+
+// TimeReportGroups lists the valid time report groupings.
+var TimeReportGroups = []string{TimeReportByAgent, TimeReportByTeam, TimeReportByContact, TimeReportByCompany}
+
+// TimeReportRow is the time logged for a single group in the time report.
+// Key is the ID of the agent, team or contact, or the email domain for companies.
+type TimeReportRow struct {
+	Key               string `json:"key" db:"key"`
+	Name              string `json:"name" db:"name"`
+	EntryCount        int    `json:"entry_count" db:"entry_count"`
+	ConversationCount int    `json:"conversation_count" db:"conversation_count"`
+	TotalSeconds      int    `json:"total_seconds" db:"total_seconds"`
+	BillableSeconds   int    `json:"billable_seconds" db:"billable_seconds"`
+}
+
+// SLA report groupings, `all` reports each metric across all conversations.
+const (
+	SLAReportByAll      = "all"
+	SLAReportByTeam     = "team"
+	SLAReportByAgent    = "agent"
+	SLAReportByPolicy   = "policy"
+	SLAReportByPriority = "priority"
+	SLAReportByInbox    = "inbox"
+)
+
+// SLAReportGroups lists the valid SLA report groupings.
+var SLAReportGroups = []string{SLAReportByAll, SLAReportByTeam, SLAReportByAgent, SLAReportByPolicy, SLAReportByPriority, SLAReportByInbox}
+
+// SLAReport is the SLA compliance over a date range.
+type SLAReport struct {
+	GroupBy   string                    `json:"group_by"`
+	Timezone  string                    `json:"timezone"`
+	Breakdown []SLAReportRow            `json:"breakdown"`
+	Trend     []SLAReportTrendRow       `json:"trend"`
+	Breached  []SLAReportBreachedRecord `json:"breached"`
+}
+
+// SLAReportRow is the compliance of a single SLA metric for a single group.
+// Key is the ID of the team, agent, policy, priority or inbox, empty for conversations without one.
+type SLAReportRow struct {
+	Key                string  `json:"key" db:"key"`
+	Name               string  `json:"name" db:"name"`
+	Metric             string  `json:"metric" db:"metric"`
+	MetCount           int     `json:"met_count" db:"met_count"`
+	BreachedCount      int     `json:"breached_count" db:"breached_count"`
+	CompliancePercent  float64 `json:"compliance_percent" db:"compliance_percent"`
+	BreachedPercent    float64 `json:"breached_percent" db:"breached_percent"`
+	AvgTimeToBreachSec float64 `json:"avg_time_to_breach_sec" db:"avg_time_to_breach_sec"`
+}
+
+// SLAReportTrendRow is the met and breached counts of a single SLA metric on a single day.
+type SLAReportTrendRow struct {
+	Date          string `json:"date" db:"date"`
+	Metric        string `json:"metric" db:"metric"`
+	MetCount      int    `json:"met_count" db:"met_count"`
+	BreachedCount int    `json:"breached_count" db:"breached_count"`
+}
+
+// SLAReportBreachedRecord is a conversation that breached an SLA metric.
+type SLAReportBreachedRecord struct {
+	UUID            string    `json:"uuid" db:"uuid"`
+	ReferenceNumber string    `json:"reference_number" db:"reference_number"`
+	Subject         string    `json:"subject" db:"subject"`
+	Metric          string    `json:"metric" db:"metric"`
+	DeadlineAt      time.Time `json:"deadline_at" db:"deadline_at"`
+	BreachedAt      time.Time `json:"breached_at" db:"breached_at"`
+	SLAPolicyName   string    `json:"sla_policy_name" db:"sla_policy_name"`
+	TeamName        string    `json:"team_name" db:"team_name"`
+	AgentName       string    `json:"agent_name" db:"agent_name"`
+	PriorityName    string    `json:"priority_name" db:"priority_name"`
+	InboxName       string    `json:"inbox_name" db:"inbox_name"`
+	URL             string    `json:"url" db:"-"`
+}
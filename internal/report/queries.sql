@@ -116,7 +116,22 @@ WITH first_and_resolution AS (
                     resolution_met_at IS NOT NULL
             ),
             0
-        ) AS avg_resolution_time_sec
+        ) AS avg_resolution_time_sec,
+        -- Time the clocks were paused waiting on the contact, averaged over the SLAs that were paused.
+        COALESCE(
+            AVG(resolution_paused_seconds) FILTER (
+                WHERE
+                    resolution_paused_seconds > 0
+            ),
+            0
+        ) AS avg_resolution_paused_time_sec,
+        COALESCE(
+            AVG(next_response_paused_seconds) FILTER (
+                WHERE
+                    next_response_paused_seconds > 0
+            ),
+            0
+        ) AS avg_next_response_paused_time_sec
     FROM
         applied_slas
     WHERE
@@ -167,6 +182,8 @@ SELECT
     fas.resolution_met_count,
     fas.resolution_breached_count,
     fas.avg_resolution_time_sec,
+    fas.avg_resolution_paused_time_sec,
+    fas.avg_next_response_paused_time_sec,
     CASE
         WHEN (fas.first_response_met_count + fas.first_response_breached_count) > 0
         THEN ROUND((fas.first_response_met_count::numeric / (fas.first_response_met_count + fas.first_response_breached_count)::numeric) * 100, 1)
@@ -360,4 +377,178 @@ SELECT
         END
     ) AS result
 FROM
-    tagging;
\ No newline at end of file
+    tagging;
+
+-- name: get-time-report
+-- Aggregates logged time over a date range, grouped by agent, team (conversation's assigned team),
+-- contact or company (the contact's email domain). The range runs from the start of day $2 up to the start of
+-- day $3 in timezone $4.
+SELECT
+    COALESCE(CASE $1::TEXT
+        WHEN 'agent' THEN te.user_id::TEXT
+        WHEN 'team' THEN c.assigned_team_id::TEXT
+        WHEN 'contact' THEN c.contact_id::TEXT
+        WHEN 'company' THEN NULLIF(LOWER(SPLIT_PART(ct.email, '@', 2)), '')
+    END, '') AS key,
+    COALESCE(CASE $1::TEXT
+        WHEN 'agent' THEN NULLIF(CONCAT_WS(' ', a.first_name, a.last_name), '')
+        WHEN 'team' THEN tm.name
+        WHEN 'contact' THEN NULLIF(CONCAT_WS(' ', ct.first_name, ct.last_name), '')
+        WHEN 'company' THEN NULLIF(LOWER(SPLIT_PART(ct.email, '@', 2)), '')
+    END, '') AS name,
+    COUNT(*) AS entry_count,
+    COUNT(DISTINCT te.conversation_id) AS conversation_count,
+    COALESCE(SUM(te.duration_seconds), 0) AS total_seconds,
+    COALESCE(SUM(te.duration_seconds) FILTER (WHERE te.billable), 0) AS billable_seconds
+FROM conversation_time_entries te
+INNER JOIN conversations c ON c.id = te.conversation_id
+INNER JOIN users ct ON ct.id = c.contact_id
+LEFT JOIN users a ON a.id = te.user_id
+LEFT JOIN teams tm ON tm.id = c.assigned_team_id
+WHERE te.duration_seconds IS NOT NULL
+    AND te.started_at >= $2::DATE::TIMESTAMP AT TIME ZONE $4
+    AND te.started_at < $3::DATE::TIMESTAMP AT TIME ZONE $4
+GROUP BY 1, 2
+ORDER BY total_seconds DESC;
+
+-- name: get-sla-report-breakdown
+-- Met and breached counts per SLA metric over a date range, grouped by team, agent, policy, priority or inbox.
+-- A metric falls in the range by the time its clock started, `all` returns a single group per metric.
+WITH metrics AS (
+    SELECT a.conversation_id, a.sla_policy_id, 'first_response' AS metric, a.created_at AS start_at,
+        a.first_response_met_at AS met_at, a.first_response_breached_at AS breached_at
+    FROM applied_slas a
+    WHERE a.first_response_deadline_at IS NOT NULL AND a.created_at >= $2 AND a.created_at < $3
+    UNION ALL
+    SELECT a.conversation_id, a.sla_policy_id, 'resolution', a.created_at,
+        a.resolution_met_at, a.resolution_breached_at
+    FROM applied_slas a
+    WHERE a.resolution_deadline_at IS NOT NULL AND a.created_at >= $2 AND a.created_at < $3
+    UNION ALL
+    SELECT a.conversation_id, e.sla_policy_id, 'next_response', e.created_at,
+        e.met_at, e.breached_at
+    FROM sla_events e
+    INNER JOIN applied_slas a ON a.id = e.applied_sla_id
+    WHERE e.type = 'next_response' AND e.created_at >= $2 AND e.created_at < $3
+)
+SELECT
+    COALESCE(CASE $1::TEXT
+        WHEN 'team' THEN c.assigned_team_id::TEXT
+        WHEN 'agent' THEN c.assigned_user_id::TEXT
+        WHEN 'policy' THEN m.sla_policy_id::TEXT
+        WHEN 'priority' THEN c.priority_id::TEXT
+        WHEN 'inbox' THEN c.inbox_id::TEXT
+    END, '') AS key,
+    COALESCE(CASE $1::TEXT
+        WHEN 'team' THEN tm.name
+        WHEN 'agent' THEN NULLIF(CONCAT_WS(' ', u.first_name, u.last_name), '')
+        WHEN 'policy' THEN sp.name
+        WHEN 'priority' THEN pr.name
+        WHEN 'inbox' THEN ib.name
+    END, '') AS name,
+    m.metric,
+    COUNT(*) FILTER (WHERE m.met_at IS NOT NULL) AS met_count,
+    COUNT(*) FILTER (WHERE m.breached_at IS NOT NULL) AS breached_count,
+    CASE
+        WHEN COUNT(*) FILTER (WHERE m.met_at IS NOT NULL OR m.breached_at IS NOT NULL) > 0
+        THEN ROUND((COUNT(*) FILTER (WHERE m.met_at IS NOT NULL))::numeric / (COUNT(*) FILTER (WHERE m.met_at IS NOT NULL OR m.breached_at IS NOT NULL))::numeric * 100, 1)
+        ELSE 0
+    END AS compliance_percent,
+    CASE
+        WHEN COUNT(*) FILTER (WHERE m.met_at IS NOT NULL OR m.breached_at IS NOT NULL) > 0
+        THEN ROUND((COUNT(*) FILTER (WHERE m.breached_at IS NOT NULL))::numeric / (COUNT(*) FILTER (WHERE m.met_at IS NOT NULL OR m.breached_at IS NOT NULL))::numeric * 100, 1)
+        ELSE 0
+    END AS breached_percent,
+    COALESCE(AVG(EXTRACT(EPOCH FROM (m.breached_at - m.start_at))) FILTER (WHERE m.breached_at IS NOT NULL), 0) AS avg_time_to_breach_sec
+FROM metrics m
+INNER JOIN conversations c ON c.id = m.conversation_id
+LEFT JOIN teams tm ON tm.id = c.assigned_team_id
+LEFT JOIN users u ON u.id = c.assigned_user_id
+LEFT JOIN sla_policies sp ON sp.id = m.sla_policy_id
+LEFT JOIN conversation_priorities pr ON pr.id = c.priority_id
+LEFT JOIN inboxes ib ON ib.id = c.inbox_id
+GROUP BY 1, 2, 3
+ORDER BY 2, 3;
+
+-- name: get-sla-report-trend
+-- Daily met and breached counts per SLA metric, days are bucketed in the given timezone.
+-- When a group key is given only the metrics of that team, agent, policy, priority or inbox are counted,
+-- the key is matched the way get-sla-report-breakdown builds it.
+WITH metrics AS (
+    SELECT a.conversation_id, a.sla_policy_id, 'first_response' AS metric, a.created_at AS start_at,
+        a.first_response_met_at AS met_at, a.first_response_breached_at AS breached_at
+    FROM applied_slas a
+    WHERE a.first_response_deadline_at IS NOT NULL AND a.created_at >= $1 AND a.created_at < $2
+    UNION ALL
+    SELECT a.conversation_id, a.sla_policy_id, 'resolution', a.created_at, a.resolution_met_at, a.resolution_breached_at
+    FROM applied_slas a
+    WHERE a.resolution_deadline_at IS NOT NULL AND a.created_at >= $1 AND a.created_at < $2
+    UNION ALL
+    SELECT a.conversation_id, e.sla_policy_id, 'next_response', e.created_at, e.met_at, e.breached_at
+    FROM sla_events e
+    INNER JOIN applied_slas a ON a.id = e.applied_sla_id
+    WHERE e.type = 'next_response' AND e.created_at >= $1 AND e.created_at < $2
+)
+SELECT
+    TO_CHAR(m.start_at AT TIME ZONE $3, 'YYYY-MM-DD') AS date,
+    m.metric,
+    COUNT(*) FILTER (WHERE m.met_at IS NOT NULL) AS met_count,
+    COUNT(*) FILTER (WHERE m.breached_at IS NOT NULL) AS breached_count
+FROM metrics m
+INNER JOIN conversations c ON c.id = m.conversation_id
+WHERE $5::TEXT IS NULL OR COALESCE(CASE $4::TEXT
+    WHEN 'team' THEN c.assigned_team_id::TEXT
+    WHEN 'agent' THEN c.assigned_user_id::TEXT
+    WHEN 'policy' THEN m.sla_policy_id::TEXT
+    WHEN 'priority' THEN c.priority_id::TEXT
+    WHEN 'inbox' THEN c.inbox_id::TEXT
+END, '') = $5::TEXT
+GROUP BY 1, 2
+ORDER BY 1, 2;
+
+-- name: get-sla-report-breached
+-- Conversations with a breached SLA metric over a date range, most recent breach first.
+-- When a group key is given only the breaches of that team, agent, policy, priority or inbox are listed.
+WITH breaches AS (
+    SELECT a.conversation_id, a.sla_policy_id, 'first_response' AS metric,
+        a.first_response_deadline_at AS deadline_at, a.first_response_breached_at AS breached_at
+    FROM applied_slas a
+    WHERE a.first_response_breached_at IS NOT NULL AND a.created_at >= $1 AND a.created_at < $2
+    UNION ALL
+    SELECT a.conversation_id, a.sla_policy_id, 'resolution', a.resolution_deadline_at, a.resolution_breached_at
+    FROM applied_slas a
+    WHERE a.resolution_breached_at IS NOT NULL AND a.created_at >= $1 AND a.created_at < $2
+    UNION ALL
+    SELECT a.conversation_id, e.sla_policy_id, 'next_response', e.deadline_at, e.breached_at
+    FROM sla_events e
+    INNER JOIN applied_slas a ON a.id = e.applied_sla_id
+    WHERE e.type = 'next_response' AND e.breached_at IS NOT NULL AND e.created_at >= $1 AND e.created_at < $2
+)
+SELECT
+    c.uuid,
+    c.reference_number,
+    COALESCE(c.subject, '') AS subject,
+    b.metric,
+    b.deadline_at,
+    b.breached_at,
+    COALESCE(sp.name, '') AS sla_policy_name,
+    COALESCE(tm.name, '') AS team_name,
+    COALESCE(NULLIF(CONCAT_WS(' ', u.first_name, u.last_name), ''), '') AS agent_name,
+    COALESCE(pr.name, '') AS priority_name,
+    COALESCE(ib.name, '') AS inbox_name
+FROM breaches b
+INNER JOIN conversations c ON c.id = b.conversation_id
+LEFT JOIN sla_policies sp ON sp.id = b.sla_policy_id
+LEFT JOIN teams tm ON tm.id = c.assigned_team_id
+LEFT JOIN users u ON u.id = c.assigned_user_id
+LEFT JOIN conversation_priorities pr ON pr.id = c.priority_id
+LEFT JOIN inboxes ib ON ib.id = c.inbox_id
+WHERE $5::TEXT IS NULL OR COALESCE(CASE $4::TEXT
+    WHEN 'team' THEN c.assigned_team_id::TEXT
+    WHEN 'agent' THEN c.assigned_user_id::TEXT
+    WHEN 'policy' THEN b.sla_policy_id::TEXT
+    WHEN 'priority' THEN c.priority_id::TEXT
+    WHEN 'inbox' THEN c.inbox_id::TEXT
+END, '') = $5::TEXT
+ORDER BY b.breached_at DESC
+LIMIT $3;
@@ -7,12 +7,15 @@ import (
 	"embed"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/abhinavxd/libredesk/internal/dbutil"
 	"github.com/abhinavxd/libredesk/internal/envelope"
 	"github.com/abhinavxd/libredesk/internal/report/models"
 	"github.com/jmoiron/sqlx"
 	"github.com/knadh/go-i18n"
+	"github.com/volatiletech/null/v9"
 	"github.com/zerodha/logf"
 )
 
@@ -21,6 +24,9 @@ var (
 	efs embed.FS
 )
 
+// maxSLAReportBreached is the maximum number of breached conversations listed in the SLA report.
+const maxSLAReportBreached = 500
+
 type Manager struct {
 	q    queries
 	lo   *logf.Logger
@@ -43,6 +49,10 @@ type queries struct {
 	GetOverviewCSAT            string `query:"get-overview-csat"`
 	GetOverviewMessageVolume   string `query:"get-overview-message-volume"`
 	GetOverviewTagDistribution string `query:"get-overview-tag-distribution"`
+	GetTimeReport              string `query:"get-time-report"`
+	GetSLAReportBreakdown      string `query:"get-sla-report-breakdown"`
+	GetSLAReportTrend          string `query:"get-sla-report-trend"`
+	GetSLAReportBreached       string `query:"get-sla-report-breached"`
 }
 
 // New creates and returns a new instance of the Manager.
@@ -196,3 +206,69 @@ func (m *Manager) GetOverviewTagDistribution(days int) (json.RawMessage, error)
 	}
 	return stats, nil
 }
+
+// GetTimeReport returns time logged on conversations from the start of day from up to the start of day to in the given
+// timezone, grouped by agent, team, contact or company.
+func (m *Manager) GetTimeReport(groupBy string, from, to time.Time, timezone string) ([]models.TimeReportRow, error) {
+	if !slices.Contains(models.TimeReportGroups, groupBy) {
+		return nil, envelope.NewError(envelope.InputError, m.i18n.T("validation.invalidValue"), nil)
+	}
+	if from.IsZero() || to.IsZero() || !to.After(from) {
+		return nil, envelope.NewError(envelope.InputError, m.i18n.T("report.invalidDateRange"), nil)
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, envelope.NewError(envelope.InputError, m.i18n.Ts("validation.invalidValue", "name", "`timezone`"), nil)
+	}
+
+	var rows = make([]models.TimeReportRow, 0)
+	if err := m.db.Select(&rows, m.q.GetTimeReport, groupBy, from.Format(time.DateOnly), to.Format(time.DateOnly), timezone); err != nil {
+		m.lo.Error("error fetching time report", "group_by", groupBy, "error", err)
+		return nil, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return rows, nil
+}
+
+// GetSLAReport returns SLA compliance per metric between from and to, grouped by team, agent, policy, priority or inbox,
+// along with a daily trend in the given timezone and the conversations that breached.
+// A valid key limits the trend and the breached conversations to that group, an empty key being the group without one.
+func (m *Manager) GetSLAReport(groupBy string, key null.String, from, to time.Time, timezone string) (models.SLAReport, error) {
+	var report = models.SLAReport{
+		GroupBy:   groupBy,
+		Timezone:  timezone,
+		Breakdown: make([]models.SLAReportRow, 0),
+		Trend:     make([]models.SLAReportTrendRow, 0),
+		Breached:  make([]models.SLAReportBreachedRecord, 0),
+	}
+	if !slices.Contains(models.SLAReportGroups, groupBy) {
+		return report, envelope.NewError(envelope.InputError, m.i18n.Ts("validation.invalidValue", "name", "`group_by`"), nil)
+	}
+	if key.Valid && groupBy == models.SLAReportByAll {
+		return report, envelope.NewError(envelope.InputError, m.i18n.Ts("validation.invalidValue", "name", "`key`"), nil)
+	}
+	if from.IsZero() || to.IsZero() || !to.After(from) {
+		return report, envelope.NewError(envelope.InputError, m.i18n.T("report.invalidDateRange"), nil)
+	}
+
+	tx, err := m.db.BeginTxx(context.Background(), &sql.TxOptions{
+		ReadOnly: true,
+	})
+	if err != nil {
+		m.lo.Error("error starting db txn", "error", err)
+		return report, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	defer tx.Rollback()
+
+	if err := tx.Select(&report.Breakdown, m.q.GetSLAReportBreakdown, groupBy, from, to); err != nil {
+		m.lo.Error("error fetching SLA report breakdown", "group_by", groupBy, "error", err)
+		return report, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	if err := tx.Select(&report.Trend, m.q.GetSLAReportTrend, from, to, timezone, groupBy, key); err != nil {
+		m.lo.Error("error fetching SLA report trend", "group_by", groupBy, "error", err)
+		return report, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	if err := tx.Select(&report.Breached, m.q.GetSLAReportBreached, from, to, maxSLAReportBreached, groupBy, key); err != nil {
+		m.lo.Error("error fetching SLA report breached conversations", "group_by", groupBy, "error", err)
+		return report, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return report, nil
+}
@@ -0,0 +1,112 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	"github.com/abhinavxd/libredesk/internal/report/models"
+	"github.com/knadh/go-i18n"
+	"github.com/knadh/goyesql/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volatiletech/null/v9"
+	"github.com/zerodha/logf"
+)
+
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+	tr, err := i18n.New([]byte(`{"_.code":"en","_.name":"English"}`))
+	require.NoError(t, err)
+	lo := logf.New(logf.Opts{})
+	return &Manager{lo: &lo, i18n: tr}
+}
+
+func TestGetTimeReport_InvalidInput(t *testing.T) {
+	var (
+		m    = newTestManager(t)
+		from = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		to   = from.AddDate(0, 0, 7)
+	)
+	tests := []struct {
+		name     string
+		groupBy  string
+		from, to time.Time
+		timezone string
+	}{
+		{name: "unknown group", groupBy: "inbox", from: from, to: to, timezone: "UTC"},
+		{name: "to before from", groupBy: models.TimeReportByAgent, from: to, to: from, timezone: "UTC"},
+		{name: "unknown timezone", groupBy: models.TimeReportByAgent, from: from, to: to, timezone: "Mars/Olympus"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := m.GetTimeReport(tt.groupBy, tt.from, tt.to, tt.timezone)
+			require.Error(t, err)
+			e, ok := err.(envelope.Error)
+			require.True(t, ok)
+			assert.Equal(t, envelope.InputError, e.ErrorType)
+			assert.Nil(t, rows)
+		})
+	}
+}
+
+func TestGetSLAReport_InvalidInput(t *testing.T) {
+	var (
+		m    = newTestManager(t)
+		from = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		to   = from.AddDate(0, 0, 7)
+	)
+	tests := []struct {
+		name     string
+		groupBy  string
+		key      null.String
+		from, to time.Time
+	}{
+		{name: "unknown group", groupBy: "company", from: from, to: to},
+		{name: "key without a group", groupBy: models.SLAReportByAll, key: null.StringFrom("1"), from: from, to: to},
+		{name: "empty key without a group", groupBy: models.SLAReportByAll, key: null.StringFrom(""), from: from, to: to},
+		{name: "missing from", groupBy: models.SLAReportByTeam, to: to},
+		{name: "to before from", groupBy: models.SLAReportByTeam, from: to, to: from},
+		{name: "empty range", groupBy: models.SLAReportByTeam, from: from, to: from},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := m.GetSLAReport(tt.groupBy, tt.key, tt.from, tt.to, "UTC")
+			require.Error(t, err)
+			e, ok := err.(envelope.Error)
+			require.True(t, ok)
+			assert.Equal(t, envelope.InputError, e.ErrorType)
+			assert.Equal(t, tt.groupBy, report.GroupBy)
+			assert.NotNil(t, report.Breakdown)
+			assert.NotNil(t, report.Trend)
+			assert.NotNil(t, report.Breached)
+		})
+	}
+}
+
+func TestSLAReportQueries_FilterByGroupKey(t *testing.T) {
+	b, err := efs.ReadFile("queries.sql")
+	require.NoError(t, err)
+	parsed, err := goyesql.ParseBytes(b)
+	require.NoError(t, err)
+
+	var q queries
+	require.NoError(t, goyesql.ScanToStruct(&q, parsed, nil))
+
+	// The trend and breached conversations take the group and key after their own arguments,
+	// and match every group the breakdown can return.
+	for name, query := range map[string]string{
+		"get-sla-report-trend":    q.GetSLAReportTrend,
+		"get-sla-report-breached": q.GetSLAReportBreached,
+	} {
+		assert.Contains(t, query, "$5::TEXT IS NULL", name)
+		assert.Contains(t, query, "CASE $4::TEXT", name)
+		for _, group := range models.SLAReportGroups {
+			if group == models.SLAReportByAll {
+				continue
+			}
+			assert.True(t, strings.Contains(query, "WHEN '"+group+"'"), "%s doesn't filter by %s", name, group)
+		}
+	}
+}
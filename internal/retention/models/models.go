@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"github.com/volatiletech/null/v9"
+)
+
+const (
+	// ActionAnonymize redacts message content, drops attachments and clears PII while keeping
+	// the conversation row around so reports stay intact.
+	ActionAnonymize = "anonymize"
+	// ActionDelete hard-deletes the conversation along with its messages and attachments.
+	ActionDelete = "delete"
+)
+
+// Policy represents a per-inbox data retention policy.
+type Policy struct {
+	ID            int       `db:"id" json:"id"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+	InboxID       int       `db:"inbox_id" json:"inbox_id"`
+	InboxName     string    `db:"inbox_name" json:"inbox_name"`
+	RetentionDays int       `db:"retention_days" json:"retention_days"`
+	Action        string    `db:"action" json:"action"`
+	Enabled       bool      `db:"enabled" json:"enabled"`
+}
+
+// Cutoff returns the time before which resolved / closed conversations are considered expired.
+func (p Policy) Cutoff(now time.Time) time.Time {
+	return now.Add(-time.Duration(p.RetentionDays) * 24 * time.Hour)
+}
+
+// Conversation is a conversation that has outlived its inbox retention policy.
+type Conversation struct {
+	Total           int         `db:"total" json:"-"`
+	ID              int         `db:"id" json:"id"`
+	UUID            string      `db:"uuid" json:"uuid"`
+	ReferenceNumber string      `db:"reference_number" json:"reference_number"`
+	Subject         null.String `db:"subject" json:"subject"`
+	ResolvedAt      null.Time   `db:"resolved_at" json:"resolved_at"`
+	ClosedAt        null.Time   `db:"closed_at" json:"closed_at"`
+}
+
+// Summary holds the counts of records affected by a retention policy.
+type Summary struct {
+	ConversationCount int `db:"conversation_count" json:"conversation_count"`
+	MessageCount      int `db:"message_count" json:"message_count"`
+	MediaCount        int `db:"media_count" json:"media_count"`
+}
+
+// Report is the result of a retention policy dry-run.
+type Report struct {
+	Summary
+	Policy        Policy         `json:"policy"`
+	Cutoff        time.Time      `json:"cutoff"`
+	Conversations []Conversation `json:"conversations"`
+}
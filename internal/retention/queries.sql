@@ -0,0 +1,92 @@
+-- name: get-all-policies
+SELECT rp.id, rp.created_at, rp.updated_at, rp.inbox_id, i.name AS inbox_name, rp.retention_days, rp.action, rp.enabled
+FROM retention_policies rp
+INNER JOIN inboxes i ON i.id = rp.inbox_id
+WHERE i.deleted_at IS NULL
+ORDER BY rp.id;
+
+-- name: get-enabled-policies
+SELECT rp.id, rp.created_at, rp.updated_at, rp.inbox_id, i.name AS inbox_name, rp.retention_days, rp.action, rp.enabled
+FROM retention_policies rp
+INNER JOIN inboxes i ON i.id = rp.inbox_id
+WHERE rp.enabled = true AND i.deleted_at IS NULL
+ORDER BY rp.id;
+
+-- name: get-policy
+SELECT rp.id, rp.created_at, rp.updated_at, rp.inbox_id, i.name AS inbox_name, rp.retention_days, rp.action, rp.enabled
+FROM retention_policies rp
+INNER JOIN inboxes i ON i.id = rp.inbox_id
+WHERE rp.inbox_id = $1;
+
+-- name: upsert-policy
+INSERT INTO retention_policies (inbox_id, retention_days, action, enabled)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (inbox_id) DO UPDATE SET
+    retention_days = EXCLUDED.retention_days,
+    action = EXCLUDED.action,
+    enabled = EXCLUDED.enabled,
+    updated_at = NOW();
+
+-- name: delete-policy
+DELETE FROM retention_policies WHERE inbox_id = $1;
+
+-- name: get-expired-conversations
+-- $1 = inbox id, $2 = cutoff, $3 = action, $4 = limit.
+-- Anonymized conversations are skipped so that they are not processed again.
+SELECT COUNT(*) OVER() AS total, c.id, c.uuid, c.reference_number, c.subject, c.resolved_at, c.closed_at
+FROM conversations c
+INNER JOIN conversation_statuses s ON s.id = c.status_id
+WHERE c.inbox_id = $1
+  AND s.category = 'resolved'
+  AND COALESCE(c.closed_at, c.resolved_at, c.updated_at) < $2
+  AND ($3::TEXT = 'delete' OR c.meta->>'anonymized_at' IS NULL)
+ORDER BY c.id
+LIMIT $4;
+
+-- name: get-expired-summary
+-- $1 = inbox id, $2 = cutoff, $3 = action.
+WITH expired AS (
+    SELECT c.id
+    FROM conversations c
+    INNER JOIN conversation_statuses s ON s.id = c.status_id
+    WHERE c.inbox_id = $1
+      AND s.category = 'resolved'
+      AND COALESCE(c.closed_at, c.resolved_at, c.updated_at) < $2
+      AND ($3::TEXT = 'delete' OR c.meta->>'anonymized_at' IS NULL)
+)
+SELECT
+    (SELECT COUNT(*) FROM expired) AS conversation_count,
+    (SELECT COUNT(*) FROM conversation_messages cm WHERE cm.conversation_id IN (SELECT id FROM expired)) AS message_count,
+    (
+        SELECT COUNT(*)
+        FROM media m
+        INNER JOIN conversation_messages cm ON cm.id = m.model_id
+        WHERE m.model_type = 'messages' AND cm.conversation_id IN (SELECT id FROM expired)
+    ) AS media_count;
+
+-- name: anonymize-messages
+-- Activity messages carry no customer content and are kept as is for the conversation timeline.
+UPDATE conversation_messages
+SET content = $2, text_content = $2, meta = jsonb_build_object('anonymized_at', NOW()), updated_at = NOW()
+WHERE conversation_id = $1 AND type != 'activity';
+
+-- name: anonymize-conversation
+UPDATE conversations
+SET subject = NULL,
+    last_message = $2,
+    last_interaction = $2,
+    custom_attributes = '{}'::jsonb,
+    meta = meta || jsonb_build_object('anonymized_at', NOW()),
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: anonymize-csat-feedback
+UPDATE csat_responses
+SET feedback = NULL, updated_at = NOW()
+WHERE conversation_id = $1 AND feedback IS NOT NULL;
+
+-- name: delete-conversation-drafts
+DELETE FROM conversation_drafts WHERE conversation_id = $1;
+
+-- name: delete-conversation
+DELETE FROM conversations WHERE id = $1;
@@ -0,0 +1,272 @@
+// Package retention enforces per-inbox data retention policies by anonymizing or deleting
+// resolved and closed conversations once they are older than the configured retention period.
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"slices"
+	"time"
+
+	"github.com/abhinavxd/libredesk/internal/dbutil"
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	"github.com/abhinavxd/libredesk/internal/retention/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/knadh/go-i18n"
+	"github.com/zerodha/logf"
+)
+
+var (
+	//go:embed queries.sql
+	efs embed.FS
+
+	// purgeBatchSize is the number of conversations purged per policy in a single batch.
+	purgeBatchSize = 200
+
+	// dryRunLimit is the maximum number of conversations listed in a dry-run report.
+	dryRunLimit = 100
+)
+
+type mediaStore interface {
+	DeleteConversationMedia(conversationID int) (int, error)
+}
+
+type activityLogStore interface {
+	ConversationsPurged(actorID, inboxID int, inboxName, action string, count int) error
+}
+
+// Manager handles data retention policies.
+type Manager struct {
+	q             queries
+	db            *sqlx.DB
+	lo            *logf.Logger
+	i18n          *i18n.I18n
+	mediaStore    mediaStore
+	activityStore activityLogStore
+	systemUserID  int
+}
+
+// Opts contains options for initializing the Manager.
+type Opts struct {
+	DB   *sqlx.DB
+	Lo   *logf.Logger
+	I18n *i18n.I18n
+	// SystemUserID is recorded as the actor of purge activity logs.
+	SystemUserID int
+}
+
+// queries contains prepared SQL queries.
+type queries struct {
+	GetAllPolicies           *sqlx.Stmt `query:"get-all-policies"`
+	GetEnabledPolicies       *sqlx.Stmt `query:"get-enabled-policies"`
+	GetPolicy                *sqlx.Stmt `query:"get-policy"`
+	UpsertPolicy             *sqlx.Stmt `query:"upsert-policy"`
+	DeletePolicy             *sqlx.Stmt `query:"delete-policy"`
+	GetExpiredConversations  *sqlx.Stmt `query:"get-expired-conversations"`
+	GetExpiredSummary        *sqlx.Stmt `query:"get-expired-summary"`
+	AnonymizeMessages        *sqlx.Stmt `query:"anonymize-messages"`
+	AnonymizeConversation    *sqlx.Stmt `query:"anonymize-conversation"`
+	AnonymizeCSATFeedback    *sqlx.Stmt `query:"anonymize-csat-feedback"`
+	DeleteConversationDrafts *sqlx.Stmt `query:"delete-conversation-drafts"`
+	DeleteConversation       *sqlx.Stmt `query:"delete-conversation"`
+}
+
+// New creates and returns a new instance of the Manager.
+func New(media mediaStore, activity activityLogStore, opts Opts) (*Manager, error) {
+	var q queries
+	if err := dbutil.ScanSQLFile("queries.sql", &q, opts.DB, efs); err != nil {
+		return nil, err
+	}
+	return &Manager{
+		q:             q,
+		db:            opts.DB,
+		lo:            opts.Lo,
+		i18n:          opts.I18n,
+		mediaStore:    media,
+		activityStore: activity,
+		systemUserID:  opts.SystemUserID,
+	}, nil
+}
+
+// GetAll retrieves all retention policies.
+func (m *Manager) GetAll() ([]models.Policy, error) {
+	var policies = make([]models.Policy, 0)
+	if err := m.q.GetAllPolicies.Select(&policies); err != nil {
+		m.lo.Error("error fetching retention policies", "error", err)
+		return nil, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return policies, nil
+}
+
+// Get retrieves the retention policy of an inbox.
+func (m *Manager) Get(inboxID int) (models.Policy, error) {
+	var policy models.Policy
+	if err := m.q.GetPolicy.Get(&policy, inboxID); err != nil {
+		if err == sql.ErrNoRows {
+			return policy, envelope.NewError(envelope.NotFoundError, m.i18n.T("globals.messages.notFound"), nil)
+		}
+		m.lo.Error("error fetching retention policy", "inbox_id", inboxID, "error", err)
+		return policy, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return policy, nil
+}
+
+// Upsert creates or updates the retention policy of an inbox.
+func (m *Manager) Upsert(inboxID int, policy models.Policy) (models.Policy, error) {
+	if policy.RetentionDays < 1 || policy.RetentionDays > 36500 {
+		return models.Policy{}, envelope.NewError(envelope.InputError, m.i18n.Ts("validation.minmaxNumber", "min", "1", "max", "36500"), nil)
+	}
+	if !slices.Contains([]string{models.ActionAnonymize, models.ActionDelete}, policy.Action) {
+		return models.Policy{}, envelope.NewError(envelope.InputError, m.i18n.T("validation.invalidValue"), nil)
+	}
+	if _, err := m.q.UpsertPolicy.Exec(inboxID, policy.RetentionDays, policy.Action, policy.Enabled); err != nil {
+		if dbutil.IsForeignKeyError(err) {
+			return models.Policy{}, envelope.NewError(envelope.InputError, m.i18n.T("validation.notFoundInbox"), nil)
+		}
+		m.lo.Error("error upserting retention policy", "inbox_id", inboxID, "error", err)
+		return models.Policy{}, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return m.Get(inboxID)
+}
+
+// Delete deletes the retention policy of an inbox.
+func (m *Manager) Delete(inboxID int) error {
+	if _, err := m.q.DeletePolicy.Exec(inboxID); err != nil {
+		m.lo.Error("error deleting retention policy", "inbox_id", inboxID, "error", err)
+		return envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return nil
+}
+
+// DryRun reports the conversations, messages and media the retention policy of an inbox would purge right now, without purging anything.
+func (m *Manager) DryRun(inboxID int) (models.Report, error) {
+	policy, err := m.Get(inboxID)
+	if err != nil {
+		return models.Report{}, err
+	}
+
+	var report = models.Report{
+		Policy:        policy,
+		Cutoff:        policy.Cutoff(time.Now()),
+		Conversations: make([]models.Conversation, 0),
+	}
+	if err := m.q.GetExpiredSummary.Get(&report.Summary, policy.InboxID, report.Cutoff, policy.Action); err != nil {
+		m.lo.Error("error fetching retention summary", "inbox_id", inboxID, "error", err)
+		return models.Report{}, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	if err := m.q.GetExpiredConversations.Select(&report.Conversations, policy.InboxID, report.Cutoff, policy.Action, dryRunLimit); err != nil {
+		m.lo.Error("error fetching expired conversations", "inbox_id", inboxID, "error", err)
+		return models.Report{}, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return report, nil
+}
+
+// Run is a blocking function that periodically enforces all enabled retention policies.
+func (m *Manager) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.enforcePolicies(ctx)
+		}
+	}
+}
+
+// enforcePolicies purges expired conversations for every enabled retention policy.
+func (m *Manager) enforcePolicies(ctx context.Context) {
+	var policies []models.Policy
+	if err := m.q.GetEnabledPolicies.Select(&policies); err != nil {
+		m.lo.Error("error fetching enabled retention policies", "error", err)
+		return
+	}
+	for _, policy := range policies {
+		if ctx.Err() != nil {
+			return
+		}
+		purged := m.enforcePolicy(ctx, policy)
+		if purged == 0 {
+			continue
+		}
+		m.lo.Info("purged conversations as per retention policy", "inbox_id", policy.InboxID, "action", policy.Action, "count", purged)
+		if err := m.activityStore.ConversationsPurged(m.systemUserID, policy.InboxID, policy.InboxName, policy.Action, purged); err != nil {
+			m.lo.Error("error recording retention purge activity", "inbox_id", policy.InboxID, "error", err)
+		}
+	}
+}
+
+// enforcePolicy purges expired conversations of a single policy in batches and returns the number of conversations purged.
+func (m *Manager) enforcePolicy(ctx context.Context, policy models.Policy) int {
+	var (
+		cutoff = policy.Cutoff(time.Now())
+		purged int
+	)
+	for ctx.Err() == nil {
+		var conversations []models.Conversation
+		if err := m.q.GetExpiredConversations.Select(&conversations, policy.InboxID, cutoff, policy.Action, purgeBatchSize); err != nil {
+			m.lo.Error("error fetching expired conversations", "inbox_id", policy.InboxID, "error", err)
+			return purged
+		}
+
+		var batchPurged int
+		for _, c := range conversations {
+			if err := m.purgeConversation(c.ID, policy.Action); err != nil {
+				m.lo.Error("error purging conversation", "conversation_id", c.ID, "action", policy.Action, "error", err)
+				continue
+			}
+			batchPurged++
+		}
+		purged += batchPurged
+
+		// Stop on the last batch, or when nothing in the batch could be purged to avoid spinning on failures.
+		if len(conversations) < purgeBatchSize || batchPurged == 0 {
+			break
+		}
+	}
+	return purged
+}
+
+// purgeConversation anonymizes or deletes a conversation along with its media.
+func (m *Manager) purgeConversation(conversationID int, action string) error {
+	if action == models.ActionDelete {
+		if _, err := m.mediaStore.DeleteConversationMedia(conversationID); err != nil {
+			return err
+		}
+		_, err := m.q.DeleteConversation.Exec(conversationID)
+		return err
+	}
+	return m.AnonymizeConversation(conversationID)
+}
+
+// AnonymizeConversation deletes a conversation's media and redacts its message content, subject, custom attributes,
+// CSAT feedback and drafts. The conversation row itself is kept so that reports stay intact.
+func (m *Manager) AnonymizeConversation(conversationID int) error {
+	if _, err := m.mediaStore.DeleteConversationMedia(conversationID); err != nil {
+		return err
+	}
+
+	tx, err := m.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	redacted := m.i18n.T("retention.contentRedacted")
+	if _, err := tx.Stmtx(m.q.AnonymizeMessages).Exec(conversationID, redacted); err != nil {
+		return err
+	}
+	if _, err := tx.Stmtx(m.q.AnonymizeConversation).Exec(conversationID, redacted); err != nil {
+		return err
+	}
+	if _, err := tx.Stmtx(m.q.AnonymizeCSATFeedback).Exec(conversationID); err != nil {
+		return err
+	}
+	if _, err := tx.Stmtx(m.q.DeleteConversationDrafts).Exec(conversationID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
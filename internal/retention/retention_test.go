@@ -0,0 +1,90 @@
+package retention
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	"github.com/abhinavxd/libredesk/internal/retention/models"
+	"github.com/knadh/go-i18n"
+	"github.com/knadh/goyesql/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zerodha/logf"
+)
+
+type mockMediaStore struct {
+	deleted []int
+	err     error
+}
+
+func (m *mockMediaStore) DeleteConversationMedia(conversationID int) (int, error) {
+	m.deleted = append(m.deleted, conversationID)
+	return 0, m.err
+}
+
+func newTestManager(t *testing.T, media mediaStore) *Manager {
+	t.Helper()
+	tr, err := i18n.New([]byte(`{"_.code":"en","_.name":"English"}`))
+	require.NoError(t, err)
+	lo := logf.New(logf.Opts{})
+	return &Manager{lo: &lo, i18n: tr, mediaStore: media}
+}
+
+func TestPolicyCutoff(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), models.Policy{RetentionDays: 30}.Cutoff(now))
+	assert.Equal(t, now.AddDate(0, 0, -1), models.Policy{RetentionDays: 1}.Cutoff(now))
+}
+
+func TestUpsert_InvalidPolicy(t *testing.T) {
+	m := newTestManager(t, &mockMediaStore{})
+	tests := []struct {
+		name   string
+		policy models.Policy
+	}{
+		{name: "no retention days", policy: models.Policy{RetentionDays: 0, Action: models.ActionDelete}},
+		{name: "too many retention days", policy: models.Policy{RetentionDays: 36501, Action: models.ActionDelete}},
+		{name: "unknown action", policy: models.Policy{RetentionDays: 30, Action: "archive"}},
+		{name: "no action", policy: models.Policy{RetentionDays: 30}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := m.Upsert(1, tt.policy)
+			require.Error(t, err)
+			e, ok := err.(envelope.Error)
+			require.True(t, ok)
+			assert.Equal(t, envelope.InputError, e.ErrorType)
+		})
+	}
+}
+
+func TestPurgeConversation_KeepsConversationWhenMediaFails(t *testing.T) {
+	// The conversation is left in place when its media can't be deleted, so the next run retries it.
+	for _, action := range []string{models.ActionDelete, models.ActionAnonymize} {
+		t.Run(action, func(t *testing.T) {
+			media := &mockMediaStore{err: errors.New("storage unavailable")}
+			m := newTestManager(t, media)
+
+			err := m.purgeConversation(42, action)
+			assert.EqualError(t, err, "storage unavailable")
+			assert.Equal(t, []int{42}, media.deleted)
+		})
+	}
+}
+
+func TestQueries_ExpiredConversations(t *testing.T) {
+	b, err := efs.ReadFile("queries.sql")
+	require.NoError(t, err)
+	parsed, err := goyesql.ParseBytes(b)
+	require.NoError(t, err)
+
+	// Only resolved and closed conversations expire, and anonymized ones are left alone unless they're to be deleted.
+	for _, name := range []string{"get-expired-conversations", "get-expired-summary"} {
+		query, ok := parsed[name]
+		require.True(t, ok, name)
+		assert.Contains(t, query.Query, "s.category = 'resolved'", name)
+		assert.Contains(t, query.Query, "($3::TEXT = 'delete' OR c.meta->>'anonymized_at' IS NULL)", name)
+	}
+}
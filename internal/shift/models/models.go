@@ -0,0 +1,39 @@
+// Package models contains the data models for the shift package.
+package models
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/volatiletech/null/v9"
+)
+
+// Schedule is the work schedule of an agent, a weekly pattern of working hours plus time off in the agent's time zone.
+// Agents are set away at the end of their shift and back at its start, and are left out of auto assignment while off shift.
+type Schedule struct {
+	UserID    int       `db:"user_id" json:"user_id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	Enabled   bool      `db:"enabled" json:"enabled"`
+	Timezone  string    `db:"timezone" json:"timezone"`
+	// Hours are the working hours of each weekday in the business hours format.
+	Hours   types.JSONText `db:"hours" json:"hours"`
+	TimeOff types.JSONText `db:"time_off" json:"time_off"`
+	// ReassignAtShiftEnd sets the agent away and reassigning instead of away at the end of their shift,
+	// so conversations they have open go back to the team when the contact replies.
+	ReassignAtShiftEnd bool      `db:"reassign_at_shift_end" json:"reassign_at_shift_end"`
+	OnShift            null.Bool `db:"on_shift" json:"on_shift"`
+}
+
+// TimeOff is a period the agent does not work, whole days from From to To inclusive in "YYYY-MM-DD" format.
+type TimeOff struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Note string `json:"note,omitempty"`
+}
+
+// Includes reports whether the time off covers the date of t.
+func (o TimeOff) Includes(t time.Time) bool {
+	date := t.Format(time.DateOnly)
+	return date >= o.From && date <= o.To
+}
@@ -0,0 +1,46 @@
+-- name: get-schedule
+SELECT user_id, created_at, updated_at, enabled, timezone, hours, time_off, reassign_at_shift_end, on_shift
+FROM agent_schedules WHERE user_id = $1;
+
+-- name: get-enabled-schedules
+SELECT s.user_id, s.created_at, s.updated_at, s.enabled, s.timezone, s.hours, s.time_off, s.reassign_at_shift_end, s.on_shift
+FROM agent_schedules s
+JOIN users u ON u.id = s.user_id
+WHERE s.enabled = true AND u.deleted_at IS NULL AND u.enabled = true;
+
+-- name: upsert-schedule
+-- The shift is checked afresh on the next run after a change.
+INSERT INTO agent_schedules (user_id, enabled, timezone, hours, time_off, reassign_at_shift_end)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (user_id) DO UPDATE SET
+    enabled = EXCLUDED.enabled,
+    timezone = EXCLUDED.timezone,
+    hours = EXCLUDED.hours,
+    time_off = EXCLUDED.time_off,
+    reassign_at_shift_end = EXCLUDED.reassign_at_shift_end,
+    on_shift = NULL,
+    updated_at = NOW()
+RETURNING user_id, created_at, updated_at, enabled, timezone, hours, time_off, reassign_at_shift_end, on_shift;
+
+-- name: delete-schedule
+DELETE FROM agent_schedules WHERE user_id = $1;
+
+-- name: set-on-shift
+UPDATE agent_schedules SET on_shift = $2 WHERE user_id = $1;
+
+-- name: set-shift-end-availability
+-- Sets the agent away at the end of their shift unless they already are, marking the away status as set by the shift.
+UPDATE users SET availability_status = $2, away_set_by_shift = true
+WHERE id = $1 AND availability_status NOT IN ('away_manual', 'away_and_reassigning')
+RETURNING availability_status;
+
+-- name: set-shift-start-availability
+-- Brings the agent back at the start of their shift from the away status set at its end, online if they are active
+-- and offline otherwise. An away status the agent set themselves is kept. Also run when the schedule is deleted or disabled.
+UPDATE users SET availability_status = CASE
+        WHEN last_active_at > NOW() - INTERVAL '5 minutes' THEN 'online'::user_availability_status
+        ELSE 'offline'::user_availability_status
+    END,
+    away_set_by_shift = false
+WHERE id = $1 AND away_set_by_shift AND availability_status IN ('away_manual', 'away_and_reassigning')
+RETURNING availability_status;
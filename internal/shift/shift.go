@@ -0,0 +1,356 @@
+// Package shift handles agent work schedules, setting agents away when their shift ends and back when it starts.
+package shift
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	bhmodels "github.com/abhinavxd/libredesk/internal/business_hours/models"
+	"github.com/abhinavxd/libredesk/internal/dbutil"
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	"github.com/abhinavxd/libredesk/internal/shift/models"
+	"github.com/abhinavxd/libredesk/internal/sla"
+	umodels "github.com/abhinavxd/libredesk/internal/user/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/knadh/go-i18n"
+	"github.com/zerodha/logf"
+)
+
+var (
+	//go:embed queries.sql
+	efs embed.FS
+
+	// timeOfDayRe matches times in "HH:MM" format.
+	timeOfDayRe = regexp.MustCompile(`^(?:[01]\d|2[0-3]):[0-5]\d$`)
+
+	weekdays = map[string]bool{
+		"Monday": true, "Tuesday": true, "Wednesday": true, "Thursday": true, "Friday": true, "Saturday": true, "Sunday": true,
+	}
+)
+
+// Manager handles agent work schedules.
+type Manager struct {
+	q             queries
+	lo            *logf.Logger
+	i18n          *i18n.I18n
+	db            *sqlx.DB
+	scheduleStore scheduleStore
+}
+
+// scheduleStore reads the enabled schedules and records the shift state and shift availability changes of agents.
+// The availability setters return the new availability status, empty if the agent was left as they were.
+type scheduleStore interface {
+	GetEnabledSchedules() ([]models.Schedule, error)
+	SetShiftEndAvailability(userID int, awayStatus string) (string, error)
+	SetShiftStartAvailability(userID int) (string, error)
+	SetOnShift(userID int, onShift bool) error
+}
+
+// Opts contains options for initializing the Manager.
+type Opts struct {
+	DB   *sqlx.DB
+	Lo   *logf.Logger
+	I18n *i18n.I18n
+}
+
+// queries contains prepared SQL queries.
+type queries struct {
+	GetSchedule               *sqlx.Stmt `query:"get-schedule"`
+	GetEnabledSchedules       *sqlx.Stmt `query:"get-enabled-schedules"`
+	UpsertSchedule            *sqlx.Stmt `query:"upsert-schedule"`
+	DeleteSchedule            *sqlx.Stmt `query:"delete-schedule"`
+	SetOnShift                *sqlx.Stmt `query:"set-on-shift"`
+	SetShiftEndAvailability   *sqlx.Stmt `query:"set-shift-end-availability"`
+	SetShiftStartAvailability *sqlx.Stmt `query:"set-shift-start-availability"`
+}
+
+// New creates and returns a new instance of the Manager.
+func New(opts Opts) (*Manager, error) {
+	var q queries
+	if err := dbutil.ScanSQLFile("queries.sql", &q, opts.DB, efs); err != nil {
+		return nil, err
+	}
+	return &Manager{
+		q:             q,
+		lo:            opts.Lo,
+		i18n:          opts.I18n,
+		db:            opts.DB,
+		scheduleStore: &dbScheduleStore{q: &q},
+	}, nil
+}
+
+// Get returns the schedule of an agent.
+func (m *Manager) Get(userID int) (models.Schedule, error) {
+	var schedule models.Schedule
+	if err := m.q.GetSchedule.Get(&schedule, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return schedule, envelope.NewError(envelope.NotFoundError, m.i18n.T("globals.messages.notFound"), nil)
+		}
+		m.lo.Error("error fetching agent schedule", "user_id", userID, "error", err)
+		return schedule, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return schedule, nil
+}
+
+// Upsert creates or replaces the schedule of an agent. Disabling the schedule brings the agent back from an away status
+// set at the end of their shift, the restored availability status is returned, empty if it did not change.
+func (m *Manager) Upsert(userID int, schedule models.Schedule) (models.Schedule, string, error) {
+	if len(schedule.Hours) == 0 {
+		schedule.Hours = []byte("{}")
+	}
+	if len(schedule.TimeOff) == 0 {
+		schedule.TimeOff = []byte("[]")
+	}
+	if err := m.validate(schedule); err != nil {
+		return models.Schedule{}, "", err
+	}
+
+	tx, err := m.db.Beginx()
+	if err != nil {
+		m.lo.Error("error starting transaction", "error", err)
+		return models.Schedule{}, "", envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	defer tx.Rollback()
+
+	var saved models.Schedule
+	if err := tx.Stmtx(m.q.UpsertSchedule).Get(&saved, userID, schedule.Enabled, schedule.Timezone, schedule.Hours, schedule.TimeOff, schedule.ReassignAtShiftEnd); err != nil {
+		if dbutil.IsForeignKeyError(err) {
+			return saved, "", envelope.NewError(envelope.NotFoundError, m.i18n.T("globals.messages.notFound"), nil)
+		}
+		m.lo.Error("error saving agent schedule", "user_id", userID, "error", err)
+		return saved, "", envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	var status string
+	if !saved.Enabled {
+		if status, err = m.restoreAvailability(tx, userID); err != nil {
+			return models.Schedule{}, "", envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		m.lo.Error("error committing agent schedule", "user_id", userID, "error", err)
+		return models.Schedule{}, "", envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return saved, status, nil
+}
+
+// Delete deletes the schedule of an agent and brings them back from an away status set at the end of their shift,
+// the restored availability status is returned, empty if it did not change.
+func (m *Manager) Delete(userID int) (string, error) {
+	tx, err := m.db.Beginx()
+	if err != nil {
+		m.lo.Error("error starting transaction", "error", err)
+		return "", envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Stmtx(m.q.DeleteSchedule).Exec(userID); err != nil {
+		m.lo.Error("error deleting agent schedule", "user_id", userID, "error", err)
+		return "", envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	status, err := m.restoreAvailability(tx, userID)
+	if err != nil {
+		return "", envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	if err := tx.Commit(); err != nil {
+		m.lo.Error("error committing agent schedule deletion", "user_id", userID, "error", err)
+		return "", envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return status, nil
+}
+
+// restoreAvailability brings an agent back from an away status set at the end of their shift, as the start of a shift
+// does. Returns the new availability status, empty if the agent was not away because of their shift.
+func (m *Manager) restoreAvailability(tx *sqlx.Tx, userID int) (string, error) {
+	var status string
+	if err := tx.Stmtx(m.q.SetShiftStartAvailability).Get(&status, userID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		m.lo.Error("error restoring agent availability", "user_id", userID, "error", err)
+		return "", err
+	}
+	return status, nil
+}
+
+// validate checks the time zone, working hours and time off of a schedule.
+func (m *Manager) validate(schedule models.Schedule) error {
+	if _, err := time.LoadLocation(schedule.Timezone); err != nil || schedule.Timezone == "" {
+		return envelope.NewError(envelope.InputError, m.i18n.Ts("validation.invalidValue", "name", "`timezone`"), nil)
+	}
+	var timeOff []models.TimeOff
+	if err := json.Unmarshal(schedule.TimeOff, &timeOff); err != nil {
+		return envelope.NewError(envelope.InputError, m.i18n.Ts("validation.invalidValue", "name", "`time_off`"), nil)
+	}
+	for _, off := range timeOff {
+		from, err := time.Parse(time.DateOnly, off.From)
+		if err != nil {
+			return envelope.NewError(envelope.InputError, m.i18n.Ts("validation.invalidValue", "name", "`time_off`"), nil)
+		}
+		to, err := time.Parse(time.DateOnly, off.To)
+		if err != nil || to.Before(from) {
+			return envelope.NewError(envelope.InputError, m.i18n.Ts("validation.invalidValue", "name", "`time_off`"), nil)
+		}
+	}
+	if !validHours(schedule.Hours) {
+		return envelope.NewError(envelope.InputError, m.i18n.Ts("validation.invalidValue", "name", "`hours`"), nil)
+	}
+	return nil
+}
+
+// validHours reports whether working hours are keyed by weekday names with windows in "HH:MM" format that close after
+// they open and do not overlap.
+func validHours(b []byte) bool {
+	var hours map[string]bhmodels.WorkingHours
+	if err := json.Unmarshal(b, &hours); err != nil {
+		return false
+	}
+	for day, wh := range hours {
+		if !weekdays[day] {
+			return false
+		}
+		windows := slices.Clone(wh.Intervals())
+		slices.SortFunc(windows, func(a, b bhmodels.Shift) int {
+			return strings.Compare(a.Open, b.Open)
+		})
+		for i, w := range windows {
+			if !timeOfDayRe.MatchString(w.Open) || !timeOfDayRe.MatchString(w.Close) || w.Open >= w.Close {
+				return false
+			}
+			if i > 0 && w.Open < windows[i-1].Close {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IsOnShift reports whether t falls inside the working hours of the schedule and outside its time off, in the schedule's time zone.
+func IsOnShift(schedule models.Schedule, t time.Time) (bool, error) {
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return false, fmt.Errorf("invalid time zone %s: %v", schedule.Timezone, err)
+	}
+
+	if len(schedule.TimeOff) > 0 {
+		var timeOff []models.TimeOff
+		if err := json.Unmarshal(schedule.TimeOff, &timeOff); err != nil {
+			return false, fmt.Errorf("could not unmarshal time off: %v", err)
+		}
+		for _, off := range timeOff {
+			if off.Includes(t.In(loc)) {
+				return false, nil
+			}
+		}
+	}
+
+	hours := schedule.Hours
+	if len(hours) == 0 {
+		hours = []byte("{}")
+	}
+	return sla.IsWithinBusinessHours(t, bhmodels.BusinessHours{Hours: hours}, schedule.Timezone)
+}
+
+// Run checks the enabled schedules at the interval, updating the availability of agents whose shift started or ended.
+// onAvailabilityChange is called with the new availability status of every agent updated.
+func (m *Manager) Run(ctx context.Context, interval time.Duration, onAvailabilityChange func(userID int, status string)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.checkShifts(time.Now(), onAvailabilityChange)
+		}
+	}
+}
+
+// checkShifts updates the availability of agents whose shift started or ended since the last check.
+func (m *Manager) checkShifts(now time.Time, onAvailabilityChange func(userID int, status string)) {
+	schedules, err := m.scheduleStore.GetEnabledSchedules()
+	if err != nil {
+		m.lo.Error("error fetching agent schedules", "error", err)
+		return
+	}
+
+	for _, schedule := range schedules {
+		onShift, err := IsOnShift(schedule, now)
+		if err != nil {
+			m.lo.Error("error checking agent shift", "user_id", schedule.UserID, "error", err)
+			continue
+		}
+		if schedule.OnShift.Valid && schedule.OnShift.Bool == onShift {
+			continue
+		}
+
+		// Agents found on shift on the first check are left as they are.
+		var status string
+		switch {
+		case !onShift:
+			awayStatus := umodels.AwayManual
+			if schedule.ReassignAtShiftEnd {
+				awayStatus = umodels.AwayAndReassigning
+			}
+			status, err = m.scheduleStore.SetShiftEndAvailability(schedule.UserID, awayStatus)
+		case schedule.OnShift.Valid:
+			status, err = m.scheduleStore.SetShiftStartAvailability(schedule.UserID)
+		}
+		if err != nil {
+			m.lo.Error("error updating agent availability for shift", "user_id", schedule.UserID, "on_shift", onShift, "error", err)
+			continue
+		}
+
+		if err := m.scheduleStore.SetOnShift(schedule.UserID, onShift); err != nil {
+			m.lo.Error("error updating agent shift", "user_id", schedule.UserID, "error", err)
+			continue
+		}
+		if status != "" {
+			m.lo.Info("updated agent availability for shift", "user_id", schedule.UserID, "on_shift", onShift, "availability_status", status)
+			if onAvailabilityChange != nil {
+				onAvailabilityChange(schedule.UserID, status)
+			}
+		}
+	}
+}
+
+// dbScheduleStore reads and updates agent schedules and availability in the database.
+type dbScheduleStore struct {
+	q *queries
+}
+
+// GetEnabledSchedules returns the enabled schedules of enabled agents.
+func (s *dbScheduleStore) GetEnabledSchedules() ([]models.Schedule, error) {
+	var schedules []models.Schedule
+	err := s.q.GetEnabledSchedules.Select(&schedules)
+	return schedules, err
+}
+
+// SetShiftEndAvailability sets the agent away at the end of their shift unless they already are.
+func (s *dbScheduleStore) SetShiftEndAvailability(userID int, awayStatus string) (string, error) {
+	var status string
+	if err := s.q.SetShiftEndAvailability.Get(&status, userID, awayStatus); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+	return status, nil
+}
+
+// SetShiftStartAvailability brings the agent back from the away status set at the end of their shift.
+func (s *dbScheduleStore) SetShiftStartAvailability(userID int) (string, error) {
+	var status string
+	if err := s.q.SetShiftStartAvailability.Get(&status, userID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+	return status, nil
+}
+
+// SetOnShift records whether the agent was on shift at the last check.
+func (s *dbScheduleStore) SetOnShift(userID int, onShift bool) error {
+	_, err := s.q.SetOnShift.Exec(userID, onShift)
+	return err
+}
@@ -0,0 +1,180 @@
+package shift
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abhinavxd/libredesk/internal/shift/models"
+	umodels "github.com/abhinavxd/libredesk/internal/user/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/null/v9"
+	"github.com/zerodha/logf"
+)
+
+func TestIsOnShift(t *testing.T) {
+	schedule := models.Schedule{
+		Timezone: "Europe/Berlin",
+		Hours:    []byte(`{"Monday": {"shifts": [{"open": "09:00", "close": "13:00"}, {"open": "14:00", "close": "18:00"}]}, "Tuesday": {"open": "09:00", "close": "17:00"}}`),
+		TimeOff:  []byte(`[{"from": "2026-03-10", "to": "2026-03-11", "note": "Vacation"}]`),
+	}
+	loc, _ := time.LoadLocation("Europe/Berlin")
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want bool
+	}{
+		{"inside the morning shift", time.Date(2026, 3, 2, 10, 0, 0, 0, loc), true},
+		{"lunch break between shifts", time.Date(2026, 3, 2, 13, 30, 0, 0, loc), false},
+		{"after the last shift", time.Date(2026, 3, 2, 18, 0, 0, 0, loc), false},
+		{"day without working hours", time.Date(2026, 3, 4, 10, 0, 0, 0, loc), false},
+		{"time off", time.Date(2026, 3, 10, 10, 0, 0, 0, loc), false},
+		{"last day of time off", time.Date(2026, 3, 11, 10, 0, 0, 0, loc), false},
+		{"back from time off", time.Date(2026, 3, 16, 10, 0, 0, 0, loc), true},
+		// 08:30 UTC is 09:30 in Berlin.
+		{"other time zone", time.Date(2026, 3, 3, 8, 30, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IsOnShift(schedule, tt.t)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsOnShiftWithoutHours(t *testing.T) {
+	// A schedule without working hours is never on shift.
+	got, err := IsOnShift(models.Schedule{Timezone: "UTC"}, time.Now())
+	assert.NoError(t, err)
+	assert.False(t, got)
+
+	_, err = IsOnShift(models.Schedule{Timezone: "Nowhere/City"}, time.Now())
+	assert.Error(t, err)
+}
+
+func TestValidHours(t *testing.T) {
+	tests := []struct {
+		name  string
+		hours string
+		want  bool
+	}{
+		{"no hours", `{}`, true},
+		{"open and close", `{"Monday": {"open": "09:00", "close": "17:00"}}`, true},
+		{"shifts", `{"Monday": {"shifts": [{"open": "14:00", "close": "18:00"}, {"open": "09:00", "close": "13:00"}]}}`, true},
+		{"not an object", `[]`, false},
+		{"unknown day", `{"Funday": {"open": "09:00", "close": "17:00"}}`, false},
+		{"malformed time", `{"Monday": {"open": "9am", "close": "17:00"}}`, false},
+		{"closes before it opens", `{"Monday": {"open": "17:00", "close": "09:00"}}`, false},
+		{"day without times", `{"Monday": {}}`, false},
+		{"overlapping shifts", `{"Monday": {"shifts": [{"open": "09:00", "close": "13:00"}, {"open": "12:00", "close": "18:00"}]}}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, validHours([]byte(tt.hours)))
+		})
+	}
+}
+
+// fakeScheduleStore holds schedules and agent availability in memory, setting agents away and back as the queries do.
+type fakeScheduleStore struct {
+	schedules     map[int]*models.Schedule
+	status        map[int]string
+	awaySetByShift map[int]bool
+}
+
+func (f *fakeScheduleStore) GetEnabledSchedules() ([]models.Schedule, error) {
+	var out []models.Schedule
+	for _, schedule := range f.schedules {
+		out = append(out, *schedule)
+	}
+	return out, nil
+}
+
+func (f *fakeScheduleStore) SetShiftEndAvailability(userID int, awayStatus string) (string, error) {
+	if f.status[userID] == umodels.AwayManual || f.status[userID] == umodels.AwayAndReassigning {
+		return "", nil
+	}
+	f.status[userID] = awayStatus
+	f.awaySetByShift[userID] = true
+	return awayStatus, nil
+}
+
+func (f *fakeScheduleStore) SetShiftStartAvailability(userID int) (string, error) {
+	if !f.awaySetByShift[userID] {
+		return "", nil
+	}
+	f.status[userID] = umodels.Online
+	f.awaySetByShift[userID] = false
+	return umodels.Online, nil
+}
+
+func (f *fakeScheduleStore) SetOnShift(userID int, onShift bool) error {
+	f.schedules[userID].OnShift = null.BoolFrom(onShift)
+	return nil
+}
+
+func TestCheckShifts(t *testing.T) {
+	var (
+		lo    = logf.New(logf.Opts{})
+		hours = []byte(`{"Monday": {"open": "09:00", "close": "17:00"}}`)
+		store = &fakeScheduleStore{
+			schedules: map[int]*models.Schedule{
+				1: {UserID: 1, Timezone: "UTC", Hours: hours},
+				2: {UserID: 2, Timezone: "UTC", Hours: hours, ReassignAtShiftEnd: true},
+				3: {UserID: 3, Timezone: "UTC", Hours: hours},
+			},
+			status:        map[int]string{1: umodels.Online, 2: umodels.Online, 3: umodels.AwayManual},
+			awaySetByShift: make(map[int]bool),
+		}
+		m       = &Manager{lo: &lo, scheduleStore: store}
+		changes map[int]string
+		check   = func(now time.Time) {
+			changes = make(map[int]string)
+			m.checkShifts(now, func(userID int, status string) {
+				changes[userID] = status
+			})
+		}
+		monday = func(hour int) time.Time { return time.Date(2026, 3, 2, hour, 0, 0, 0, time.UTC) }
+	)
+
+	// Agents found on shift on the first check are left as they are.
+	check(monday(10))
+	assert.Empty(t, changes)
+	assert.Equal(t, null.BoolFrom(true), store.schedules[1].OnShift)
+
+	// Nothing changes until the shift ends.
+	check(monday(12))
+	assert.Empty(t, changes)
+
+	// At the end of the shift agents are set away, and away and reassigning if the schedule says so.
+	// The agent who was already away stays as they set themselves.
+	check(monday(17))
+	assert.Equal(t, map[int]string{1: umodels.AwayManual, 2: umodels.AwayAndReassigning}, changes)
+	assert.Equal(t, umodels.AwayManual, store.status[3])
+
+	// At the start of the next shift agents set away by the shift come back, the agent away on their own stays away.
+	check(monday(17).AddDate(0, 0, 7).Add(-8 * time.Hour))
+	assert.Equal(t, map[int]string{1: umodels.Online, 2: umodels.Online}, changes)
+	assert.Equal(t, umodels.AwayManual, store.status[3])
+}
+
+func TestCheckShiftsFirstCheckOffShift(t *testing.T) {
+	var (
+		lo    = logf.New(logf.Opts{})
+		store = &fakeScheduleStore{
+			schedules:     map[int]*models.Schedule{1: {UserID: 1, Timezone: "UTC", Hours: []byte(`{"Monday": {"open": "09:00", "close": "17:00"}}`)}},
+			status:        map[int]string{1: umodels.Online},
+			awaySetByShift: make(map[int]bool),
+		}
+		m       = &Manager{lo: &lo, scheduleStore: store}
+		changes = make(map[int]string)
+	)
+
+	// Agents found off shift on the first check are set away.
+	m.checkShifts(time.Date(2026, 3, 2, 20, 0, 0, 0, time.UTC), func(userID int, status string) {
+		changes[userID] = status
+	})
+	assert.Equal(t, map[int]string{1: umodels.AwayManual}, changes)
+	assert.Equal(t, null.BoolFrom(false), store.schedules[1].OnShift)
+}
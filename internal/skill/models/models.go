@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Proficiency bounds of an agent in a skill.
+const (
+	MinProficiency = 1
+	MaxProficiency = 5
+)
+
+// Skill is a capability such as a language or product area that conversations can require of the agents they are assigned to.
+type Skill struct {
+	ID          int       `db:"id" json:"id"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+}
+
+// AgentSkill is a skill of an agent along with their proficiency in it.
+type AgentSkill struct {
+	SkillID     int    `db:"skill_id" json:"skill_id"`
+	Name        string `db:"name" json:"name"`
+	Proficiency int    `db:"proficiency" json:"proficiency"`
+}
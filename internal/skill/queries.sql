@@ -0,0 +1,32 @@
+-- name: get-all-skills
+SELECT id, created_at, updated_at, "name", description FROM skills ORDER BY "name";
+
+-- name: get-skill
+SELECT id, created_at, updated_at, "name", description FROM skills WHERE id = $1;
+
+-- name: insert-skill
+INSERT INTO skills ("name", description) VALUES ($1, $2) RETURNING *;
+
+-- name: update-skill
+UPDATE skills SET "name" = $2, description = $3, updated_at = NOW() WHERE id = $1 RETURNING *;
+
+-- name: delete-skill
+DELETE FROM skills WHERE id = $1;
+
+-- name: get-agent-skills
+SELECT us.skill_id, s."name", us.proficiency
+FROM user_skills us
+JOIN skills s ON s.id = us.skill_id
+WHERE us.user_id = $1
+ORDER BY s."name";
+
+-- name: set-agent-skills
+-- Replaces the skills of an agent with the skill IDs in $2 at the proficiencies in $3.
+WITH delete_old_skills AS (
+    DELETE FROM user_skills
+    WHERE user_id = $1 AND skill_id <> ALL($2::INT[])
+)
+INSERT INTO user_skills (user_id, skill_id, proficiency)
+SELECT $1, skill_id, proficiency
+FROM unnest($2::INT[], $3::INT[]) AS s(skill_id, proficiency)
+ON CONFLICT (user_id, skill_id) DO UPDATE SET proficiency = EXCLUDED.proficiency;
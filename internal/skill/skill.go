@@ -0,0 +1,156 @@
+// Package skill handles the management of skills and the skills of agents used for skills based routing.
+// Conversations require skills through the require_skill automation action, so tags, custom attributes and the inbox
+// can all be skill sources.
+package skill
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+
+	"github.com/abhinavxd/libredesk/internal/dbutil"
+	"github.com/abhinavxd/libredesk/internal/envelope"
+	"github.com/abhinavxd/libredesk/internal/skill/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/knadh/go-i18n"
+	"github.com/lib/pq"
+	"github.com/zerodha/logf"
+)
+
+var (
+	//go:embed queries.sql
+	efs embed.FS
+)
+
+type Manager struct {
+	q    queries
+	lo   *logf.Logger
+	i18n *i18n.I18n
+}
+
+// Opts contains options for initializing the Manager.
+type Opts struct {
+	DB   *sqlx.DB
+	Lo   *logf.Logger
+	I18n *i18n.I18n
+}
+
+// queries contains prepared SQL queries.
+type queries struct {
+	GetAllSkills   *sqlx.Stmt `query:"get-all-skills"`
+	GetSkill       *sqlx.Stmt `query:"get-skill"`
+	InsertSkill    *sqlx.Stmt `query:"insert-skill"`
+	UpdateSkill    *sqlx.Stmt `query:"update-skill"`
+	DeleteSkill    *sqlx.Stmt `query:"delete-skill"`
+	GetAgentSkills *sqlx.Stmt `query:"get-agent-skills"`
+	SetAgentSkills *sqlx.Stmt `query:"set-agent-skills"`
+}
+
+// New creates and returns a new instance of the Manager.
+func New(opts Opts) (*Manager, error) {
+	var q queries
+
+	if err := dbutil.ScanSQLFile("queries.sql", &q, opts.DB, efs); err != nil {
+		return nil, err
+	}
+
+	return &Manager{
+		q:    q,
+		lo:   opts.Lo,
+		i18n: opts.I18n,
+	}, nil
+}
+
+// GetAll retrieves all skills.
+func (m *Manager) GetAll() ([]models.Skill, error) {
+	var skills = make([]models.Skill, 0)
+	if err := m.q.GetAllSkills.Select(&skills); err != nil {
+		m.lo.Error("error fetching skills", "error", err)
+		return nil, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return skills, nil
+}
+
+// Get retrieves a skill by ID.
+func (m *Manager) Get(id int) (models.Skill, error) {
+	var skill models.Skill
+	if err := m.q.GetSkill.Get(&skill, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return skill, envelope.NewError(envelope.NotFoundError, m.i18n.T("globals.messages.notFound"), nil)
+		}
+		m.lo.Error("error fetching skill", "id", id, "error", err)
+		return skill, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return skill, nil
+}
+
+// Create creates a new skill.
+func (m *Manager) Create(name, description string) (models.Skill, error) {
+	var skill models.Skill
+	if err := m.q.InsertSkill.Get(&skill, name, description); err != nil {
+		if dbutil.IsUniqueViolationError(err) {
+			return skill, envelope.NewError(envelope.ConflictError, m.i18n.T("errors.alreadyExistsSkill"), nil)
+		}
+		m.lo.Error("error inserting skill", "error", err)
+		return skill, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return skill, nil
+}
+
+// Update updates a skill by ID.
+func (m *Manager) Update(id int, name, description string) (models.Skill, error) {
+	var skill models.Skill
+	if err := m.q.UpdateSkill.Get(&skill, id, name, description); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return skill, envelope.NewError(envelope.NotFoundError, m.i18n.T("globals.messages.notFound"), nil)
+		}
+		if dbutil.IsUniqueViolationError(err) {
+			return skill, envelope.NewError(envelope.ConflictError, m.i18n.T("errors.alreadyExistsSkill"), nil)
+		}
+		m.lo.Error("error updating skill", "id", id, "error", err)
+		return skill, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return skill, nil
+}
+
+// Delete deletes a skill by ID, removing it from agents and conversations.
+func (m *Manager) Delete(id int) error {
+	if _, err := m.q.DeleteSkill.Exec(id); err != nil {
+		m.lo.Error("error deleting skill", "id", id, "error", err)
+		return envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return nil
+}
+
+// GetAgentSkills retrieves the skills of an agent.
+func (m *Manager) GetAgentSkills(userID int) ([]models.AgentSkill, error) {
+	var skills = make([]models.AgentSkill, 0)
+	if err := m.q.GetAgentSkills.Select(&skills, userID); err != nil {
+		m.lo.Error("error fetching agent skills", "user_id", userID, "error", err)
+		return nil, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return skills, nil
+}
+
+// SetAgentSkills replaces the skills of an agent.
+func (m *Manager) SetAgentSkills(userID int, skills []models.AgentSkill) error {
+	var (
+		skillIDs      = make([]int64, 0, len(skills))
+		proficiencies = make([]int64, 0, len(skills))
+	)
+	for _, s := range skills {
+		if s.Proficiency < models.MinProficiency || s.Proficiency > models.MaxProficiency {
+			return envelope.NewError(envelope.InputError, m.i18n.Ts("validation.invalidValue", "name", "`proficiency`"), nil)
+		}
+		skillIDs = append(skillIDs, int64(s.SkillID))
+		proficiencies = append(proficiencies, int64(s.Proficiency))
+	}
+	if _, err := m.q.SetAgentSkills.Exec(userID, pq.Int64Array(skillIDs), pq.Int64Array(proficiencies)); err != nil {
+		if dbutil.IsForeignKeyError(err) {
+			return envelope.NewError(envelope.InputError, m.i18n.Ts("validation.invalidValue", "name", "`skill_id`"), nil)
+		}
+		m.lo.Error("error setting agent skills", "user_id", userID, "error", err)
+		return envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	return nil
+}
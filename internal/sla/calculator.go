@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"slices"
 	"time"
 
 	"github.com/abhinavxd/libredesk/internal/business_hours/models"
@@ -52,12 +53,6 @@ func (m *Manager) CalculateDeadline(start time.Time, slaMinutes int, businessHou
 		}
 	}
 
-	// Create a map of holidays.
-	holidaysMap := make(map[string]struct{})
-	for _, holiday := range holidays {
-		holidaysMap[holiday.Date] = struct{}{}
-	}
-
 	iterations := 0
 	for remainingMinutes > 0 {
 		iterations++
@@ -65,61 +60,238 @@ func (m *Manager) CalculateDeadline(start time.Time, slaMinutes int, businessHou
 			return time.Time{}, ErrMaxIterations
 		}
 
-		// Move to next day if current day is a holiday.
-		dateStr := currentTime.Format(time.DateOnly)
-		if _, isHoliday := holidaysMap[dateStr]; isHoliday {
-			currentTime = nextDay(currentTime, loc)
-			continue
+		// Get the working intervals of the current day, none on holidays and non working days.
+		intervals, err := workingIntervals(currentTime, workingHours, holidays, loc)
+		if err != nil {
+			return time.Time{}, err
 		}
 
-		// Get working hours for the current day.
-		dayOfWeek := currentTime.Weekday().String()
-		workHours, exists := workingHours[dayOfWeek]
+		// Deduct minutes worked in each interval from remaining SLA time.
+		for _, interval := range intervals {
+			startOfWork, endOfWork := interval[0], interval[1]
 
-		// Not a working day, move to next day.
-		if !exists {
-			currentTime = nextDay(currentTime, loc)
-			continue
+			// Adjust to start of work if current time is before it.
+			if currentTime.Before(startOfWork) {
+				currentTime = startOfWork
+			}
+
+			// Skip the interval if current time is after it.
+			if currentTime.After(endOfWork) {
+				continue
+			}
+
+			workMinutesLeft := int(endOfWork.Sub(currentTime).Minutes())
+			if workMinutesLeft >= remainingMinutes {
+				return currentTime.Add(time.Duration(remainingMinutes) * time.Minute), nil
+			}
+			remainingMinutes -= workMinutesLeft
+			currentTime = endOfWork
 		}
 
-		// Parse open and close times for the current day in the specified time zone.
-		var startOfWork, endOfWork time.Time
-		var err error
-		startOfWork, err = parseTime(currentTime, workHours.Open, loc)
+		currentTime = nextDay(currentTime, loc)
+	}
+
+	return currentTime, nil
+}
+
+// IsWithinBusinessHours reports whether t falls inside the working hours of the business hours in the given time zone.
+func IsWithinBusinessHours(t time.Time, businessHours models.BusinessHours, timeZone string) (bool, error) {
+	if businessHours.IsAlwaysOpen {
+		return true, nil
+	}
+
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return false, fmt.Errorf("invalid time zone %s: %v", timeZone, err)
+	}
+	current := t.In(loc)
+
+	var holidays = []models.Holiday{}
+	if len(businessHours.Holidays) > 0 {
+		if err := json.Unmarshal(businessHours.Holidays, &holidays); err != nil {
+			return false, fmt.Errorf("could not unmarshal holidays: %v", err)
+		}
+	}
+	var workingHours map[string]models.WorkingHours
+	if err := json.Unmarshal(businessHours.Hours, &workingHours); err != nil {
+		return false, fmt.Errorf("could not unmarshal working hours: %v", err)
+	}
+
+	intervals, err := workingIntervals(current, workingHours, holidays, loc)
+	if err != nil {
+		return false, err
+	}
+	for _, interval := range intervals {
+		if !current.Before(interval[0]) && current.Before(interval[1]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// NextOpenTime returns the time business next opens at or after t in the given time zone, t itself when it falls inside working hours.
+// A zero time is returned when business does not open within the next year, e.g. no working hours are configured.
+func NextOpenTime(t time.Time, businessHours models.BusinessHours, timeZone string) (time.Time, error) {
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time zone %s: %v", timeZone, err)
+	}
+	current := t.In(loc)
+	if businessHours.IsAlwaysOpen {
+		return current, nil
+	}
+
+	var workingHours map[string]models.WorkingHours
+	if err := json.Unmarshal(businessHours.Hours, &workingHours); err != nil {
+		return time.Time{}, fmt.Errorf("could not unmarshal working hours: %v", err)
+	}
+	var holidays = []models.Holiday{}
+	if len(businessHours.Holidays) > 0 {
+		if err := json.Unmarshal(businessHours.Holidays, &holidays); err != nil {
+			return time.Time{}, fmt.Errorf("could not unmarshal holidays: %v", err)
+		}
+	}
+
+	day := current
+	for range 366 {
+		intervals, err := workingIntervals(day, workingHours, holidays, loc)
 		if err != nil {
-			return time.Time{}, fmt.Errorf("invalid open time %s for %s: %v", workHours.Open, dayOfWeek, err)
+			return time.Time{}, err
+		}
+		for _, interval := range intervals {
+			if current.Before(interval[0]) {
+				return interval[0], nil
+			}
+			if current.Before(interval[1]) {
+				return current, nil
+			}
+		}
+		day = nextDay(day, loc)
+	}
+	return time.Time{}, nil
+}
+
+// BusinessMinutesBetween returns the number of working minutes between start and end
+// considering the provided holidays, working hours, and time zone.
+func BusinessMinutesBetween(start, end time.Time, businessHours models.BusinessHours, timeZone string) (int, error) {
+	if !end.After(start) {
+		return 0, nil
+	}
+	if businessHours.IsAlwaysOpen {
+		return int(end.Sub(start).Minutes()), nil
+	}
+
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time zone %s: %v", timeZone, err)
+	}
+
+	var workingHours map[string]models.WorkingHours
+	if err := json.Unmarshal(businessHours.Hours, &workingHours); err != nil {
+		return 0, fmt.Errorf("could not unmarshal working hours: %v", err)
+	}
+	var holidays = []models.Holiday{}
+	if len(businessHours.Holidays) > 0 {
+		if err := json.Unmarshal(businessHours.Holidays, &holidays); err != nil {
+			return 0, fmt.Errorf("could not unmarshal holidays: %v", err)
 		}
-		endOfWork, err = parseTime(currentTime, workHours.Close, loc)
+	}
+	var (
+		current = start.In(loc)
+		endLoc  = end.In(loc)
+		total   time.Duration
+	)
+	for day := time.Date(current.Year(), current.Month(), current.Day(), 0, 0, 0, 0, loc); day.Before(endLoc); day = nextDay(day, loc) {
+		intervals, err := workingIntervals(day, workingHours, holidays, loc)
 		if err != nil {
-			return time.Time{}, fmt.Errorf("invalid close time %s for %s: %v", workHours.Close, dayOfWeek, err)
+			return 0, err
 		}
 
-		// Adjust to start of work if current time is before it.
-		if currentTime.Before(startOfWork) {
-			currentTime = startOfWork
+		// Count the part of the working intervals that falls between start and end.
+		for _, interval := range intervals {
+			from, to := interval[0], interval[1]
+			if current.After(from) {
+				from = current
+			}
+			if endLoc.Before(to) {
+				to = endLoc
+			}
+			if to.After(from) {
+				total += to.Sub(from)
+			}
 		}
+	}
+	return int(total.Minutes()), nil
+}
 
-		// Move to next day if current time is after end of work.
-		if currentTime.After(endOfWork) {
-			currentTime = nextDay(startOfWork, loc)
-			continue
+// workingIntervals returns the working hours of the day of t as open and close time pairs in the specified time zone,
+// one per shift in order, excluding holiday closures. A day without working hours or closed for a whole holiday has none.
+func workingIntervals(t time.Time, workingHours map[string]models.WorkingHours, holidays []models.Holiday, loc *time.Location) ([][2]time.Time, error) {
+	dayOfWeek := t.Weekday().String()
+	workHours, exists := workingHours[dayOfWeek]
+	if !exists {
+		return nil, nil
+	}
+
+	var intervals [][2]time.Time
+	for _, shift := range workHours.Intervals() {
+		startOfWork, err := parseTime(t, shift.Open, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid open time %s for %s: %v", shift.Open, dayOfWeek, err)
+		}
+		endOfWork, err := parseTime(t, shift.Close, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid close time %s for %s: %v", shift.Close, dayOfWeek, err)
 		}
+		intervals = append(intervals, [2]time.Time{startOfWork, endOfWork})
+	}
+	slices.SortFunc(intervals, func(a, b [2]time.Time) int {
+		return a[0].Compare(b[0])
+	})
 
-		// Deduct minutes worked today from remaining SLA time.
-		workMinutesLeft := int(endOfWork.Sub(currentTime).Minutes())
-		if workMinutesLeft >= remainingMinutes {
-			return currentTime.Add(time.Duration(remainingMinutes) * time.Minute), nil
+	for _, holiday := range holidays {
+		if !holiday.OccursOn(t) {
+			continue
+		}
+		if !holiday.IsPartial() {
+			return nil, nil
+		}
+		closedFrom, err := parseTime(t, holiday.From, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday start time %s for %s: %v", holiday.From, holiday.Date, err)
+		}
+		closedTo, err := parseTime(t, holiday.To, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday end time %s for %s: %v", holiday.To, holiday.Date, err)
 		}
 
-		remainingMinutes -= workMinutesLeft
-		if remainingMinutes == 0 {
-			return endOfWork, nil
+		// Cut the closure out of the intervals, keeping what is left before and after it.
+		var open [][2]time.Time
+		for _, interval := range intervals {
+			if closedFrom.After(interval[0]) {
+				open = append(open, [2]time.Time{interval[0], minTime(interval[1], closedFrom)})
+			}
+			if closedTo.Before(interval[1]) {
+				open = append(open, [2]time.Time{maxTime(interval[0], closedTo), interval[1]})
+			}
 		}
+		intervals = open
+	}
+	return intervals, nil
+}
 
-		currentTime = nextDay(startOfWork, loc)
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
 	}
+	return b
+}
 
-	return currentTime, nil
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
 }
 
 // nextDay advances the time to the start of the next day in the specified time zone.
@@ -511,3 +511,166 @@ func TestCalculateDeadline(t *testing.T) {
 		})
 	}
 }
+
+func TestIsWithinBusinessHours(t *testing.T) {
+	businessHours := models.BusinessHours{
+		Holidays: mustMarshalJSON([]models.Holiday{{Date: "2023-10-11"}}),
+		Hours: mustMarshalJSON(map[string]models.WorkingHours{
+			"Tuesday":   {Open: "09:00", Close: "17:00"},
+			"Wednesday": {Open: "09:00", Close: "17:00"},
+		}),
+	}
+	locIST, _ := time.LoadLocation("Asia/Kolkata")
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		timeZone string
+		expected bool
+	}{
+		{name: "Inside working hours", at: time.Date(2023, 10, 10, 10, 0, 0, 0, time.UTC), timeZone: "UTC", expected: true},
+		{name: "At opening time", at: time.Date(2023, 10, 10, 9, 0, 0, 0, time.UTC), timeZone: "UTC", expected: true},
+		{name: "At closing time", at: time.Date(2023, 10, 10, 17, 0, 0, 0, time.UTC), timeZone: "UTC", expected: false},
+		{name: "Holiday", at: time.Date(2023, 10, 11, 10, 0, 0, 0, time.UTC), timeZone: "UTC", expected: false},
+		{name: "Non working day", at: time.Date(2023, 10, 12, 10, 0, 0, 0, time.UTC), timeZone: "UTC", expected: false},
+		{name: "Converted to business time zone", at: time.Date(2023, 10, 10, 4, 0, 0, 0, time.UTC), timeZone: locIST.String(), expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			within, err := IsWithinBusinessHours(tt.at, businessHours, tt.timeZone)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, within)
+		})
+	}
+
+	within, err := IsWithinBusinessHours(time.Now(), models.BusinessHours{IsAlwaysOpen: true}, "UTC")
+	assert.NoError(t, err)
+	assert.True(t, within)
+}
+
+func TestBusinessMinutesBetween(t *testing.T) {
+	businessHours := models.BusinessHours{
+		Holidays: mustMarshalJSON([]models.Holiday{{Date: "2023-10-11"}}),
+		Hours: mustMarshalJSON(map[string]models.WorkingHours{
+			"Tuesday":   {Open: "09:00", Close: "17:00"},
+			"Wednesday": {Open: "09:00", Close: "17:00"},
+			"Thursday":  {Open: "09:00", Close: "17:00"},
+		}),
+	}
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		expected   int
+	}{
+		{name: "Within a working day", start: time.Date(2023, 10, 10, 10, 0, 0, 0, time.UTC), end: time.Date(2023, 10, 10, 11, 30, 0, 0, time.UTC), expected: 90},
+		{name: "Outside working hours", start: time.Date(2023, 10, 10, 18, 0, 0, 0, time.UTC), end: time.Date(2023, 10, 10, 23, 0, 0, 0, time.UTC), expected: 0},
+		{name: "Across a holiday", start: time.Date(2023, 10, 10, 16, 0, 0, 0, time.UTC), end: time.Date(2023, 10, 12, 10, 0, 0, 0, time.UTC), expected: 120},
+		{name: "Across a non working day", start: time.Date(2023, 10, 12, 16, 0, 0, 0, time.UTC), end: time.Date(2023, 10, 17, 9, 30, 0, 0, time.UTC), expected: 90},
+		{name: "End before start", start: time.Date(2023, 10, 10, 11, 0, 0, 0, time.UTC), end: time.Date(2023, 10, 10, 10, 0, 0, 0, time.UTC), expected: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			minutes, err := BusinessMinutesBetween(tt.start, tt.end, businessHours, "UTC")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, minutes)
+		})
+	}
+
+	minutes, err := BusinessMinutesBetween(time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC), time.Date(2023, 10, 10, 2, 0, 0, 0, time.UTC), models.BusinessHours{IsAlwaysOpen: true}, "UTC")
+	assert.NoError(t, err)
+	assert.Equal(t, 120, minutes)
+}
+
+func TestPartialAndRecurringHolidays(t *testing.T) {
+	businessHours := models.BusinessHours{
+		Holidays: mustMarshalJSON([]models.Holiday{
+			{Name: "Half day", Date: "2023-10-10", From: "13:00", To: "17:00"},
+			{Name: "Lunch closure", Date: "2023-10-12", From: "12:00", To: "13:00"},
+			{Name: "Annual", Date: "2020-10-11", Recurring: true},
+		}),
+		Hours: mustMarshalJSON(map[string]models.WorkingHours{
+			"Tuesday":   {Open: "09:00", Close: "17:00"},
+			"Wednesday": {Open: "09:00", Close: "17:00"},
+			"Thursday":  {Open: "09:00", Close: "17:00"},
+		}),
+	}
+	m := &Manager{}
+
+	// Half day closes at 13:00, the recurring holiday skips Wednesday, the lunch closure is skipped on Thursday.
+	deadline, err := m.CalculateDeadline(time.Date(2023, 10, 10, 12, 0, 0, 0, time.UTC), 300, businessHours, "UTC")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 10, 12, 14, 0, 0, 0, time.UTC), deadline)
+
+	within, err := IsWithinBusinessHours(time.Date(2023, 10, 10, 14, 0, 0, 0, time.UTC), businessHours, "UTC")
+	assert.NoError(t, err)
+	assert.False(t, within)
+	within, err = IsWithinBusinessHours(time.Date(2023, 10, 12, 13, 0, 0, 0, time.UTC), businessHours, "UTC")
+	assert.NoError(t, err)
+	assert.True(t, within)
+
+	minutes, err := BusinessMinutesBetween(time.Date(2023, 10, 10, 9, 0, 0, 0, time.UTC), time.Date(2023, 10, 12, 17, 0, 0, 0, time.UTC), businessHours, "UTC")
+	assert.NoError(t, err)
+	assert.Equal(t, 4*60+7*60, minutes)
+}
+
+func TestSplitShifts(t *testing.T) {
+	businessHours := models.BusinessHours{
+		Hours: mustMarshalJSON(map[string]models.WorkingHours{
+			"Tuesday":   {Shifts: []models.Shift{{Open: "14:00", Close: "18:00"}, {Open: "09:00", Close: "13:00"}}},
+			"Wednesday": {Open: "09:00", Close: "17:00"},
+		}),
+	}
+	m := &Manager{}
+
+	// Two hours before the break and one after it, the shifts are used in order of their opening time.
+	deadline, err := m.CalculateDeadline(time.Date(2023, 10, 10, 11, 0, 0, 0, time.UTC), 180, businessHours, "UTC")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 10, 10, 15, 0, 0, 0, time.UTC), deadline)
+
+	// Rolls over from the second shift into the next day.
+	deadline, err = m.CalculateDeadline(time.Date(2023, 10, 10, 17, 0, 0, 0, time.UTC), 120, businessHours, "UTC")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 10, 11, 10, 0, 0, 0, time.UTC), deadline)
+
+	within, err := IsWithinBusinessHours(time.Date(2023, 10, 10, 13, 30, 0, 0, time.UTC), businessHours, "UTC")
+	assert.NoError(t, err)
+	assert.False(t, within)
+
+	minutes, err := BusinessMinutesBetween(time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC), time.Date(2023, 10, 11, 0, 0, 0, 0, time.UTC), businessHours, "UTC")
+	assert.NoError(t, err)
+	assert.Equal(t, 8*60, minutes)
+}
+
+func TestNextOpenTime(t *testing.T) {
+	businessHours := models.BusinessHours{
+		Hours: mustMarshalJSON(map[string]models.WorkingHours{
+			"Monday":  {Open: "09:00", Close: "17:00"},
+			"Tuesday": {Shifts: []models.Shift{{Open: "09:00", Close: "13:00"}, {Open: "14:00", Close: "18:00"}}},
+			"Friday":  {Open: "09:00", Close: "17:00"},
+		}),
+		Holidays: mustMarshalJSON([]models.Holiday{{Name: "Closed", Date: "2023-10-16"}}),
+	}
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected time.Time
+	}{
+		{"within working hours", time.Date(2023, 10, 13, 10, 0, 0, 0, time.UTC), time.Date(2023, 10, 13, 10, 0, 0, 0, time.UTC)},
+		{"before opening", time.Date(2023, 10, 13, 7, 0, 0, 0, time.UTC), time.Date(2023, 10, 13, 9, 0, 0, 0, time.UTC)},
+		{"over the weekend and a holiday", time.Date(2023, 10, 13, 18, 0, 0, 0, time.UTC), time.Date(2023, 10, 17, 9, 0, 0, 0, time.UTC)},
+		{"between shifts", time.Date(2023, 10, 17, 13, 30, 0, 0, time.UTC), time.Date(2023, 10, 17, 14, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, err := NextOpenTime(tt.at, businessHours, "UTC")
+			assert.NoError(t, err)
+			assert.True(t, tt.expected.Equal(next), "expected %s, got %s", tt.expected, next)
+		})
+	}
+
+	next, err := NextOpenTime(time.Date(2023, 10, 13, 18, 0, 0, 0, time.UTC), models.BusinessHours{Hours: mustMarshalJSON(map[string]models.WorkingHours{})}, "UTC")
+	assert.NoError(t, err)
+	assert.True(t, next.IsZero())
+}
@@ -0,0 +1,148 @@
+package sla
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/abhinavxd/libredesk/internal/sla/models"
+	umodels "github.com/abhinavxd/libredesk/internal/user/models"
+	"github.com/volatiletech/null/v9"
+)
+
+const (
+	EscalationTriggerBreach  = "breach"
+	EscalationTriggerPercent = "percent"
+)
+
+// SetConversationStore sets the conversation store used to run escalation actions.
+func (m *Manager) SetConversationStore(store conversationStore) {
+	m.conversationStore = store
+}
+
+// evaluateEscalations runs the escalations of unmet metrics that have reached their percentage of the deadline.
+func (m *Manager) evaluateEscalations() {
+	var candidates []models.SLAEscalationCandidate
+	if err := m.q.GetSLAEscalationCandidates.Select(&candidates); err != nil {
+		m.lo.Error("error fetching SLA escalation candidates", "error", err)
+		return
+	}
+
+	var (
+		now         = time.Now()
+		policyCache = make(map[int]models.SLAPolicy)
+	)
+	for _, c := range candidates {
+		policy, ok := policyCache[c.SLAPolicyID]
+		if !ok {
+			var err error
+			if policy, err = m.Get(c.SLAPolicyID); err != nil {
+				continue
+			}
+			policyCache[c.SLAPolicyID] = policy
+		}
+		for _, esc := range dueEscalations(policy, c, now) {
+			m.escalate(c.AppliedSLAID, c.SLAEventID, esc)
+		}
+	}
+}
+
+// dueEscalations returns the percentage escalations of the candidate's metric whose threshold has been reached at now.
+// A threshold is reached once the given percentage of the time from start to deadline has elapsed.
+func dueEscalations(policy models.SLAPolicy, c models.SLAEscalationCandidate, now time.Time) []models.SlaEscalation {
+	var due []models.SlaEscalation
+	for _, esc := range policy.Escalations {
+		if esc.Metric != c.Metric || esc.Trigger != EscalationTriggerPercent {
+			continue
+		}
+		threshold := c.StartAt.Add(c.DeadlineAt.Sub(c.StartAt) * time.Duration(esc.Percent) / 100)
+		if now.Before(threshold) {
+			continue
+		}
+		due = append(due, esc)
+	}
+	return due
+}
+
+// escalateBreach runs the breach escalations of a metric on an applied SLA.
+func (m *Manager) escalateBreach(appliedSLAID int, slaEventID null.Int, slaPolicyID int, metric string) {
+	policy, err := m.Get(slaPolicyID)
+	if err != nil {
+		return
+	}
+	for _, esc := range policy.Escalations {
+		if esc.Metric == metric && esc.Trigger == EscalationTriggerBreach {
+			m.escalate(appliedSLAID, slaEventID, esc)
+		}
+	}
+}
+
+// escalate runs the actions of an escalation on the conversation of an applied SLA and records it as an activity.
+// An escalation runs only once per applied SLA, or per event for next response. The claim is released when the
+// conversation cannot be fetched or an action fails, so the escalation is retried on the next evaluation.
+func (m *Manager) escalate(appliedSLAID int, slaEventID null.Int, esc models.SlaEscalation) {
+	if m.conversationStore == nil {
+		return
+	}
+
+	conversationUUID, err := m.escalationStore.Claim(appliedSLAID, slaEventID, esc)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			m.lo.Error("error claiming SLA escalation", "applied_sla_id", appliedSLAID, "escalation", esc.Key(), "error", err)
+		}
+		return
+	}
+	conversation, err := m.conversationStore.GetConversation(0, conversationUUID, "")
+	if err != nil {
+		m.lo.Error("error fetching conversation for SLA escalation", "applied_sla_id", appliedSLAID, "error", err)
+		m.releaseEscalation(appliedSLAID, slaEventID, esc)
+		return
+	}
+
+	m.lo.Info("running SLA escalation", "applied_sla_id", appliedSLAID, "conversation_uuid", conversation.UUID, "escalation", esc.Key())
+	for _, action := range esc.Actions {
+		if err := m.conversationStore.ApplyAction(action, conversation, umodels.User{}); err != nil {
+			m.lo.Error("error applying SLA escalation action", "conversation_uuid", conversation.UUID, "action", action.Type, "error", err)
+			m.releaseEscalation(appliedSLAID, slaEventID, esc)
+			return
+		}
+	}
+	if err := m.conversationStore.RecordSLAEscalation(conversation.UUID, escalationReason(esc)); err != nil {
+		m.lo.Error("error recording SLA escalation activity", "conversation_uuid", conversation.UUID, "error", err)
+	}
+}
+
+// releaseEscalation releases the claim of an escalation that failed to run.
+func (m *Manager) releaseEscalation(appliedSLAID int, slaEventID null.Int, esc models.SlaEscalation) {
+	if err := m.escalationStore.Release(appliedSLAID, slaEventID, esc); err != nil {
+		m.lo.Error("error releasing SLA escalation", "applied_sla_id", appliedSLAID, "escalation", esc.Key(), "error", err)
+	}
+}
+
+// dbEscalationStore claims escalations in applied_sla_escalations.
+type dbEscalationStore struct {
+	q *queries
+}
+
+// Claim records an escalation as run on an applied SLA and returns the UUID of its conversation, sql.ErrNoRows if the
+// escalation already ran.
+func (s *dbEscalationStore) Claim(appliedSLAID int, slaEventID null.Int, esc models.SlaEscalation) (string, error) {
+	var conversationUUID string
+	err := s.q.InsertAppliedSLAEscalation.QueryRow(appliedSLAID, slaEventID, esc.Metric, esc.Key()).Scan(&conversationUUID)
+	return conversationUUID, err
+}
+
+// Release deletes the claim of an escalation.
+func (s *dbEscalationStore) Release(appliedSLAID int, slaEventID null.Int, esc models.SlaEscalation) error {
+	_, err := s.q.DeleteAppliedSLAEscalation.Exec(appliedSLAID, slaEventID, esc.Key())
+	return err
+}
+
+// escalationReason describes why an escalation ran, e.g. "First response breached".
+func escalationReason(esc models.SlaEscalation) string {
+	label := metricLabels[esc.Metric]
+	if esc.Trigger == EscalationTriggerPercent {
+		return fmt.Sprintf("%s reached %d%% of the deadline", label, esc.Percent)
+	}
+	return fmt.Sprintf("%s breached", label)
+}
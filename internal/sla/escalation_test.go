@@ -0,0 +1,177 @@
+package sla
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	amodels "github.com/abhinavxd/libredesk/internal/automation/models"
+	cmodels "github.com/abhinavxd/libredesk/internal/conversation/models"
+	"github.com/abhinavxd/libredesk/internal/sla/models"
+	umodels "github.com/abhinavxd/libredesk/internal/user/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/null/v9"
+	"github.com/zerodha/logf"
+)
+
+// fakeConversationStore records the escalation actions applied to conversations.
+type fakeConversationStore struct {
+	actions  []string
+	reasons  []string
+	failures int
+}
+
+func (f *fakeConversationStore) GetConversation(id int, uuid, refNum string) (cmodels.Conversation, error) {
+	return cmodels.Conversation{UUID: uuid}, nil
+}
+
+func (f *fakeConversationStore) ApplyAction(action amodels.RuleAction, conversation cmodels.Conversation, user umodels.User) error {
+	if f.failures > 0 {
+		f.failures--
+		return errors.New("action failed")
+	}
+	f.actions = append(f.actions, conversation.UUID+":"+action.Type)
+	return nil
+}
+
+func (f *fakeConversationStore) RecordSLAEscalation(conversationUUID, reason string) error {
+	f.reasons = append(f.reasons, reason)
+	return nil
+}
+
+// fakeEscalationStore claims escalations in memory, as the unique index on applied_sla_escalations does.
+type fakeEscalationStore struct {
+	claimed map[string]bool
+}
+
+func escalationClaimKey(appliedSLAID int, slaEventID null.Int, esc models.SlaEscalation) string {
+	return fmt.Sprintf("%d:%d:%s", appliedSLAID, slaEventID.Int, esc.Key())
+}
+
+func (f *fakeEscalationStore) Claim(appliedSLAID int, slaEventID null.Int, esc models.SlaEscalation) (string, error) {
+	key := escalationClaimKey(appliedSLAID, slaEventID, esc)
+	if f.claimed[key] {
+		return "", sql.ErrNoRows
+	}
+	f.claimed[key] = true
+	return "conv-uuid", nil
+}
+
+func (f *fakeEscalationStore) Release(appliedSLAID int, slaEventID null.Int, esc models.SlaEscalation) error {
+	delete(f.claimed, escalationClaimKey(appliedSLAID, slaEventID, esc))
+	return nil
+}
+
+func newEscalationManager(store *fakeConversationStore) *Manager {
+	lo := logf.New(logf.Opts{})
+	return &Manager{
+		lo:                &lo,
+		conversationStore: store,
+		escalationStore:   &fakeEscalationStore{claimed: make(map[string]bool)},
+	}
+}
+
+func TestEscalationReason(t *testing.T) {
+	assert.Equal(t, "First response breached", escalationReason(models.SlaEscalation{Metric: MetricFirstResponse, Trigger: EscalationTriggerBreach}))
+	assert.Equal(t, "Resolution reached 75% of the deadline", escalationReason(models.SlaEscalation{Metric: MetricResolution, Trigger: EscalationTriggerPercent, Percent: 75}))
+}
+
+func TestEscalationKey(t *testing.T) {
+	breach := models.SlaEscalation{Metric: MetricNextResponse, Trigger: EscalationTriggerBreach}
+	percent := models.SlaEscalation{Metric: MetricNextResponse, Trigger: EscalationTriggerPercent, Percent: 50}
+	assert.NotEqual(t, breach.Key(), percent.Key())
+	assert.Equal(t, "next_response:percent:50", percent.Key())
+}
+
+func TestDueEscalations(t *testing.T) {
+	var (
+		start  = time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+		policy = models.SLAPolicy{Escalations: models.SlaEscalations{
+			{Metric: MetricResolution, Trigger: EscalationTriggerPercent, Percent: 50},
+			{Metric: MetricResolution, Trigger: EscalationTriggerPercent, Percent: 75},
+			{Metric: MetricResolution, Trigger: EscalationTriggerBreach},
+			{Metric: MetricFirstResponse, Trigger: EscalationTriggerPercent, Percent: 50},
+		}}
+		candidate = models.SLAEscalationCandidate{Metric: MetricResolution, StartAt: start, DeadlineAt: start.Add(4 * time.Hour)}
+	)
+
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		want    []int
+	}{
+		{"before the first threshold", 119 * time.Minute, nil},
+		{"at 50 percent", 2 * time.Hour, []int{50}},
+		{"between thresholds", 170 * time.Minute, []int{50}},
+		{"at 75 percent", 3 * time.Hour, []int{50, 75}},
+		{"past the deadline", 5 * time.Hour, []int{50, 75}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for _, esc := range dueEscalations(policy, candidate, start.Add(tt.elapsed)) {
+				assert.Equal(t, MetricResolution, esc.Metric)
+				got = append(got, esc.Percent)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEscalationRunsOnce(t *testing.T) {
+	var (
+		store = &fakeConversationStore{}
+		m     = newEscalationManager(store)
+		start = time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+		esc   = models.SlaEscalation{
+			Metric:  MetricFirstResponse,
+			Trigger: EscalationTriggerPercent,
+			Percent: 50,
+			Actions: []amodels.RuleAction{{Type: amodels.ActionSetPriority, Value: []string{"1"}}},
+		}
+		policy    = models.SLAPolicy{Escalations: models.SlaEscalations{esc}}
+		candidate = models.SLAEscalationCandidate{AppliedSLAID: 1, Metric: MetricFirstResponse, StartAt: start, DeadlineAt: start.Add(time.Hour)}
+	)
+
+	// Evaluations run every few minutes, the escalation keeps being due after its threshold.
+	for elapsed := time.Duration(0); elapsed <= 2*time.Hour; elapsed += 10 * time.Minute {
+		for _, due := range dueEscalations(policy, candidate, start.Add(elapsed)) {
+			m.escalate(candidate.AppliedSLAID, candidate.SLAEventID, due)
+		}
+		if elapsed < 30*time.Minute {
+			assert.Empty(t, store.actions, "escalated at %s, before the threshold", elapsed)
+		}
+	}
+	assert.Equal(t, []string{"conv-uuid:" + amodels.ActionSetPriority}, store.actions)
+	assert.Equal(t, []string{"First response reached 50% of the deadline"}, store.reasons)
+
+	// Another applied SLA, or another next response event, runs its own escalation.
+	m.escalate(2, null.Int{}, esc)
+	m.escalate(1, null.IntFrom(7), esc)
+	m.escalate(1, null.IntFrom(7), esc)
+	assert.Len(t, store.actions, 3)
+}
+
+func TestEscalationRetriedAfterFailedAction(t *testing.T) {
+	var (
+		store = &fakeConversationStore{failures: 1}
+		m     = newEscalationManager(store)
+		esc   = models.SlaEscalation{
+			Metric:  MetricResolution,
+			Trigger: EscalationTriggerBreach,
+			Actions: []amodels.RuleAction{{Type: amodels.ActionAssignTeam, Value: []string{"2"}}},
+		}
+	)
+
+	// The failed escalation is not recorded and runs again on the next evaluation.
+	m.escalate(1, null.Int{}, esc)
+	assert.Empty(t, store.actions)
+	assert.Empty(t, store.reasons)
+
+	m.escalate(1, null.Int{}, esc)
+	m.escalate(1, null.Int{}, esc)
+	assert.Equal(t, []string{"conv-uuid:" + amodels.ActionAssignTeam}, store.actions)
+	assert.Equal(t, []string{"Resolution breached"}, store.reasons)
+}
@@ -6,21 +6,192 @@ import (
 	"fmt"
 	"time"
 
+	amodels "github.com/abhinavxd/libredesk/internal/automation/models"
 	"github.com/lib/pq"
 	"github.com/volatiletech/null/v9"
 )
 
 // SLAPolicy represents a service level agreement policy definition
 type SLAPolicy struct {
-	ID                int              `db:"id" json:"id"`
-	CreatedAt         time.Time        `db:"created_at" json:"created_at"`
-	UpdatedAt         time.Time        `db:"updated_at" json:"updated_at"`
-	Name              string           `db:"name" json:"name"`
-	Description       string           `db:"description" json:"description"`
-	FirstResponseTime null.String      `db:"first_response_time" json:"first_response_time"`
-	NextResponseTime  null.String      `db:"next_response_time" json:"next_response_time"`
-	ResolutionTime    null.String      `db:"resolution_time" json:"resolution_time"`
-	Notifications     SlaNotifications `db:"notifications" json:"notifications"`
+	ID                int                `db:"id" json:"id"`
+	CreatedAt         time.Time          `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time          `db:"updated_at" json:"updated_at"`
+	Name              string             `db:"name" json:"name"`
+	Description       string             `db:"description" json:"description"`
+	FirstResponseTime null.String        `db:"first_response_time" json:"first_response_time"`
+	NextResponseTime  null.String        `db:"next_response_time" json:"next_response_time"`
+	ResolutionTime    null.String        `db:"resolution_time" json:"resolution_time"`
+	Notifications     SlaNotifications   `db:"notifications" json:"notifications"`
+	PauseStatuses     SlaPauseStatuses   `db:"pause_statuses" json:"pause_statuses"`
+	PriorityTargets   SlaPriorityTargets `db:"priority_targets" json:"priority_targets"`
+	Escalations       SlaEscalations     `db:"escalations" json:"escalations"`
+	Conditions        SlaConditions      `db:"conditions" json:"conditions"`
+	// MatchWeight ranks policies whose conditions match the same conversation, the highest weight is applied.
+	MatchWeight int `db:"match_weight" json:"match_weight"`
+}
+
+// SlaConditions are automation rule conditions that select the policy for conversations automatically.
+type SlaConditions struct {
+	GroupOperator string              `json:"group_operator"`
+	Groups        []amodels.RuleGroup `json:"groups"`
+}
+
+// IsEmpty reports whether there are no conditions, policies without conditions are only applied explicitly.
+func (sc SlaConditions) IsEmpty() bool {
+	for _, g := range sc.Groups {
+		if len(g.Rules) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Value implements the driver.Valuer interface.
+func (sc SlaConditions) Value() (driver.Value, error) {
+	if sc.Groups == nil {
+		sc.Groups = []amodels.RuleGroup{}
+	}
+	return json.Marshal(sc)
+}
+
+// Scan implements the sql.Scanner interface.
+func (sc *SlaConditions) Scan(src any) error {
+	var data []byte
+
+	switch v := src.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("unsupported type: %T", src)
+	}
+	return json.Unmarshal(data, sc)
+}
+
+// Targets returns the target times for a conversation priority. Times not set for the priority fall back to the policy's times.
+func (p SLAPolicy) Targets(priorityID int) (firstResponseTime, nextResponseTime, resolutionTime null.String) {
+	firstResponseTime, nextResponseTime, resolutionTime = p.FirstResponseTime, p.NextResponseTime, p.ResolutionTime
+	for _, t := range p.PriorityTargets {
+		if priorityID == 0 || t.PriorityID != priorityID {
+			continue
+		}
+		if t.FirstResponseTime.String != "" {
+			firstResponseTime = t.FirstResponseTime
+		}
+		if t.NextResponseTime.String != "" {
+			nextResponseTime = t.NextResponseTime
+		}
+		if t.ResolutionTime.String != "" {
+			resolutionTime = t.ResolutionTime
+		}
+		break
+	}
+	return firstResponseTime, nextResponseTime, resolutionTime
+}
+
+// SlaPriorityTarget overrides the target times of a policy for conversations of a priority.
+type SlaPriorityTarget struct {
+	PriorityID        int         `json:"priority_id"`
+	FirstResponseTime null.String `json:"first_response_time"`
+	NextResponseTime  null.String `json:"next_response_time"`
+	ResolutionTime    null.String `json:"resolution_time"`
+}
+
+type SlaPriorityTargets []SlaPriorityTarget
+
+// Value implements the driver.Valuer interface.
+func (pt SlaPriorityTargets) Value() (driver.Value, error) {
+	if pt == nil {
+		pt = SlaPriorityTargets{}
+	}
+	return json.Marshal(pt)
+}
+
+// Scan implements the sql.Scanner interface.
+func (pt *SlaPriorityTargets) Scan(src any) error {
+	var data []byte
+
+	switch v := src.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("unsupported type: %T", src)
+	}
+	return json.Unmarshal(data, pt)
+}
+
+// SlaPauseStatuses holds the conversation status IDs that pause the clock of each metric.
+// First response is never paused as the conversation is waiting on the agent until the first reply.
+type SlaPauseStatuses struct {
+	Resolution   []int `json:"resolution"`
+	NextResponse []int `json:"next_response"`
+}
+
+// Value implements the driver.Valuer interface.
+func (ps SlaPauseStatuses) Value() (driver.Value, error) {
+	if ps.Resolution == nil {
+		ps.Resolution = []int{}
+	}
+	if ps.NextResponse == nil {
+		ps.NextResponse = []int{}
+	}
+	return json.Marshal(ps)
+}
+
+// Scan implements the sql.Scanner interface.
+func (ps *SlaPauseStatuses) Scan(src any) error {
+	var data []byte
+
+	switch v := src.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("unsupported type: %T", src)
+	}
+	return json.Unmarshal(data, ps)
+}
+
+// SlaEscalation holds the actions run on a conversation when a metric breaches or reaches a percentage of its deadline.
+type SlaEscalation struct {
+	Metric  string               `json:"metric"`
+	Trigger string               `json:"trigger"`
+	Percent int                  `json:"percent,omitempty"`
+	Actions []amodels.RuleAction `json:"actions"`
+}
+
+// Key identifies the escalation on an applied SLA, so that it runs only once per applied SLA or next response event.
+func (e SlaEscalation) Key() string {
+	return fmt.Sprintf("%s:%s:%d", e.Metric, e.Trigger, e.Percent)
+}
+
+type SlaEscalations []SlaEscalation
+
+// Value implements the driver.Valuer interface.
+func (se SlaEscalations) Value() (driver.Value, error) {
+	if se == nil {
+		se = SlaEscalations{}
+	}
+	return json.Marshal(se)
+}
+
+// Scan implements the sql.Scanner interface.
+func (se *SlaEscalations) Scan(src any) error {
+	var data []byte
+
+	switch v := src.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("unsupported type: %T", src)
+	}
+	return json.Unmarshal(data, se)
 }
 
 type SlaNotifications []SlaNotification
@@ -81,6 +252,8 @@ type AppliedSLA struct {
 	ResolutionBreachedAt    null.Time `db:"resolution_breached_at"`
 	FirstResponseMetAt      null.Time `db:"first_response_met_at"`
 	ResolutionMetAt         null.Time `db:"resolution_met_at"`
+	ResolutionPaused        bool      `db:"resolution_paused"`
+	NextResponsePaused      bool      `db:"next_response_paused"`
 
 	// Conversation fields.
 	ConversationFirstResponseAt null.Time `db:"conversation_first_response_at"`
@@ -91,6 +264,10 @@ type AppliedSLA struct {
 	ConversationAssignedUserID  null.Int  `db:"conversation_assigned_user_id"`
 	ConversationStatus          string    `db:"conversation_status"`
 	ConversationStatusCategory  string    `db:"conversation_status_category"`
+	ConversationCreatedAt       time.Time `db:"conversation_created_at"`
+	ConversationAssignedTeamID  null.Int  `db:"conversation_assigned_team_id"`
+	ConversationPriorityID      null.Int  `db:"conversation_priority_id"`
+	ConversationInboxID         int       `db:"conversation_inbox_id"`
 }
 
 type SLAEvent struct {
@@ -104,3 +281,26 @@ type SLAEvent struct {
 	MetAt        null.Time `db:"met_at"`
 	BreachedAt   null.Time `db:"breached_at"`
 }
+
+// SLAPause is an open pause of the clock of a metric on an applied SLA.
+type SLAPause struct {
+	ID                   int       `db:"id"`
+	AppliedSLAID         int       `db:"applied_sla_id"`
+	Metric               string    `db:"metric"`
+	PausedAt             time.Time `db:"paused_at"`
+	ConversationID       int       `db:"conversation_id"`
+	SLAPolicyID          int       `db:"sla_policy_id"`
+	AssignedTeamID       null.Int  `db:"assigned_team_id"`
+	InboxID              int       `db:"inbox_id"`
+	ResolutionDeadlineAt null.Time `db:"resolution_deadline_at"`
+}
+
+// SLAEscalationCandidate is an unmet metric of a pending applied SLA that escalations can run on.
+type SLAEscalationCandidate struct {
+	AppliedSLAID int       `db:"applied_sla_id"`
+	SLAEventID   null.Int  `db:"sla_event_id"`
+	SLAPolicyID  int       `db:"sla_policy_id"`
+	Metric       string    `db:"metric"`
+	StartAt      time.Time `db:"start_at"`
+	DeadlineAt   time.Time `db:"deadline_at"`
+}
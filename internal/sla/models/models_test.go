@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/null/v9"
+)
+
+func TestSLAPolicyTargets(t *testing.T) {
+	policy := SLAPolicy{
+		FirstResponseTime: null.StringFrom("4h"),
+		NextResponseTime:  null.StringFrom("8h"),
+		ResolutionTime:    null.StringFrom("24h"),
+		PriorityTargets: SlaPriorityTargets{
+			{PriorityID: 1, FirstResponseTime: null.StringFrom("1h"), ResolutionTime: null.StringFrom("4h")},
+			{PriorityID: 2, NextResponseTime: null.StringFrom("2h")},
+		},
+	}
+
+	tests := []struct {
+		name                         string
+		priorityID                   int
+		firstResponse, next, resolve string
+	}{
+		{name: "Priority with targets", priorityID: 1, firstResponse: "1h", next: "8h", resolve: "4h"},
+		{name: "Priority with a single target", priorityID: 2, firstResponse: "4h", next: "2h", resolve: "24h"},
+		{name: "Priority without targets", priorityID: 3, firstResponse: "4h", next: "8h", resolve: "24h"},
+		{name: "No priority", priorityID: 0, firstResponse: "4h", next: "8h", resolve: "24h"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frt, nrt, rt := policy.Targets(tt.priorityID)
+			assert.Equal(t, tt.firstResponse, frt.String)
+			assert.Equal(t, tt.next, nrt.String)
+			assert.Equal(t, tt.resolve, rt.String)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package sla
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/abhinavxd/libredesk/internal/sla/models"
+	"github.com/volatiletech/null/v9"
+)
+
+// SyncPauses pauses or resumes the SLA clocks of a conversation after its status changed, as configured on its SLA policy.
+func (m *Manager) SyncPauses(conversationID int) {
+	m.syncPauses(conversationID)
+}
+
+// syncPauses pauses the clocks of pending applied SLAs whose conversation is in a pausing status and resumes
+// the ones whose conversation has left it. A conversation ID of 0 syncs all conversations.
+func (m *Manager) syncPauses(conversationID int) {
+	if _, err := m.q.InsertSLAPauses.Exec(conversationID); err != nil {
+		m.lo.Error("error pausing SLA clocks", "conversation_id", conversationID, "error", err)
+	}
+
+	var pauses []models.SLAPause
+	if err := m.q.GetSLAPausesToResume.Select(&pauses, conversationID); err != nil {
+		m.lo.Error("error fetching SLA pauses to resume", "conversation_id", conversationID, "error", err)
+		return
+	}
+	for _, pause := range pauses {
+		if err := m.resumePause(pause); err != nil {
+			m.lo.Error("error resuming SLA clock", "applied_sla_id", pause.AppliedSLAID, "metric", pause.Metric, "error", err)
+		}
+	}
+}
+
+// resumePause closes a pause and pushes the deadline of the metric back by the business time it was paused for.
+func (m *Manager) resumePause(pause models.SLAPause) error {
+	var resumedAt time.Time
+	if err := m.q.ResumeSLAPause.QueryRow(pause.ID).Scan(&resumedAt); err != nil {
+		return err
+	}
+
+	businessHrs, timezone, err := m.getBusinessHoursAndTimezone(pause.AssignedTeamID.Int, pause.InboxID)
+	if err != nil {
+		return err
+	}
+	sla, err := m.Get(pause.SLAPolicyID)
+	if err != nil {
+		return err
+	}
+
+	// extend returns the deadline moved by the business minutes between from and resumedAt.
+	extend := func(deadline, from time.Time) (time.Time, bool, error) {
+		minutes, err := BusinessMinutesBetween(from, resumedAt, businessHrs, timezone)
+		if err != nil || minutes == 0 {
+			return deadline, false, err
+		}
+		newDeadline, err := m.CalculateDeadline(deadline, minutes, businessHrs, timezone)
+		if err != nil {
+			return deadline, false, err
+		}
+		return newDeadline, true, nil
+	}
+
+	switch pause.Metric {
+	case MetricResolution:
+		if !pause.ResolutionDeadlineAt.Valid {
+			return nil
+		}
+		deadline, moved, err := extend(pause.ResolutionDeadlineAt.Time, pause.PausedAt)
+		if err != nil || !moved {
+			return err
+		}
+		if _, err := m.q.UpdateAppliedSLAResolutionDeadline.Exec(pause.AppliedSLAID, deadline); err != nil {
+			return err
+		}
+		if _, err := m.q.UpdateConversationNextSLADeadline.Exec(pause.ConversationID, nil); err != nil {
+			return err
+		}
+		if _, err := m.q.DeletePendingSLAWarnings.Exec(pause.AppliedSLAID, MetricResolution); err != nil {
+			return err
+		}
+		m.createNotificationSchedule(sla.Notifications, pause.AppliedSLAID, null.Int{}, Deadlines{Resolution: null.TimeFrom(deadline)}, Breaches{})
+		m.lo.Info("resumed SLA resolution clock", "applied_sla_id", pause.AppliedSLAID, "deadline", deadline)
+	case MetricNextResponse:
+		var event models.SLAEvent
+		if err := m.q.GetUnmetNextResponseSLAEvent.Get(&event, pause.AppliedSLAID); err != nil {
+			// No next response is awaited, so there is no deadline to move.
+			if err == sql.ErrNoRows {
+				return nil
+			}
+			return err
+		}
+		// The event may have been created while paused, only the paused time after it counts.
+		from := pause.PausedAt
+		if event.CreatedAt.After(from) {
+			from = event.CreatedAt
+		}
+		deadline, moved, err := extend(event.DeadlineAt, from)
+		if err != nil || !moved {
+			return err
+		}
+		if _, err := m.q.UpdateSLAEventDeadline.Exec(event.ID, deadline); err != nil {
+			return err
+		}
+		if _, err := m.q.UpdateConversationNextSLADeadline.Exec(pause.ConversationID, deadline); err != nil {
+			return err
+		}
+		if _, err := m.q.DeletePendingSLAWarnings.Exec(pause.AppliedSLAID, MetricNextResponse); err != nil {
+			return err
+		}
+		m.createNotificationSchedule(sla.Notifications, pause.AppliedSLAID, null.IntFrom(event.ID), Deadlines{NextResponse: null.TimeFrom(deadline)}, Breaches{})
+		m.lo.Info("resumed SLA next response clock", "applied_sla_id", pause.AppliedSLAID, "sla_event_id", event.ID, "deadline", deadline)
+	}
+	return nil
+}
@@ -1,8 +1,8 @@
 -- name: get-sla-policy
-SELECT id, name, description, first_response_time, resolution_time, next_response_time, notifications, created_at, updated_at FROM sla_policies WHERE id = $1;
+SELECT id, name, description, first_response_time, resolution_time, next_response_time, notifications, pause_statuses, priority_targets, escalations, conditions, match_weight, created_at, updated_at FROM sla_policies WHERE id = $1;
 
 -- name: get-all-sla-policies
-SELECT id, name, description, first_response_time, resolution_time, next_response_time, notifications, created_at, updated_at FROM sla_policies ORDER BY updated_at DESC;
+SELECT id, name, description, first_response_time, resolution_time, next_response_time, notifications, pause_statuses, priority_targets, escalations, conditions, match_weight, created_at, updated_at FROM sla_policies ORDER BY updated_at DESC;
 
 -- name: insert-sla-policy
 INSERT INTO sla_policies (
@@ -11,8 +11,13 @@ INSERT INTO sla_policies (
    first_response_time,
    resolution_time,
    next_response_time,
-   notifications
-) VALUES ($1, $2, $3, $4, $5, $6)
+   notifications,
+   pause_statuses,
+   priority_targets,
+   escalations,
+   conditions,
+   match_weight
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 RETURNING *;
 
 -- name: update-sla-policy
@@ -23,6 +28,11 @@ UPDATE sla_policies SET
    resolution_time = $5,
    next_response_time = $6,
    notifications = $7,
+   pause_statuses = $8,
+   priority_targets = $9,
+   escalations = $10,
+   conditions = $11,
+   match_weight = $12,
    updated_at = NOW()
 WHERE id = $1
 RETURNING *;
@@ -56,16 +66,19 @@ RETURNING ns.id;
 -- Returns only actionable pending SLAs: a metric is unresolved AND either its deadline has passed
 -- or the conversation has transitioned (first reply / resolve) since the last evaluation.
 SELECT a.id, a.first_response_deadline_at, c.first_reply_at as conversation_first_response_at, a.sla_policy_id,
-a.resolution_deadline_at, c.resolved_at as conversation_resolved_at, c.id as conversation_id, a.first_response_met_at, a.resolution_met_at, a.first_response_breached_at, a.resolution_breached_at
+a.resolution_deadline_at, c.resolved_at as conversation_resolved_at, c.id as conversation_id, a.first_response_met_at, a.resolution_met_at, a.first_response_breached_at, a.resolution_breached_at,
+rp.id IS NOT NULL as resolution_paused
 FROM applied_slas a
 JOIN conversations c ON a.conversation_id = c.id and c.sla_policy_id = a.sla_policy_id
+LEFT JOIN applied_sla_pauses rp ON rp.applied_sla_id = a.id AND rp.metric = 'resolution' AND rp.resumed_at IS NULL
 WHERE a.status = 'pending'::applied_sla_status
   AND (
     (a.first_response_met_at IS NULL AND a.first_response_breached_at IS NULL
      AND (a.first_response_deadline_at <= NOW() OR c.first_reply_at IS NOT NULL))
     OR
+    -- A paused resolution clock cannot breach, it is only evaluated once resolved.
     (a.resolution_met_at IS NULL AND a.resolution_breached_at IS NULL
-     AND (a.resolution_deadline_at <= NOW() OR c.resolved_at IS NOT NULL))
+     AND ((a.resolution_deadline_at <= NOW() AND rp.id IS NULL) OR c.resolved_at IS NOT NULL))
   );
 
 -- name: update-applied-sla-breached-at
@@ -161,7 +174,9 @@ SELECT a.id,
    c.subject as conversation_subject,
    c.assigned_user_id as conversation_assigned_user_id,
    s.name as conversation_status,
-   s.category as conversation_status_category
+   s.category as conversation_status_category,
+   EXISTS (SELECT 1 FROM applied_sla_pauses p WHERE p.applied_sla_id = a.id AND p.metric = 'resolution' AND p.resumed_at IS NULL) as resolution_paused,
+   EXISTS (SELECT 1 FROM applied_sla_pauses p WHERE p.applied_sla_id = a.id AND p.metric = 'next_response' AND p.resumed_at IS NULL) as next_response_paused
 FROM applied_slas a INNER JOIN conversations c on a.conversation_id = c.id
 LEFT JOIN conversation_statuses s ON c.status_id = s.id
 WHERE a.id = $1;
@@ -210,8 +225,152 @@ WHERE id = $1;
 
 -- name: get-pending-sla-events
 -- Returns full event rows whose deadline has already passed (or that already have a met_at);
+-- events whose clock is paused are skipped until they are met or resumed.
 SELECT id, created_at, updated_at, applied_sla_id, sla_policy_id, type, deadline_at, met_at, breached_at
-FROM sla_events
+FROM sla_events e
 WHERE status = 'pending'
   AND deadline_at IS NOT NULL
-  AND (deadline_at <= NOW() OR met_at IS NOT NULL);
+  AND (
+    met_at IS NOT NULL
+    OR (deadline_at <= NOW() AND NOT EXISTS (
+      SELECT 1 FROM applied_sla_pauses p
+      WHERE p.applied_sla_id = e.applied_sla_id AND p.metric = e.type AND p.resumed_at IS NULL
+    ))
+  );
+
+-- name: insert-sla-pauses
+-- Pauses the clocks of pending applied SLAs whose conversation is in a status the policy pauses the metric on.
+-- $1 is a conversation ID, 0 for all conversations.
+INSERT INTO applied_sla_pauses (applied_sla_id, metric)
+SELECT a.id, m.metric
+FROM applied_slas a
+JOIN conversations c ON c.id = a.conversation_id AND c.sla_policy_id = a.sla_policy_id
+JOIN sla_policies sp ON sp.id = a.sla_policy_id
+CROSS JOIN (VALUES ('resolution'::sla_metric), ('next_response'::sla_metric)) AS m(metric)
+WHERE a.status = 'pending'
+  AND ($1 = 0 OR a.conversation_id = $1)
+  AND c.status_id IN (SELECT jsonb_array_elements_text(sp.pause_statuses->m.metric::TEXT)::INT)
+  AND (m.metric != 'resolution' OR (a.resolution_met_at IS NULL AND a.resolution_breached_at IS NULL AND c.resolved_at IS NULL))
+ON CONFLICT DO NOTHING;
+
+-- name: get-sla-pauses-to-resume
+-- Returns open pauses whose conversation is no longer in a status that pauses the metric.
+-- $1 is a conversation ID, 0 for all conversations.
+SELECT p.id, p.applied_sla_id, p.metric, p.paused_at, a.conversation_id, a.sla_policy_id, c.assigned_team_id, c.inbox_id, a.resolution_deadline_at
+FROM applied_sla_pauses p
+JOIN applied_slas a ON a.id = p.applied_sla_id
+JOIN conversations c ON c.id = a.conversation_id
+JOIN sla_policies sp ON sp.id = a.sla_policy_id
+WHERE p.resumed_at IS NULL
+  AND ($1 = 0 OR a.conversation_id = $1)
+  AND (
+    a.status != 'pending'
+    OR c.sla_policy_id IS DISTINCT FROM a.sla_policy_id
+    OR c.status_id IS NULL
+    OR c.status_id NOT IN (SELECT jsonb_array_elements_text(sp.pause_statuses->p.metric::TEXT)::INT)
+  );
+
+-- name: resume-sla-pause
+-- Closes a pause and adds its length to the paused time of the applied SLA.
+WITH resumed AS (
+  UPDATE applied_sla_pauses SET resumed_at = NOW()
+  WHERE id = $1 AND resumed_at IS NULL
+  RETURNING applied_sla_id, metric, resumed_at, EXTRACT(EPOCH FROM (resumed_at - paused_at))::INT AS seconds
+)
+UPDATE applied_slas a SET
+  resolution_paused_seconds = a.resolution_paused_seconds + CASE WHEN r.metric = 'resolution' THEN r.seconds ELSE 0 END,
+  next_response_paused_seconds = a.next_response_paused_seconds + CASE WHEN r.metric = 'next_response' THEN r.seconds ELSE 0 END,
+  updated_at = NOW()
+FROM resumed r
+WHERE a.id = r.applied_sla_id
+RETURNING r.resumed_at;
+
+-- name: update-applied-sla-resolution-deadline
+UPDATE applied_slas SET resolution_deadline_at = $2, updated_at = NOW()
+WHERE id = $1 AND status = 'pending' AND resolution_met_at IS NULL AND resolution_breached_at IS NULL;
+
+-- name: get-unmet-next-response-sla-event
+SELECT id, created_at, updated_at, applied_sla_id, sla_policy_id, type, deadline_at, met_at, breached_at
+FROM sla_events
+WHERE applied_sla_id = $1 AND type = 'next_response' AND status = 'pending' AND met_at IS NULL
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: update-sla-event-deadline
+UPDATE sla_events SET deadline_at = $2, updated_at = NOW() WHERE id = $1;
+
+-- name: delete-pending-sla-warnings
+-- Deletes the unsent warnings of a metric so that they can be rescheduled for a moved deadline.
+DELETE FROM scheduled_sla_notifications
+WHERE applied_sla_id = $1 AND metric = $2 AND notification_type = 'warning' AND processed_at IS NULL;
+
+-- name: get-pending-applied-sla-by-conversation
+SELECT a.id, a.created_at, a.status, a.conversation_id, a.sla_policy_id, a.first_response_deadline_at, a.resolution_deadline_at,
+   a.first_response_met_at, a.resolution_met_at, a.first_response_breached_at, a.resolution_breached_at,
+   c.created_at as conversation_created_at, c.assigned_team_id as conversation_assigned_team_id, c.priority_id as conversation_priority_id,
+   c.inbox_id as conversation_inbox_id
+FROM applied_slas a
+JOIN conversations c ON c.id = a.conversation_id AND c.sla_policy_id = a.sla_policy_id
+WHERE a.conversation_id = $1 AND a.status = 'pending';
+
+-- name: get-closed-sla-pauses
+SELECT paused_at, resumed_at FROM applied_sla_pauses
+WHERE applied_sla_id = $1 AND metric = $2 AND resumed_at IS NOT NULL AND resumed_at > $3
+ORDER BY paused_at;
+
+-- name: update-applied-sla-deadlines
+-- Moves the deadlines of the metrics that are neither met nor breached.
+UPDATE applied_slas SET
+   first_response_deadline_at = CASE WHEN first_response_met_at IS NULL AND first_response_breached_at IS NULL THEN $2 ELSE first_response_deadline_at END,
+   resolution_deadline_at = CASE WHEN resolution_met_at IS NULL AND resolution_breached_at IS NULL THEN $3 ELSE resolution_deadline_at END,
+   updated_at = NOW()
+WHERE id = $1;
+
+-- name: insert-applied-sla-escalation
+-- Claims an escalation on an applied SLA and returns the UUID of its conversation, no row if it already ran.
+WITH claimed AS (
+   INSERT INTO applied_sla_escalations (applied_sla_id, sla_event_id, metric, escalation_key)
+   VALUES ($1, $2, $3, $4)
+   ON CONFLICT DO NOTHING
+   RETURNING applied_sla_id
+)
+SELECT c.uuid
+FROM claimed
+JOIN applied_slas a ON a.id = claimed.applied_sla_id
+JOIN conversations c ON c.id = a.conversation_id;
+
+-- name: delete-applied-sla-escalation
+-- Releases the claim of an escalation that failed to run so it is retried.
+DELETE FROM applied_sla_escalations
+WHERE applied_sla_id = $1 AND COALESCE(sla_event_id, 0) = COALESCE($2::BIGINT, 0) AND escalation_key = $3;
+
+-- name: get-sla-escalation-candidates
+-- Returns the unmet and unpaused metrics of pending applied SLAs whose policy has escalations.
+SELECT a.id AS applied_sla_id, NULL::BIGINT AS sla_event_id, a.sla_policy_id, m.metric, c.created_at AS start_at, m.deadline_at
+FROM applied_slas a
+JOIN conversations c ON c.id = a.conversation_id AND c.sla_policy_id = a.sla_policy_id
+JOIN sla_policies sp ON sp.id = a.sla_policy_id AND sp.escalations != '[]'::jsonb
+CROSS JOIN LATERAL (VALUES
+   ('first_response'::sla_metric, a.first_response_deadline_at, a.first_response_met_at IS NULL AND a.first_response_breached_at IS NULL AND c.first_reply_at IS NULL),
+   ('resolution'::sla_metric, a.resolution_deadline_at, a.resolution_met_at IS NULL AND a.resolution_breached_at IS NULL AND c.resolved_at IS NULL)
+) AS m(metric, deadline_at, unmet)
+WHERE a.status = 'pending'
+  AND m.unmet
+  AND m.deadline_at IS NOT NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM applied_sla_pauses p WHERE p.applied_sla_id = a.id AND p.metric = m.metric AND p.resumed_at IS NULL
+  )
+UNION ALL
+SELECT e.applied_sla_id, e.id, a.sla_policy_id, e.type, e.created_at, e.deadline_at
+FROM sla_events e
+JOIN applied_slas a ON a.id = e.applied_sla_id
+JOIN sla_policies sp ON sp.id = a.sla_policy_id AND sp.escalations != '[]'::jsonb
+WHERE e.status = 'pending'
+  AND e.type = 'next_response'
+  AND e.met_at IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM applied_sla_pauses p WHERE p.applied_sla_id = e.applied_sla_id AND p.metric = 'next_response' AND p.resumed_at IS NULL
+  );
+
+-- name: get-inbox-business-hours-id
+SELECT COALESCE(business_hours_id, 0) FROM inboxes WHERE id = $1;
@@ -11,8 +11,10 @@ import (
 	"sync"
 	"time"
 
+	amodels "github.com/abhinavxd/libredesk/internal/automation/models"
 	businesshours "github.com/abhinavxd/libredesk/internal/business_hours"
 	bmodels "github.com/abhinavxd/libredesk/internal/business_hours/models"
+	cmodels "github.com/abhinavxd/libredesk/internal/conversation/models"
 	cstatusmodels "github.com/abhinavxd/libredesk/internal/conversation/status/models"
 	"github.com/abhinavxd/libredesk/internal/dbutil"
 	"github.com/abhinavxd/libredesk/internal/envelope"
@@ -64,8 +66,11 @@ type Manager struct {
 	businessHrsStore businessHrsStore
 	template         *template.Manager
 	dispatcher       *notifier.Dispatcher
-	wg               sync.WaitGroup
-	opts             Opts
+	// conversationStore is set after the conversation manager is created as it depends on the SLA manager.
+	conversationStore conversationStore
+	escalationStore   escalationStore
+	wg                sync.WaitGroup
+	opts              Opts
 }
 
 // Opts defines the options for creating SLA manager.
@@ -105,29 +110,55 @@ type businessHrsStore interface {
 	Get(id int) (bmodels.BusinessHours, error)
 }
 
+type conversationStore interface {
+	GetConversation(id int, uuid, refNum string) (cmodels.Conversation, error)
+	ApplyAction(action amodels.RuleAction, conversation cmodels.Conversation, user umodels.User) error
+	RecordSLAEscalation(conversationUUID, reason string) error
+}
+
+// escalationStore claims escalations so each runs only once, and releases the claim of an escalation that failed to run.
+type escalationStore interface {
+	Claim(appliedSLAID int, slaEventID null.Int, esc models.SlaEscalation) (string, error)
+	Release(appliedSLAID int, slaEventID null.Int, esc models.SlaEscalation) error
+}
+
 // queries hold prepared SQL queries.
 type queries struct {
-	GetSLAPolicy                      *sqlx.Stmt `query:"get-sla-policy"`
-	GetAllSLAPolicies                 *sqlx.Stmt `query:"get-all-sla-policies"`
-	GetAppliedSLA                     *sqlx.Stmt `query:"get-applied-sla"`
-	GetSLAEvent                       *sqlx.Stmt `query:"get-sla-event"`
-	GetScheduledSLANotifications      *sqlx.Stmt `query:"get-scheduled-sla-notifications"`
-	GetPendingAppliedSLA              *sqlx.Stmt `query:"get-pending-applied-sla"`
-	GetPendingSLAEvents               *sqlx.Stmt `query:"get-pending-sla-events"`
-	InsertScheduledSLANotification    *sqlx.Stmt `query:"insert-scheduled-sla-notification"`
-	InsertSLAPolicy                   *sqlx.Stmt `query:"insert-sla-policy"`
-	InsertNextResponseSLAEvent        *sqlx.Stmt `query:"insert-next-response-sla-event"`
-	UpdateSLAPolicy                   *sqlx.Stmt `query:"update-sla-policy"`
-	UpdateAppliedSLABreachedAt        *sqlx.Stmt `query:"update-applied-sla-breached-at"`
-	UpdateAppliedSLAMetAt             *sqlx.Stmt `query:"update-applied-sla-met-at"`
-	UpdateConversationNextSLADeadline *sqlx.Stmt `query:"update-conversation-sla-deadline"`
-	UpdateAppliedSLAStatus            *sqlx.Stmt `query:"update-applied-sla-status"`
-	UpdateSLANotificationProcessed    *sqlx.Stmt `query:"update-notification-processed"`
-	UpdateSLAEventAsBreached          *sqlx.Stmt `query:"update-sla-event-as-breached"`
-	UpdateSLAEventAsMet               *sqlx.Stmt `query:"update-sla-event-as-met"`
-	SetLatestSLAEventMetAt            *sqlx.Stmt `query:"set-latest-sla-event-met-at"`
-	ApplySLA                          *sqlx.Stmt `query:"apply-sla"`
-	DeleteSLAPolicy                   *sqlx.Stmt `query:"delete-sla-policy"`
+	GetSLAPolicy                       *sqlx.Stmt `query:"get-sla-policy"`
+	GetAllSLAPolicies                  *sqlx.Stmt `query:"get-all-sla-policies"`
+	GetAppliedSLA                      *sqlx.Stmt `query:"get-applied-sla"`
+	GetSLAEvent                        *sqlx.Stmt `query:"get-sla-event"`
+	GetScheduledSLANotifications       *sqlx.Stmt `query:"get-scheduled-sla-notifications"`
+	GetPendingAppliedSLA               *sqlx.Stmt `query:"get-pending-applied-sla"`
+	GetPendingSLAEvents                *sqlx.Stmt `query:"get-pending-sla-events"`
+	InsertScheduledSLANotification     *sqlx.Stmt `query:"insert-scheduled-sla-notification"`
+	InsertSLAPolicy                    *sqlx.Stmt `query:"insert-sla-policy"`
+	InsertNextResponseSLAEvent         *sqlx.Stmt `query:"insert-next-response-sla-event"`
+	UpdateSLAPolicy                    *sqlx.Stmt `query:"update-sla-policy"`
+	UpdateAppliedSLABreachedAt         *sqlx.Stmt `query:"update-applied-sla-breached-at"`
+	UpdateAppliedSLAMetAt              *sqlx.Stmt `query:"update-applied-sla-met-at"`
+	UpdateConversationNextSLADeadline  *sqlx.Stmt `query:"update-conversation-sla-deadline"`
+	UpdateAppliedSLAStatus             *sqlx.Stmt `query:"update-applied-sla-status"`
+	UpdateSLANotificationProcessed     *sqlx.Stmt `query:"update-notification-processed"`
+	UpdateSLAEventAsBreached           *sqlx.Stmt `query:"update-sla-event-as-breached"`
+	UpdateSLAEventAsMet                *sqlx.Stmt `query:"update-sla-event-as-met"`
+	SetLatestSLAEventMetAt             *sqlx.Stmt `query:"set-latest-sla-event-met-at"`
+	ApplySLA                           *sqlx.Stmt `query:"apply-sla"`
+	DeleteSLAPolicy                    *sqlx.Stmt `query:"delete-sla-policy"`
+	InsertSLAPauses                    *sqlx.Stmt `query:"insert-sla-pauses"`
+	GetSLAPausesToResume               *sqlx.Stmt `query:"get-sla-pauses-to-resume"`
+	ResumeSLAPause                     *sqlx.Stmt `query:"resume-sla-pause"`
+	UpdateAppliedSLAResolutionDeadline *sqlx.Stmt `query:"update-applied-sla-resolution-deadline"`
+	GetUnmetNextResponseSLAEvent       *sqlx.Stmt `query:"get-unmet-next-response-sla-event"`
+	UpdateSLAEventDeadline             *sqlx.Stmt `query:"update-sla-event-deadline"`
+	DeletePendingSLAWarnings           *sqlx.Stmt `query:"delete-pending-sla-warnings"`
+	GetPendingAppliedSLAByConversation *sqlx.Stmt `query:"get-pending-applied-sla-by-conversation"`
+	GetClosedSLAPauses                 *sqlx.Stmt `query:"get-closed-sla-pauses"`
+	GetInboxBusinessHoursID            *sqlx.Stmt `query:"get-inbox-business-hours-id"`
+	UpdateAppliedSLADeadlines          *sqlx.Stmt `query:"update-applied-sla-deadlines"`
+	InsertAppliedSLAEscalation         *sqlx.Stmt `query:"insert-applied-sla-escalation"`
+	DeleteAppliedSLAEscalation         *sqlx.Stmt `query:"delete-applied-sla-escalation"`
+	GetSLAEscalationCandidates         *sqlx.Stmt `query:"get-sla-escalation-candidates"`
 }
 
 // New creates a new SLA manager.
@@ -149,7 +180,7 @@ func New(
 	); err != nil {
 		return nil, err
 	}
-	return &Manager{
+	m := &Manager{
 		q:                q,
 		lo:               opts.Lo,
 		i18n:             opts.I18n,
@@ -160,7 +191,9 @@ func New(
 		userStore:        userStore,
 		dispatcher:       dispatcher,
 		opts:             opts,
-	}, nil
+	}
+	m.escalationStore = &dbEscalationStore{q: &m.q}
+	return m, nil
 }
 
 // Get retrieves an SLA by ID.
@@ -187,9 +220,9 @@ func (m *Manager) GetAll() ([]models.SLAPolicy, error) {
 }
 
 // Create creates a new SLA policy.
-func (m *Manager) Create(name, description string, firstResponseTime, resolutionTime, nextResponseTime null.String, notifications models.SlaNotifications) (models.SLAPolicy, error) {
+func (m *Manager) Create(name, description string, firstResponseTime, resolutionTime, nextResponseTime null.String, notifications models.SlaNotifications, pauseStatuses models.SlaPauseStatuses, priorityTargets models.SlaPriorityTargets, escalations models.SlaEscalations, conditions models.SlaConditions, matchWeight int) (models.SLAPolicy, error) {
 	var result models.SLAPolicy
-	if err := m.q.InsertSLAPolicy.Get(&result, name, description, firstResponseTime, resolutionTime, nextResponseTime, notifications); err != nil {
+	if err := m.q.InsertSLAPolicy.Get(&result, name, description, firstResponseTime, resolutionTime, nextResponseTime, notifications, pauseStatuses, priorityTargets, escalations, conditions, matchWeight); err != nil {
 		m.lo.Error("error inserting SLA", "error", err)
 		return models.SLAPolicy{}, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
 	}
@@ -197,9 +230,9 @@ func (m *Manager) Create(name, description string, firstResponseTime, resolution
 }
 
 // Update updates a SLA policy.
-func (m *Manager) Update(id int, name, description string, firstResponseTime, resolutionTime, nextResponseTime null.String, notifications models.SlaNotifications) (models.SLAPolicy, error) {
+func (m *Manager) Update(id int, name, description string, firstResponseTime, resolutionTime, nextResponseTime null.String, notifications models.SlaNotifications, pauseStatuses models.SlaPauseStatuses, priorityTargets models.SlaPriorityTargets, escalations models.SlaEscalations, conditions models.SlaConditions, matchWeight int) (models.SLAPolicy, error) {
 	var result models.SLAPolicy
-	if err := m.q.UpdateSLAPolicy.Get(&result, id, name, description, firstResponseTime, resolutionTime, nextResponseTime, notifications); err != nil {
+	if err := m.q.UpdateSLAPolicy.Get(&result, id, name, description, firstResponseTime, resolutionTime, nextResponseTime, notifications, pauseStatuses, priorityTargets, escalations, conditions, matchWeight); err != nil {
 		m.lo.Error("error updating SLA", "error", err)
 		return models.SLAPolicy{}, envelope.NewError(envelope.GeneralError, m.i18n.T("globals.messages.somethingWentWrong"), nil)
 	}
@@ -215,11 +248,11 @@ func (m *Manager) Delete(id int) error {
 	return nil
 }
 
-// GetDeadlines returns the deadline for a given start time, sla policy and assigned team.
-func (m *Manager) GetDeadlines(startTime time.Time, slaPolicyID, assignedTeamID int) (Deadlines, error) {
+// GetDeadlines returns the deadline for a given start time, sla policy, assigned team, inbox and conversation priority.
+func (m *Manager) GetDeadlines(startTime time.Time, slaPolicyID, assignedTeamID, inboxID, priorityID int) (Deadlines, error) {
 	var deadlines Deadlines
 
-	businessHrs, timezone, err := m.getBusinessHoursAndTimezone(assignedTeamID)
+	businessHrs, timezone, err := m.getBusinessHoursAndTimezone(assignedTeamID, inboxID)
 	if err != nil {
 		return deadlines, err
 	}
@@ -247,24 +280,25 @@ func (m *Manager) GetDeadlines(startTime time.Time, slaPolicyID, assignedTeamID
 		return null.TimeFrom(deadline), nil
 	}
 
-	if deadlines.FirstResponse, err = calculateDeadline(sla.FirstResponseTime.String); err != nil {
+	firstResponseTime, nextResponseTime, resolutionTime := sla.Targets(priorityID)
+	if deadlines.FirstResponse, err = calculateDeadline(firstResponseTime.String); err != nil {
 		return deadlines, err
 	}
-	if deadlines.Resolution, err = calculateDeadline(sla.ResolutionTime.String); err != nil {
+	if deadlines.Resolution, err = calculateDeadline(resolutionTime.String); err != nil {
 		return deadlines, err
 	}
-	if deadlines.NextResponse, err = calculateDeadline(sla.NextResponseTime.String); err != nil {
+	if deadlines.NextResponse, err = calculateDeadline(nextResponseTime.String); err != nil {
 		return deadlines, err
 	}
 	return deadlines, nil
 }
 
 // ApplySLA applies an SLA policy to a conversation by calculating and setting the deadlines.
-func (m *Manager) ApplySLA(startTime time.Time, conversationID, assignedTeamID, slaPolicyID int) (models.SLAPolicy, error) {
+func (m *Manager) ApplySLA(startTime time.Time, conversationID, assignedTeamID, inboxID, priorityID, slaPolicyID int) (models.SLAPolicy, error) {
 	var sla models.SLAPolicy
 
-	// Get deadlines for the SLA policy and assigned team.
-	deadlines, err := m.GetDeadlines(startTime, slaPolicyID, assignedTeamID)
+	// Get deadlines for the SLA policy, assigned team, inbox and priority.
+	deadlines, err := m.GetDeadlines(startTime, slaPolicyID, assignedTeamID, inboxID, priorityID)
 	if err != nil {
 		return sla, err
 	}
@@ -295,7 +329,7 @@ func (m *Manager) ApplySLA(startTime time.Time, conversationID, assignedTeamID,
 }
 
 // CreateNextResponseSLAEvent creates a next response SLA event for a conversation.
-func (m *Manager) CreateNextResponseSLAEvent(conversationID, appliedSLAID, slaPolicyID, assignedTeamID int) (time.Time, error) {
+func (m *Manager) CreateNextResponseSLAEvent(conversationID, appliedSLAID, slaPolicyID, assignedTeamID, inboxID, priorityID int) (time.Time, error) {
 	var slaPolicy models.SLAPolicy
 	if err := m.q.GetSLAPolicy.Get(&slaPolicy, slaPolicyID); err != nil {
 		if err == sql.ErrNoRows {
@@ -305,7 +339,7 @@ func (m *Manager) CreateNextResponseSLAEvent(conversationID, appliedSLAID, slaPo
 		return time.Time{}, fmt.Errorf("fetching SLA policy: %w", err)
 	}
 
-	if slaPolicy.NextResponseTime.String == "" {
+	if _, nextResponseTime, _ := slaPolicy.Targets(priorityID); nextResponseTime.String == "" {
 		m.lo.Info("no next response time set for SLA policy, skipping event creation",
 			"conversation_id", conversationID,
 			"policy_id", slaPolicyID,
@@ -315,7 +349,7 @@ func (m *Manager) CreateNextResponseSLAEvent(conversationID, appliedSLAID, slaPo
 	}
 
 	// Calculate the deadline for the next response SLA event.
-	deadlines, err := m.GetDeadlines(time.Now(), slaPolicy.ID, assignedTeamID)
+	deadlines, err := m.GetDeadlines(time.Now(), slaPolicy.ID, assignedTeamID, inboxID, priorityID)
 	if err != nil {
 		m.lo.Error("error calculating deadlines for next response SLA event", "error", err)
 		return time.Time{}, fmt.Errorf("calculating deadlines for next response SLA event: %w", err)
@@ -380,6 +414,145 @@ func (m *Manager) SetLatestSLAEventMetAt(appliedSLAID int, metric string) (time.
 	return metAt, nil
 }
 
+// RecalculateDeadlines recomputes the deadlines of the pending SLA applied to a conversation for its current priority,
+// keeping the time the clocks were paused. Metrics that are already met or breached keep their deadlines.
+// It returns whether any deadline moved.
+func (m *Manager) RecalculateDeadlines(conversationID int) (bool, error) {
+	var appliedSLA models.AppliedSLA
+	if err := m.q.GetPendingAppliedSLAByConversation.Get(&appliedSLA, conversationID); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		m.lo.Error("error fetching pending applied SLA", "conversation_id", conversationID, "error", err)
+		return false, fmt.Errorf("fetching pending applied SLA: %w", err)
+	}
+
+	var (
+		teamID     = appliedSLA.ConversationAssignedTeamID.Int
+		inboxID    = appliedSLA.ConversationInboxID
+		priorityID = appliedSLA.ConversationPriorityID.Int
+	)
+	deadlines, err := m.GetDeadlines(appliedSLA.ConversationCreatedAt, appliedSLA.SLAPolicyID, teamID, inboxID, priorityID)
+	if err != nil {
+		return false, err
+	}
+	if deadlines.Resolution, err = m.withPausedTime(deadlines.Resolution, appliedSLA.ID, MetricResolution, appliedSLA.ConversationCreatedAt, teamID, inboxID); err != nil {
+		return false, err
+	}
+
+	var changed bool
+	if !appliedSLA.FirstResponseMetAt.Valid && !appliedSLA.FirstResponseBreachedAt.Valid && !deadlines.FirstResponse.Time.Equal(appliedSLA.FirstResponseDeadlineAt.Time) {
+		changed = true
+	}
+	if !appliedSLA.ResolutionMetAt.Valid && !appliedSLA.ResolutionBreachedAt.Valid && !deadlines.Resolution.Time.Equal(appliedSLA.ResolutionDeadlineAt.Time) {
+		changed = true
+	}
+	if _, err := m.q.UpdateAppliedSLADeadlines.Exec(appliedSLA.ID, deadlines.FirstResponse, deadlines.Resolution); err != nil {
+		m.lo.Error("error updating applied SLA deadlines", "applied_sla_id", appliedSLA.ID, "error", err)
+		return false, fmt.Errorf("updating applied SLA deadlines: %w", err)
+	}
+
+	sla, err := m.Get(appliedSLA.SLAPolicyID)
+	if err != nil {
+		return false, err
+	}
+	if changed {
+		for _, metric := range []string{MetricFirstResponse, MetricResolution} {
+			if _, err := m.q.DeletePendingSLAWarnings.Exec(appliedSLA.ID, metric); err != nil {
+				m.lo.Error("error deleting pending SLA warnings", "applied_sla_id", appliedSLA.ID, "error", err)
+			}
+		}
+		var warnAt Deadlines
+		if !appliedSLA.FirstResponseMetAt.Valid && !appliedSLA.FirstResponseBreachedAt.Valid {
+			warnAt.FirstResponse = deadlines.FirstResponse
+		}
+		if !appliedSLA.ResolutionMetAt.Valid && !appliedSLA.ResolutionBreachedAt.Valid {
+			warnAt.Resolution = deadlines.Resolution
+		}
+		m.createNotificationSchedule(sla.Notifications, appliedSLA.ID, null.Int{}, warnAt, Breaches{})
+	}
+
+	// Move the awaited next response, counted from when the event was created.
+	var (
+		event        models.SLAEvent
+		nextDeadline null.Time
+	)
+	if err := m.q.GetUnmetNextResponseSLAEvent.Get(&event, appliedSLA.ID); err != nil && err != sql.ErrNoRows {
+		m.lo.Error("error fetching next response SLA event", "applied_sla_id", appliedSLA.ID, "error", err)
+		return false, fmt.Errorf("fetching next response SLA event: %w", err)
+	}
+	if event.ID > 0 {
+		eventDeadlines, err := m.GetDeadlines(event.CreatedAt, appliedSLA.SLAPolicyID, teamID, inboxID, priorityID)
+		if err != nil {
+			return false, err
+		}
+		if nextDeadline, err = m.withPausedTime(eventDeadlines.NextResponse, appliedSLA.ID, MetricNextResponse, event.CreatedAt, teamID, inboxID); err != nil {
+			return false, err
+		}
+		if nextDeadline.Valid && !nextDeadline.Time.Equal(event.DeadlineAt) {
+			changed = true
+			if _, err := m.q.UpdateSLAEventDeadline.Exec(event.ID, nextDeadline.Time); err != nil {
+				m.lo.Error("error updating SLA event deadline", "sla_event_id", event.ID, "error", err)
+				return false, fmt.Errorf("updating SLA event deadline: %w", err)
+			}
+			if _, err := m.q.DeletePendingSLAWarnings.Exec(appliedSLA.ID, MetricNextResponse); err != nil {
+				m.lo.Error("error deleting pending SLA warnings", "applied_sla_id", appliedSLA.ID, "error", err)
+			}
+			m.createNotificationSchedule(sla.Notifications, appliedSLA.ID, null.IntFrom(event.ID), Deadlines{NextResponse: nextDeadline}, Breaches{})
+		} else {
+			nextDeadline = null.TimeFrom(event.DeadlineAt)
+		}
+	}
+
+	if _, err := m.q.UpdateConversationNextSLADeadline.Exec(conversationID, nextDeadline); err != nil {
+		m.lo.Error("error updating conversation next SLA deadline", "conversation_id", conversationID, "error", err)
+		return false, fmt.Errorf("updating conversation next SLA deadline: %w", err)
+	}
+	return changed, nil
+}
+
+// withPausedTime moves a deadline by the business time the clock of the metric was paused for since the given time.
+func (m *Manager) withPausedTime(deadline null.Time, appliedSLAID int, metric string, since time.Time, teamID, inboxID int) (null.Time, error) {
+	if !deadline.Valid {
+		return deadline, nil
+	}
+	var pauses []struct {
+		PausedAt  time.Time `db:"paused_at"`
+		ResumedAt time.Time `db:"resumed_at"`
+	}
+	if err := m.q.GetClosedSLAPauses.Select(&pauses, appliedSLAID, metric, since); err != nil {
+		m.lo.Error("error fetching SLA pauses", "applied_sla_id", appliedSLAID, "error", err)
+		return deadline, fmt.Errorf("fetching SLA pauses: %w", err)
+	}
+	if len(pauses) == 0 {
+		return deadline, nil
+	}
+	businessHrs, timezone, err := m.getBusinessHoursAndTimezone(teamID, inboxID)
+	if err != nil {
+		return deadline, err
+	}
+	var minutes int
+	for _, p := range pauses {
+		from := p.PausedAt
+		if since.After(from) {
+			from = since
+		}
+		n, err := BusinessMinutesBetween(from, p.ResumedAt, businessHrs, timezone)
+		if err != nil {
+			return deadline, err
+		}
+		minutes += n
+	}
+	if minutes == 0 {
+		return deadline, nil
+	}
+	moved, err := m.CalculateDeadline(deadline.Time, minutes, businessHrs, timezone)
+	if err != nil {
+		return deadline, err
+	}
+	return null.TimeFrom(moved), nil
+}
+
 // evaluatePendingSLAEvents fetches pending SLA events, updates their status based on deadlines, and schedules notifications for breached SLAs.
 func (m *Manager) evaluatePendingSLAEvents(ctx context.Context) error {
 	var slaEvents []models.SLAEvent
@@ -440,6 +613,7 @@ func (m *Manager) evaluatePendingSLAEvents(ctx context.Context) error {
 			m.createNotificationSchedule(slaPolicy.Notifications, event.AppliedSLAID, null.IntFrom(event.ID), Deadlines{}, Breaches{
 				NextResponse: null.TimeFrom(time.Now()),
 			})
+			m.escalateBreach(event.AppliedSLAID, null.IntFrom(event.ID), event.SlaPolicyID, MetricNextResponse)
 		}
 	}
 	return nil
@@ -463,9 +637,12 @@ func (m *Manager) runSLAEvaluation(ctx context.Context, interval time.Duration)
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
+			// Status changes that do not go through the conversation manager, e.g. snoozed conversations waking up, are picked up here.
+			m.syncPauses(0)
 			if err := m.evaluatePendingSLAs(ctx); err != nil {
 				m.lo.Error("error processing pending SLAs", "error", err)
 			}
+			m.evaluateEscalations()
 		}
 	}
 }
@@ -546,6 +723,17 @@ func (m *Manager) SendNotification(scheduledNotification models.ScheduledSLANoti
 		return nil
 	}
 
+	// Warnings are not sent while the clock is paused, they are scheduled again for the moved deadline on resume.
+	paused := (scheduledNotification.Metric == MetricResolution && appliedSLA.ResolutionPaused) ||
+		(scheduledNotification.Metric == MetricNextResponse && appliedSLA.NextResponsePaused)
+	if paused && scheduledNotification.NotificationType == NotificationTypeWarning {
+		m.lo.Info("skipping sla warning as the clock is paused", "metric", scheduledNotification.Metric, "scheduled_notification_id", scheduledNotification.ID)
+		if _, err := m.q.UpdateSLANotificationProcessed.Exec(scheduledNotification.ID); err != nil {
+			m.lo.Error("error marking notification as processed", "error", err)
+		}
+		return nil
+	}
+
 	// Send to all recipients (agents).
 	for _, recipientS := range scheduledNotification.Recipients {
 		// Check if SLA is already met, if met mark notification as processed and return.
@@ -738,18 +926,46 @@ func (m *Manager) Close() error {
 	return nil
 }
 
-// getBusinessHoursAndTimezone returns the business hours ID and timezone for a team, falling back to app settings i.e. default helpdesk settings.
-func (m *Manager) getBusinessHoursAndTimezone(assignedTeamID int) (bmodels.BusinessHours, string, error) {
+// IsWithinBusinessHours reports whether t falls inside the business hours of an inbox or team, falling back to the default business hours.
+func (m *Manager) IsWithinBusinessHours(assignedTeamID, inboxID int, t time.Time) (bool, error) {
+	bh, timezone, err := m.getBusinessHoursAndTimezone(assignedTeamID, inboxID)
+	if err != nil {
+		return false, err
+	}
+	return IsWithinBusinessHours(t, bh, timezone)
+}
+
+// NextOpenTime returns the time the business hours of an inbox or team next open at or after t, falling back to the default business hours.
+// The returned time is in the timezone of the business hours and is t when it falls inside them.
+func (m *Manager) NextOpenTime(assignedTeamID, inboxID int, t time.Time) (time.Time, error) {
+	bh, timezone, err := m.getBusinessHoursAndTimezone(assignedTeamID, inboxID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return NextOpenTime(t, bh, timezone)
+}
+
+// getBusinessHoursAndTimezone returns the business hours and timezone of the inbox, falling back to the business hours
+// of the team and then to app settings i.e. default helpdesk settings. Inbox hours come first as they describe when the
+// channel itself is staffed, e.g. a phone inbox with shorter hours than the team, and are evaluated in the system timezone.
+func (m *Manager) getBusinessHoursAndTimezone(assignedTeamID, inboxID int) (bmodels.BusinessHours, string, error) {
 	var (
 		businessHrsID int
 		timezone      string
 		bh            bmodels.BusinessHours
 	)
 
-	// Fetch from team if assignedTeamID is provided.
-	if assignedTeamID != 0 {
+	if inboxID != 0 {
+		if err := m.q.GetInboxBusinessHoursID.Get(&businessHrsID, inboxID); err != nil && err != sql.ErrNoRows {
+			m.lo.Error("error fetching inbox business hours", "inbox_id", inboxID, "error", err)
+		}
+	}
+
+	// Business hours of the team apply when the inbox has none, the team timezone goes with the team business hours only,
+	// the same as calculateBusinessHoursInfo of the conversation manager.
+	if businessHrsID == 0 && assignedTeamID != 0 {
 		team, err := m.teamStore.Get(assignedTeamID)
-		if err == nil {
+		if err == nil && team.BusinessHoursID.Valid {
 			businessHrsID = team.BusinessHoursID.Int
 			timezone = team.Timezone
 		}
@@ -767,9 +983,13 @@ func (m *Manager) getBusinessHoursAndTimezone(assignedTeamID int) (bmodels.Busin
 			return bh, "", fmt.Errorf("parsing settings: %v", err)
 		}
 
-		businessHrsIDStr, _ := out["app.business_hours_id"].(string)
-		businessHrsID, _ = strconv.Atoi(businessHrsIDStr)
-		timezone, _ = out["app.timezone"].(string)
+		if businessHrsID == 0 {
+			businessHrsIDStr, _ := out["app.business_hours_id"].(string)
+			businessHrsID, _ = strconv.Atoi(businessHrsIDStr)
+		}
+		if timezone == "" {
+			timezone, _ = out["app.timezone"].(string)
+		}
 	}
 
 	// If still not found, return error.
@@ -882,6 +1102,7 @@ func (m *Manager) evaluateSLA(appliedSLA models.AppliedSLA) error {
 			if err := m.handleSLABreach(appliedSLA.ID, appliedSLA.SLAPolicyID, metric); err != nil {
 				return fmt.Errorf("updating SLA breach timestamp: %w", err)
 			}
+			m.escalateBreach(appliedSLA.ID, null.Int{}, appliedSLA.SLAPolicyID, metric)
 			changed = true
 			return nil
 		}
@@ -913,7 +1134,8 @@ func (m *Manager) evaluateSLA(appliedSLA models.AppliedSLA) error {
 	}
 
 	// If resolution is not breached and not met, check the deadine and set them.
-	if !appliedSLA.ResolutionBreachedAt.Valid && !appliedSLA.ResolutionMetAt.Valid {
+	// A paused resolution clock is only checked once the conversation is resolved.
+	if !appliedSLA.ResolutionBreachedAt.Valid && !appliedSLA.ResolutionMetAt.Valid && (!appliedSLA.ResolutionPaused || appliedSLA.ConversationResolvedAt.Valid) {
 		m.lo.Debug("checking deadline", "deadline", appliedSLA.ResolutionDeadlineAt.Time, "met_at", appliedSLA.ConversationResolvedAt.Time, "metric", MetricResolution)
 		if err := checkDeadline(appliedSLA.ResolutionDeadlineAt.Time, appliedSLA.ConversationResolvedAt, MetricResolution); err != nil {
 			return err
@@ -20,6 +20,8 @@ type Team struct {
 	BusinessHoursID              null.Int    `db:"business_hours_id" json:"business_hours_id"`
 	SLAPolicyID                  null.Int    `db:"sla_policy_id" json:"sla_policy_id"`
 	MaxAutoAssignedConversations int         `db:"max_auto_assigned_conversations" json:"max_auto_assigned_conversations"`
+	SkillFallbackMinutes         int         `db:"skill_fallback_minutes" json:"skill_fallback_minutes"`
+	StickyAssignmentDays         int         `db:"sticky_assignment_days" json:"sticky_assignment_days"`
 }
 
 type TeamCompact struct {
@@ -29,9 +31,77 @@ type TeamCompact struct {
 }
 
 type TeamMember struct {
-	ID                 int    `db:"id" json:"id"`
-	AvailabilityStatus string `db:"availability_status" json:"availability_status"`
-	TeamID             int    `db:"team_id" json:"team_id"`
+	ID                 int                `db:"id" json:"id"`
+	AvailabilityStatus string             `db:"availability_status" json:"availability_status"`
+	TeamID             int                `db:"team_id" json:"team_id"`
+	Skills             SkillProficiencies `db:"skills" json:"skills"`
+	Capacity           int                `db:"capacity" json:"capacity"`
+	ChannelWeights     ChannelWeights     `db:"channel_weights" json:"channel_weights"`
+	// OffShift is true while the agent is outside the hours of their enabled work schedule.
+	OffShift bool `db:"off_shift" json:"off_shift"`
+	// LastAutoAssignedAt is when the agent was last auto assigned a conversation.
+	LastAutoAssignedAt null.Time `db:"last_auto_assigned_at" json:"-"`
+}
+
+// ChannelWeights maps inbox channels to the capacity units a conversation on the channel takes.
+type ChannelWeights map[string]int
+
+// Weight returns the capacity units a conversation on the channel takes, 1 when not set.
+func (w ChannelWeights) Weight(channel string) int {
+	if weight, ok := w[channel]; ok && weight > 0 {
+		return weight
+	}
+	return 1
+}
+
+// Load returns the capacity units taken by the given number of conversations per channel.
+func (w ChannelWeights) Load(counts map[string]int) int {
+	var load int
+	for channel, count := range counts {
+		load += count * w.Weight(channel)
+	}
+	return load
+}
+
+// Scan implements the sql.Scanner interface for ChannelWeights
+func (w *ChannelWeights) Scan(src interface{}) error {
+	if src == nil {
+		*w = nil
+		return nil
+	}
+
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, w)
+	default:
+		return fmt.Errorf("unsupported type for ChannelWeights: %T", src)
+	}
+}
+
+// Value implements the driver.Valuer interface for ChannelWeights
+func (w ChannelWeights) Value() (driver.Value, error) {
+	if w == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(w)
+}
+
+// SkillProficiencies maps skill IDs to the proficiency of an agent in them.
+type SkillProficiencies map[int]int
+
+// Scan implements the sql.Scanner interface for SkillProficiencies
+func (s *SkillProficiencies) Scan(src interface{}) error {
+	if src == nil {
+		*s = nil
+		return nil
+	}
+
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, s)
+	default:
+		return fmt.Errorf("unsupported type for SkillProficiencies: %T", src)
+	}
 }
 
 type TeamsCompact []TeamCompact
@@ -2,11 +2,14 @@ package user
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"strings"
 	"time"
 
 	"github.com/abhinavxd/libredesk/internal/dbutil"
 	"github.com/abhinavxd/libredesk/internal/envelope"
+	tmodels "github.com/abhinavxd/libredesk/internal/team/models"
 	"github.com/abhinavxd/libredesk/internal/user/models"
 	"github.com/lib/pq"
 	"github.com/volatiletech/null/v9"
@@ -160,6 +163,43 @@ func (u *Manager) SoftDeleteAgent(id int) error {
 	return nil
 }
 
+// GetAgentCapacity returns the auto assignment capacity of an agent.
+func (u *Manager) GetAgentCapacity(id int) (models.AgentCapacity, error) {
+	var capacity models.AgentCapacity
+	if err := u.q.GetAgentCapacity.Get(&capacity, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return capacity, envelope.NewError(envelope.NotFoundError, u.i18n.Ts("globals.messages.notFound", "name", u.i18n.T("globals.terms.agent")), nil)
+		}
+		u.lo.Error("error fetching agent capacity", "id", id, "error", err)
+		return capacity, envelope.NewError(envelope.GeneralError, u.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	if capacity.ChannelWeights == nil {
+		capacity.ChannelWeights = tmodels.ChannelWeights{}
+	}
+	return capacity, nil
+}
+
+// UpdateAgentCapacity sets the auto assignment capacity of an agent and the units a conversation on each channel takes.
+func (u *Manager) UpdateAgentCapacity(id int, capacity models.AgentCapacity) error {
+	if capacity.Capacity < 0 {
+		return envelope.NewError(envelope.InputError, u.i18n.Ts("validation.invalidValue", "name", "`capacity`"), nil)
+	}
+	for _, weight := range capacity.ChannelWeights {
+		if weight < 1 {
+			return envelope.NewError(envelope.InputError, u.i18n.Ts("validation.invalidValue", "name", "`channel_weights`"), nil)
+		}
+	}
+	res, err := u.q.UpdateAgentCapacity.Exec(id, capacity.Capacity, capacity.ChannelWeights)
+	if err != nil {
+		u.lo.Error("error updating agent capacity", "id", id, "error", err)
+		return envelope.NewError(envelope.GeneralError, u.i18n.T("globals.messages.somethingWentWrong"), nil)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return envelope.NewError(envelope.NotFoundError, u.i18n.Ts("globals.messages.notFound", "name", u.i18n.T("globals.terms.agent")), nil)
+	}
+	return nil
+}
+
 // MarkInactiveUsersOffline sets users offline if they have been inactive for more than 5 minutes.
 func (u *Manager) MarkInactiveUsersOffline() []models.OfflineUser {
 	var users []models.OfflineUser
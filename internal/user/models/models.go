@@ -77,6 +77,8 @@ type User struct {
 	InboxID                int                  `json:"-"`
 	SourceChannel          null.String          `json:"-"`
 	SourceChannelID        null.String          `json:"-"`
+	// AutomationRuleID is the automation rule acting as the user, it links the activities raised by its actions back to the rule.
+	AutomationRuleID int `db:"-" json:"-"`
 
 	// API Key fields
 	APIKey           null.String `db:"api_key" json:"api_key"`
@@ -84,6 +86,14 @@ type User struct {
 	APISecret        null.String `db:"api_secret" json:"-"`
 }
 
+// AgentCapacity is how much concurrent work an agent can be auto assigned, in units.
+// A conversation takes the units of its channel weight, so an agent can e.g. take 5 chats or 20 emails.
+type AgentCapacity struct {
+	// Capacity is the total units, 0 for unlimited.
+	Capacity       int                    `db:"capacity" json:"capacity"`
+	ChannelWeights tmodels.ChannelWeights `db:"channel_weights" json:"channel_weights"`
+}
+
 // ChatUser is a user with limited fields for live chat.
 type ChatUser struct {
 	ID                 int         `db:"id" json:"id"`
@@ -95,6 +95,7 @@ SET first_name = COALESCE($2, first_name),
  password = COALESCE($7, password),
  enabled = COALESCE($8, enabled),
  availability_status = COALESCE($9, availability_status),
+ away_set_by_shift = away_set_by_shift AND $9 IS NULL,
  updated_at = now()
 WHERE id = $1;
 
@@ -115,9 +116,16 @@ UPDATE users
 SET avatar_url = $2, updated_at = now()
 WHERE id = $1;
 
+-- name: get-agent-capacity
+SELECT capacity, channel_weights FROM users WHERE id = $1 AND type = 'agent' AND deleted_at IS NULL;
+
+-- name: update-agent-capacity
+UPDATE users SET capacity = $2, channel_weights = $3, updated_at = now()
+WHERE id = $1 AND type = 'agent' AND deleted_at IS NULL;
+
 -- name: update-availability
 UPDATE users
-SET availability_status = $2
+SET availability_status = $2, away_set_by_shift = false
 WHERE id = $1;
 
 -- name: update-last-active-at
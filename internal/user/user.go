@@ -88,6 +88,8 @@ type queries struct {
 	UpsertCustomAttributes        *sqlx.Stmt `query:"upsert-custom-attributes"`
 	UpdateAvatar                  *sqlx.Stmt `query:"update-avatar"`
 	UpdateAvailability            *sqlx.Stmt `query:"update-availability"`
+	GetAgentCapacity              *sqlx.Stmt `query:"get-agent-capacity"`
+	UpdateAgentCapacity           *sqlx.Stmt `query:"update-agent-capacity"`
 	UpdateLastActiveAt            *sqlx.Stmt `query:"update-last-active-at"`
 	UpdateInactiveOffline         *sqlx.Stmt `query:"update-inactive-offline"`
 	GetAvailabilityStatus         *sqlx.Stmt `query:"get-availability-status"`
@@ -5,7 +5,7 @@ DROP TYPE IF EXISTS "message_type" CASCADE; CREATE TYPE "message_type" AS ENUM (
 DROP TYPE IF EXISTS "message_sender_type" CASCADE; CREATE TYPE "message_sender_type" AS ENUM ('agent','contact');
 DROP TYPE IF EXISTS "message_status" CASCADE; CREATE TYPE "message_status" AS ENUM ('received','sent','failed','pending');
 DROP TYPE IF EXISTS "content_type" CASCADE; CREATE TYPE "content_type" AS ENUM ('text','html');
-DROP TYPE IF EXISTS "conversation_assignment_type" CASCADE; CREATE TYPE "conversation_assignment_type" AS ENUM ('Round robin','Manual');
+DROP TYPE IF EXISTS "conversation_assignment_type" CASCADE; CREATE TYPE "conversation_assignment_type" AS ENUM ('Round robin','Manual','Least busy');
 DROP TYPE IF EXISTS "template_type" CASCADE; CREATE TYPE "template_type" AS ENUM ('email_outgoing', 'email_notification');
 -- Visitors are unauthenticated contacts.
 DROP TYPE IF EXISTS "user_type" CASCADE; CREATE TYPE "user_type" AS ENUM ('agent', 'contact', 'visitor', 'ai_assistant');
@@ -20,10 +20,14 @@ DROP TYPE IF EXISTS "applied_sla_status" CASCADE; CREATE TYPE "applied_sla_statu
 DROP TYPE IF EXISTS "sla_event_status" CASCADE; CREATE TYPE "sla_event_status" AS ENUM ('pending', 'breached', 'met');
 DROP TYPE IF EXISTS "sla_metric" CASCADE; CREATE TYPE "sla_metric" AS ENUM ('first_response', 'resolution', 'next_response');
 DROP TYPE IF EXISTS "sla_notification_type" CASCADE; CREATE TYPE "sla_notification_type" AS ENUM ('warning', 'breach');
-DROP TYPE IF EXISTS "activity_log_type" CASCADE; CREATE TYPE "activity_log_type" AS ENUM ('agent_login', 'agent_logout', 'agent_away', 'agent_away_reassigned', 'agent_online', 'agent_password_set', 'agent_role_permissions_changed');
+DROP TYPE IF EXISTS "activity_log_type" CASCADE; CREATE TYPE "activity_log_type" AS ENUM ('agent_login', 'agent_logout', 'agent_away', 'agent_away_reassigned', 'agent_online', 'agent_password_set', 'agent_role_permissions_changed', 'conversations_purged', 'contact_data_exported', 'contact_data_erased');
 DROP TYPE IF EXISTS "macro_visible_when" CASCADE; CREATE TYPE "macro_visible_when" AS ENUM ('replying', 'starting_conversation', 'adding_private_note');
-DROP TYPE IF EXISTS "user_notification_type" CASCADE; CREATE TYPE "user_notification_type" AS ENUM ('mention', 'assignment', 'sla_warning', 'sla_breach');
+DROP TYPE IF EXISTS "user_notification_type" CASCADE; CREATE TYPE "user_notification_type" AS ENUM ('mention', 'assignment', 'sla_warning', 'sla_breach', 'task_due', 'task_overdue');
 DROP TYPE IF EXISTS "conversation_status_category" CASCADE; CREATE TYPE "conversation_status_category" AS ENUM ('open', 'waiting', 'resolved');
+DROP TYPE IF EXISTS "retention_action" CASCADE; CREATE TYPE "retention_action" AS ENUM ('anonymize', 'delete');
+DROP TYPE IF EXISTS "contact_data_request_type" CASCADE; CREATE TYPE "contact_data_request_type" AS ENUM ('export', 'erasure');
+DROP TYPE IF EXISTS "contact_data_request_status" CASCADE; CREATE TYPE "contact_data_request_status" AS ENUM ('pending', 'processing', 'completed', 'failed');
+DROP TYPE IF EXISTS "automation_scheduled_action_status" CASCADE; CREATE TYPE "automation_scheduled_action_status" AS ENUM ('pending', 'running', 'completed', 'failed', 'cancelled');
 DROP TYPE IF EXISTS "ai_knowledge_type" CASCADE; CREATE TYPE "ai_knowledge_type" AS ENUM ('snippet');
 DROP TYPE IF EXISTS "webhook_event" CASCADE; CREATE TYPE webhook_event AS ENUM (
 	'conversation.created',
@@ -57,6 +61,15 @@ CREATE TABLE sla_policies (
 	resolution_time TEXT NOT NULL,
 	next_response_time TEXT NULL,
 	notifications JSONB DEFAULT '[]'::jsonb NOT NULL,
+	-- Conversation status IDs that pause the resolution and next response clocks, keyed by metric.
+	pause_statuses JSONB DEFAULT '{}'::jsonb NOT NULL,
+	-- Target times per conversation priority, overriding the times above.
+	priority_targets JSONB DEFAULT '[]'::jsonb NOT NULL,
+	-- Actions run on breach or at a percentage of a deadline.
+	escalations JSONB DEFAULT '[]'::jsonb NOT NULL,
+	-- Conditions that apply the policy to conversations automatically, checked in order of match weight.
+	conditions JSONB DEFAULT '{}'::jsonb NOT NULL,
+	match_weight INT DEFAULT 0 NOT NULL,
 	CONSTRAINT constraint_sla_policies_on_name CHECK (length(name) <= 140),
 	CONSTRAINT constraint_sla_policies_on_description CHECK (length(description) <= 300)
 );
@@ -71,6 +84,7 @@ CREATE TABLE business_hours (
 	is_always_open BOOL DEFAULT false NOT NULL,
 	hours JSONB NOT NULL,
 	holidays JSONB DEFAULT '{}'::jsonb NOT NULL,
+	holiday_calendar_url TEXT NULL,
 	CONSTRAINT constraint_business_hours_on_name CHECK (length(name) <= 140),
 	CONSTRAINT constraint_business_hours_on_description CHECK (length(description) <= 300)
 );
@@ -87,11 +101,13 @@ CREATE TABLE inboxes (
 	enabled bool DEFAULT TRUE NOT NULL,
 	csat_enabled bool DEFAULT false NOT NULL,
 	prompt_tags_on_reply bool DEFAULT false NOT NULL,
+	out_of_office_enabled bool DEFAULT false NOT NULL,
 	config jsonb DEFAULT '{}'::jsonb NOT NULL,
 	"from" TEXT NULL,
 	from_name_template TEXT NOT NULL DEFAULT '',
 	secret TEXT NULL,
 	linked_email_inbox_id INT REFERENCES inboxes(id) ON DELETE SET NULL,
+	business_hours_id INT REFERENCES business_hours(id) ON DELETE SET NULL ON UPDATE CASCADE NULL,
 	CONSTRAINT constraint_inboxes_on_name CHECK (length("name") <= 140)
 );
 
@@ -104,6 +120,10 @@ CREATE TABLE teams (
 	emoji TEXT NULL,
 	conversation_assignment_type conversation_assignment_type NOT NULL,
 	max_auto_assigned_conversations INT DEFAULT 0 NOT NULL,
+	-- Minutes after which conversations requiring skills are assigned to any team member, 0 to never fall back.
+	skill_fallback_minutes INT DEFAULT 0 NOT NULL,
+	-- Days within which returning contacts are auto assigned to the agent of their previous conversation, 0 to disable.
+	sticky_assignment_days INT DEFAULT 0 NOT NULL,
 
 	-- Set to NULL when business hours or SLA policy is deleted.
 	business_hours_id INT REFERENCES business_hours(id) ON DELETE SET NULL ON UPDATE CASCADE NULL,
@@ -151,10 +171,18 @@ CREATE TABLE users (
 	availability_status user_availability_status DEFAULT 'offline' NOT NULL,
 	last_active_at TIMESTAMPTZ NULL,
 	last_login_at TIMESTAMPTZ NULL,
+	-- Set when the away status was set at the end of the agent's shift, so that only it is cleared when the shift starts.
+	away_set_by_shift BOOL DEFAULT FALSE NOT NULL,
 	-- API key authentication fields
 	api_key TEXT NULL,
 	api_secret TEXT NULL,
 	api_key_last_used_at TIMESTAMPTZ NULL,
+	-- Auto assignment capacity in units, 0 for unlimited, and the units a conversation on each channel takes.
+	capacity INT DEFAULT 0 NOT NULL,
+	channel_weights JSONB DEFAULT '{}'::jsonb NOT NULL,
+	-- Time the agent was last auto assigned a conversation, breaks ties between equally busy agents.
+	last_auto_assigned_at TIMESTAMPTZ NULL,
+    CONSTRAINT constraint_users_on_capacity CHECK (capacity >= 0),
     CONSTRAINT constraint_users_on_country CHECK (LENGTH(country) <= 140),
     CONSTRAINT constraint_users_on_phone_number CHECK (LENGTH(phone_number) <= 20),
 	CONSTRAINT constraint_users_on_phone_number_country_code CHECK (LENGTH(phone_number_country_code) <= 10),
@@ -304,12 +332,94 @@ CREATE TABLE automation_rules (
     enabled BOOL DEFAULT TRUE NOT NULL,
 	weight INT DEFAULT 0 NOT NULL,
 	execution_mode automation_execution_mode DEFAULT 'all' NOT NULL,
+	-- Incremented on every update of the rule.
+	version INT DEFAULT 1 NOT NULL,
     CONSTRAINT constraint_automation_rules_on_name CHECK (length("name") <= 140),
     CONSTRAINT constraint_automation_rules_on_description CHECK (length(description) <= 300)
 );
 CREATE INDEX index_automation_rules_on_enabled_and_weight ON automation_rules(enabled, weight);
 CREATE INDEX index_automation_rules_on_type_and_weight ON automation_rules(type, weight);
 
+DROP TABLE IF EXISTS automation_rule_executions CASCADE;
+CREATE TABLE automation_rule_executions (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMPTZ DEFAULT NOW(),
+	rule_id INT REFERENCES automation_rules(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+	rule_version INT NOT NULL,
+	conversation_id BIGINT REFERENCES conversations(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+	-- Rule type or conversation event that triggered the evaluation.
+	"trigger" TEXT NOT NULL,
+	matched_conditions JSONB DEFAULT '[]'::JSONB NOT NULL,
+	actions JSONB DEFAULT '[]'::JSONB NOT NULL,
+	error TEXT NULL
+);
+CREATE INDEX index_automation_rule_executions_on_rule_id_and_created_at ON automation_rule_executions(rule_id, created_at);
+CREATE INDEX index_automation_rule_executions_on_conversation_id ON automation_rule_executions(conversation_id);
+CREATE INDEX index_automation_rule_executions_on_created_at ON automation_rule_executions(created_at);
+
+DROP TABLE IF EXISTS automation_rule_versions CASCADE;
+CREATE TABLE automation_rule_versions (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMPTZ DEFAULT NOW(),
+	rule_id INT REFERENCES automation_rules(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+	"version" INT NOT NULL,
+	-- Null for versions saved before history was kept or by a deleted user.
+	author_id INT REFERENCES users(id) ON DELETE SET NULL ON UPDATE CASCADE NULL,
+	-- Snapshot of the rule as saved in this version.
+	"name" TEXT NOT NULL,
+	description TEXT NULL,
+	"type" VARCHAR NOT NULL,
+	events TEXT[] DEFAULT '{}'::TEXT[] NOT NULL,
+	rules JSONB NULL,
+	enabled BOOL NOT NULL,
+	-- Fields changed from the previous version.
+	diff JSONB DEFAULT '[]'::JSONB NOT NULL
+);
+CREATE UNIQUE INDEX index_uniq_automation_rule_versions_on_rule_id_and_version ON automation_rule_versions(rule_id, "version");
+
+DROP TABLE IF EXISTS automation_schedules CASCADE;
+CREATE TABLE automation_schedules (
+	id SERIAL PRIMARY KEY,
+	created_at TIMESTAMPTZ DEFAULT NOW(),
+	updated_at TIMESTAMPTZ DEFAULT NOW(),
+	"name" TEXT NOT NULL,
+	description TEXT DEFAULT '' NOT NULL,
+	enabled BOOL DEFAULT TRUE NOT NULL,
+	-- Five field cron expression evaluated in the schedule's timezone.
+	cron_expression TEXT NOT NULL,
+	timezone TEXT DEFAULT 'UTC' NOT NULL,
+	-- Conversation list filters selecting the conversations the actions are applied to.
+	filters JSONB DEFAULT '[]'::JSONB NOT NULL,
+	actions JSONB DEFAULT '[]'::JSONB NOT NULL,
+	next_run_at TIMESTAMPTZ NULL,
+	last_run_at TIMESTAMPTZ NULL,
+	last_run_conversations INT DEFAULT 0 NOT NULL,
+	last_run_error TEXT NULL,
+	CONSTRAINT constraint_automation_schedules_on_name CHECK (length("name") <= 140),
+	CONSTRAINT constraint_automation_schedules_on_description CHECK (length(description) <= 300)
+);
+CREATE INDEX index_automation_schedules_on_next_run_at ON automation_schedules(next_run_at) WHERE enabled = TRUE;
+
+DROP TABLE IF EXISTS automation_scheduled_actions CASCADE;
+CREATE TABLE automation_scheduled_actions (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMPTZ DEFAULT NOW(),
+	updated_at TIMESTAMPTZ DEFAULT NOW(),
+	rule_id INT REFERENCES automation_rules(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+	rule_version INT NOT NULL,
+	conversation_id BIGINT REFERENCES conversations(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+	action JSONB NOT NULL,
+	-- Previous field values the rule matched with, used when re-checking its conditions.
+	previous_values JSONB DEFAULT '{}'::JSONB NOT NULL,
+	run_at TIMESTAMPTZ NOT NULL,
+	status automation_scheduled_action_status DEFAULT 'pending' NOT NULL,
+	error TEXT NULL
+);
+CREATE INDEX index_automation_scheduled_actions_on_run_at ON automation_scheduled_actions(run_at) WHERE status = 'pending';
+CREATE INDEX index_automation_scheduled_actions_on_conversation_id ON automation_scheduled_actions(conversation_id);
+-- A rule schedules an action once per conversation until it runs.
+CREATE UNIQUE INDEX index_uniq_automation_scheduled_actions_on_rule_id_conversation_id_action ON automation_scheduled_actions(rule_id, conversation_id, action) WHERE status = 'pending';
+
 DROP TABLE IF EXISTS conversation_drafts CASCADE;
 CREATE TABLE conversation_drafts (
     id BIGSERIAL PRIMARY KEY,
@@ -324,6 +434,66 @@ CREATE TABLE conversation_drafts (
 );
 CREATE UNIQUE INDEX index_uniq_conversation_drafts_on_conversation_id_and_user_id_and_type ON conversation_drafts (conversation_id, user_id, type);
 
+DROP TABLE IF EXISTS conversation_tasks CASCADE;
+CREATE TABLE conversation_tasks (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMPTZ DEFAULT NOW(),
+	updated_at TIMESTAMPTZ DEFAULT NOW(),
+	conversation_id BIGINT REFERENCES conversations(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+	title TEXT NOT NULL,
+	assignee_id BIGINT REFERENCES users(id) ON DELETE SET NULL ON UPDATE CASCADE NULL,
+	created_by BIGINT REFERENCES users(id) ON DELETE SET NULL ON UPDATE CASCADE NULL,
+	due_at TIMESTAMPTZ NULL,
+	completed_at TIMESTAMPTZ NULL,
+	completed_by BIGINT REFERENCES users(id) ON DELETE SET NULL ON UPDATE CASCADE NULL,
+	due_reminder_sent_at TIMESTAMPTZ NULL,
+	overdue_reminder_sent_at TIMESTAMPTZ NULL,
+	CONSTRAINT constraint_conversation_tasks_on_title CHECK (length(title) <= 500)
+);
+CREATE INDEX index_conversation_tasks_on_conversation_id ON conversation_tasks(conversation_id);
+CREATE INDEX index_conversation_tasks_on_assignee_id ON conversation_tasks(assignee_id);
+CREATE INDEX index_conversation_tasks_on_due_at ON conversation_tasks(due_at) WHERE completed_at IS NULL;
+
+DROP TABLE IF EXISTS conversation_time_entries CASCADE;
+CREATE TABLE conversation_time_entries (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMPTZ DEFAULT NOW(),
+	updated_at TIMESTAMPTZ DEFAULT NOW(),
+	conversation_id BIGINT REFERENCES conversations(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+	-- Entries are kept for billing when the agent is deleted.
+	user_id BIGINT REFERENCES users(id) ON DELETE SET NULL ON UPDATE CASCADE NULL,
+	started_at TIMESTAMPTZ NOT NULL,
+	-- NULL while the timer is running.
+	duration_seconds INT NULL,
+	note TEXT DEFAULT '' NOT NULL,
+	billable BOOLEAN DEFAULT FALSE NOT NULL,
+	CONSTRAINT constraint_conversation_time_entries_on_duration_seconds CHECK (duration_seconds IS NULL OR duration_seconds >= 0),
+	CONSTRAINT constraint_conversation_time_entries_on_note CHECK (length(note) <= 1000)
+);
+CREATE INDEX index_conversation_time_entries_on_conversation_id ON conversation_time_entries(conversation_id);
+CREATE INDEX index_conversation_time_entries_on_started_at ON conversation_time_entries(started_at);
+-- One running timer per agent.
+CREATE UNIQUE INDEX index_uniq_conversation_time_entries_on_user_id_running ON conversation_time_entries(user_id) WHERE duration_seconds IS NULL;
+
+DROP TABLE IF EXISTS resolution_requirements CASCADE;
+CREATE TABLE resolution_requirements (
+	id SERIAL PRIMARY KEY,
+	created_at TIMESTAMPTZ DEFAULT NOW(),
+	updated_at TIMESTAMPTZ DEFAULT NOW(),
+	inbox_id INT REFERENCES inboxes(id) ON DELETE CASCADE ON UPDATE CASCADE NULL,
+	team_id INT REFERENCES teams(id) ON DELETE CASCADE ON UPDATE CASCADE NULL,
+	custom_attribute_ids INT[] DEFAULT '{}'::INT[] NOT NULL,
+	-- Require at least one tag of any kind.
+	require_tag BOOLEAN DEFAULT FALSE NOT NULL,
+	required_tag_ids INT[] DEFAULT '{}'::INT[] NOT NULL,
+	bypass_automation BOOLEAN DEFAULT TRUE NOT NULL,
+	bypass_ai_assistant BOOLEAN DEFAULT TRUE NOT NULL,
+	enabled BOOLEAN DEFAULT TRUE NOT NULL,
+	CONSTRAINT constraint_resolution_requirements_on_inbox_id_team_id CHECK ((inbox_id IS NULL) <> (team_id IS NULL))
+);
+CREATE UNIQUE INDEX index_uniq_resolution_requirements_on_inbox_id ON resolution_requirements(inbox_id) WHERE inbox_id IS NOT NULL;
+CREATE UNIQUE INDEX index_uniq_resolution_requirements_on_team_id ON resolution_requirements(team_id) WHERE team_id IS NOT NULL;
+
 DROP TABLE IF EXISTS macros CASCADE;
 CREATE TABLE macros (
    id SERIAL PRIMARY KEY,
@@ -478,6 +648,58 @@ CREATE TABLE conversation_tags (
 );
 CREATE UNIQUE INDEX index_conversation_tags_on_conversation_id_and_tag_id ON conversation_tags (conversation_id, tag_id);
 
+DROP TABLE IF EXISTS skills CASCADE;
+CREATE TABLE skills (
+	id SERIAL PRIMARY KEY,
+	created_at TIMESTAMPTZ DEFAULT NOW(),
+	updated_at TIMESTAMPTZ DEFAULT NOW(),
+	"name" TEXT NOT NULL UNIQUE,
+	description TEXT DEFAULT '' NOT NULL,
+	CONSTRAINT constraint_skills_on_name CHECK (length("name") <= 140),
+	CONSTRAINT constraint_skills_on_description CHECK (length(description) <= 300)
+);
+
+DROP TABLE IF EXISTS user_skills CASCADE;
+CREATE TABLE user_skills (
+	id SERIAL PRIMARY KEY,
+	created_at TIMESTAMPTZ DEFAULT NOW(),
+	-- Cascade deletes when user or skill is deleted.
+	user_id BIGINT REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+	skill_id INT REFERENCES skills(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+	proficiency INT DEFAULT 1 NOT NULL,
+	CONSTRAINT constraint_user_skills_on_proficiency CHECK (proficiency BETWEEN 1 AND 5)
+);
+CREATE UNIQUE INDEX index_user_skills_on_user_id_and_skill_id ON user_skills (user_id, skill_id);
+
+DROP TABLE IF EXISTS conversation_skills CASCADE;
+CREATE TABLE conversation_skills (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMPTZ DEFAULT NOW(),
+	-- Cascade deletes when skill or conversation is deleted.
+	conversation_id BIGINT REFERENCES conversations(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+	skill_id INT REFERENCES skills(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+	min_proficiency INT DEFAULT 1 NOT NULL,
+	CONSTRAINT constraint_conversation_skills_on_min_proficiency CHECK (min_proficiency BETWEEN 1 AND 5)
+);
+CREATE UNIQUE INDEX index_conversation_skills_on_conversation_id_and_skill_id ON conversation_skills (conversation_id, skill_id);
+
+DROP TABLE IF EXISTS agent_schedules CASCADE;
+CREATE TABLE agent_schedules (
+	id SERIAL PRIMARY KEY,
+	created_at TIMESTAMPTZ DEFAULT NOW(),
+	updated_at TIMESTAMPTZ DEFAULT NOW(),
+	-- Cascade deletes when user is deleted.
+	user_id BIGINT REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL UNIQUE,
+	enabled BOOL DEFAULT TRUE NOT NULL,
+	timezone TEXT NOT NULL,
+	-- Weekly working hours in the business hours format.
+	hours JSONB DEFAULT '{}'::jsonb NOT NULL,
+	time_off JSONB DEFAULT '[]'::jsonb NOT NULL,
+	reassign_at_shift_end BOOL DEFAULT FALSE NOT NULL,
+	-- Whether the agent was on shift when last checked, NULL until the first check.
+	on_shift BOOL NULL
+);
+
 DROP TABLE IF EXISTS csat_responses CASCADE;
 CREATE TABLE csat_responses (
     id SERIAL PRIMARY KEY,
@@ -534,12 +756,28 @@ CREATE TABLE applied_slas (
 	first_response_breached_at TIMESTAMPTZ NULL,
 	resolution_breached_at TIMESTAMPTZ NULL,
 	first_response_met_at TIMESTAMPTZ NULL,
-	resolution_met_at TIMESTAMPTZ NULL
+	resolution_met_at TIMESTAMPTZ NULL,
+
+	-- Total time the clocks were paused, in seconds.
+	resolution_paused_seconds INT DEFAULT 0 NOT NULL,
+	next_response_paused_seconds INT DEFAULT 0 NOT NULL
 );
 CREATE INDEX index_applied_slas_on_conversation_id ON applied_slas(conversation_id);
 CREATE INDEX index_applied_slas_on_status ON applied_slas(status);
 CREATE UNIQUE INDEX index_applied_slas_unique_pending_per_conv ON applied_slas(conversation_id) WHERE status = 'pending';
 
+DROP TABLE IF EXISTS applied_sla_pauses CASCADE;
+CREATE TABLE applied_sla_pauses (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMPTZ DEFAULT NOW(),
+	applied_sla_id BIGINT REFERENCES applied_slas(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+	metric sla_metric NOT NULL,
+	paused_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+	resumed_at TIMESTAMPTZ NULL
+);
+CREATE INDEX index_applied_sla_pauses_on_applied_sla_id ON applied_sla_pauses(applied_sla_id);
+CREATE UNIQUE INDEX index_uniq_applied_sla_pauses_open_per_metric ON applied_sla_pauses(applied_sla_id, metric) WHERE resumed_at IS NULL;
+
 DROP TABLE IF EXISTS sla_events CASCADE;
 CREATE TABLE sla_events (
 	id BIGSERIAL PRIMARY KEY,
@@ -556,6 +794,17 @@ CREATE TABLE sla_events (
 CREATE INDEX index_sla_events_on_applied_sla_id ON sla_events(applied_sla_id);
 CREATE INDEX index_sla_events_on_status ON sla_events(status);
 
+DROP TABLE IF EXISTS applied_sla_escalations CASCADE;
+CREATE TABLE applied_sla_escalations (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMPTZ DEFAULT NOW(),
+	applied_sla_id BIGINT REFERENCES applied_slas(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+	sla_event_id BIGINT REFERENCES sla_events(id) ON DELETE CASCADE ON UPDATE CASCADE NULL,
+	metric sla_metric NOT NULL,
+	escalation_key TEXT NOT NULL
+);
+CREATE UNIQUE INDEX index_uniq_applied_sla_escalations ON applied_sla_escalations(applied_sla_id, COALESCE(sla_event_id, 0), escalation_key);
+
 DROP TABLE IF EXISTS scheduled_sla_notifications CASCADE;
 CREATE TABLE scheduled_sla_notifications (
   id BIGSERIAL PRIMARY KEY,
@@ -823,6 +1072,37 @@ CREATE INDEX index_user_notifications_on_user_id_is_read ON user_notifications(u
 CREATE INDEX index_user_notifications_on_created_at ON user_notifications(created_at);
 CREATE INDEX index_user_notifications_on_conversation_id ON user_notifications(conversation_id);
 
+DROP TABLE IF EXISTS retention_policies CASCADE;
+CREATE TABLE retention_policies (
+	id SERIAL PRIMARY KEY,
+	created_at TIMESTAMPTZ DEFAULT NOW(),
+	updated_at TIMESTAMPTZ DEFAULT NOW(),
+	-- Cascade deletes when inbox is deleted.
+	inbox_id INT REFERENCES inboxes(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL UNIQUE,
+	retention_days INT NOT NULL,
+	action retention_action NOT NULL,
+	enabled BOOL DEFAULT TRUE NOT NULL,
+	CONSTRAINT constraint_retention_policies_on_retention_days CHECK (retention_days > 0)
+);
+
+DROP TABLE IF EXISTS contact_data_requests CASCADE;
+CREATE TABLE contact_data_requests (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMPTZ DEFAULT NOW(),
+	updated_at TIMESTAMPTZ DEFAULT NOW(),
+	-- Cascade deletes when contact is deleted.
+	contact_id BIGINT REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE NOT NULL,
+	requested_by BIGINT REFERENCES users(id) ON DELETE SET NULL ON UPDATE CASCADE NULL,
+	type contact_data_request_type NOT NULL,
+	status contact_data_request_status DEFAULT 'pending' NOT NULL,
+	-- Export archive, cleared once the archive expires.
+	media_uuid UUID NULL,
+	error TEXT NULL,
+	completed_at TIMESTAMPTZ NULL
+);
+CREATE INDEX index_contact_data_requests_on_contact_id ON contact_data_requests(contact_id);
+CREATE INDEX index_contact_data_requests_on_status ON contact_data_requests(status);
+
 INSERT INTO ai_providers
 ("name", provider, type, config, is_default)
 VALUES
@@ -897,7 +1177,7 @@ VALUES
 	(
 		'Admin',
 		'Role for users who have complete access to everything.',
-		'{webhooks:manage,context_links:manage,activity_logs:manage,custom_attributes:manage,contacts:read_all,contacts:read,contacts:write,contacts:block,contact_notes:read,contact_notes:write,contact_notes:delete,conversations:write,ai:manage,general_settings:manage,notification_settings:manage,oidc:manage,conversations:read_all,conversations:read_unassigned,conversations:read_assigned,conversations:read_team_inbox,conversations:read_team_all,conversations:read,conversations:update_user_assignee,conversations:update_team_assignee,conversations:update_priority,conversations:update_status,conversations:update_tags,messages:read,messages:write,view:manage,shared_views:manage,status:manage,tags:manage,macros:manage,users:manage,teams:manage,automations:manage,inboxes:manage,roles:manage,reports:manage,templates:manage,business_hours:manage,sla:manage}'
+		'{webhooks:manage,context_links:manage,activity_logs:manage,custom_attributes:manage,contacts:read_all,contacts:read,contacts:write,contacts:block,contacts:export,contacts:erase,contact_notes:read,contact_notes:write,contact_notes:delete,conversations:write,ai:manage,general_settings:manage,notification_settings:manage,oidc:manage,conversations:read_all,conversations:read_unassigned,conversations:read_assigned,conversations:read_team_inbox,conversations:read_team_all,conversations:read,conversations:update_user_assignee,conversations:update_team_assignee,conversations:update_priority,conversations:update_status,conversations:update_tags,messages:read,messages:write,view:manage,shared_views:manage,status:manage,tags:manage,macros:manage,users:manage,teams:manage,automations:manage,inboxes:manage,roles:manage,reports:manage,templates:manage,business_hours:manage,sla:manage}'
 	);
 
 
@@ -1066,6 +1346,21 @@ VALUES (
   true
 );
 
+INSERT INTO templates
+("type", body, is_default, "name", subject, is_builtin)
+VALUES('email_notification'::template_type, '
+<p>Hi {{ .Contact.FirstName }},</p>
+
+<p>Thanks for reaching out. We are currently closed{{ if .NextOpenTime }} and will be back {{ .NextOpenTime }}{{ end }}.</p>
+
+<p>Your message has been received under reference number {{ .Conversation.ReferenceNumber }} and we will get back to you as soon as we are open.</p>
+',
+  false,
+  'Out of office',
+  '',
+  true
+);
+
 -- Default business hours
 INSERT INTO business_hours ("name", description, is_always_open, hours, holidays) VALUES
 ('Default', 'Default business hours, Monday to Friday, 09:00 to 17:00.', false, '{"Monday": {"open": "09:00", "close": "17:00"}, "Tuesday": {"open": "09:00", "close": "17:00"}, "Wednesday": {"open": "09:00", "close": "17:00"}, "Thursday": {"open": "09:00", "close": "17:00"}, "Friday": {"open": "09:00", "close": "17:00"}}'::jsonb, '[]'::jsonb);